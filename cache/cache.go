@@ -3,11 +3,15 @@ package cache
 
 import (
 	"bytes"
+	stdcontext "context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,8 +27,20 @@ type Entry struct {
 	StatusCode   int                 // The HTTP status code
 	Headers      map[string][]string // The HTTP headers
 	Expiration   time.Time           // When this entry expires
-	LastModified time.Time           // When this entry was last modified
+	LastModified time.Time           // When this entry was last modified, from the handler's Last-Modified header if set
 	ETag         string              // Entity Tag for this response
+	FreshUntil   time.Time           // When this entry goes stale; zero means fresh until it expires
+	StoredAt     time.Time           // When this entry was cached, for the Age header
+}
+
+// isFresh reports whether an entry can be served without revalidation
+func (e *Entry) isFresh(now time.Time) bool {
+	return e.FreshUntil.IsZero() || now.Before(e.FreshUntil)
+}
+
+// isStaleWithin reports whether a stale entry went stale less than window ago
+func (e *Entry) isStaleWithin(window time.Duration, now time.Time) bool {
+	return window > 0 && !e.FreshUntil.IsZero() && now.Before(e.FreshUntil.Add(window))
 }
 
 // Store defines the interface for cache storage backends.
@@ -35,73 +51,42 @@ type Store interface {
 	Set(key string, entry *Entry, ttl time.Duration)
 	// Delete removes an entry from the cache
 	Delete(key string)
+	// DeleteByPrefix removes every entry whose key starts with prefix
+	DeleteByPrefix(prefix string)
 	// Clear removes all entries from the cache
 	Clear()
 }
 
-// MemoryStore is an in-memory implementation of CacheStore
-type MemoryStore struct {
-	items map[string]*Entry
-	mutex sync.RWMutex
-}
-
-// NewMemoryStore creates a new memory cache store
-func NewMemoryStore() *MemoryStore {
-	return &MemoryStore{
-		items: make(map[string]*Entry),
-	}
-}
-
-// Get retrieves an entry from the memory cache
-func (s *MemoryStore) Get(key string) (*Entry, bool) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	entry, exists := s.items[key]
-	if !exists {
-		return nil, false
-	}
-
-	// Check if the entry has expired
-	if time.Now().After(entry.Expiration) {
-		delete(s.items, key)
-		return nil, false
-	}
-
-	return entry, true
+// GenerateETag returns the strong entity tag used for a response body
+func GenerateETag(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
 }
 
-// Set stores an entry in the memory cache
-func (s *MemoryStore) Set(key string, entry *Entry, ttl time.Duration) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	// Set expiration time
+// prepareEntry sets the expiration of an entry being stored, and its ETag if unset
+func prepareEntry(entry *Entry, ttl time.Duration) {
 	entry.Expiration = time.Now().Add(ttl)
-
-	// Generate ETag if not set
 	if entry.ETag == "" {
-		hash := sha256.Sum256(entry.Body)
-		entry.ETag = hex.EncodeToString(hash[:])
+		entry.ETag = GenerateETag(entry.Body)
 	}
-
-	s.items[key] = entry
 }
 
-// Delete removes an entry from the memory cache
-func (s *MemoryStore) Delete(key string) {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	delete(s.items, key)
+// encodeEntry prepares an entry and encodes it for stores that keep bytes
+func encodeEntry(entry *Entry, ttl time.Duration) ([]byte, error) {
+	prepareEntry(entry, ttl)
+	return json.Marshal(entry)
 }
 
-// Clear removes all entries from the memory cache
-func (s *MemoryStore) Clear() {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	s.items = make(map[string]*Entry)
+// decodeEntry decodes an entry encoded by encodeEntry, reporting expired entries as missing
+func decodeEntry(data []byte) (*Entry, bool, error) {
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, err
+	}
+	if time.Now().After(entry.Expiration) {
+		return nil, false, nil
+	}
+	return &entry, true, nil
 }
 
 // ResponseWriter is a wrapper for http.ResponseWriter that captures the response
@@ -111,6 +96,9 @@ type ResponseWriter struct {
 	status    int
 	headerSet bool
 	written   bool
+	header    http.Header // Headers of a buffered response, nil when writing through
+	limit     int64       // Body size after which a buffered response is streamed, 0 for no limit
+	streaming bool        // Whether the response was streamed through uncaptured
 }
 
 // NewResponseWriter creates a new response writer wrapper
@@ -122,8 +110,22 @@ func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
 	}
 }
 
+// newBufferedResponseWriter creates a response writer that holds the response
+// until flush is called, so cache headers can be added or the response replaced
+// by a stale entry. Bodies larger than limit bytes and flushed responses are
+// streamed through instead, without being captured.
+func newBufferedResponseWriter(w http.ResponseWriter, limit int64) *ResponseWriter {
+	rw := NewResponseWriter(w)
+	rw.header = make(http.Header)
+	rw.limit = limit
+	return rw
+}
+
 // Header returns the header map to set before writing a response
 func (w *ResponseWriter) Header() http.Header {
+	if w.header != nil {
+		return w.header
+	}
 	return w.writer.Header()
 }
 
@@ -139,15 +141,56 @@ func (w *ResponseWriter) Write(b []byte) (int, error) {
 		w.WriteHeader(http.StatusOK)
 	}
 
+	if w.header != nil {
+		if w.limit <= 0 || int64(w.body.Len()+len(b)) <= w.limit {
+			return w.body.Write(b)
+		}
+		w.stream()
+	}
+
 	if !w.written {
 		w.writer.WriteHeader(w.status)
 		w.written = true
 	}
 
-	w.body.Write(b)
+	if !w.streaming {
+		w.body.Write(b)
+	}
 	return w.writer.Write(b)
 }
 
+// Flush sends the response written so far to the client. A buffered response is
+// streamed from then on and not cached.
+func (w *ResponseWriter) Flush() {
+	if w.header != nil {
+		w.stream()
+	}
+	_ = http.NewResponseController(w.writer).Flush()
+}
+
+// flush sends a buffered response to the underlying writer
+func (w *ResponseWriter) flush() {
+	maps.Copy(w.writer.Header(), w.header)
+	w.writer.WriteHeader(w.status)
+	w.written = true
+	if _, err := w.writer.Write(w.body.Bytes()); err != nil {
+		log.Printf("Error writing buffered response: %v", err)
+	}
+}
+
+// stream sends a buffered response and writes the rest of it through uncaptured
+func (w *ResponseWriter) stream() {
+	w.flush()
+	w.header = nil
+	w.streaming = true
+	w.body.Reset()
+}
+
+// Unwrap returns the underlying ResponseWriter so http.ResponseController can reach it
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.writer
+}
+
 // Status returns the HTTP status code
 func (w *ResponseWriter) Status() int {
 	return w.status
@@ -166,12 +209,47 @@ type Config struct {
 	Methods []string
 	// Store is the cache store to use
 	Store Store
-	// KeyGenerator generates cache keys from the request
-	KeyGenerator func(*context.Context) string
+	// KeyGenerator generates cache keys from the request (default: DefaultKey, or
+	// PathKey when VaryQuery is set)
+	KeyGenerator KeyFunc
+	// VaryHeaders are request headers whose values are part of the cache key, e.g.
+	// Accept-Language; they are also listed in the Vary response header
+	VaryHeaders []string
+	// VaryQuery are query parameters whose values are part of the cache key. With
+	// the default key, other parameters such as tracking codes are ignored.
+	VaryQuery []string
+	// VaryUser returns the user or tenant a response is personalized for, e.g. the
+	// subject of the JWT claims, making it part of the cache key. It lets requests
+	// with credentials and responses marked Cache-Control: private be cached;
+	// requests with credentials it returns no user for bypass the cache. Responses
+	// are sent with Cache-Control: private unless the handler sets its own.
+	VaryUser func(*context.Context) string
+	// VaryAPIVersion makes the version resolved by the versioning middleware part
+	// of the cache key; the cache middleware must run after it
+	VaryAPIVersion bool
 	// SkipCache determines whether to skip caching for a request
 	SkipCache func(*context.Context) bool
+	// IgnoreCredentials caches requests with an Authorization or Cookie header in
+	// entries shared by all users. By default they bypass the cache unless VaryUser
+	// is set; only enable it for responses that don't depend on the user.
+	IgnoreCredentials bool
 	// MaxBodySize is the maximum size of the body to cache (default: 1MB)
 	MaxBodySize int64
+	// StaleWhileRevalidate is how long a stale entry is still served, with X-Cache
+	// STALE, while it is refreshed in the background (default: 0, disabled)
+	StaleWhileRevalidate time.Duration
+	// StaleIfError is how long a stale entry is still served in place of a 5xx
+	// response from the handler (default: 0, disabled)
+	StaleIfError time.Duration
+	// Metrics is notified of the result of every lookup, see Counter (default: nil)
+	Metrics MetricsRecorder
+	// RefreshHeader is a request header forcing the cached entry to be refreshed,
+	// like Cache-Control: no-cache (default: "X-Cache-Refresh")
+	RefreshHeader string
+	// AllowRefresh decides whether a request may refresh entries with no-cache or
+	// RefreshHeader, or bypass the cache with no-store, e.g. only for internal
+	// clients (default: every request may)
+	AllowRefresh func(*context.Context) bool
 }
 
 // DefaultCacheConfig returns the default cache configuration
@@ -180,14 +258,14 @@ func DefaultCacheConfig() Config {
 		TTL:     time.Minute * 5,
 		Methods: []string{http.MethodGet},
 		Store:   NewMemoryStore(),
-		KeyGenerator: func(c *context.Context) string {
-			return c.Request.Method + ":" + c.Request.URL.String()
-		},
 		SkipCache: func(c *context.Context) bool {
-			// Skip caching if the request includes Authorization header
-			return c.GetHeader("Authorization") != ""
+			return false
+		},
+		MaxBodySize:   1024 * 1024, // 1MB
+		RefreshHeader: "X-Cache-Refresh",
+		AllowRefresh: func(c *context.Context) bool {
+			return true
 		},
-		MaxBodySize: 1024 * 1024, // 1MB
 	}
 }
 
@@ -208,7 +286,10 @@ func initializeConfig(config *Config) {
 		config.Store = DefaultCacheConfig().Store
 	}
 	if config.KeyGenerator == nil {
-		config.KeyGenerator = DefaultCacheConfig().KeyGenerator
+		config.KeyGenerator = DefaultKey
+		if len(config.VaryQuery) > 0 {
+			config.KeyGenerator = PathKey
+		}
 	}
 	if config.SkipCache == nil {
 		config.SkipCache = DefaultCacheConfig().SkipCache
@@ -216,6 +297,12 @@ func initializeConfig(config *Config) {
 	if config.MaxBodySize == 0 {
 		config.MaxBodySize = DefaultCacheConfig().MaxBodySize
 	}
+	if config.RefreshHeader == "" {
+		config.RefreshHeader = DefaultCacheConfig().RefreshHeader
+	}
+	if config.AllowRefresh == nil {
+		config.AllowRefresh = DefaultCacheConfig().AllowRefresh
+	}
 }
 
 // isMethodAllowed checks if the HTTP method is allowed for caching
@@ -228,18 +315,21 @@ func isMethodAllowed(method string, allowedMethods []string) bool {
 	return false
 }
 
-// serveFromCache serves a cached response to the client
-func serveFromCache(c *context.Context, entry *Entry) {
+// serveFromCache serves a cached response to the client, reporting status in X-Cache
+func serveFromCache(c *context.Context, entry *Entry, status Result) {
 	// Serve headers from cache
+	header := c.Writer.Header()
 	for name, values := range entry.Headers {
-		for _, value := range values {
-			c.SetHeader(name, value)
-		}
+		header[name] = slices.Clone(values)
 	}
 
 	// Add cache headers
-	c.SetHeader("X-Cache", "HIT")
-	c.SetHeader("Age", strconv.FormatInt(int64(time.Since(entry.LastModified).Seconds()), 10))
+	storedAt := entry.StoredAt
+	if storedAt.IsZero() {
+		storedAt = entry.LastModified
+	}
+	c.SetHeader("X-Cache", string(status))
+	c.SetHeader("Age", strconv.FormatInt(int64(time.Since(storedAt).Seconds()), 10))
 
 	// Write status and body
 	c.Status(entry.StatusCode)
@@ -251,36 +341,28 @@ func serveFromCache(c *context.Context, entry *Entry) {
 
 // handleConditionalGET checks for conditional GET headers and returns true if 304 Not Modified was sent
 func handleConditionalGET(c *context.Context, entry *Entry) bool {
-	// Check for conditional GET requests
-	ifNoneMatch := c.GetHeader("If-None-Match")
-	ifModifiedSince := c.GetHeader("If-Modified-Since")
-
-	// Compare ETag values properly, handling quotes
-	if ifNoneMatch != "" {
-		// Clean the If-None-Match header to handle quoted ETags
-		cleanETag := strings.Trim(ifNoneMatch, "\"")
-		entryETag := strings.Trim(entry.ETag, "\"")
-
-		if cleanETag == entryETag {
-			c.Status(http.StatusNotModified)
-			return true
-		}
-	}
+	c.SetETag(entry.ETag)
+	c.SetLastModified(entry.LastModified)
 
-	if ifModifiedSince != "" {
-		if parsedTime, err := time.Parse(http.TimeFormat, ifModifiedSince); err == nil {
-			if !entry.LastModified.After(parsedTime) {
-				c.Status(http.StatusNotModified)
-				return true
-			}
-		}
+	if c.IsFresh() {
+		c.NotModified()
+		return true
 	}
 
 	return false
 }
 
+// serveEntry answers a request from a cached entry, with 304 Not Modified when the client's copy is current
+func serveEntry(c *context.Context, entry *Entry, status Result) {
+	c.SetHeader("X-Cache", string(status))
+	if handleConditionalGET(c, entry) {
+		return
+	}
+	serveFromCache(c, entry, status)
+}
+
 // createCacheEntry creates a new cache entry from the response
-func createCacheEntry(responseWriter *ResponseWriter, now time.Time) (*Entry, string) {
+func createCacheEntry(responseWriter *ResponseWriter, now time.Time) *Entry {
 	headers := make(map[string][]string)
 
 	// Copy headers that should be cached
@@ -292,20 +374,29 @@ func createCacheEntry(responseWriter *ResponseWriter, now time.Time) (*Entry, st
 		headers[name] = values
 	}
 
-	// Generate ETag
+	// Reuse an ETag set by the handler or the ETag middleware so validators stay consistent
 	body := responseWriter.Body()
-	hash := sha256.Sum256(body)
-	etag := hex.EncodeToString(hash[:])
+	etag := responseWriter.Header().Get("ETag")
+	if etag == "" {
+		etag = GenerateETag(body)
+	}
+
+	// Keep a Last-Modified time set by the handler, e.g. from the record's update time
+	lastModified := now
+	if modified, err := http.ParseTime(responseWriter.Header().Get("Last-Modified")); err == nil {
+		lastModified = modified
+	}
 
 	entry := &Entry{
 		Body:         body,
 		StatusCode:   responseWriter.Status(),
 		Headers:      headers,
-		LastModified: now,
+		LastModified: lastModified,
 		ETag:         etag,
+		StoredAt:     now,
 	}
 
-	return entry, etag
+	return entry
 }
 
 // WithConfig creates a new cache middleware with custom configuration
@@ -313,55 +404,265 @@ func WithConfig(config Config) router.Middleware {
 	// Initialize configuration with defaults
 	initializeConfig(&config)
 
+	// Keys of stale entries being refreshed in the background, and the config of
+	// refreshes, which aren't client lookups to count
+	var refreshing sync.Map
+	refreshConfig := config
+	refreshConfig.Metrics = nil
+
 	return func(next router.HandlerFunc) router.HandlerFunc {
 		return func(c *context.Context) {
-			// Skip cache if the method is not cacheable or if SkipCache returns true
-			if !isMethodAllowed(c.Request.Method, config.Methods) || config.SkipCache(c) {
-				next(c)
+			// This middleware's policy replaces the one of an enclosing cache middleware
+			overrideEnclosing(c)
+
+			// Skip cache if the method is not cacheable, if SkipCache returns true,
+			// for credentials or when the client asks not to store the response
+			if !isMethodAllowed(c.Request.Method, config.Methods) {
+				bypass(c, next, nil)
+				return
+			}
+			noCache, noStore := config.requestDirectives(c)
+			if config.SkipCache(c) || config.skipCredentials(c) || noStore {
+				bypass(c, next, &config)
 				return
 			}
 
 			// Generate cache key
-			key := config.KeyGenerator(c)
+			key := buildKey(c, &config)
+
+			// Refresh the entry without looking it up when the client asks to
+			if noCache {
+				cacheResponse(c, next, &config, key, nil)
+				return
+			}
 
 			// Check if we have a cached response
-			if entry, found := config.Store.Get(key); found {
-				// Check for conditional GET requests that may result in 304 Not Modified
-				if handleConditionalGET(c, entry) {
-					return
+			entry, found := config.Store.Get(key)
+			now := time.Now()
+			switch {
+			case found && entry.isFresh(now):
+				config.recordResult(c, ResultHit)
+				serveEntry(c, entry, ResultHit)
+				return
+			case found && entry.isStaleWithin(config.StaleWhileRevalidate, now):
+				// Serve the stale entry and refresh it once, off the request
+				if _, busy := refreshing.LoadOrStore(key, struct{}{}); !busy {
+					go func(rc *context.Context) {
+						defer refreshing.Delete(key)
+						defer func() {
+							if r := recover(); r != nil {
+								log.Printf("Panic refreshing cache entry %s: %v", key, r)
+							}
+						}()
+						cacheResponse(rc, next, &refreshConfig, key, nil)
+					}(newRefreshContext(c))
 				}
-
-				// Serve the cached response
-				serveFromCache(c, entry)
+				config.recordResult(c, ResultStale)
+				serveEntry(c, entry, ResultStale)
 				return
 			}
 
-			// Cache miss, capture the response
-			responseWriter := NewResponseWriter(c.Writer)
-			c.Writer = responseWriter
+			// Keep a stale entry to fall back to if the handler fails
+			var stale *Entry
+			if found && entry.isStaleWithin(config.StaleIfError, now) {
+				stale = entry
+			}
+			cacheResponse(c, next, &config, key, stale)
+		}
+	}
+}
 
-			// Call the next handler
-			next(c)
+// cacheResponse calls the handler and caches its response. The response is buffered
+// so cache headers are sent with it; when stale is set, it is replaced by stale if
+// the handler fails with a 5xx status.
+func cacheResponse(c *context.Context, next router.HandlerFunc, config *Config, key string, stale *Entry) {
+	// Cache miss, capture the response
+	writer := c.Writer
+	responseWriter := newBufferedResponseWriter(writer, config.MaxBodySize)
+	c.Writer = responseWriter
+	defer func() { c.Writer = writer }()
+
+	scope := &cacheScope{}
+	context.Set(c, cacheScopeKey, scope)
+	responseWriter.Header().Set("X-Cache", string(ResultMiss))
+	for _, name := range config.VaryHeaders {
+		responseWriter.Header().Add("Vary", http.CanonicalHeaderKey(name))
+	}
 
-			// Don't cache errors or oversized responses
-			if responseWriter.Status() >= 400 || int64(len(responseWriter.Body())) > config.MaxBodySize {
-				return
-			}
+	// Call the next handler
+	next(c)
 
-			// Create cache entry
-			now := time.Now()
-			entry, etag := createCacheEntry(responseWriter, now)
+	// The lookup is counted by a nested middleware when it overrides this one
+	result := ResultMiss
+	defer func() {
+		if !scope.overridden {
+			config.recordResult(c, result)
+		}
+	}()
+
+	switch {
+	case responseWriter.streaming:
+		// Oversized and flushed responses were sent as they were written
+	case stale != nil && responseWriter.Status() >= 500:
+		result = ResultStale
+		c.Writer = writer
+		serveEntry(c, stale, ResultStale)
+	case scope.overridden || responseWriter.Status() >= 400 || responseWriter.Status() == http.StatusNotModified ||
+		config.isPrivate(responseWriter.Header()):
+		// Don't cache errors, bodiless 304s, private responses or responses cached by a nested middleware
+		responseWriter.flush()
+	default:
+		storeResponse(c, responseWriter, config, key)
+	}
+}
+
+// storeResponse caches a buffered response and sends it with its validators, or
+// answers 304 Not Modified if they match the client's copy
+func storeResponse(c *context.Context, responseWriter *ResponseWriter, config *Config, key string) {
+	// Keep a Cache-Control header set by the handler
+	if responseWriter.Header().Get("Cache-Control") == "" {
+		responseWriter.Header().Set("Cache-Control", cacheControl(config))
+	}
+
+	// Create cache entry
+	now := time.Now()
+	entry := createCacheEntry(responseWriter, now)
+
+	// Store in cache, keeping the entry past its TTL while it may be served stale
+	entry.FreshUntil = now.Add(config.TTL)
+	config.Store.Set(key, entry, config.TTL+max(config.StaleWhileRevalidate, config.StaleIfError))
+
+	// Add cache headers to response
+	c.SetETag(entry.ETag)
+	c.SetLastModified(entry.LastModified)
+	c.SetHeader("X-Cache", string(ResultMiss))
+
+	if c.IsFresh() {
+		c.Writer = responseWriter.Unwrap()
+		maps.Copy(c.Writer.Header(), responseWriter.Header())
+		c.NotModified()
+		return
+	}
+	responseWriter.flush()
+}
+
+// cacheControl returns the Cache-Control header of cached responses, private when
+// they are personalized by VaryUser so shared caches don't serve them to other users
+func cacheControl(config *Config) string {
+	visibility := "public"
+	if config.VaryUser != nil {
+		visibility = "private"
+	}
+	value := fmt.Sprintf("max-age=%d, %s", int(config.TTL.Seconds()), visibility)
+	if config.StaleWhileRevalidate > 0 {
+		value += fmt.Sprintf(", stale-while-revalidate=%d", int(config.StaleWhileRevalidate.Seconds()))
+	}
+	if config.StaleIfError > 0 {
+		value += fmt.Sprintf(", stale-if-error=%d", int(config.StaleIfError.Seconds()))
+	}
+	return value
+}
+
+// newRefreshContext copies a request's context for refreshing a stale entry in the
+// background. The copy outlives the request and its response only goes to the cache.
+func newRefreshContext(c *context.Context) *context.Context {
+	req := c.Request.Clone(stdcontext.WithoutCancel(c.Request.Context()))
+	rc := context.New(discardWriter{header: make(http.Header)}, req)
+	maps.Copy(rc.Params, c.Params)
+	return rc
+}
 
-			// Add cache headers to response
-			c.SetHeader("ETag", etag)
-			c.SetHeader("Last-Modified", now.Format(http.TimeFormat))
-			c.SetHeader("Cache-Control", fmt.Sprintf("max-age=%d, public", int(config.TTL.Seconds())))
-			c.SetHeader("X-Cache", "MISS")
+// discardWriter is an http.ResponseWriter that discards the response
+type discardWriter struct {
+	header http.Header
+}
+
+func (w discardWriter) Header() http.Header       { return w.header }
+func (discardWriter) WriteHeader(int)             {}
+func (discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+
+// bypass passes a request the cache doesn't handle on to the handler, counting it
+// when config is set unless a nested cache middleware handles it
+func bypass(c *context.Context, next router.HandlerFunc, config *Config) {
+	c.SetHeader("X-Cache", string(ResultBypass))
+	scope := &cacheScope{}
+	context.Set(c, cacheScopeKey, scope)
 
-			// Store in cache
-			config.Store.Set(key, entry, config.TTL)
+	next(c)
+
+	if config != nil && !scope.overridden {
+		config.recordResult(c, ResultBypass)
+	}
+}
+
+// requestDirectives reports whether a request asks to revalidate the cached entry,
+// with Cache-Control or Pragma no-cache or the refresh header, or not to store the
+// response, with no-store. Both are ignored for requests AllowRefresh rejects.
+func (config *Config) requestDirectives(c *context.Context) (noCache, noStore bool) {
+	cacheControl := c.GetHeader("Cache-Control")
+	for _, name := range directives(cacheControl) {
+		switch name {
+		case "no-cache":
+			noCache = true
+		case "no-store":
+			noStore = true
 		}
 	}
+	if cacheControl == "" && strings.EqualFold(strings.TrimSpace(c.GetHeader("Pragma")), "no-cache") {
+		noCache = true
+	}
+	if c.GetHeader(config.RefreshHeader) != "" {
+		noCache = true
+	}
+
+	if (noCache || noStore) && !config.AllowRefresh(c) {
+		return false, false
+	}
+	return noCache, noStore
+}
+
+// directives returns the lowercased directive names of a Cache-Control header
+func directives(cacheControl string) []string {
+	var names []string
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if name != "" {
+			names = append(names, strings.ToLower(name))
+		}
+	}
+	return names
+}
+
+// skipCredentials reports whether a request carries credentials and may get a
+// response personalized for its user, which must not be shared with other users.
+// With VaryUser, such requests are only cached once their user is known.
+func (config *Config) skipCredentials(c *context.Context) bool {
+	if config.IgnoreCredentials {
+		return false
+	}
+	if c.GetHeader("Authorization") == "" && c.GetHeader("Cookie") == "" {
+		return false
+	}
+	return config.VaryUser == nil || config.VaryUser(c) == ""
+}
+
+// isPrivate reports whether a response must not be stored: it sets a cookie, is
+// marked Cache-Control: no-store, or private while not varied by user
+func (config *Config) isPrivate(header http.Header) bool {
+	if header.Get("Set-Cookie") != "" {
+		return true
+	}
+	for _, name := range directives(header.Get("Cache-Control")) {
+		switch name {
+		case "no-store":
+			return true
+		case "private":
+			if config.VaryUser == nil {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // isHopByHopHeader determines if the header is a hop-by-hop header
@@ -386,3 +687,9 @@ func ClearCache(store Store) {
 func InvalidateCache(store Store, key string) {
 	store.Delete(key)
 }
+
+// InvalidatePrefix invalidates every cache entry whose key starts with prefix,
+// e.g. "GET:/users" for all cached user listings
+func InvalidatePrefix(store Store, prefix string) {
+	store.DeleteByPrefix(prefix)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -110,6 +111,17 @@ func TestMemoryStore(t *testing.T) {
 			testCacheEntryExists(t, store, testKey, false, "be deleted")
 		})
 
+		t.Run("DeleteByPrefix", func(t *testing.T) {
+			store.Set("GET:/users?page=1", entry, standardTTL)
+			store.Set("GET:/users?page=2", entry, standardTTL)
+			store.Set(testKey, entry, standardTTL)
+			store.DeleteByPrefix("GET:/users")
+
+			testCacheEntryExists(t, store, "GET:/users?page=1", false, "be deleted by prefix")
+			testCacheEntryExists(t, store, "GET:/users?page=2", false, "be deleted by prefix")
+			testCacheEntryExists(t, store, testKey, true, "")
+		})
+
 		t.Run("Clear", func(t *testing.T) {
 			// Test Clear
 			store.Set(testKey1, entry, standardTTL)
@@ -327,9 +339,9 @@ func TestSkipCache(t *testing.T) {
 		t.Errorf(errHandlerCallCount, "once", handlerCalled)
 	}
 
-	// Should not be cached, so header should not exist
-	if w1.Header().Get(headerXCache) != "" {
-		t.Errorf(errNoHeader, headerXCache, w1.Header().Get(headerXCache))
+	// Should not be cached, so the response is marked as bypassed
+	if got := w1.Header().Get(headerXCache); got != string(ResultBypass) {
+		t.Errorf(errHeader, headerXCache, ResultBypass, got)
 	}
 
 	// Same request should still skip cache and call handler again
@@ -370,8 +382,105 @@ func TestNonGetMethod(t *testing.T) {
 	}
 
 	// Should not be cached
-	if w.Header().Get(headerXCache) != "" {
-		t.Errorf(errNoHeader, headerXCache, w.Header().Get(headerXCache))
+	if got := w.Header().Get(headerXCache); got != string(ResultBypass) {
+		t.Errorf(errHeader, headerXCache, ResultBypass, got)
+	}
+}
+
+// setupStaleTest returns a middleware whose handler answers with its call count, or fails once failFrom calls were made
+func setupStaleTest(config Config, failFrom int32) (router.HandlerFunc, *atomic.Int32) {
+	calls := new(atomic.Int32)
+	handler := func(c *context.Context) {
+		n := calls.Add(1)
+		if failFrom > 0 && n >= failFrom {
+			c.JSON(http.StatusBadGateway, map[string]string{"error": "upstream down"})
+			return
+		}
+		c.JSON(http.StatusOK, map[string]int32{"call": n})
+	}
+	return WithConfig(config)(handler), calls
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	_, config, _ := setupCacheTest()
+	config.TTL = shortTTL
+	config.StaleWhileRevalidate = standardTTL
+	middleware, calls := setupStaleTest(config, 0)
+
+	w1, c1 := setupRequest(http.MethodGet, "/stale", nil)
+	middleware(c1)
+	if cc := w1.Header().Get("Cache-Control"); cc != "max-age=0, public, stale-while-revalidate=60" {
+		t.Errorf(errHeader, "Cache-Control", "stale-while-revalidate", cc)
+	}
+	time.Sleep(expirationWaitTime)
+
+	// The stale entry is served at once and refreshed in the background
+	w2, c2 := setupRequest(http.MethodGet, "/stale", nil)
+	middleware(c2)
+	testCacheHeader(t, w2, "STALE")
+	if w2.Body.String() != w1.Body.String() {
+		t.Errorf(errEntryBody, w1.Body.String(), w2.Body.String())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The refreshed entry is stored by the time the next request arrives
+	var w3 *httptest.ResponseRecorder
+	for time.Now().Before(deadline) {
+		var c3 *context.Context
+		w3, c3 = setupRequest(http.MethodGet, "/stale", nil)
+		middleware(c3)
+		if w3.Header().Get(headerXCache) == valCacheHit {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	testCacheHeader(t, w3, valCacheHit)
+	if !strings.Contains(w3.Body.String(), `"call":2`) {
+		t.Errorf(errEntryBody, `{"call":2}`, w3.Body.String())
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf(errHandlerCallCount, "twice", n)
+	}
+}
+
+func TestStaleIfError(t *testing.T) {
+	tests := []struct {
+		name         string
+		staleIfError time.Duration
+		failFrom     int32
+		status       int
+		cacheHeader  string
+	}{
+		{"serves stale on 5xx", standardTTL, 2, http.StatusOK, "STALE"},
+		{"serves fresh response", standardTTL, 0, http.StatusOK, valCacheMiss},
+		{"disabled", 0, 2, http.StatusBadGateway, valCacheMiss},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, config, _ := setupCacheTest()
+			config.TTL = shortTTL
+			config.StaleIfError = tt.staleIfError
+			middleware, _ := setupStaleTest(config, tt.failFrom)
+
+			w1, c1 := setupRequest(http.MethodGet, "/flaky", nil)
+			middleware(c1)
+			time.Sleep(expirationWaitTime)
+
+			w2, c2 := setupRequest(http.MethodGet, "/flaky", nil)
+			middleware(c2)
+			if w2.Code != tt.status {
+				t.Errorf(errStatus, tt.status, w2.Code)
+			}
+			testCacheHeader(t, w2, tt.cacheHeader)
+			if stale := w2.Body.String() == w1.Body.String(); stale != (tt.cacheHeader == "STALE") {
+				t.Errorf("Expected stale body: %v, got %s", !stale, w2.Body.String())
+			}
+		})
 	}
 }
 
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	_, config, calls := setupCacheTest()
+	middleware := WithConfig(config)(func(c *context.Context) {
+		(*calls)++
+		c.SetLastModified(updated)
+		c.JSON(http.StatusOK, map[string]string{"message": testMessage})
+	})
+
+	// Validators are sent with the first response, not only recorded afterwards
+	w1, c1 := setupRequest(http.MethodGet, "/article", nil)
+	middleware(c1)
+	sent := w1.Result().Header
+	etag := sent.Get(headerETag)
+	if etag == "" || sent.Get(headerXCache) != valCacheMiss || sent.Get("Cache-Control") == "" {
+		t.Fatalf("Expected cache headers to be sent, got %v", sent)
+	}
+	if modified := sent.Get(headerLastModified); modified != updated.Format(http.TimeFormat) {
+		t.Errorf(errHeader, headerLastModified, updated.Format(http.TimeFormat), modified)
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"If-None-Match", map[string]string{headerIfNoneMatch: etag}, http.StatusNotModified},
+		{"stale If-None-Match", map[string]string{headerIfNoneMatch: `"other"`}, http.StatusOK},
+		{"If-Modified-Since", map[string]string{"If-Modified-Since": updated.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": updated.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, c := setupRequest(http.MethodGet, "/article", tt.headers)
+			middleware(c)
+			if w.Code != tt.status {
+				t.Errorf(errStatus, tt.status, w.Code)
+			}
+			if tt.status == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Errorf("Expected no body, got %s", w.Body.String())
+			}
+		})
+	}
+	if *calls != 1 {
+		t.Errorf(errHandlerCallCount, "once", *calls)
+	}
+
+	// A client holding the current response gets a 304 even after the entry expired
+	config.Store.Clear()
+	w, c := setupRequest(http.MethodGet, "/article", map[string]string{headerIfNoneMatch: etag})
+	middleware(c)
+	if w.Code != http.StatusNotModified || *calls != 2 {
+		t.Errorf(errStatus, http.StatusNotModified, w.Code)
+	}
+	testCacheEntryExists(t, config.Store.(*MemoryStore), "GET:/article", true, "")
+}
+
+func TestUncachedResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(c *context.Context)
+		body    string
+	}{
+		{"oversized", func(c *context.Context) {
+			c.Writer.Write([]byte(strings.Repeat("x", 64)))
+			c.Writer.Write([]byte(strings.Repeat("y", 64)))
+		}, strings.Repeat("x", 64) + strings.Repeat("y", 64)},
+		{"flushed", func(c *context.Context) {
+			c.Writer.Write([]byte("event: one\n\n"))
+			c.Flush()
+			c.Writer.Write([]byte("event: two\n\n"))
+		}, "event: one\n\nevent: two\n\n"},
+		{"not modified", func(c *context.Context) {
+			c.NotModified()
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, config, _ := setupCacheTest()
+			config.MaxBodySize = 100
+			calls := 0
+			middleware := WithConfig(config)(func(c *context.Context) {
+				calls++
+				tt.handler(c)
+			})
+
+			for range 2 {
+				w, c := setupRequest(http.MethodGet, "/stream", nil)
+				middleware(c)
+				if w.Body.String() != tt.body {
+					t.Errorf(errEntryBody, tt.body, w.Body.String())
+				}
+			}
+			if calls != 2 || store.Len() != 0 {
+				t.Errorf("Expected response not to be cached, got %d entries", store.Len())
+			}
+		})
+	}
+}
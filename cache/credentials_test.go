@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+func TestCredentials(t *testing.T) {
+	session := map[string]string{"Cookie": "session=abc"}
+	bearer := map[string]string{headerAuthorization: testBearerToken}
+	byUser := func(c *context.Context) string { return c.GetHeader("Cookie") }
+
+	tests := []struct {
+		name         string
+		opts         []Option
+		headers      map[string]string
+		cacheControl string
+		setCookie    bool
+		calls        int
+	}{
+		{name: "authorization bypasses", headers: bearer, calls: 2},
+		{name: "cookie bypasses", headers: session, calls: 2},
+		{name: "varied by user", opts: []Option{VaryByUser(byUser)}, headers: session, calls: 1},
+		{name: "ignored credentials", opts: []Option{WithIgnoreCredentials()}, headers: bearer, calls: 1},
+		{name: "set-cookie response", setCookie: true, calls: 2},
+		{name: "private response", cacheControl: "private, max-age=60", calls: 2},
+		{name: "private response varied by user", opts: []Option{VaryByUser(byUser)}, cacheControl: "private", calls: 1},
+		{name: "no-store response", opts: []Option{VaryByUser(byUser)}, cacheControl: "no-store", calls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			middleware := ForRoute(standardTTL, tt.opts...)(func(c *context.Context) {
+				calls++
+				if tt.cacheControl != "" {
+					c.SetHeader("Cache-Control", tt.cacheControl)
+				}
+				if tt.setCookie {
+					c.SetCookie("session", "new", 3600, "/", "", false, true)
+				}
+				c.JSON(http.StatusOK, map[string]string{"message": testMessage})
+			})
+
+			for range 2 {
+				_, c := setupRequest(http.MethodGet, "/account", tt.headers)
+				middleware(c)
+			}
+			if calls != tt.calls {
+				t.Errorf(errHandlerCallCount, "per caching rules", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/versioning"
+)
+
+// KeyFunc builds the cache key of a request
+type KeyFunc func(*context.Context) string
+
+// DefaultKey keys responses by method and full URL, e.g. "GET:/users?page=1"
+func DefaultKey(c *context.Context) string {
+	return c.Request.Method + ":" + c.Request.URL.String()
+}
+
+// PathKey keys responses by method and path, leaving the query string out, e.g.
+// "GET:/users"
+func PathKey(c *context.Context) string {
+	return c.Request.Method + ":" + c.Request.URL.EscapedPath()
+}
+
+// buildKey returns the cache key of a request: the KeyGenerator key followed by the
+// values of the configured vary-by attributes, e.g.
+// "GET:/users|header.Accept-Language=en&user=42"
+func buildKey(c *context.Context, config *Config) string {
+	key := config.KeyGenerator(c)
+
+	vary := url.Values{}
+	for _, name := range config.VaryHeaders {
+		name = http.CanonicalHeaderKey(name)
+		vary["header."+name] = c.Request.Header.Values(name)
+	}
+	if len(config.VaryQuery) > 0 {
+		query := c.Request.URL.Query()
+		for _, name := range config.VaryQuery {
+			vary["query."+name] = query[name]
+		}
+	}
+	if config.VaryUser != nil {
+		vary.Set("user", config.VaryUser(c))
+	}
+	if config.VaryAPIVersion {
+		info, _ := versioning.GetAPIVersion(c)
+		vary.Set("version", info.Version)
+	}
+
+	if len(vary) == 0 {
+		return key
+	}
+	return key + "|" + vary.Encode()
+}
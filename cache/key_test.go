@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/versioning"
+)
+
+func TestBuildKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		path     string
+		headers  map[string]string
+		expected string
+	}{
+		{
+			name:     "default",
+			path:     "/users?page=1",
+			expected: "GET:/users?page=1",
+		},
+		{
+			name:     "query",
+			config:   Config{VaryQuery: []string{"page", "sort"}},
+			path:     "/users?utm_source=mail&page=2",
+			expected: "GET:/users|query.page=2",
+		},
+		{
+			name:     "headers",
+			config:   Config{VaryHeaders: []string{"accept-language"}},
+			path:     "/users",
+			headers:  map[string]string{"Accept-Language": "id"},
+			expected: "GET:/users|header.Accept-Language=id",
+		},
+		{
+			name: "user and version",
+			config: Config{
+				KeyGenerator:   PathKey,
+				VaryUser:       func(*context.Context) string { return "42" },
+				VaryAPIVersion: true,
+			},
+			path:     "/users?page=1",
+			expected: "GET:/users|user=42&version=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initializeConfig(&tt.config)
+			_, c := setupRequest(http.MethodGet, tt.path, tt.headers)
+			c.WithValue("API-Version", versioning.VersionInfo{Version: "2"})
+
+			if key := buildKey(c, &tt.config); key != tt.expected {
+				t.Errorf("Expected key %q, got %q", tt.expected, key)
+			}
+		})
+	}
+}
+
+func TestVaryByUser(t *testing.T) {
+	_, config, handlerCalled := setupCacheTest()
+	config.VaryUser = func(c *context.Context) string { return c.GetHeader("X-User") }
+	config.VaryHeaders = []string{"Accept-Language"}
+	middleware := WithConfig(config)(createTestHandler(handlerCalled))
+
+	for _, user := range []string{"alice", "bob", "alice"} {
+		_, c := setupRequest(http.MethodGet, "/profile", map[string]string{"X-User": user})
+		middleware(c)
+	}
+	if *handlerCalled != 2 {
+		t.Errorf(errHandlerCallCount, "once per user", *handlerCalled)
+	}
+
+	w, c := setupRequest(http.MethodGet, "/profile", map[string]string{"X-User": "alice"})
+	middleware(c)
+	testCacheHeader(t, w, valCacheHit)
+	if vary := w.Header().Get("Vary"); vary != "Accept-Language" {
+		t.Errorf(errHeader, "Vary", "Accept-Language", vary)
+	}
+}
+
+func TestVaryByUserIsPrivate(t *testing.T) {
+	_, config, handlerCalled := setupCacheTest()
+	config.VaryUser = func(c *context.Context) string { return c.GetHeader("X-User") }
+	middleware := WithConfig(config)(createTestHandler(handlerCalled))
+
+	w, c := setupRequest(http.MethodGet, "/profile", map[string]string{"X-User": "alice", "Authorization": "Bearer alice"})
+	middleware(c)
+	if cc := w.Header().Get("Cache-Control"); !strings.Contains(cc, "private") || strings.Contains(cc, "public") {
+		t.Errorf(errHeader, "Cache-Control", "private", cc)
+	}
+
+	w, c = setupRequest(http.MethodGet, "/profile", map[string]string{"X-User": "alice", "Authorization": "Bearer alice"})
+	middleware(c)
+	testCacheHeader(t, w, valCacheHit)
+	if cc := w.Header().Get("Cache-Control"); !strings.Contains(cc, "private") {
+		t.Errorf(errHeader, "Cache-Control", "private", cc)
+	}
+}
+
+func TestVaryByUserWithoutUser(t *testing.T) {
+	_, config, handlerCalled := setupCacheTest()
+	config.VaryUser = func(c *context.Context) string { return c.GetHeader("X-User") }
+	middleware := WithConfig(config)(createTestHandler(handlerCalled))
+
+	// Credentials without a resolved user must not share an entry
+	for range 2 {
+		w, c := setupRequest(http.MethodGet, "/profile", map[string]string{"Authorization": "Bearer unparsed"})
+		middleware(c)
+		if result := w.Header().Get("X-Cache"); result == valCacheHit {
+			t.Error("Expected requests with credentials and no user to bypass the cache")
+		}
+	}
+	if *handlerCalled != 2 {
+		t.Errorf(errHandlerCallCount, "for every request", *handlerCalled)
+	}
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"time"
+)
+
+// MemcacheClient is the subset of Memcached commands used by MemcachedStore. It is
+// satisfied by a small adapter around any Memcached client, e.g. for gomemcache:
+//
+//	type client struct{ mc *memcache.Client }
+//
+//	func (c client) Get(key string) ([]byte, bool, error) {
+//		item, err := c.mc.Get(key)
+//		if errors.Is(err, memcache.ErrCacheMiss) {
+//			return nil, false, nil
+//		}
+//		if err != nil {
+//			return nil, false, err
+//		}
+//		return item.Value, true, nil
+//	}
+//
+//	func (c client) Set(key string, value []byte, ttl time.Duration) error {
+//		return c.mc.Set(&memcache.Item{Key: key, Value: value, Expiration: int32(math.Ceil(ttl.Seconds()))})
+//	}
+//
+//	func (c client) Delete(key string) error {
+//		if err := c.mc.Delete(key); err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+//			return err
+//		}
+//		return nil
+//	}
+type MemcacheClient interface {
+	// Get returns the value of a key and whether it exists
+	Get(key string) ([]byte, bool, error)
+	// Set stores a value that expires after ttl; a zero ttl never expires
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes a key, ignoring missing keys
+	Delete(key string) error
+}
+
+// MemcachedStore is a Store backed by Memcached, for deployments that already share
+// a Memcached pool. Entries are stored as JSON under hashed keys, as Memcached
+// limits key length and characters.
+//
+// Memcached can't enumerate keys, so entries are namespaced by a generation stored
+// in Memcached: Clear and DeleteByPrefix start a new generation, which invalidates
+// every entry of the store at once. Each lookup costs one extra round trip to read
+// the generation.
+type MemcachedStore struct {
+	client MemcacheClient
+	prefix string
+	// OnError is called when a Memcached command fails; the failed lookup is
+	// treated as a cache miss (default: log the error)
+	OnError func(error)
+}
+
+// NewMemcachedStore creates a Memcached cache store. Keys are stored under prefix;
+// an empty prefix defaults to "gra:cache:".
+func NewMemcachedStore(client MemcacheClient, prefix string) *MemcachedStore {
+	if prefix == "" {
+		prefix = "gra:cache:"
+	}
+	return &MemcachedStore{
+		client: client,
+		prefix: prefix,
+		OnError: func(err error) {
+			log.Printf("Memcached cache error: %v", err)
+		},
+	}
+}
+
+// Get retrieves an entry from Memcached
+func (s *MemcachedStore) Get(key string) (*Entry, bool) {
+	storeKey, err := s.storeKey(key)
+	if err != nil {
+		s.handleError(err)
+		return nil, false
+	}
+
+	data, found, err := s.client.Get(storeKey)
+	if err != nil {
+		s.handleError(err)
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+
+	entry, found, err := decodeEntry(data)
+	if err != nil {
+		s.handleError(err)
+	}
+	return entry, found
+}
+
+// Set stores an entry in Memcached
+func (s *MemcachedStore) Set(key string, entry *Entry, ttl time.Duration) {
+	storeKey, err := s.storeKey(key)
+	if err != nil {
+		s.handleError(err)
+		return
+	}
+
+	data, err := encodeEntry(entry, ttl)
+	if err != nil {
+		s.handleError(err)
+		return
+	}
+	if err := s.client.Set(storeKey, data, ttl); err != nil {
+		s.handleError(err)
+	}
+}
+
+// Delete removes an entry from Memcached
+func (s *MemcachedStore) Delete(key string) {
+	storeKey, err := s.storeKey(key)
+	if err != nil {
+		s.handleError(err)
+		return
+	}
+	if err := s.client.Delete(storeKey); err != nil {
+		s.handleError(err)
+	}
+}
+
+// DeleteByPrefix invalidates every entry of the store, as Memcached can't look up
+// keys by prefix
+func (s *MemcachedStore) DeleteByPrefix(string) {
+	s.Clear()
+}
+
+// Clear invalidates every entry of the store by starting a new generation
+func (s *MemcachedStore) Clear() {
+	if _, err := s.newGeneration(); err != nil {
+		s.handleError(err)
+	}
+}
+
+// storeKey returns the Memcached key of a cache key in the current generation
+func (s *MemcachedStore) storeKey(key string) (string, error) {
+	generation, err := s.generation()
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(key))
+	return s.prefix + generation + ":" + hex.EncodeToString(hash[:]), nil
+}
+
+// generation returns the current generation, starting one if Memcached has none
+// so entries of an evicted generation are never served again
+func (s *MemcachedStore) generation() (string, error) {
+	generation, found, err := s.client.Get(s.prefix + "generation")
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return string(generation), nil
+	}
+	return s.newGeneration()
+}
+
+// newGeneration stores and returns a new generation
+func (s *MemcachedStore) newGeneration() (string, error) {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	return generation, s.client.Set(s.prefix+"generation", []byte(generation), 0)
+}
+
+// handleError reports a failed Memcached command
+func (s *MemcachedStore) handleError(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeMemcache is an in-memory MemcacheClient enforcing Memcached's key rules
+type fakeMemcache struct {
+	values map[string][]byte
+	err    error
+}
+
+func (f *fakeMemcache) Get(key string) ([]byte, bool, error) {
+	if f.err != nil {
+		return nil, false, f.err
+	}
+	value, ok := f.values[key]
+	return value, ok, nil
+}
+
+func (f *fakeMemcache) Set(key string, value []byte, ttl time.Duration) error {
+	if f.err != nil {
+		return f.err
+	}
+	if len(key) > 250 || strings.ContainsAny(key, " \r\n") {
+		return errors.New("malformed key")
+	}
+	f.values[key] = value
+	return nil
+}
+
+func (f *fakeMemcache) Delete(key string) error {
+	if f.err != nil {
+		return f.err
+	}
+	delete(f.values, key)
+	return nil
+}
+
+func TestMemcachedStore(t *testing.T) {
+	mc := &fakeMemcache{values: make(map[string][]byte)}
+	store := NewMemcachedStore(mc, "")
+	var _ Store = store
+
+	longKey := "GET:/search?q=" + strings.Repeat("a b", 200)
+	store.Set(longKey, createTestEntry(), standardTTL)
+	entry, found := store.Get(longKey)
+	if !found {
+		t.Fatalf(errCacheEntryExists, "exist")
+	}
+	if string(entry.Body) != testBody || entry.ETag != GenerateETag([]byte(testBody)) {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+
+	store.Delete(longKey)
+	if _, found := store.Get(longKey); found {
+		t.Errorf(errInvalidatedEntry, "long key")
+	}
+
+	t.Run("DeleteByPrefix", func(t *testing.T) {
+		store.Set(testKey1, createTestEntry(), standardTTL)
+		store.Set(testKey2, createTestEntry(), standardTTL)
+		InvalidatePrefix(store, testKey1)
+
+		if _, found := store.Get(testKey1); found {
+			t.Errorf(errInvalidatedEntry, testKey1)
+		}
+		if _, found := store.Get(testKey2); found {
+			t.Errorf(errInvalidatedEntry, testKey2)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var reported []error
+		store.OnError = func(err error) { reported = append(reported, err) }
+		mc.err = errors.New("connection refused")
+
+		store.Set(testKey, createTestEntry(), standardTTL)
+		if _, found := store.Get(testKey); found {
+			t.Error("Expected Memcached errors to be treated as a miss")
+		}
+		if len(reported) != 2 {
+			t.Errorf("Expected 2 reported errors, got %d", len(reported))
+		}
+	})
+}
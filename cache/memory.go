@@ -0,0 +1,275 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EvictionPolicy selects the entries a bounded MemoryStore evicts when it is full
+type EvictionPolicy int
+
+const (
+	// EvictLRU evicts the least recently used entry
+	EvictLRU EvictionPolicy = iota
+	// EvictLFU evicts the least frequently used entry, the least recently used
+	// among equally used ones. It keeps popular entries through bursts of
+	// one-off requests.
+	EvictLFU
+)
+
+// MemoryStoreConfig holds the limits of a MemoryStore
+type MemoryStoreConfig struct {
+	// MaxEntries is the maximum number of entries (0: unlimited)
+	MaxEntries int
+	// MaxBytes is the maximum total size of the entries, see EntrySize (0: unlimited)
+	MaxBytes int64
+	// Eviction selects the entries evicted when a limit is reached (default: EvictLRU)
+	Eviction EvictionPolicy
+	// CleanupInterval is how often expired entries are swept out on Set, so they
+	// don't take up space until they are requested again (0: never)
+	CleanupInterval time.Duration
+}
+
+// DefaultMemoryStoreConfig returns the default memory store limits
+func DefaultMemoryStoreConfig() MemoryStoreConfig {
+	return MemoryStoreConfig{
+		MaxEntries:      10000,
+		MaxBytes:        64 << 20, // 64MB
+		Eviction:        EvictLRU,
+		CleanupInterval: time.Minute,
+	}
+}
+
+// memoryItem is an entry of a MemoryStore with its bookkeeping
+type memoryItem struct {
+	key     string
+	entry   *Entry
+	size    int64
+	freq    int // Number of hits, only counted with EvictLFU
+	element *list.Element
+}
+
+// MemoryStore is an in-memory implementation of Store, bounded by entry count
+// and size
+type MemoryStore struct {
+	config    MemoryStoreConfig
+	items     map[string]*memoryItem
+	freqs     map[int]*list.List // Items by hit count, most recently used first
+	minFreq   int                // Lowest hit count in freqs, may be stale after deletes
+	bytes     int64
+	evictions int64
+	nextSweep time.Time
+	mutex     sync.Mutex
+}
+
+// NewMemoryStore creates a new memory cache store with default limits
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithConfig(DefaultMemoryStoreConfig())
+}
+
+// NewMemoryStoreWithConfig creates a new memory cache store with custom limits
+func NewMemoryStoreWithConfig(config MemoryStoreConfig) *MemoryStore {
+	s := &MemoryStore{config: config}
+	s.reset()
+	return s
+}
+
+// Get retrieves an entry from the memory cache
+func (s *MemoryStore) Get(key string) (*Entry, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	item, exists := s.items[key]
+	if !exists {
+		return nil, false
+	}
+
+	// Check if the entry has expired
+	if time.Now().After(item.entry.Expiration) {
+		s.remove(item)
+		return nil, false
+	}
+
+	s.touch(item)
+	return item.entry, true
+}
+
+// Set stores an entry in the memory cache, evicting entries to stay within limits.
+// Entries larger than MaxBytes are not stored.
+func (s *MemoryStore) Set(key string, entry *Entry, ttl time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if s.config.CleanupInterval > 0 && now.After(s.nextSweep) {
+		s.sweep(now)
+		s.nextSweep = now.Add(s.config.CleanupInterval)
+	}
+
+	if item, exists := s.items[key]; exists {
+		s.remove(item)
+	}
+
+	prepareEntry(entry, ttl)
+	size := EntrySize(entry)
+	if s.config.MaxBytes > 0 && size > s.config.MaxBytes {
+		return
+	}
+
+	// Evict before inserting, so a new entry is never evicted in its place
+	for len(s.items) > 0 &&
+		((s.config.MaxEntries > 0 && len(s.items) >= s.config.MaxEntries) ||
+			(s.config.MaxBytes > 0 && s.bytes+size > s.config.MaxBytes)) {
+		s.evict()
+	}
+
+	item := &memoryItem{key: key, entry: entry, size: size}
+	item.element = s.list(0).PushFront(item)
+	s.items[key] = item
+	s.minFreq = 0
+	s.bytes += size
+}
+
+// Delete removes an entry from the memory cache
+func (s *MemoryStore) Delete(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if item, exists := s.items[key]; exists {
+		s.remove(item)
+	}
+}
+
+// DeleteByPrefix removes every entry whose key starts with prefix
+func (s *MemoryStore) DeleteByPrefix(prefix string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, item := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			s.remove(item)
+		}
+	}
+}
+
+// Clear removes all entries from the memory cache
+func (s *MemoryStore) Clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.reset()
+}
+
+// Len returns the number of entries in the memory cache, including expired ones
+// not swept out yet
+func (s *MemoryStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.items)
+}
+
+// Bytes returns the total size of the entries in the memory cache
+func (s *MemoryStore) Bytes() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.bytes
+}
+
+// Evictions returns the number of entries evicted to stay within limits
+func (s *MemoryStore) Evictions() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.evictions
+}
+
+// reset removes all entries
+func (s *MemoryStore) reset() {
+	s.items = make(map[string]*memoryItem)
+	s.freqs = make(map[int]*list.List)
+	s.minFreq = 0
+	s.bytes = 0
+}
+
+// list returns the list of items with a hit count, creating it if needed
+func (s *MemoryStore) list(freq int) *list.List {
+	l, exists := s.freqs[freq]
+	if !exists {
+		l = list.New()
+		s.freqs[freq] = l
+	}
+	return l
+}
+
+// unlink removes an item from its frequency list, dropping the list once empty
+func (s *MemoryStore) unlink(item *memoryItem) {
+	l := s.freqs[item.freq]
+	l.Remove(item.element)
+	if l.Len() == 0 {
+		delete(s.freqs, item.freq)
+	}
+}
+
+// touch records a hit on an item
+func (s *MemoryStore) touch(item *memoryItem) {
+	if s.config.Eviction != EvictLFU {
+		s.freqs[item.freq].MoveToFront(item.element)
+		return
+	}
+
+	s.unlink(item)
+	if item.freq == s.minFreq && s.freqs[item.freq] == nil {
+		s.minFreq++
+	}
+	item.freq++
+	item.element = s.list(item.freq).PushFront(item)
+}
+
+// remove deletes an item
+func (s *MemoryStore) remove(item *memoryItem) {
+	s.unlink(item)
+	delete(s.items, item.key)
+	s.bytes -= item.size
+}
+
+// evict removes the item chosen by the eviction policy
+func (s *MemoryStore) evict() {
+	l := s.freqs[s.minFreq]
+	if l == nil {
+		// The lowest hit count was deleted, find the new one
+		first := true
+		for freq := range s.freqs {
+			if first || freq < s.minFreq {
+				s.minFreq, first = freq, false
+			}
+		}
+		l = s.freqs[s.minFreq]
+	}
+	s.remove(l.Back().Value.(*memoryItem))
+	s.evictions++
+}
+
+// sweep removes expired entries
+func (s *MemoryStore) sweep(now time.Time) {
+	for _, item := range s.items {
+		if now.After(item.entry.Expiration) {
+			s.remove(item)
+		}
+	}
+}
+
+// EntrySize approximates the memory used by an entry in bytes
+func EntrySize(entry *Entry) int64 {
+	size := len(entry.Body) + len(entry.ETag)
+	for name, values := range entry.Headers {
+		size += len(name)
+		for _, value := range values {
+			size += len(value)
+		}
+	}
+	return int64(size)
+}
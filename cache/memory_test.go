@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// entryOfSize creates a cache entry whose EntrySize is size
+func entryOfSize(size int) *Entry {
+	return &Entry{Body: []byte(strings.Repeat("x", size-1)), StatusCode: http.StatusOK, ETag: "e"}
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   MemoryStoreConfig
+		size     int
+		expected []string // Keys left after setting a, b, c, reading a twice and b once, then setting d
+	}{
+		{
+			name:     "LRU by entries",
+			config:   MemoryStoreConfig{MaxEntries: 3, Eviction: EvictLRU},
+			size:     10,
+			expected: []string{"a", "b", "d"},
+		},
+		{
+			name:     "LFU by entries",
+			config:   MemoryStoreConfig{MaxEntries: 3, Eviction: EvictLFU},
+			size:     10,
+			expected: []string{"a", "b", "d"},
+		},
+		{
+			name:     "LRU by bytes",
+			config:   MemoryStoreConfig{MaxBytes: 25},
+			size:     9,
+			expected: []string{"b", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStoreWithConfig(tt.config)
+			for _, key := range []string{"a", "b", "c"} {
+				store.Set(key, entryOfSize(tt.size), standardTTL)
+			}
+			store.Get("a")
+			store.Get("b")
+			store.Get("a")
+			store.Set("d", entryOfSize(tt.size), standardTTL)
+
+			for _, key := range []string{"a", "b", "c", "d"} {
+				_, found := store.Get(key)
+				if expected := strings.Contains(strings.Join(tt.expected, ","), key); found != expected {
+					t.Errorf("Expected %s to be present: %v, got %v", key, expected, found)
+				}
+			}
+			if store.Len() != len(tt.expected) || store.Bytes() != int64(len(tt.expected)*tt.size) {
+				t.Errorf("Expected %d entries, got %d using %d bytes", len(tt.expected), store.Len(), store.Bytes())
+			}
+		})
+	}
+}
+
+func TestMemoryStoreLFUKeepsPopularEntries(t *testing.T) {
+	store := NewMemoryStoreWithConfig(MemoryStoreConfig{MaxEntries: 2, Eviction: EvictLFU})
+	store.Set("popular", entryOfSize(1), standardTTL)
+	store.Get("popular")
+
+	// A burst of one-off keys evicts each other, not the popular entry
+	for _, key := range []string{"k1", "k2", "k3", "k4"} {
+		store.Set(key, entryOfSize(1), standardTTL)
+	}
+	testCacheEntryExists(t, store, "popular", true, "")
+	testCacheEntryExists(t, store, "k4", true, "")
+	testCacheEntryExists(t, store, "k3", false, "be evicted")
+
+	// Deleting the least used entries leaves the next ones to evict
+	store.Delete("k4")
+	store.Set("k5", entryOfSize(1), standardTTL)
+	store.Set("k6", entryOfSize(1), standardTTL)
+	testCacheEntryExists(t, store, "popular", true, "")
+	testCacheEntryExists(t, store, "k6", true, "")
+}
+
+func TestMemoryStoreLimits(t *testing.T) {
+	store := NewMemoryStoreWithConfig(MemoryStoreConfig{MaxBytes: 10, CleanupInterval: time.Millisecond})
+
+	store.Set(testKey, entryOfSize(11), standardTTL)
+	testCacheEntryExists(t, store, testKey, false, "be too large to store")
+
+	store.Set(testKey1, entryOfSize(4), shortTTL)
+	store.Set(testKey1, entryOfSize(5), shortTTL)
+	if store.Bytes() != 5 {
+		t.Errorf("Expected replaced entry to be accounted once, got %d bytes", store.Bytes())
+	}
+
+	// Expired entries are swept out by the next Set
+	time.Sleep(expirationWaitTime)
+	store.Set(testKey2, entryOfSize(1), standardTTL)
+	if store.Len() != 1 || store.Bytes() != 1 {
+		t.Errorf("Expected expired entries to be swept, got %d entries", store.Len())
+	}
+
+	store.Clear()
+	if store.Len() != 0 || store.Bytes() != 0 {
+		t.Error(errEntriesCleared)
+	}
+}
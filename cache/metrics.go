@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// Result is the outcome of a cache lookup, also sent in the X-Cache header
+type Result string
+
+// Cache lookup results
+const (
+	ResultHit    Result = "HIT"    // Served from a fresh entry
+	ResultStale  Result = "STALE"  // Served from a stale entry
+	ResultMiss   Result = "MISS"   // Served by the handler
+	ResultBypass Result = "BYPASS" // The cache was skipped for the request
+)
+
+// MetricsRecorder is notified of the result of every cache lookup, e.g. to
+// increment Prometheus counters labeled by result
+type MetricsRecorder interface {
+	RecordCache(c *context.Context, result Result)
+}
+
+// StatsStore is implemented by stores that report their size, like MemoryStore
+type StatsStore interface {
+	Len() int
+	Bytes() int64
+	Evictions() int64
+}
+
+// Stats is a snapshot of cache metrics
+type Stats struct {
+	Hits      int64   `json:"hits"`
+	Stale     int64   `json:"stale"`
+	Misses    int64   `json:"misses"`
+	Bypassed  int64   `json:"bypassed"`
+	HitRatio  float64 `json:"hit_ratio"` // Share of lookups served from the cache, stale included
+	Entries   int     `json:"entries"`
+	Bytes     int64   `json:"bytes"`
+	Evictions int64   `json:"evictions"`
+}
+
+// Counter is a MetricsRecorder counting lookups by result in memory. The zero
+// value is ready to use.
+type Counter struct {
+	hits, stale, misses, bypassed atomic.Int64
+}
+
+// RecordCache counts a lookup
+func (m *Counter) RecordCache(_ *context.Context, result Result) {
+	switch result {
+	case ResultHit:
+		m.hits.Add(1)
+	case ResultStale:
+		m.stale.Add(1)
+	case ResultMiss:
+		m.misses.Add(1)
+	case ResultBypass:
+		m.bypassed.Add(1)
+	}
+}
+
+// Stats returns the lookup counts, with the size of the stores that implement StatsStore
+func (m *Counter) Stats(stores ...Store) Stats {
+	stats := Stats{
+		Hits:     m.hits.Load(),
+		Stale:    m.stale.Load(),
+		Misses:   m.misses.Load(),
+		Bypassed: m.bypassed.Load(),
+	}
+	if lookups := stats.Hits + stats.Stale + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits+stats.Stale) / float64(lookups)
+	}
+
+	for _, store := range stores {
+		if s, ok := store.(StatsStore); ok {
+			stats.Entries += s.Len()
+			stats.Bytes += s.Bytes()
+			stats.Evictions += s.Evictions()
+		}
+	}
+	return stats
+}
+
+// Handler serves the stats as JSON, e.g. r.GET("/debug/cache", counter.Handler(store))
+func (m *Counter) Handler(stores ...Store) router.HandlerFunc {
+	return func(c *context.Context) {
+		c.JSON(http.StatusOK, m.Stats(stores...))
+	}
+}
+
+// PrometheusHandler serves the stats in the Prometheus text exposition format, so
+// they can be scraped next to other application metrics
+func (m *Counter) PrometheusHandler(stores ...Store) router.HandlerFunc {
+	return func(c *context.Context) {
+		stats := m.Stats(stores...)
+
+		c.SetHeader("Content-Type", "text/plain; version=0.0.4")
+		c.Status(http.StatusOK)
+		fmt.Fprintf(c.Writer, "# HELP gra_cache_requests_total Cache lookups by result.\n# TYPE gra_cache_requests_total counter\n")
+		for _, r := range []struct {
+			result string
+			count  int64
+		}{{"hit", stats.Hits}, {"stale", stats.Stale}, {"miss", stats.Misses}, {"bypass", stats.Bypassed}} {
+			fmt.Fprintf(c.Writer, "gra_cache_requests_total{result=%q} %d\n", r.result, r.count)
+		}
+		fmt.Fprintf(c.Writer, "# HELP gra_cache_entries Entries in the cache.\n# TYPE gra_cache_entries gauge\ngra_cache_entries %d\n", stats.Entries)
+		fmt.Fprintf(c.Writer, "# HELP gra_cache_bytes Size of the cached entries in bytes.\n# TYPE gra_cache_bytes gauge\ngra_cache_bytes %d\n", stats.Bytes)
+		fmt.Fprintf(c.Writer, "# HELP gra_cache_evictions_total Entries evicted to stay within limits.\n# TYPE gra_cache_evictions_total counter\ngra_cache_evictions_total %d\n", stats.Evictions)
+	}
+}
+
+// recordResult reports the result of a lookup to the metrics recorder, if any
+func (config *Config) recordResult(c *context.Context, result Result) {
+	if config.Metrics != nil {
+		config.Metrics.RecordCache(c, result)
+	}
+}
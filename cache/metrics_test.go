@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	counter := &Counter{}
+	store := NewMemoryStoreWithConfig(MemoryStoreConfig{MaxEntries: 1})
+	calls := new(int)
+	middleware := ForRoute(standardTTL, WithStore(store), WithMetrics(counter))(createTestHandler(calls))
+
+	requests := []struct {
+		path    string
+		headers map[string]string
+	}{
+		{"/a", nil},
+		{"/a", nil},
+		{"/a", map[string]string{headerAuthorization: testBearerToken}},
+		{"/b", nil}, // Evicts /a
+	}
+	for _, req := range requests {
+		_, c := setupRequest(http.MethodGet, req.path, req.headers)
+		middleware(c)
+	}
+
+	stats := counter.Stats(store)
+	expected := Stats{Hits: 1, Misses: 2, Bypassed: 1, HitRatio: 1.0 / 3, Entries: 1, Bytes: store.Bytes(), Evictions: 1}
+	if stats != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+
+	t.Run("Handler", func(t *testing.T) {
+		w, c := setupRequest(http.MethodGet, "/debug/cache", nil)
+		counter.Handler(store)(c)
+
+		var served Stats
+		if err := json.Unmarshal(w.Body.Bytes(), &served); err != nil || served != expected {
+			t.Errorf("Expected %+v, got %s", expected, w.Body.String())
+		}
+	})
+
+	t.Run("PrometheusHandler", func(t *testing.T) {
+		w, c := setupRequest(http.MethodGet, "/metrics", nil)
+		counter.PrometheusHandler(store)(c)
+
+		body := w.Body.String()
+		for _, line := range []string{
+			`gra_cache_requests_total{result="hit"} 1`,
+			`gra_cache_requests_total{result="miss"} 2`,
+			`gra_cache_requests_total{result="bypass"} 1`,
+			"gra_cache_entries 1",
+			"gra_cache_evictions_total 1",
+		} {
+			if !strings.Contains(body, line+"\n") {
+				t.Errorf("Expected metrics to contain %q, got:\n%s", line, body)
+			}
+		}
+		if ct := w.Header().Get(headerContentType); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf(errHeader, headerContentType, "text/plain", ct)
+		}
+	})
+}
+
+func TestCounterSkipsOverriddenLookups(t *testing.T) {
+	counter := &Counter{}
+	calls := new(int)
+	outer := ForRoute(standardTTL, WithMetrics(counter))
+	inner := ForRoute(standardTTL, WithMetrics(counter))
+	handler := outer(inner(createTestHandler(calls)))
+
+	for range 2 {
+		_, c := setupRequest(http.MethodGet, "/nested", nil)
+		handler(c)
+	}
+	if stats := counter.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected each lookup to be counted once, got %+v", stats)
+	}
+}
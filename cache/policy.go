@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// Option customizes the cache policy of ForRoute
+type Option func(*Config)
+
+// cacheScope lets a cache middleware know that a nested one handles the request
+type cacheScope struct {
+	overridden bool
+}
+
+// cacheScopeKey holds the scope of the innermost cache middleware handling a request
+var cacheScopeKey = context.NewKey[*cacheScope]("cache-scope")
+
+// ForRoute creates a cache middleware with its own TTL and options, for a single
+// route or a group:
+//
+//	r.GET("/products", cache.ForRoute(time.Hour)(listProducts))
+//	api.Use(cache.ForRoute(time.Minute, cache.VaryByUser(userID)))
+//
+// A cache middleware nested in another, e.g. ForRoute on a group under a global
+// cache.New(), overrides it: the innermost policy decides whether and how long a
+// response is cached. A ttl of zero or less disables caching for the route.
+//
+// Each policy uses a memory store of its own unless WithStore is given.
+func ForRoute(ttl time.Duration, opts ...Option) router.Middleware {
+	if ttl <= 0 {
+		return func(next router.HandlerFunc) router.HandlerFunc {
+			return func(c *context.Context) {
+				overrideEnclosing(c)
+				next(c)
+			}
+		}
+	}
+
+	config := DefaultCacheConfig()
+	config.TTL = ttl
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return WithConfig(config)
+}
+
+// overrideEnclosing tells an enclosing cache middleware not to cache the response
+func overrideEnclosing(c *context.Context) {
+	if scope, ok := context.Get(c, cacheScopeKey); ok {
+		scope.overridden = true
+	}
+}
+
+// WithStore sets the store of a cache policy, e.g. to share it with other routes
+// or invalidate it with InvalidatePrefix
+func WithStore(store Store) Option {
+	return func(config *Config) {
+		config.Store = store
+	}
+}
+
+// WithMethods sets the HTTP methods a cache policy applies to
+func WithMethods(methods ...string) Option {
+	return func(config *Config) {
+		config.Methods = methods
+	}
+}
+
+// WithKey sets the KeyFunc of a cache policy
+func WithKey(key KeyFunc) Option {
+	return func(config *Config) {
+		config.KeyGenerator = key
+	}
+}
+
+// WithSkipCache sets the function deciding which requests bypass a cache policy
+func WithSkipCache(skip func(*context.Context) bool) Option {
+	return func(config *Config) {
+		config.SkipCache = skip
+	}
+}
+
+// WithIgnoreCredentials caches requests with credentials in shared entries, see
+// Config.IgnoreCredentials
+func WithIgnoreCredentials() Option {
+	return func(config *Config) {
+		config.IgnoreCredentials = true
+	}
+}
+
+// WithStale sets how long stale entries are served while revalidating and in
+// place of 5xx responses, see Config.StaleWhileRevalidate and Config.StaleIfError
+func WithStale(whileRevalidate, ifError time.Duration) Option {
+	return func(config *Config) {
+		config.StaleWhileRevalidate = whileRevalidate
+		config.StaleIfError = ifError
+	}
+}
+
+// WithMetrics sets the recorder notified of the lookups of a cache policy, e.g. a
+// Counter shared by every policy
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(config *Config) {
+		config.Metrics = recorder
+	}
+}
+
+// VaryByHeaders makes request headers part of the cache key, see Config.VaryHeaders
+func VaryByHeaders(names ...string) Option {
+	return func(config *Config) {
+		config.VaryHeaders = append(config.VaryHeaders, names...)
+	}
+}
+
+// VaryByQuery makes query parameters part of the cache key, see Config.VaryQuery
+func VaryByQuery(names ...string) Option {
+	return func(config *Config) {
+		config.VaryQuery = append(config.VaryQuery, names...)
+	}
+}
+
+// VaryByUser makes the user returned by user part of the cache key, see Config.VaryUser
+func VaryByUser(user func(*context.Context) string) Option {
+	return func(config *Config) {
+		config.VaryUser = user
+	}
+}
+
+// VaryByAPIVersion makes the API version part of the cache key, see Config.VaryAPIVersion
+func VaryByAPIVersion() Option {
+	return func(config *Config) {
+		config.VaryAPIVersion = true
+	}
+}
+
+// WithRefreshHeader sets the request header forcing a refresh, see Config.RefreshHeader
+func WithRefreshHeader(name string) Option {
+	return func(config *Config) {
+		config.RefreshHeader = name
+	}
+}
+
+// WithAllowRefresh sets the function deciding which requests may refresh or bypass
+// entries, see Config.AllowRefresh
+func WithAllowRefresh(allow func(*context.Context) bool) Option {
+	return func(config *Config) {
+		config.AllowRefresh = allow
+	}
+}
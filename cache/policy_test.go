@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+func TestForRoute(t *testing.T) {
+	globalStore, config, calls := setupCacheTest()
+	groupStore := NewMemoryStore()
+	handler := createTestHandler(calls)
+
+	r := router.New()
+	r.Use(WithConfig(config))
+	r.GET("/home", handler)
+	r.GET("/live", ForRoute(0)(handler))
+	api := r.Group("/api").Use(ForRoute(time.Hour, WithStore(groupStore), VaryByHeaders("X-Tenant")))
+	api.GET("/products", handler)
+	api.GET("/quotes", ForRoute(time.Second, WithStore(groupStore))(handler))
+
+	tests := []struct {
+		path  string
+		store *MemoryStore
+		key   string
+		calls int
+	}{
+		{"/home", globalStore, "GET:/home", 1},
+		{"/live", nil, "", 2},
+		{"/api/products", groupStore, "GET:/api/products|header.X-Tenant=acme", 1},
+		{"/api/quotes", groupStore, "GET:/api/quotes", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			*calls = 0
+			var w *httptest.ResponseRecorder
+			for range 2 {
+				w = httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				req.Header.Set("X-Tenant", "acme")
+				r.ServeHTTP(w, req)
+			}
+
+			if *calls != tt.calls {
+				t.Errorf(errHandlerCallCount, "per policy", *calls)
+			}
+			if tt.store != nil {
+				testCacheEntryExists(t, tt.store, tt.key, true, "")
+				testCacheHeader(t, w, valCacheHit)
+			}
+			if tt.store != globalStore {
+				testCacheEntryExists(t, globalStore, "GET:"+tt.path, false, "be cached by the route policy only")
+			}
+		})
+	}
+
+	t.Run("Cache-Control", func(t *testing.T) {
+		groupStore.Clear()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/quotes", nil))
+		if cc := w.Header().Get("Cache-Control"); cc != "max-age=1, public" {
+			t.Errorf(errHeader, "Cache-Control", "max-age=1, public", cc)
+		}
+	})
+}
+
+func TestForRouteOptions(t *testing.T) {
+	store := NewMemoryStore()
+	skipped := false
+	middleware := ForRoute(time.Minute,
+		WithStore(store),
+		WithMethods(http.MethodGet, http.MethodHead),
+		WithKey(PathKey),
+		WithSkipCache(func(c *context.Context) bool { skipped = true; return false }),
+		WithStale(time.Minute, time.Hour),
+		VaryByQuery("page"),
+		VaryByUser(func(*context.Context) string { return "42" }),
+		VaryByAPIVersion(),
+	)
+
+	calls := new(int)
+	_, c := setupRequest(http.MethodHead, "/items?page=2&ref=x", nil)
+	middleware(createTestHandler(calls))(c)
+
+	if !skipped {
+		t.Error("Expected SkipCache option to be used")
+	}
+	testCacheEntryExists(t, store, "HEAD:/items|query.page=2&user=42&version=", true, "")
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	stdcontext "context"
+	"log"
+	"strings"
+	"time"
+)
+
+// RedisClient is the subset of Redis commands used by RedisStore. It is satisfied
+// by a small adapter around any Redis client, e.g. for go-redis:
+//
+//	type client struct{ rdb *redis.Client }
+//
+//	func (c client) Get(ctx context.Context, key string) ([]byte, bool, error) {
+//		b, err := c.rdb.Get(ctx, key).Bytes()
+//		if errors.Is(err, redis.Nil) {
+//			return nil, false, nil
+//		}
+//		return b, err == nil, err
+//	}
+//
+//	func (c client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+//		return c.rdb.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	func (c client) Del(ctx context.Context, keys ...string) error {
+//		return c.rdb.Del(ctx, keys...).Err()
+//	}
+//
+//	func (c client) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
+//		return c.rdb.Scan(ctx, cursor, match, count).Result()
+//	}
+type RedisClient interface {
+	// Get returns the value of a key and whether it exists
+	Get(ctx stdcontext.Context, key string) ([]byte, bool, error)
+	// Set stores a value that expires after ttl
+	Set(ctx stdcontext.Context, key string, value []byte, ttl time.Duration) error
+	// Del removes keys
+	Del(ctx stdcontext.Context, keys ...string) error
+	// Scan iterates keys matching a glob pattern, returning the next cursor
+	Scan(ctx stdcontext.Context, cursor uint64, match string, count int64) ([]string, uint64, error)
+}
+
+// redisScanCount is the number of keys requested per SCAN call
+const redisScanCount = 100
+
+// redisGlobEscaper escapes the characters Redis treats specially in SCAN patterns
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// RedisStore is a Store backed by Redis, so cached responses survive restarts and
+// are shared by every instance behind a load balancer. Entries are stored as JSON
+// and expire in Redis after their TTL.
+type RedisStore struct {
+	client RedisClient
+	prefix string
+	// OnError is called when a Redis command fails; the failed lookup is treated
+	// as a cache miss (default: log the error)
+	OnError func(error)
+}
+
+// NewRedisStore creates a Redis cache store. Keys are stored as prefix+key; an
+// empty prefix defaults to "gra:cache:".
+func NewRedisStore(client RedisClient, prefix string) *RedisStore {
+	if prefix == "" {
+		prefix = "gra:cache:"
+	}
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+		OnError: func(err error) {
+			log.Printf("Redis cache error: %v", err)
+		},
+	}
+}
+
+// Get retrieves an entry from Redis
+func (s *RedisStore) Get(key string) (*Entry, bool) {
+	data, found, err := s.client.Get(stdcontext.Background(), s.prefix+key)
+	if err != nil {
+		s.handleError(err)
+		return nil, false
+	}
+	if !found {
+		return nil, false
+	}
+
+	entry, found, err := decodeEntry(data)
+	if err != nil {
+		s.handleError(err)
+	}
+	return entry, found
+}
+
+// Set stores an entry in Redis
+func (s *RedisStore) Set(key string, entry *Entry, ttl time.Duration) {
+	data, err := encodeEntry(entry, ttl)
+	if err != nil {
+		s.handleError(err)
+		return
+	}
+	if err := s.client.Set(stdcontext.Background(), s.prefix+key, data, ttl); err != nil {
+		s.handleError(err)
+	}
+}
+
+// Delete removes an entry from Redis
+func (s *RedisStore) Delete(key string) {
+	if err := s.client.Del(stdcontext.Background(), s.prefix+key); err != nil {
+		s.handleError(err)
+	}
+}
+
+// DeleteByPrefix removes every entry whose key starts with prefix. Keys are found
+// with SCAN, so Redis is not blocked while large caches are invalidated.
+func (s *RedisStore) DeleteByPrefix(prefix string) {
+	ctx := stdcontext.Background()
+	match := redisGlobEscaper.Replace(s.prefix+prefix) + "*"
+
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, match, redisScanCount)
+		if err != nil {
+			s.handleError(err)
+			return
+		}
+		if len(keys) > 0 {
+			if err := s.client.Del(ctx, keys...); err != nil {
+				s.handleError(err)
+				return
+			}
+		}
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
+
+// Clear removes all entries under the store's prefix
+func (s *RedisStore) Clear() {
+	s.DeleteByPrefix("")
+}
+
+// handleError reports a failed Redis command
+func (s *RedisStore) handleError(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	stdcontext "context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+// fakeRedis is an in-memory RedisClient that pages SCAN results one key at a time
+type fakeRedis struct {
+	values  map[string][]byte
+	ttls    map[string]time.Duration
+	matches []string
+	err     error
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (f *fakeRedis) Get(ctx stdcontext.Context, key string) ([]byte, bool, error) {
+	if f.err != nil {
+		return nil, false, f.err
+	}
+	value, ok := f.values[key]
+	return value, ok, nil
+}
+
+func (f *fakeRedis) Set(ctx stdcontext.Context, key string, value []byte, ttl time.Duration) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.values[key] = value
+	f.ttls[key] = ttl
+	return nil
+}
+
+func (f *fakeRedis) Del(ctx stdcontext.Context, keys ...string) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, key := range keys {
+		delete(f.values, key)
+	}
+	return nil
+}
+
+func (f *fakeRedis) Scan(ctx stdcontext.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	f.matches = append(f.matches, match)
+
+	// Only trailing-wildcard patterns are used by RedisStore
+	prefix := strings.NewReplacer(`\\`, `\`, `\*`, "*", `\?`, "?", `\[`, "[", `\]`, "]").Replace(strings.TrimSuffix(match, "*"))
+	for key := range f.values {
+		if strings.HasPrefix(key, prefix) {
+			return []string{key}, cursor + 1, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+func TestRedisStore(t *testing.T) {
+	redis := newFakeRedis()
+	store := NewRedisStore(redis, "")
+	var _ Store = store
+
+	store.Set(testKey, createTestEntry(), standardTTL)
+	if redis.ttls["gra:cache:"+testKey] != standardTTL {
+		t.Errorf("Expected prefixed key with TTL %v, got %v", standardTTL, redis.ttls)
+	}
+
+	entry, found := store.Get(testKey)
+	if !found {
+		t.Fatalf(errCacheEntryExists, "exist")
+	}
+	if string(entry.Body) != testBody || entry.StatusCode != http.StatusOK || entry.ETag != GenerateETag([]byte(testBody)) {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if entry.Headers[headerContentType][0] != valApplicationJSON {
+		t.Errorf(errHeader, headerContentType, valApplicationJSON, entry.Headers[headerContentType])
+	}
+
+	t.Run("DeleteByPrefix", func(t *testing.T) {
+		store.Set("GET:/users?page=1", createTestEntry(), standardTTL)
+		store.Set("GET:/users?page=2", createTestEntry(), standardTTL)
+		store.Set("GET:/orders", createTestEntry(), standardTTL)
+
+		InvalidatePrefix(store, "GET:/users?")
+		if _, found := store.Get("GET:/users?page=1"); found {
+			t.Errorf(errInvalidatedEntry, "GET:/users?page=1")
+		}
+		if _, found := store.Get("GET:/users?page=2"); found {
+			t.Errorf(errInvalidatedEntry, "GET:/users?page=2")
+		}
+		if _, found := store.Get("GET:/orders"); !found {
+			t.Errorf(errEntryStillExists, "GET:/orders")
+		}
+		if match := redis.matches[0]; match != `gra:cache:GET:/users\?*` {
+			t.Errorf("Expected escaped SCAN pattern, got %s", match)
+		}
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		redis.values["other:key"] = []byte(testValue)
+		store.Clear()
+		if len(redis.values) != 1 {
+			t.Errorf("Expected only keys outside the prefix to remain, got %v", redis.values)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var reported []error
+		store.OnError = func(err error) { reported = append(reported, err) }
+		redis.err = errors.New("connection refused")
+
+		store.Set(testKey, createTestEntry(), standardTTL)
+		if _, found := store.Get(testKey); found {
+			t.Error("Expected Redis errors to be treated as a miss")
+		}
+		if len(reported) != 2 {
+			t.Errorf("Expected 2 reported errors, got %d", len(reported))
+		}
+	})
+}
+
+func TestRedisStoreSharedAcrossInstances(t *testing.T) {
+	redis := newFakeRedis()
+	calls := 0
+	handler := func(c *context.Context) {
+		calls++
+		c.JSON(http.StatusOK, map[string]string{"message": testMessage})
+	}
+
+	// Two middleware instances stand in for two replicas sharing one Redis
+	for range 2 {
+		config := DefaultCacheConfig()
+		config.Store = NewRedisStore(redis, "")
+		_, c := setupRequest(http.MethodGet, "/shared", nil)
+		WithConfig(config)(handler)(c)
+	}
+
+	if calls != 1 {
+		t.Errorf(errHandlerCallCount, "once", calls)
+	}
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+func TestRefresh(t *testing.T) {
+	internal := func(c *context.Context) bool { return c.GetHeader("X-Internal") != "" }
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		headers map[string]string
+		result  Result
+		calls   int
+		stored  bool // Whether the refreshed response replaced the entry
+	}{
+		{name: "plain request", result: ResultHit, calls: 1},
+		{name: "no-cache", headers: map[string]string{"Cache-Control": "no-cache"}, result: ResultMiss, calls: 2, stored: true},
+		{name: "max-age=0, no-cache", headers: map[string]string{"Cache-Control": "max-age=0, No-Cache"}, result: ResultMiss, calls: 2, stored: true},
+		{name: "pragma no-cache", headers: map[string]string{"Pragma": "no-cache"}, result: ResultMiss, calls: 2, stored: true},
+		{name: "refresh header", headers: map[string]string{"X-Cache-Refresh": "1"}, result: ResultMiss, calls: 2, stored: true},
+		{name: "custom refresh header", opts: []Option{WithRefreshHeader("X-Purge")}, headers: map[string]string{"X-Purge": "1"}, result: ResultMiss, calls: 2, stored: true},
+		{name: "no-store", headers: map[string]string{"Cache-Control": "no-store"}, result: ResultBypass, calls: 2},
+		{name: "refresh not allowed", opts: []Option{WithAllowRefresh(internal)}, headers: map[string]string{"Cache-Control": "no-cache"}, result: ResultHit, calls: 1},
+		{name: "refresh allowed", opts: []Option{WithAllowRefresh(internal)}, headers: map[string]string{"Cache-Control": "no-cache", "X-Internal": "1"}, result: ResultMiss, calls: 2, stored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &Counter{}
+			calls := 0
+			opts := append([]Option{WithMetrics(counter)}, tt.opts...)
+			middleware := ForRoute(standardTTL, opts...)(func(c *context.Context) {
+				calls++
+				c.JSON(http.StatusOK, map[string]int{"call": calls})
+			})
+
+			_, c := setupRequest(http.MethodGet, "/products", nil)
+			middleware(c)
+			w, c := setupRequest(http.MethodGet, "/products", tt.headers)
+			middleware(c)
+
+			if calls != tt.calls {
+				t.Errorf(errHandlerCallCount, "per refresh rules", calls)
+			}
+			if got := w.Result().Header.Get(headerXCache); got != string(tt.result) {
+				t.Errorf(errHeader, headerXCache, tt.result, got)
+			}
+
+			// A later plain request is served the refreshed response if it was stored
+			w, c = setupRequest(http.MethodGet, "/products", nil)
+			middleware(c)
+			want := `{"call":1}`
+			if tt.stored {
+				want = `{"call":2}`
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != want {
+				t.Errorf(errEntryBody, want, got)
+			}
+
+			stats := counter.Stats()
+			if tt.result == ResultBypass && stats.Bypassed != 1 {
+				t.Errorf("Expected 1 bypassed lookup, got %d", stats.Bypassed)
+			}
+		})
+	}
+}
+
+func TestBypassHeaderWithNestedPolicy(t *testing.T) {
+	counter := &Counter{}
+	outer := ForRoute(standardTTL, WithMetrics(counter), WithSkipCache(func(*context.Context) bool { return true }))
+	inner := ForRoute(standardTTL, WithMetrics(counter))
+	handler := outer(inner(func(c *context.Context) {
+		c.JSON(http.StatusOK, map[string]string{"message": testMessage})
+	}))
+
+	w, c := setupRequest(http.MethodGet, "/products", nil)
+	handler(c)
+
+	if got := w.Result().Header.Get(headerXCache); got != string(ResultMiss) {
+		t.Errorf(errHeader, headerXCache, ResultMiss, got)
+	}
+	if stats := counter.Stats(); stats.Bypassed != 0 || stats.Misses != 1 {
+		t.Errorf("Expected only the inner miss to be counted, got %+v", stats)
+	}
+}
+
+func TestMissHeaderOnUncachedResponse(t *testing.T) {
+	middleware := ForRoute(standardTTL)(func(c *context.Context) {
+		c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
+	})
+
+	w, c := setupRequest(http.MethodGet, "/missing", nil)
+	middleware(c)
+
+	if got := w.Result().Header.Get(headerXCache); got != string(ResultMiss) {
+		t.Errorf(errHeader, headerXCache, ResultMiss, got)
+	}
+}
@@ -0,0 +1,66 @@
+package cache
+
+import "time"
+
+// RistrettoCache is an in-process cache with cost-based eviction. It is satisfied
+// by *ristretto.Cache[string, *cache.Entry] from github.com/dgraph-io/ristretto/v2
+// without an adapter:
+//
+//	rc, err := ristretto.NewCache(&ristretto.Config[string, *cache.Entry]{
+//		NumCounters: 1e6,       // keys to track frequency of
+//		MaxCost:     256 << 20, // 256MB of cached responses
+//		BufferItems: 64,
+//	})
+//	config.Store = cache.NewRistrettoStore(rc)
+type RistrettoCache interface {
+	Get(key string) (*Entry, bool)
+	SetWithTTL(key string, value *Entry, cost int64, ttl time.Duration) bool
+	Del(key string)
+	Clear()
+}
+
+// RistrettoStore is a Store backed by a RistrettoCache, for high-throughput
+// in-process caching: the cache is sharded and evicts by admission policy
+// instead of growing without bound. Entries are charged their size in bytes.
+//
+// Ristretto applies writes asynchronously and may drop them under contention, so
+// a Set is not guaranteed to be visible to the next Get. It can't enumerate keys,
+// so DeleteByPrefix clears the whole cache.
+type RistrettoStore struct {
+	cache RistrettoCache
+}
+
+// NewRistrettoStore creates a cache store backed by a Ristretto cache
+func NewRistrettoStore(cache RistrettoCache) *RistrettoStore {
+	return &RistrettoStore{cache: cache}
+}
+
+// Get retrieves an entry from the cache
+func (s *RistrettoStore) Get(key string) (*Entry, bool) {
+	entry, found := s.cache.Get(key)
+	if !found || entry == nil || time.Now().After(entry.Expiration) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// Set stores an entry in the cache
+func (s *RistrettoStore) Set(key string, entry *Entry, ttl time.Duration) {
+	prepareEntry(entry, ttl)
+	s.cache.SetWithTTL(key, entry, EntrySize(entry), ttl)
+}
+
+// Delete removes an entry from the cache
+func (s *RistrettoStore) Delete(key string) {
+	s.cache.Del(key)
+}
+
+// DeleteByPrefix clears the whole cache, as Ristretto can't look up keys by prefix
+func (s *RistrettoStore) DeleteByPrefix(string) {
+	s.cache.Clear()
+}
+
+// Clear removes all entries from the cache
+func (s *RistrettoStore) Clear() {
+	s.cache.Clear()
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeRistretto is a synchronous RistrettoCache recording entry costs
+type fakeRistretto struct {
+	entries map[string]*Entry
+	costs   map[string]int64
+}
+
+func (f *fakeRistretto) Get(key string) (*Entry, bool) {
+	entry, ok := f.entries[key]
+	return entry, ok
+}
+
+func (f *fakeRistretto) SetWithTTL(key string, value *Entry, cost int64, ttl time.Duration) bool {
+	f.entries[key] = value
+	f.costs[key] = cost
+	return true
+}
+
+func (f *fakeRistretto) Del(key string) {
+	delete(f.entries, key)
+}
+
+func (f *fakeRistretto) Clear() {
+	clear(f.entries)
+}
+
+func TestRistrettoStore(t *testing.T) {
+	rc := &fakeRistretto{entries: make(map[string]*Entry), costs: make(map[string]int64)}
+	store := NewRistrettoStore(rc)
+	var _ Store = store
+
+	store.Set(testKey, createTestEntry(), standardTTL)
+	entry, found := store.Get(testKey)
+	if !found {
+		t.Fatalf(errCacheEntryExists, "exist")
+	}
+	if expected := EntrySize(entry); rc.costs[testKey] != expected || expected < int64(len(testBody)) {
+		t.Errorf("Expected cost %d, got %d", expected, rc.costs[testKey])
+	}
+
+	entry.Expiration = time.Now().Add(-time.Second)
+	testRistrettoMiss(t, store, testKey, "be expired")
+
+	store.Set(testKey1, createTestEntry(), standardTTL)
+	InvalidatePrefix(store, "other")
+	testRistrettoMiss(t, store, testKey1, "be cleared")
+}
+
+// testRistrettoMiss verifies that a key is not served by the store
+func testRistrettoMiss(t *testing.T, store *RistrettoStore, key, reason string) {
+	t.Helper()
+	if _, found := store.Get(key); found {
+		t.Errorf(errCacheEntryExists, reason)
+	}
+}
@@ -0,0 +1,30 @@
+package context
+
+// Abort stops the middleware chain. Middleware and handlers that have not run yet
+// are skipped; code after next(c) in earlier middleware still runs.
+func (c *Context) Abort() {
+	c.aborted = true
+}
+
+// IsAborted reports whether Abort has been called for this request
+func (c *Context) IsAborted() bool {
+	return c.aborted
+}
+
+// AbortWithStatus writes the status code and stops the chain
+func (c *Context) AbortWithStatus(status int) {
+	c.Abort()
+	c.Writer.WriteHeader(status)
+}
+
+// AbortWithStatusJSON writes a JSON response and stops the chain
+func (c *Context) AbortWithStatusJSON(status int, obj any) {
+	c.Abort()
+	c.JSON(status, obj)
+}
+
+// AbortWithError writes a standardized error response and stops the chain
+func (c *Context) AbortWithError(status int, errorMsg string) {
+	c.Abort()
+	c.Error(status, errorMsg)
+}
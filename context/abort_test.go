@@ -0,0 +1,62 @@
+package context
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAbort(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if c.IsAborted() {
+		t.Error("Expected new context not to be aborted")
+	}
+
+	c.Abort()
+	if !c.IsAborted() {
+		t.Error("Expected context to be aborted")
+	}
+}
+
+func TestAbortWithStatusJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	c.AbortWithStatusJSON(http.StatusForbidden, map[string]string{"error": "forbidden"})
+
+	if !c.IsAborted() {
+		t.Error("Expected context to be aborted")
+	}
+	if w.Code != http.StatusForbidden {
+		t.Errorf(errStatusCode, http.StatusForbidden, w.Code)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf(errUnmarshalResponse, err)
+	}
+	if body["error"] != "forbidden" {
+		t.Errorf(errResponseValue, "forbidden", body["error"])
+	}
+}
+
+func TestAbortWithStatusAndError(t *testing.T) {
+	w := httptest.NewRecorder()
+	New(w, httptest.NewRequest(http.MethodGet, "/", nil)).AbortWithStatus(http.StatusNoContent)
+	if w.Code != http.StatusNoContent {
+		t.Errorf(errStatusCode, http.StatusNoContent, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	c.AbortWithError(http.StatusUnauthorized, "Invalid token")
+
+	var resp APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(errUnmarshalResponse, err)
+	}
+	if !c.IsAborted() || w.Code != http.StatusUnauthorized || resp.Error != "Invalid token" {
+		t.Errorf(errResponseValue, "aborted 401 Invalid token", resp)
+	}
+}
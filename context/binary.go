@@ -0,0 +1,80 @@
+package context
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Binary content types
+const (
+	ContentTypeMsgpack  = "application/msgpack"
+	ContentTypeProtoBuf = "application/x-protobuf"
+)
+
+// BindMsgpack binds a MessagePack request body to a struct using `msgpack` tags
+func (c *Context) BindMsgpack(obj any) error {
+	return c.autoValidate(obj, c.bindMsgpack(obj))
+}
+
+// bindMsgpack decodes a MessagePack body into obj
+func (c *Context) bindMsgpack(obj any) error {
+	body, err := c.BodyBytes()
+	if err != nil {
+		return err
+	}
+
+	if err := applyDefaults(obj); err != nil {
+		return err
+	}
+
+	return msgpack.Unmarshal(body, obj)
+}
+
+// Msgpack sends a MessagePack response
+func (c *Context) Msgpack(status int, obj any) {
+	c.Writer.Header().Set(HeaderContentType, ContentTypeMsgpack)
+	c.Writer.WriteHeader(status)
+
+	if err := msgpack.NewEncoder(c.Writer).Encode(obj); err != nil {
+		log.Printf("Error encoding MessagePack: %v", err)
+	}
+}
+
+// BindProtoBuf binds a Protocol Buffers request body to msg
+func (c *Context) BindProtoBuf(msg proto.Message) error {
+	body, err := c.BodyBytes()
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(body, msg)
+}
+
+// ProtoBuf sends a Protocol Buffers response
+func (c *Context) ProtoBuf(status int, msg proto.Message) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		log.Printf("Error encoding ProtoBuf: %v", err)
+		c.Error(http.StatusInternalServerError, "Failed to encode response")
+		return
+	}
+
+	c.Writer.Header().Set(HeaderContentType, ContentTypeProtoBuf)
+	c.Writer.WriteHeader(status)
+	if _, err := c.Writer.Write(data); err != nil {
+		log.Printf("Error writing ProtoBuf response: %v", err)
+	}
+}
+
+// isMsgpackMediaType reports whether the media type denotes MessagePack
+func isMsgpackMediaType(mediaType string) bool {
+	return mediaType == ContentTypeMsgpack || mediaType == "application/x-msgpack"
+}
+
+// isProtoBufMediaType reports whether the media type denotes Protocol Buffers
+func isProtoBufMediaType(mediaType string) bool {
+	return mediaType == ContentTypeProtoBuf || mediaType == "application/protobuf"
+}
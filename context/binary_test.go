@@ -0,0 +1,94 @@
+package context
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type msgpackPayload struct {
+	ID    int      `msgpack:"id"`
+	Name  string   `msgpack:"name"`
+	Tags  []string `msgpack:"tags"`
+	Limit int      `msgpack:"limit" default:"10"`
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	w := httptest.NewRecorder()
+	New(w, httptest.NewRequest(http.MethodGet, "/", nil)).Msgpack(http.StatusOK, msgpackPayload{ID: 1, Name: "gra", Tags: []string{"a"}, Limit: 5})
+
+	if ct := w.Header().Get(HeaderContentType); ct != ContentTypeMsgpack {
+		t.Errorf(errResponseValue, ContentTypeMsgpack, ct)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(w.Body.Bytes()))
+	r.Header.Set(HeaderContentType, ContentTypeMsgpack)
+
+	var got msgpackPayload
+	if err := New(httptest.NewRecorder(), r).Bind(&got); err != nil {
+		t.Fatalf("Bind returned error: %v", err)
+	}
+	if got.ID != 1 || got.Name != "gra" || len(got.Tags) != 1 || got.Limit != 5 {
+		t.Errorf(errResponseValue, "id=1 name=gra tags=[a] limit=5", got)
+	}
+}
+
+func TestBindMsgpackDefaults(t *testing.T) {
+	body, err := msgpack.Marshal(map[string]any{"id": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+
+	var got msgpackPayload
+	if err := New(httptest.NewRecorder(), r).BindMsgpack(&got); err != nil {
+		t.Fatalf("BindMsgpack returned error: %v", err)
+	}
+	if got.ID != 2 || got.Limit != 10 {
+		t.Errorf(errResponseValue, "id=2 limit=10", got)
+	}
+}
+
+func TestProtoBufRoundTrip(t *testing.T) {
+	msg, err := structpb.NewStruct(map[string]any{"name": "gra", "count": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	New(w, httptest.NewRequest(http.MethodGet, "/", nil)).ProtoBuf(http.StatusCreated, msg)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf(errStatusCode, http.StatusCreated, w.Code)
+	}
+	if ct := w.Header().Get(HeaderContentType); ct != ContentTypeProtoBuf {
+		t.Errorf(errResponseValue, ContentTypeProtoBuf, ct)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(w.Body.Bytes()))
+	r.Header.Set(HeaderContentType, "application/protobuf")
+
+	var got structpb.Struct
+	if err := New(httptest.NewRecorder(), r).Bind(&got); err != nil {
+		t.Fatalf("Bind returned error: %v", err)
+	}
+	if !proto.Equal(msg, &got) {
+		t.Errorf(errResponseValue, msg, &got)
+	}
+}
+
+func TestBindProtoBufRequiresMessage(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte{0x0a}))
+	r.Header.Set(HeaderContentType, ContentTypeProtoBuf)
+
+	var target struct{ Name string }
+	if err := New(httptest.NewRecorder(), r).Bind(&target); !errors.Is(err, ErrInvalidBindTarget) {
+		t.Errorf(errWrongErrorType, ErrInvalidBindTarget, err)
+	}
+}
@@ -0,0 +1,433 @@
+package context
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// Content type constants used by the binding helpers
+const (
+	ContentTypeForm          = "application/x-www-form-urlencoded"
+	ContentTypeMultipartForm = "multipart/form-data"
+)
+
+// DefaultMaxMultipartMemory is the maximum number of bytes of a multipart form
+// kept in memory; the remaining file parts are stored in temporary files.
+const DefaultMaxMultipartMemory = 32 << 20 // 32 MB
+
+// Binding errors
+var (
+	// ErrInvalidBindTarget is returned when a Bind* method receives something other
+	// than a non-nil pointer to a struct
+	ErrInvalidBindTarget = errors.New("bind target must be a non-nil pointer to a struct")
+	// ErrUnsupportedContentType is returned by Bind when no binder handles the request's Content-Type
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	durationType    = reflect.TypeOf(time.Duration(0))
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// valueLookup returns the raw values stored under a name, if any
+type valueLookup func(name string) ([]string, bool)
+
+// Bind binds the request body to obj, choosing the decoder from the Content-Type header.
+// JSON (including +json media types), YAML, TOML, MessagePack, Protocol Buffers,
+// URL-encoded and multipart forms are supported. Requests without a body or Content-Type (for example GET requests) are
+// bound from the query string using `form` tags. Fields that receive no value
+// are set from their `default` tag, e.g. `default:"20"`.
+func (c *Context) Bind(obj any) error {
+	return c.autoValidate(obj, c.bind(obj))
+}
+
+// bind binds obj with the binder matching the Content-Type
+func (c *Context) bind(obj any) error {
+	mediaType := c.mediaType()
+
+	switch {
+	case mediaType == "" && hasNoBody(c.Request):
+		return c.bindForm(obj)
+	case mediaType == ContentTypeJSON || strings.HasSuffix(mediaType, "+json"):
+		return c.bindJSON(obj)
+	case mediaType == ContentTypeForm, mediaType == ContentTypeMultipartForm:
+		return c.bindForm(obj)
+	case isYAMLMediaType(mediaType):
+		return c.bindYAML(obj)
+	case mediaType == ContentTypeTOML:
+		return c.bindTOML(obj)
+	case isMsgpackMediaType(mediaType):
+		return c.bindMsgpack(obj)
+	case isProtoBufMediaType(mediaType):
+		msg, ok := obj.(proto.Message)
+		if !ok {
+			return fmt.Errorf("%w: %s requires a proto.Message", ErrInvalidBindTarget, mediaType)
+		}
+		return c.BindProtoBuf(msg)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+	}
+}
+
+// BindYAML binds a YAML request body to a struct using `yaml` tags
+func (c *Context) BindYAML(obj any) error {
+	return c.autoValidate(obj, c.bindYAML(obj))
+}
+
+// bindYAML decodes a YAML body into obj
+func (c *Context) bindYAML(obj any) error {
+	body, err := c.BodyBytes()
+	if err != nil {
+		return err
+	}
+
+	if err := applyDefaults(obj); err != nil {
+		return err
+	}
+
+	// An empty document leaves obj at its defaults rather than failing with io.EOF
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	return yaml.Unmarshal(body, obj)
+}
+
+// BindTOML binds a TOML request body to a struct using `toml` tags
+func (c *Context) BindTOML(obj any) error {
+	return c.autoValidate(obj, c.bindTOML(obj))
+}
+
+// bindTOML decodes a TOML body into obj
+func (c *Context) bindTOML(obj any) error {
+	body, err := c.BodyBytes()
+	if err != nil {
+		return err
+	}
+
+	if err := applyDefaults(obj); err != nil {
+		return err
+	}
+
+	return toml.Unmarshal(body, obj)
+}
+
+// mediaType returns the request's media type without parameters, lowercased
+func (c *Context) mediaType() string {
+	contentType := c.GetContentType()
+	if contentType == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+	return mediaType
+}
+
+// hasNoBody reports whether the request carries no body
+func hasNoBody(r *http.Request) bool {
+	return r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0
+}
+
+// isYAMLMediaType reports whether the media type denotes YAML
+func isYAMLMediaType(mediaType string) bool {
+	switch mediaType {
+	case ContentTypeYAML, "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+yaml")
+}
+
+// BindForm binds form data to a struct using `form` tags.
+// It supports both application/x-www-form-urlencoded and multipart/form-data
+// bodies. Fields of type *multipart.FileHeader or []*multipart.FileHeader
+// receive uploaded files from multipart requests.
+func (c *Context) BindForm(obj any) error {
+	return c.autoValidate(obj, c.bindForm(obj))
+}
+
+// bindForm maps form values onto obj
+func (c *Context) bindForm(obj any) error {
+	var files map[string][]*multipart.FileHeader
+
+	if isMultipartForm(c.GetContentType()) {
+		if err := c.Request.ParseMultipartForm(DefaultMaxMultipartMemory); err != nil {
+			return err
+		}
+		files = c.Request.MultipartForm.File
+	} else if err := c.Request.ParseForm(); err != nil {
+		return err
+	}
+
+	form := c.Request.Form
+	return bindValues(obj, "form", func(name string) ([]string, bool) {
+		values, ok := form[name]
+		return values, ok
+	}, files)
+}
+
+// BindUri binds route parameters (such as :id) to a struct using `uri` tags
+func (c *Context) BindUri(obj any) error {
+	params := c.Params
+	return bindValues(obj, "uri", func(name string) ([]string, bool) {
+		value, ok := params[name]
+		if !ok {
+			return nil, false
+		}
+		return []string{value}, true
+	}, nil)
+}
+
+// BindHeader binds request headers to a struct using `header` tags.
+// Header names are matched case-insensitively.
+func (c *Context) BindHeader(obj any) error {
+	header := c.Request.Header
+	return bindValues(obj, "header", func(name string) ([]string, bool) {
+		values := header.Values(name)
+		return values, len(values) > 0
+	}, nil)
+}
+
+// isMultipartForm checks if the content type is multipart/form-data
+func isMultipartForm(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == ContentTypeMultipartForm
+}
+
+// bindValues maps string values onto the fields of the struct pointed to by obj,
+// using the given struct tag to resolve field names
+func bindValues(obj any, tag string, lookup valueLookup, files map[string][]*multipart.FileHeader) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidBindTarget
+	}
+	return mapStruct(rv.Elem(), tag, lookup, files)
+}
+
+// mapStruct walks the struct fields and assigns values found by lookup
+func mapStruct(rv reflect.Value, tag string, lookup valueLookup, files map[string][]*multipart.FileHeader) error {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fieldValue := rv.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		// Recurse into untagged nested and embedded structs
+		if name == "" && isNestedStruct(field.Type) {
+			if err := mapStruct(fieldValue, tag, lookup, files); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		// Multipart file uploads
+		switch field.Type {
+		case fileHeaderType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fieldValue.Set(reflect.ValueOf(fhs[0]))
+			}
+			continue
+		case fileHeadersType:
+			if fhs := files[name]; len(fhs) > 0 {
+				fieldValue.Set(reflect.ValueOf(fhs))
+			}
+			continue
+		}
+
+		values, ok := lookup(name)
+		if !ok || len(values) == 0 {
+			if err := setDefault(field, fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := setFieldValues(fieldValue, values); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyDefaults sets fields carrying a `default` tag to that value if they are still
+// zero. Body binders call it before decoding so values present in the body win.
+func applyDefaults(obj any) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return applyStructDefaults(rv.Elem())
+}
+
+// applyStructDefaults walks a struct value applying `default` tags
+func applyStructDefaults(rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if _, ok := field.Tag.Lookup("default"); !ok && isNestedStruct(field.Type) {
+			if err := applyStructDefaults(rv.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := setDefault(field, rv.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setDefault assigns the field's `default` tag value when the field is zero.
+// Slice defaults are comma separated.
+func setDefault(field reflect.StructField, fieldValue reflect.Value) error {
+	def, ok := field.Tag.Lookup("default")
+	if !ok || !fieldValue.IsZero() {
+		return nil
+	}
+
+	values := []string{def}
+	if fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() != reflect.Uint8 {
+		values = strings.Split(def, ",")
+	}
+	if err := setFieldValues(fieldValue, values); err != nil {
+		return fmt.Errorf("invalid default for %s: %w", field.Name, err)
+	}
+	return nil
+}
+
+// isNestedStruct reports whether a field type is a struct that should be walked
+// rather than assigned from a single value
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(textUnmarshaler)
+}
+
+// setFieldValues assigns one or more raw values to a field, handling pointers and slices
+func setFieldValues(field reflect.Value, values []string) error {
+	switch field.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(field.Type().Elem())
+		if err := setFieldValues(elem.Elem(), values); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			field.SetBytes([]byte(values[0]))
+			return nil
+		}
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setScalarValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	default:
+		return setScalarValue(field, values[0])
+	}
+}
+
+// setScalarValue converts a single string and assigns it to a field
+func setScalarValue(field reflect.Value, value string) error {
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshaler) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	if field.Kind() == reflect.String {
+		field.SetString(value)
+		return nil
+	}
+
+	// Empty values leave non-string fields at their zero value
+	if value == "" {
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == durationType {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Struct:
+		if field.Type() != timeType {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,381 @@
+package context
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	errBindForm       = "BindForm returned error: %v"
+	errExpectedField  = "Expected %s to be %v, got %v"
+	errExpectedBindEr = "Expected bind error, got nil"
+)
+
+type formRequest struct {
+	Name     string        `form:"name"`
+	Age      int           `form:"age"`
+	Active   bool          `form:"active"`
+	Score    *float64      `form:"score"`
+	Tags     []string      `form:"tags"`
+	Timeout  time.Duration `form:"timeout"`
+	Ignored  string        `form:"-"`
+	Untagged string
+}
+
+func TestBindFormURLEncoded(t *testing.T) {
+	form := url.Values{}
+	form.Set("name", "John")
+	form.Set("age", "30")
+	form.Set("active", "true")
+	form.Set("score", "9.5")
+	form.Add("tags", "a")
+	form.Add("tags", "b")
+	form.Set("timeout", "2s")
+	form.Set("Ignored", "x")
+	form.Set("Untagged", "y")
+
+	r := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(form.Encode()))
+	r.Header.Set(HeaderContentType, ContentTypeForm)
+	c := New(httptest.NewRecorder(), r)
+
+	var req formRequest
+	if err := c.BindForm(&req); err != nil {
+		t.Fatalf(errBindForm, err)
+	}
+
+	if req.Name != "John" {
+		t.Errorf(errExpectedField, "name", "John", req.Name)
+	}
+	if req.Age != 30 {
+		t.Errorf(errExpectedField, "age", 30, req.Age)
+	}
+	if !req.Active {
+		t.Errorf(errExpectedField, "active", true, req.Active)
+	}
+	if req.Score == nil || *req.Score != 9.5 {
+		t.Errorf(errExpectedField, "score", 9.5, req.Score)
+	}
+	if len(req.Tags) != 2 || req.Tags[0] != "a" || req.Tags[1] != "b" {
+		t.Errorf(errExpectedField, "tags", []string{"a", "b"}, req.Tags)
+	}
+	if req.Timeout != 2*time.Second {
+		t.Errorf(errExpectedField, "timeout", 2*time.Second, req.Timeout)
+	}
+	if req.Ignored != "" {
+		t.Errorf(errExpectedField, "Ignored", "", req.Ignored)
+	}
+	if req.Untagged != "y" {
+		t.Errorf(errExpectedField, "Untagged", "y", req.Untagged)
+	}
+}
+
+func TestBindFormMultipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.WriteField("title", "report"); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := mw.CreateFormFile("file", "report.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write([]byte("id,name\n1,John\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	r.Header.Set(HeaderContentType, mw.FormDataContentType())
+	c := New(httptest.NewRecorder(), r)
+
+	var req struct {
+		Title string                `form:"title"`
+		File  *multipart.FileHeader `form:"file"`
+	}
+	if err := c.BindForm(&req); err != nil {
+		t.Fatalf(errBindForm, err)
+	}
+
+	if req.Title != "report" {
+		t.Errorf(errExpectedField, "title", "report", req.Title)
+	}
+	if req.File == nil {
+		t.Fatal("Expected uploaded file to be bound")
+	}
+	if req.File.Filename != "report.csv" {
+		t.Errorf(errExpectedField, "filename", "report.csv", req.File.Filename)
+	}
+
+	f, err := req.File.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	content, _ := io.ReadAll(f)
+	if !strings.HasPrefix(string(content), "id,name") {
+		t.Errorf(errExpectedField, "file content", "id,name...", string(content))
+	}
+}
+
+func TestBindFormInvalidValue(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("age=abc"))
+	r.Header.Set(HeaderContentType, ContentTypeForm)
+	c := New(httptest.NewRecorder(), r)
+
+	var req formRequest
+	if err := c.BindForm(&req); err == nil {
+		t.Error(errExpectedBindEr)
+	}
+}
+
+func TestBindFormInvalidTarget(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("name=John"))
+	r.Header.Set(HeaderContentType, ContentTypeForm)
+	c := New(httptest.NewRecorder(), r)
+
+	var req formRequest
+	if err := c.BindForm(req); err != ErrInvalidBindTarget {
+		t.Errorf(errWrongErrorType, ErrInvalidBindTarget, err)
+	}
+}
+
+type configRequest struct {
+	Name    string   `json:"name" yaml:"name" toml:"name" form:"name"`
+	Port    int      `json:"port" yaml:"port" toml:"port" form:"port"`
+	Enabled bool     `json:"enabled" yaml:"enabled" toml:"enabled" form:"enabled"`
+	Hosts   []string `json:"hosts" yaml:"hosts" toml:"hosts" form:"hosts"`
+}
+
+func TestBindYAML(t *testing.T) {
+	body := "name: api\nport: 8080\nenabled: true\nhosts:\n  - a.example.com\n  - b.example.com\n"
+	r := httptest.NewRequest(http.MethodPost, "/config", strings.NewReader(body))
+	r.Header.Set(HeaderContentType, ContentTypeYAML)
+	c := New(httptest.NewRecorder(), r)
+
+	var cfg configRequest
+	if err := c.BindYAML(&cfg); err != nil {
+		t.Fatalf("BindYAML returned error: %v", err)
+	}
+	if cfg.Name != "api" || cfg.Port != 8080 || !cfg.Enabled || len(cfg.Hosts) != 2 {
+		t.Errorf(errResponseValue, "api:8080 enabled with 2 hosts", cfg)
+	}
+}
+
+func TestBindTOML(t *testing.T) {
+	body := "name = \"api\"\nport = 8080\nenabled = true\nhosts = [\"a.example.com\"]\n"
+	r := httptest.NewRequest(http.MethodPost, "/config", strings.NewReader(body))
+	r.Header.Set(HeaderContentType, ContentTypeTOML)
+	c := New(httptest.NewRecorder(), r)
+
+	var cfg configRequest
+	if err := c.BindTOML(&cfg); err != nil {
+		t.Fatalf("BindTOML returned error: %v", err)
+	}
+	if cfg.Name != "api" || cfg.Port != 8080 || !cfg.Enabled || len(cfg.Hosts) != 1 {
+		t.Errorf(errResponseValue, "api:8080 enabled with 1 host", cfg)
+	}
+}
+
+func TestBindDetectsContentType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		target      string
+	}{
+		{"JSON", http.MethodPost, "application/json; charset=utf-8", `{"name":"api","port":1}`, "/"},
+		{"Vendor JSON", http.MethodPost, "application/vnd.api+json", `{"name":"api","port":1}`, "/"},
+		{"YAML", http.MethodPost, "application/x-yaml", "name: api\nport: 1\n", "/"},
+		{"TOML", http.MethodPost, ContentTypeTOML, "name = \"api\"\nport = 1\n", "/"},
+		{"Form", http.MethodPost, ContentTypeForm, "name=api&port=1", "/"},
+		{"Query", http.MethodGet, "", "", "/?name=api&port=1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body io.Reader
+			if tc.body != "" {
+				body = strings.NewReader(tc.body)
+			}
+			r := httptest.NewRequest(tc.method, tc.target, body)
+			if tc.contentType != "" {
+				r.Header.Set(HeaderContentType, tc.contentType)
+			}
+			c := New(httptest.NewRecorder(), r)
+
+			var cfg configRequest
+			if err := c.Bind(&cfg); err != nil {
+				t.Fatalf("Bind returned error: %v", err)
+			}
+			if cfg.Name != "api" || cfg.Port != 1 {
+				t.Errorf(errResponseValue, "api:1", cfg)
+			}
+		})
+	}
+}
+
+func TestBindUnsupportedContentType(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("<xml/>"))
+	r.Header.Set(HeaderContentType, "application/xml")
+	c := New(httptest.NewRecorder(), r)
+
+	var cfg configRequest
+	if err := c.Bind(&cfg); !errors.Is(err, ErrUnsupportedContentType) {
+		t.Errorf(errWrongErrorType, ErrUnsupportedContentType, err)
+	}
+}
+
+func TestBindUri(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42/posts/hello", nil))
+	c.Params["id"] = "42"
+	c.Params["slug"] = "hello"
+
+	var params struct {
+		ID      int    `uri:"id"`
+		Slug    string `uri:"slug"`
+		Missing *int   `uri:"missing"`
+	}
+	if err := c.BindUri(&params); err != nil {
+		t.Fatalf("BindUri returned error: %v", err)
+	}
+
+	if params.ID != 42 {
+		t.Errorf(errExpectedField, "id", 42, params.ID)
+	}
+	if params.Slug != "hello" {
+		t.Errorf(errExpectedField, "slug", "hello", params.Slug)
+	}
+	if params.Missing != nil {
+		t.Errorf(errExpectedField, "missing", nil, params.Missing)
+	}
+}
+
+func TestBindUriInvalidValue(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+	c.Params["id"] = "abc"
+
+	var params struct {
+		ID int `uri:"id"`
+	}
+	if err := c.BindUri(&params); err == nil {
+		t.Error(errExpectedBindEr)
+	}
+}
+
+func TestBindHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "req-1")
+	r.Header.Set("x-tenant-id", "7")
+	r.Header.Add("Accept-Language", "en")
+	r.Header.Add("Accept-Language", "id")
+	c := New(httptest.NewRecorder(), r)
+
+	var h struct {
+		RequestID string   `header:"X-Request-ID"`
+		TenantID  int      `header:"X-Tenant-ID"`
+		IfMatch   string   `header:"if-match"`
+		Languages []string `header:"Accept-Language"`
+	}
+	if err := c.BindHeader(&h); err != nil {
+		t.Fatalf("BindHeader returned error: %v", err)
+	}
+
+	if h.RequestID != "req-1" {
+		t.Errorf(errExpectedField, "X-Request-ID", "req-1", h.RequestID)
+	}
+	if h.TenantID != 7 {
+		t.Errorf(errExpectedField, "X-Tenant-ID", 7, h.TenantID)
+	}
+	if h.IfMatch != "" {
+		t.Errorf(errExpectedField, "If-Match", "", h.IfMatch)
+	}
+	if len(h.Languages) != 2 {
+		t.Errorf(errExpectedField, "Accept-Language", []string{"en", "id"}, h.Languages)
+	}
+}
+
+type listRequest struct {
+	Page    int           `form:"page" json:"page" default:"1"`
+	PerPage int           `form:"per_page" json:"per_page" default:"20"`
+	Sort    string        `form:"sort" json:"sort" default:"created_at"`
+	Fields  []string      `form:"fields" json:"fields" default:"id,name"`
+	Timeout time.Duration `form:"timeout" json:"timeout" default:"5s"`
+	Filter  struct {
+		Status string `form:"status" json:"status" default:"active"`
+	} `json:"filter"`
+}
+
+func TestBindFormDefaults(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items?per_page=50", nil))
+
+	var req listRequest
+	if err := c.BindForm(&req); err != nil {
+		t.Fatalf(errBindForm, err)
+	}
+
+	if req.Page != 1 || req.PerPage != 50 || req.Sort != "created_at" || req.Timeout != 5*time.Second {
+		t.Errorf(errResponseValue, "page=1 per_page=50 sort=created_at timeout=5s", req)
+	}
+	if len(req.Fields) != 2 || req.Fields[1] != "name" {
+		t.Errorf(errExpectedField, "fields", []string{"id", "name"}, req.Fields)
+	}
+	if req.Filter.Status != "active" {
+		t.Errorf(errExpectedField, "filter.status", "active", req.Filter.Status)
+	}
+}
+
+func TestBindJSONDefaults(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"page":0,"sort":"name"}`))
+	c := New(httptest.NewRecorder(), r)
+
+	var req listRequest
+	if err := c.BindJSON(&req); err != nil {
+		t.Fatalf(errBindJSON, err)
+	}
+
+	// Values present in the body win over defaults, even when zero
+	if req.Page != 0 || req.Sort != "name" {
+		t.Errorf(errResponseValue, "page=0 sort=name", req)
+	}
+	if req.PerPage != 20 || req.Filter.Status != "active" {
+		t.Errorf(errResponseValue, "per_page=20 filter.status=active", req)
+	}
+}
+
+func TestBindUriDefaultsKeepExistingValues(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	req := struct {
+		Version string `uri:"version" default:"v1"`
+		Limit   int    `json:"limit" default:"10"`
+	}{Limit: 25}
+	if err := c.BindUri(&req); err != nil {
+		t.Fatalf("BindUri returned error: %v", err)
+	}
+
+	if req.Version != "v1" || req.Limit != 25 {
+		t.Errorf(errResponseValue, "version=v1 limit=25", req)
+	}
+}
+
+func TestBindInvalidDefault(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var req struct {
+		Page int `form:"page" default:"first"`
+	}
+	if err := c.BindForm(&req); err == nil {
+		t.Error(errExpectedBindEr)
+	}
+}
@@ -0,0 +1,72 @@
+package context
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync/atomic"
+)
+
+// DefaultMaxBodyBytes is the default limit for request bodies read by BodyBytes
+const DefaultMaxBodyBytes = 10 << 20 // 10 MB
+
+// ErrBodyTooLarge is returned when the request body exceeds the configured limit
+var ErrBodyTooLarge = errors.New("request body too large")
+
+var maxBodyBytes atomic.Int64
+
+func init() {
+	maxBodyBytes.Store(DefaultMaxBodyBytes)
+}
+
+// SetMaxBodyBytes sets the maximum request body size read by BodyBytes and the
+// body binders. A value <= 0 removes the limit.
+func SetMaxBodyBytes(n int64) {
+	maxBodyBytes.Store(n)
+}
+
+// BodyBytes reads the request body once and caches it, so it can be consumed
+// several times (for example by a signature check followed by BindJSON).
+// After reading, Request.Body is replaced with a fresh reader over the cached
+// bytes for handlers that read it directly.
+func (c *Context) BodyBytes() ([]byte, error) {
+	if !c.bodyRead {
+		body, err := c.readBody()
+		if err != nil {
+			return nil, err
+		}
+		c.body = body
+		c.bodyRead = true
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(c.body))
+	return c.body, nil
+}
+
+// readBody reads and closes the request body, enforcing the size limit
+func (c *Context) readBody() ([]byte, error) {
+	if c.Request.Body == nil {
+		return nil, nil
+	}
+	defer func() {
+		if cerr := c.Request.Body.Close(); cerr != nil {
+			log.Printf("Error closing request body: %v", cerr)
+		}
+	}()
+
+	limit := maxBodyBytes.Load()
+	if limit <= 0 {
+		return io.ReadAll(c.Request.Body)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, limit)
+	}
+	return body, nil
+}
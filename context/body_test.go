@@ -0,0 +1,72 @@
+package context
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyBytesCanBeReadRepeatedly(t *testing.T) {
+	payload := `{"name":"John","age":30}`
+	r := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(payload))
+	c := New(httptest.NewRecorder(), r)
+
+	body, err := c.BodyBytes()
+	if err != nil {
+		t.Fatalf("BodyBytes returned error: %v", err)
+	}
+	if string(body) != payload {
+		t.Errorf(errResponseValue, payload, string(body))
+	}
+
+	var first, second struct {
+		Name string `json:"name"`
+	}
+	if err := c.BindJSON(&first); err != nil {
+		t.Fatalf(errBindJSON, err)
+	}
+	if err := c.BindJSON(&second); err != nil {
+		t.Fatalf(errBindJSON, err)
+	}
+	if first.Name != "John" || second.Name != "John" {
+		t.Errorf(errExpectedName, "John", second.Name)
+	}
+
+	raw, _ := io.ReadAll(c.Request.Body)
+	if string(raw) != payload {
+		t.Errorf(errResponseValue, payload, string(raw))
+	}
+}
+
+func TestBodyBytesTooLarge(t *testing.T) {
+	SetMaxBodyBytes(8)
+	t.Cleanup(func() { SetMaxBodyBytes(DefaultMaxBodyBytes) })
+
+	r := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"John"}`))
+	c := New(httptest.NewRecorder(), r)
+
+	if _, err := c.BodyBytes(); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf(errWrongErrorType, ErrBodyTooLarge, err)
+	}
+
+	var data struct{}
+	if err := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"name":"John"}`))).BindJSON(&data); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf(errWrongErrorType, ErrBodyTooLarge, err)
+	}
+}
+
+func TestBodyBytesUnlimited(t *testing.T) {
+	SetMaxBodyBytes(0)
+	t.Cleanup(func() { SetMaxBodyBytes(DefaultMaxBodyBytes) })
+
+	payload := strings.Repeat("x", 1024)
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(payload)))
+
+	body, err := c.BodyBytes()
+	if err != nil || len(body) != len(payload) {
+		t.Errorf(errResponseValue, len(payload), len(body))
+	}
+}
@@ -0,0 +1,193 @@
+package context
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+)
+
+// Proxy header names consulted by ClientIP
+const (
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIP       = "X-Real-IP"
+	HeaderForwarded     = "Forwarded"
+)
+
+// TrustedProxies is a set of proxy address ranges whose forwarding headers are trusted
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses CIDR ranges or single IP addresses
+func ParseTrustedProxies(cidrs ...string) (TrustedProxies, error) {
+	prefixes := make(TrustedProxies, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := parseTrustedProxy(cidr)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// Contains reports whether addr belongs to a trusted range
+func (t TrustedProxies) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range t {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustsPeer reports whether the direct peer of r is a trusted proxy
+func (t TrustedProxies) TrustsPeer(r *http.Request) bool {
+	peer, ok := parseIP(remoteHost(r.RemoteAddr))
+	return ok && t.Contains(peer)
+}
+
+// ClientIP returns the client's IP address for r. Forwarding headers (X-Forwarded-For,
+// Forwarded and X-Real-IP) are only honored when the direct peer is trusted, so clients
+// cannot spoof their address. X-Forwarded-For is walked from right to left, skipping
+// trusted proxies, to find the first untrusted hop.
+func (t TrustedProxies) ClientIP(r *http.Request) string {
+	peer, ok := parseIP(remoteHost(r.RemoteAddr))
+	if !ok {
+		return remoteHost(r.RemoteAddr)
+	}
+	if !t.Contains(peer) {
+		return peer.String()
+	}
+
+	if ip, ok := t.forwardedClient(forwardedForChain(r.Header)); ok {
+		return ip
+	}
+	if ip, ok := t.forwardedClient(forwardedHeaderChain(r.Header)); ok {
+		return ip
+	}
+	if ip, ok := parseIP(r.Header.Get(HeaderXRealIP)); ok {
+		return ip.String()
+	}
+
+	return peer.String()
+}
+
+var (
+	trustedProxies   TrustedProxies
+	trustedProxiesMu sync.RWMutex
+)
+
+// SetTrustedProxies sets the CIDR ranges (or single addresses) of proxies whose
+// forwarding headers ClientIP may trust. Passing no values trusts no proxy, which
+// is the default.
+func SetTrustedProxies(cidrs ...string) error {
+	prefixes, err := ParseTrustedProxies(cidrs...)
+	if err != nil {
+		return err
+	}
+
+	trustedProxiesMu.Lock()
+	defer trustedProxiesMu.Unlock()
+	trustedProxies = prefixes
+	return nil
+}
+
+// parseTrustedProxy parses a CIDR range or a single IP address
+func parseTrustedProxy(value string) (netip.Prefix, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// ClientIP returns the client's IP address, trusting forwarding headers only from
+// the proxies configured with SetTrustedProxies
+func (c *Context) ClientIP() string {
+	trustedProxiesMu.RLock()
+	proxies := trustedProxies
+	trustedProxiesMu.RUnlock()
+
+	return proxies.ClientIP(c.Request)
+}
+
+// forwardedClient returns the rightmost address in chain that is not a trusted proxy
+func (t TrustedProxies) forwardedClient(chain []string) (string, bool) {
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip, ok := parseIP(chain[i])
+		if !ok {
+			// A malformed hop cannot be trusted further
+			return "", false
+		}
+		if i == 0 || !t.Contains(ip) {
+			return ip.String(), true
+		}
+	}
+	return "", false
+}
+
+// forwardedForChain returns the addresses listed in all X-Forwarded-For headers
+func forwardedForChain(header http.Header) []string {
+	var chain []string
+	for _, value := range header.Values(HeaderXForwardedFor) {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				chain = append(chain, hop)
+			}
+		}
+	}
+	return chain
+}
+
+// forwardedHeaderChain returns the for= addresses of the RFC 7239 Forwarded header
+func forwardedHeaderChain(header http.Header) []string {
+	var chain []string
+	for _, value := range header.Values(HeaderForwarded) {
+		for _, element := range strings.Split(value, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(key, "for") {
+					chain = append(chain, strings.Trim(val, `"`))
+				}
+			}
+		}
+	}
+	return chain
+}
+
+// remoteHost strips the port from a host:port address
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// parseIP parses an address that may carry a port or IPv6 brackets
+func parseIP(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return netip.Addr{}, false
+	}
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	addr, err := netip.ParseAddr(strings.Trim(value, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,110 @@
+package context
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	if err := SetTrustedProxies("10.0.0.0/8", "192.168.1.1"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetTrustedProxies() })
+
+	testCases := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{"Direct client", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"Untrusted peer spoofing", "203.0.113.7:5000", map[string]string{HeaderXForwardedFor: "1.2.3.4"}, "203.0.113.7"},
+		{"Trusted proxy", "10.0.0.5:80", map[string]string{HeaderXForwardedFor: "198.51.100.1"}, "198.51.100.1"},
+		{"Proxy chain", "10.0.0.5:80", map[string]string{HeaderXForwardedFor: "1.2.3.4, 198.51.100.1, 192.168.1.1"}, "198.51.100.1"},
+		{"Forwarded header", "10.0.0.5:80", map[string]string{HeaderForwarded: `for=192.0.2.60;proto=http, for="[2001:db8::1]:4711"`}, "2001:db8::1"},
+		{"X-Real-IP", "192.168.1.1:80", map[string]string{HeaderXRealIP: "198.51.100.9"}, "198.51.100.9"},
+		{"Malformed header", "10.0.0.5:80", map[string]string{HeaderXForwardedFor: "not-an-ip"}, "10.0.0.5"},
+		{"IPv6 peer", "[2001:db8::2]:443", nil, "2001:db8::2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tc.remoteAddr
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+
+			if ip := New(httptest.NewRecorder(), r).ClientIP(); ip != tc.expected {
+				t.Errorf(errResponseValue, tc.expected, ip)
+			}
+		})
+	}
+}
+
+func TestClientIPNoTrustedProxies(t *testing.T) {
+	_ = SetTrustedProxies()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.5:80"
+	r.Header.Set(HeaderXForwardedFor, "198.51.100.1")
+
+	if ip := New(httptest.NewRecorder(), r).ClientIP(); ip != "10.0.0.5" {
+		t.Errorf(errResponseValue, "10.0.0.5", ip)
+	}
+}
+
+func TestSetTrustedProxiesInvalid(t *testing.T) {
+	if err := SetTrustedProxies("10.0.0.0/99"); err == nil {
+		t.Error("Expected error for invalid CIDR")
+	}
+	if err := SetTrustedProxies("proxy.local"); err == nil {
+		t.Error("Expected error for invalid address")
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies failed: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set(HeaderXForwardedFor, "203.0.113.9")
+	if !proxies.TrustsPeer(r) {
+		t.Error("Expected peer to be trusted")
+	}
+	if ip := proxies.ClientIP(r); ip != "203.0.113.9" {
+		t.Errorf(errResponseValue, "203.0.113.9", ip)
+	}
+	if ip := (TrustedProxies{}).ClientIP(r); ip != "10.0.0.1" {
+		t.Errorf(errResponseValue, "10.0.0.1", ip)
+	}
+}
+
+func TestScheme(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := New(httptest.NewRecorder(), r)
+	if c.Scheme() != "http" || c.IsSecure() {
+		t.Errorf(errResponseValue, "http", c.Scheme())
+	}
+
+	// An absolute request target is chosen by the client
+	r.URL.Scheme = "https"
+	if c.Scheme() != "http" || c.IsSecure() {
+		t.Errorf(errResponseValue, "http", c.Scheme())
+	}
+
+	r.TLS = &tls.ConnectionState{}
+	if c.Scheme() != "https" || !c.IsSecure() {
+		t.Errorf(errResponseValue, "https", c.Scheme())
+	}
+
+	r.TLS = nil
+	if c.SetScheme("https"); c.Scheme() != "https" || !c.IsSecure() {
+		t.Errorf(errResponseValue, "https", c.Scheme())
+	}
+}
@@ -0,0 +1,105 @@
+package context
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Conditional request header names
+const (
+	HeaderETag            = "ETag"
+	HeaderLastModified    = "Last-Modified"
+	HeaderIfNoneMatch     = "If-None-Match"
+	HeaderIfModifiedSince = "If-Modified-Since"
+)
+
+// SetETag sets a strong ETag on the response. The value is quoted if needed;
+// values starting with W/ are passed through as weak validators.
+func (c *Context) SetETag(etag string) *Context {
+	return c.SetHeader(HeaderETag, quoteETag(etag))
+}
+
+// SetWeakETag sets a weak ETag (W/"...") on the response
+func (c *Context) SetWeakETag(etag string) *Context {
+	return c.SetHeader(HeaderETag, "W/"+quoteETag(strings.TrimPrefix(etag, "W/")))
+}
+
+// SetLastModified sets the Last-Modified header, truncated to whole seconds
+func (c *Context) SetLastModified(t time.Time) *Context {
+	return c.SetHeader(HeaderLastModified, t.UTC().Format(http.TimeFormat))
+}
+
+// IfNoneMatch reports whether the request's If-None-Match header matches the ETag
+// set on the response, using the weak comparison of RFC 7232 section 2.3.2
+func (c *Context) IfNoneMatch() bool {
+	header := c.GetHeader(HeaderIfNoneMatch)
+	if header == "" {
+		return false
+	}
+	return etagMatches(header, c.Writer.Header().Get(HeaderETag))
+}
+
+// IfModifiedSince reports whether the response's Last-Modified time is no later than
+// the request's If-Modified-Since header, i.e. the client's copy is still current
+func (c *Context) IfModifiedSince() bool {
+	since, err := http.ParseTime(c.GetHeader(HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(c.Writer.Header().Get(HeaderLastModified))
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// IsFresh reports whether the client's cached copy matches the response validators
+// set with SetETag and SetLastModified. Following RFC 7232 section 6, If-Modified-Since
+// is ignored when If-None-Match is present, and only GET and HEAD requests can be fresh.
+func (c *Context) IsFresh() bool {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+	if c.GetHeader(HeaderIfNoneMatch) != "" {
+		return c.IfNoneMatch()
+	}
+	return c.IfModifiedSince()
+}
+
+// NotModified sends a 304 Not Modified response without a body.
+// Content headers are removed as required by RFC 7232 section 4.1.
+func (c *Context) NotModified() {
+	header := c.Writer.Header()
+	for _, name := range []string{HeaderContentType, "Content-Length", "Content-Encoding"} {
+		header.Del(name)
+	}
+	c.Writer.WriteHeader(http.StatusNotModified)
+}
+
+// quoteETag wraps an entity tag in double quotes unless it is already quoted or weak
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, "W/") || (len(etag) >= 2 && strings.HasPrefix(etag, `"`) && strings.HasSuffix(etag, `"`)) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag using weak comparison
+func etagMatches(header, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	opaque := quoteETag(strings.TrimPrefix(etag, "W/"))
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = quoteETag(strings.TrimPrefix(strings.TrimSpace(candidate), "W/"))
+		if candidate == opaque {
+			return true
+		}
+	}
+	return false
+}
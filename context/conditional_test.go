@@ -0,0 +1,110 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newConditionalContext(method string, headers map[string]string) (*Context, *httptest.ResponseRecorder) {
+	r := httptest.NewRequest(method, "/users/1", nil)
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	return New(w, r), w
+}
+
+func TestSetETag(t *testing.T) {
+	c, w := newConditionalContext(http.MethodGet, nil)
+
+	c.SetETag("v1")
+	if etag := w.Header().Get(HeaderETag); etag != `"v1"` {
+		t.Errorf(errResponseValue, `"v1"`, etag)
+	}
+
+	c.SetETag(`"v2"`)
+	if etag := w.Header().Get(HeaderETag); etag != `"v2"` {
+		t.Errorf(errResponseValue, `"v2"`, etag)
+	}
+
+	c.SetWeakETag("v3")
+	if etag := w.Header().Get(HeaderETag); etag != `W/"v3"` {
+		t.Errorf(errResponseValue, `W/"v3"`, etag)
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{"Exact", `"v1"`, true},
+		{"List", `"v0", "v1"`, true},
+		{"Weak comparison", `W/"v1"`, true},
+		{"Wildcard", "*", true},
+		{"Mismatch", `"v2"`, false},
+		{"Missing", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := newConditionalContext(http.MethodGet, map[string]string{HeaderIfNoneMatch: tc.header})
+			c.SetETag("v1")
+
+			if got := c.IfNoneMatch(); got != tc.expected {
+				t.Errorf(errResponseValue, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsFresh(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		method   string
+		headers  map[string]string
+		expected bool
+	}{
+		{"If-Modified-Since equal", http.MethodGet, map[string]string{HeaderIfModifiedSince: modified.Format(http.TimeFormat)}, true},
+		{"If-Modified-Since older", http.MethodGet, map[string]string{HeaderIfModifiedSince: modified.Add(-time.Hour).Format(http.TimeFormat)}, false},
+		{"If-None-Match wins", http.MethodGet, map[string]string{HeaderIfNoneMatch: `"other"`, HeaderIfModifiedSince: modified.Format(http.TimeFormat)}, false},
+		{"HEAD", http.MethodHead, map[string]string{HeaderIfNoneMatch: `"v1"`}, true},
+		{"POST never fresh", http.MethodPost, map[string]string{HeaderIfNoneMatch: `"v1"`}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := newConditionalContext(tc.method, tc.headers)
+			c.SetETag("v1").SetLastModified(modified)
+
+			if got := c.IsFresh(); got != tc.expected {
+				t.Errorf(errResponseValue, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	c, w := newConditionalContext(http.MethodGet, map[string]string{HeaderIfNoneMatch: `"v1"`})
+	c.SetHeader(HeaderContentType, ContentTypeJSON)
+	c.SetETag("v1")
+
+	if c.IsFresh() {
+		c.NotModified()
+	}
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf(errStatusCode, http.StatusNotModified, w.Code)
+	}
+	if w.Body.Len() != 0 || w.Header().Get(HeaderContentType) != "" {
+		t.Error("Expected 304 response without body or Content-Type")
+	}
+	if w.Header().Get(HeaderETag) != `"v1"` {
+		t.Errorf(errResponseValue, `"v1"`, w.Header().Get(HeaderETag))
+	}
+}
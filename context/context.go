@@ -4,9 +4,11 @@ package context
 import (
 	"context"
 	"encoding/json"
-	"io"
 	"log"
 	"net/http"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/logger"
 )
 
 // HTTP header constants
@@ -16,6 +18,8 @@ const (
 	HeaderAuthorization = "Authorization"
 
 	ContentTypeJSON = "application/json"
+	ContentTypeYAML = "application/yaml"
+	ContentTypeTOML = "application/toml"
 )
 
 // APIResponse is a standardized response structure
@@ -33,6 +37,12 @@ type Context struct {
 	Request *http.Request
 	Params  map[string]string // For route parameters
 	ctx     context.Context
+
+	body     []byte // Cached request body, see BodyBytes
+	bodyRead bool
+	log      *logger.Logger
+	aborted  bool
+	scheme   string // Scheme forwarded by a trusted proxy, see SetScheme
 }
 
 // New creates a new Context
@@ -53,34 +63,62 @@ func (c *Context) Status(code int) *Context {
 
 // JSON sends a JSON response
 func (c *Context) JSON(status int, obj any) {
+	c.writeJSON(status, obj, "", true)
+}
+
+// IndentedJSON sends a pretty-printed JSON response.
+// It is meant for debugging and human-facing tools; prefer JSON in production.
+func (c *Context) IndentedJSON(status int, obj any) {
+	c.writeJSON(status, obj, "    ", true)
+}
+
+// PureJSON sends a JSON response without escaping HTML characters such as <, > and &
+func (c *Context) PureJSON(status int, obj any) {
+	c.writeJSON(status, obj, "", false)
+}
+
+// writeJSON encodes obj directly to the response writer without an intermediate buffer
+func (c *Context) writeJSON(status int, obj any, indent string, escapeHTML bool) {
 	c.Writer.Header().Set(HeaderContentType, ContentTypeJSON)
 	c.Writer.WriteHeader(status)
-	if err := json.NewEncoder(c.Writer).Encode(obj); err != nil {
+
+	encoder := json.NewEncoder(c.Writer)
+	encoder.SetEscapeHTML(escapeHTML)
+	if indent != "" {
+		encoder.SetIndent("", indent)
+	}
+	if err := encoder.Encode(obj); err != nil {
 		log.Printf("Error encoding JSON: %v", err)
 	}
 }
 
-// BindJSON binds JSON request body to a struct
+// BindJSON binds JSON request body to a struct.
+// Fields missing from the body are set from their `default` tag.
 func (c *Context) BindJSON(obj any) error {
-	body, err := io.ReadAll(c.Request.Body)
+	return c.autoValidate(obj, c.bindJSON(obj))
+}
+
+// bindJSON decodes a JSON body into obj
+func (c *Context) bindJSON(obj any) error {
+	body, err := c.BodyBytes()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if cerr := c.Request.Body.Close(); cerr != nil {
-			log.Printf("Error closing request body: %v", cerr)
-		}
-	}()
+
+	if err := applyDefaults(obj); err != nil {
+		return err
+	}
 
 	return json.Unmarshal(body, obj)
 }
 
-// Success sends a success response
+// Success sends a success response. The data passes through the response transform
+// stored under ResponseTransformKey, if any.
 func (c *Context) Success(status int, message string, data any) {
 	c.JSON(status, APIResponse{
 		Status:  "success",
 		Message: message,
-		Data:    data,
+		Data:    c.transformData(data),
 	})
 }
 
@@ -108,11 +146,7 @@ func (c *Context) GetQuery(key string) string {
 // - When you want to return an array directly in the response body
 // - When integrating with systems that expect a simple JSON structure
 func (c *Context) JSONData(status int, data any) {
-	c.Writer.Header().Set(HeaderContentType, ContentTypeJSON)
-	c.Writer.WriteHeader(status)
-	if err := json.NewEncoder(c.Writer).Encode(data); err != nil {
-		log.Printf("Error encoding JSON: %v", err)
-	}
+	c.writeJSON(status, data, "", true)
 }
 
 // WithValue adds a value to the request context
@@ -127,6 +161,65 @@ func (c *Context) Value(key any) any {
 	return c.ctx.Value(key)
 }
 
+// RequestContext returns the request's context.Context for passing to database calls
+// and HTTP clients so they honor cancellation and deadlines
+func (c *Context) RequestContext() context.Context {
+	return c.ctx
+}
+
+// SetRequestContext replaces the request context, for example with one carrying a deadline
+func (c *Context) SetRequestContext(ctx context.Context) *Context {
+	c.ctx = ctx
+	c.Request = c.Request.WithContext(ctx)
+	return c
+}
+
+// WithTimeout replaces the request context with one that is canceled after d.
+// The returned cancel function should be deferred by the caller.
+func (c *Context) WithTimeout(d time.Duration) context.CancelFunc {
+	ctx, cancel := context.WithTimeout(c.ctx, d)
+	c.SetRequestContext(ctx)
+	return cancel
+}
+
+// WithDeadline replaces the request context with one that is canceled at deadline.
+// The returned cancel function should be deferred by the caller.
+func (c *Context) WithDeadline(deadline time.Time) context.CancelFunc {
+	ctx, cancel := context.WithDeadline(c.ctx, deadline)
+	c.SetRequestContext(ctx)
+	return cancel
+}
+
+// Deadline returns the request context's deadline, see context.Context
+func (c *Context) Deadline() (time.Time, bool) {
+	return c.ctx.Deadline()
+}
+
+// Done returns a channel closed when the request is canceled, see context.Context
+func (c *Context) Done() <-chan struct{} {
+	return c.ctx.Done()
+}
+
+// Err returns why the request context was canceled, see context.Context
+func (c *Context) Err() error {
+	return c.ctx.Err()
+}
+
+// Logger returns the request-scoped logger. It carries the request method and path,
+// plus any fields added by middleware (such as the request ID and user).
+func (c *Context) Logger() *logger.Logger {
+	if c.log == nil {
+		c.log = logger.Get().With("method", c.Request.Method, "path", c.Request.URL.Path)
+	}
+	return c.log
+}
+
+// SetLogger replaces the request-scoped logger, typically with c.Logger().With(...)
+func (c *Context) SetLogger(l *logger.Logger) *Context {
+	c.log = l
+	return c
+}
+
 // GetHeader gets a header value from the request
 func (c *Context) GetHeader(key string) string {
 	return c.Request.Header.Get(key)
@@ -166,6 +259,31 @@ func (c *Context) GetContentType() string {
 	return c.GetHeader(HeaderContentType)
 }
 
+// Scheme returns the request scheme, "https" or "http". Behind a reverse proxy the
+// scheme set by middleware.ProxyHeaders is used. The scheme of the request URL is
+// ignored, as clients choose it.
+func (c *Context) Scheme() string {
+	if c.scheme != "" {
+		return c.scheme
+	}
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// SetScheme sets the scheme the client used, "https" or "http", when a trusted proxy
+// terminates TLS, see middleware.ProxyHeaders
+func (c *Context) SetScheme(scheme string) *Context {
+	c.scheme = scheme
+	return c
+}
+
+// IsSecure reports whether the request was made over HTTPS
+func (c *Context) IsSecure() bool {
+	return c.Scheme() == "https"
+}
+
 // Redirect redirects the request to a new URL
 func (c *Context) Redirect(status int, url string) {
 	http.Redirect(c.Writer, c.Request, url, status)
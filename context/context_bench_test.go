@@ -0,0 +1,42 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type benchRow struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func BenchmarkJSON(b *testing.B) {
+	rows := make([]benchRow, 1000)
+	for i := range rows {
+		rows[i] = benchRow{ID: i, Name: "John Doe", Email: "john@example.com"}
+	}
+	r := httptest.NewRequest("GET", "/users", nil)
+
+	b.Run("JSON", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New(httptest.NewRecorder(), r).JSON(http.StatusOK, rows)
+		}
+	})
+
+	b.Run("PureJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New(httptest.NewRecorder(), r).PureJSON(http.StatusOK, rows)
+		}
+	})
+
+	b.Run("IndentedJSON", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New(httptest.NewRecorder(), r).IndentedJSON(http.StatusOK, rows)
+		}
+	})
+}
@@ -1,12 +1,16 @@
 package context
 
 import (
+	stdcontext "context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Test constants
@@ -139,6 +143,37 @@ func TestJSONSpecialChars(t *testing.T) {
 	}
 }
 
+func TestIndentedJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest("GET", "/test", nil))
+
+	c.IndentedJSON(http.StatusOK, map[string]any{"name": "gra"})
+
+	expected := "{\n    \"name\": \"gra\"\n}\n"
+	if w.Body.String() != expected {
+		t.Errorf(errResponseValue, expected, w.Body.String())
+	}
+	if contentType := w.Header().Get(headerContentType); contentType != contentTypeJSON {
+		t.Errorf(errContentType, contentType)
+	}
+}
+
+func TestPureJSON(t *testing.T) {
+	data := map[string]string{"html": "<b>Tom & Jerry</b>"}
+
+	w := httptest.NewRecorder()
+	New(w, httptest.NewRequest("GET", "/test", nil)).PureJSON(http.StatusOK, data)
+	if !strings.Contains(w.Body.String(), "<b>Tom & Jerry</b>") {
+		t.Errorf(errResponseValue, "unescaped HTML", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	New(w, httptest.NewRequest("GET", "/test", nil)).JSON(http.StatusOK, data)
+	if !strings.Contains(w.Body.String(), `\u003cb\u003e`) {
+		t.Errorf(errResponseValue, "escaped HTML", w.Body.String())
+	}
+}
+
 func TestJSONComplex(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/test", nil)
@@ -313,6 +348,27 @@ func TestSuccess(t *testing.T) {
 	}
 }
 
+func TestSuccessResponseTransform(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/test", nil)
+	c := New(w, r)
+
+	Set(c, ResponseTransformKey, func(data any) any {
+		return map[string]any{"legacy": data}
+	})
+	c.Success(http.StatusOK, "Success message", "value")
+
+	var response APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf(errUnmarshalResponse, err)
+	}
+
+	expected := map[string]any{"legacy": "value"}
+	if !reflect.DeepEqual(response.Data, expected) {
+		t.Errorf(errResponseValue, expected, response.Data)
+	}
+}
+
 func TestError(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/test", nil)
@@ -548,3 +604,65 @@ func TestJSONDataEncodingError(t *testing.T) {
 		t.Errorf(errStatusCode, http.StatusCreated, w.code)
 	}
 }
+
+func TestRequestContext(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	if c.RequestContext() != c.Request.Context() {
+		t.Error("Expected RequestContext to return the request's context")
+	}
+
+	cancel := c.WithTimeout(time.Minute)
+	defer cancel()
+
+	if _, ok := c.Deadline(); !ok {
+		t.Error("Expected a deadline after WithTimeout")
+	}
+	if _, ok := c.Request.Context().Deadline(); !ok {
+		t.Error("Expected the request to carry the new context")
+	}
+
+	// Values set before and after replacing the context remain visible
+	c.WithValue("key", "value")
+	cancel()
+	if c.Value("key") != "value" {
+		t.Errorf(errExpectedValue, "value", c.Value("key"))
+	}
+	if !errors.Is(c.Err(), stdcontext.Canceled) {
+		t.Errorf(errResponseValue, stdcontext.Canceled, c.Err())
+	}
+
+	// *Context satisfies context.Context so it can be passed to database calls directly
+	var _ stdcontext.Context = c
+}
+
+func TestSetRequestContext(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	deadline := time.Now().Add(time.Hour)
+
+	ctx, cancel := stdcontext.WithDeadline(stdcontext.Background(), deadline)
+	defer cancel()
+	c.SetRequestContext(ctx)
+
+	if got, _ := c.RequestContext().Deadline(); !got.Equal(deadline) {
+		t.Errorf(errResponseValue, deadline, got)
+	}
+	if c.Done() != ctx.Done() {
+		t.Error("Expected Done to use the replaced context")
+	}
+}
+
+func TestLogger(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+
+	l := c.Logger()
+	if l == nil || c.Logger() != l {
+		t.Fatal("Expected Logger to return the same request-scoped logger")
+	}
+
+	child := l.With("request_id", "abc")
+	c.SetLogger(child)
+	if c.Logger() != child {
+		t.Error("Expected SetLogger to replace the request logger")
+	}
+}
@@ -0,0 +1,245 @@
+package context
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Cookie errors
+var (
+	// ErrCookieKeyRingNotSet is returned when signed or encrypted cookies are used without a key ring
+	ErrCookieKeyRingNotSet = errors.New("cookie key ring not configured")
+	// ErrInvalidCookie is returned when a signed or encrypted cookie fails verification
+	ErrInvalidCookie = errors.New("invalid cookie value")
+)
+
+// CookieOptions configures the attributes of a cookie set by the cookie helpers
+type CookieOptions struct {
+	Path     string
+	Domain   string
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// DefaultCookieOptions returns options suitable for session cookies
+func DefaultCookieOptions() CookieOptions {
+	return CookieOptions{
+		Path:     "/",
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// CookieKeyRing holds the secrets used to sign and encrypt cookies.
+// The first key is used for new cookies; all keys are accepted when reading,
+// so secrets can be rotated by prepending a new key.
+type CookieKeyRing struct {
+	signing    [][]byte
+	encryption []cipher.AEAD
+}
+
+// NewCookieKeyRing creates a key ring from one or more secrets, newest first
+func NewCookieKeyRing(secrets ...[]byte) (*CookieKeyRing, error) {
+	if len(secrets) == 0 {
+		return nil, errors.New("cookie key ring requires at least one secret")
+	}
+
+	ring := &CookieKeyRing{}
+	for _, secret := range secrets {
+		if len(secret) == 0 {
+			return nil, errors.New("cookie secrets must not be empty")
+		}
+
+		ring.signing = append(ring.signing, deriveCookieKey("sign", secret))
+
+		block, err := aes.NewCipher(deriveCookieKey("encrypt", secret))
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		ring.encryption = append(ring.encryption, aead)
+	}
+
+	return ring, nil
+}
+
+// deriveCookieKey derives a 32-byte key for a given purpose from a secret
+func deriveCookieKey(purpose string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("gra-cookie-" + purpose))
+	return mac.Sum(nil)
+}
+
+// Sign returns value with an HMAC bound to the cookie name
+func (k *CookieKeyRing) Sign(name, value string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(value))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(cookieMAC(k.signing[0], name, payload))
+}
+
+// Verify checks a signed cookie value against every key and returns the original value
+func (k *CookieKeyRing) Verify(name, signed string) (string, error) {
+	payload, sig, ok := strings.Cut(signed, ".")
+	if !ok {
+		return "", ErrInvalidCookie
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+
+	for _, key := range k.signing {
+		if hmac.Equal(mac, cookieMAC(key, name, payload)) {
+			value, err := base64.RawURLEncoding.DecodeString(payload)
+			if err != nil {
+				return "", ErrInvalidCookie
+			}
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+// cookieMAC computes the HMAC of a cookie name and payload
+func cookieMAC(key []byte, name, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name + "|" + payload))
+	return mac.Sum(nil)
+}
+
+// Encrypt encrypts value with AES-GCM, using the cookie name as additional data
+func (k *CookieKeyRing) Encrypt(name, value string) (string, error) {
+	aead := k.encryption[0]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a cookie value produced by Encrypt, trying every key
+func (k *CookieKeyRing) Decrypt(name, encrypted string) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+
+	for _, aead := range k.encryption {
+		if len(sealed) < aead.NonceSize() {
+			return "", ErrInvalidCookie
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if value, err := aead.Open(nil, nonce, ciphertext, []byte(name)); err == nil {
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+var (
+	cookieKeyRing   *CookieKeyRing
+	cookieKeyRingMu sync.RWMutex
+)
+
+// SetCookieKeyRing sets the key ring used by the signed and encrypted cookie helpers
+func SetCookieKeyRing(ring *CookieKeyRing) {
+	cookieKeyRingMu.Lock()
+	defer cookieKeyRingMu.Unlock()
+	cookieKeyRing = ring
+}
+
+// getCookieKeyRing returns the configured key ring
+func getCookieKeyRing() (*CookieKeyRing, error) {
+	cookieKeyRingMu.RLock()
+	defer cookieKeyRingMu.RUnlock()
+	if cookieKeyRing == nil {
+		return nil, ErrCookieKeyRingNotSet
+	}
+	return cookieKeyRing, nil
+}
+
+// SetCookieWithOptions sets a cookie in the response using the given options
+func (c *Context) SetCookieWithOptions(name, value string, opts CookieOptions) *Context {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     opts.Path,
+		Domain:   opts.Domain,
+		MaxAge:   opts.MaxAge,
+		Secure:   opts.Secure,
+		HttpOnly: opts.HttpOnly,
+		SameSite: opts.SameSite,
+	})
+	return c
+}
+
+// DeleteCookie expires a cookie on the client
+func (c *Context) DeleteCookie(name string, opts CookieOptions) *Context {
+	opts.MaxAge = -1
+	return c.SetCookieWithOptions(name, "", opts)
+}
+
+// SetSignedCookie sets a cookie whose value is signed with the configured key ring.
+// The value stays readable by the client but cannot be modified.
+func (c *Context) SetSignedCookie(name, value string, opts CookieOptions) error {
+	ring, err := getCookieKeyRing()
+	if err != nil {
+		return err
+	}
+	c.SetCookieWithOptions(name, ring.Sign(name, value), opts)
+	return nil
+}
+
+// GetSignedCookie gets a signed cookie and verifies its signature
+func (c *Context) GetSignedCookie(name string) (string, error) {
+	ring, err := getCookieKeyRing()
+	if err != nil {
+		return "", err
+	}
+	value, err := c.GetCookie(name)
+	if err != nil {
+		return "", err
+	}
+	return ring.Verify(name, value)
+}
+
+// SetEncryptedCookie sets a cookie whose value is encrypted and authenticated with the configured key ring
+func (c *Context) SetEncryptedCookie(name, value string, opts CookieOptions) error {
+	ring, err := getCookieKeyRing()
+	if err != nil {
+		return err
+	}
+	encrypted, err := ring.Encrypt(name, value)
+	if err != nil {
+		return err
+	}
+	c.SetCookieWithOptions(name, encrypted, opts)
+	return nil
+}
+
+// GetEncryptedCookie gets and decrypts an encrypted cookie
+func (c *Context) GetEncryptedCookie(name string) (string, error) {
+	ring, err := getCookieKeyRing()
+	if err != nil {
+		return "", err
+	}
+	value, err := c.GetCookie(name)
+	if err != nil {
+		return "", err
+	}
+	return ring.Decrypt(name, value)
+}
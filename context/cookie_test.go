@@ -0,0 +1,137 @@
+package context
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const errCookieValue = "Expected cookie value %q, got %q (err: %v)"
+
+// roundTripCookies returns a context whose request carries the cookies set on w
+func roundTripCookies(w *httptest.ResponseRecorder) *Context {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	return New(httptest.NewRecorder(), r)
+}
+
+func setTestKeyRing(t *testing.T, secrets ...string) *CookieKeyRing {
+	t.Helper()
+	keys := make([][]byte, len(secrets))
+	for i, secret := range secrets {
+		keys[i] = []byte(secret)
+	}
+	ring, err := NewCookieKeyRing(keys...)
+	if err != nil {
+		t.Fatalf("Failed to create key ring: %v", err)
+	}
+	SetCookieKeyRing(ring)
+	t.Cleanup(func() { SetCookieKeyRing(nil) })
+	return ring
+}
+
+func TestSignedCookie(t *testing.T) {
+	setTestKeyRing(t, "secret-1")
+
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.SetSignedCookie("session", "user:42", DefaultCookieOptions()); err != nil {
+		t.Fatalf("SetSignedCookie returned error: %v", err)
+	}
+
+	header := w.Header().Get("Set-Cookie")
+	if !strings.Contains(header, "HttpOnly") || !strings.Contains(header, "SameSite=Lax") {
+		t.Errorf(errResponseValue, "HttpOnly; SameSite=Lax", header)
+	}
+
+	value, err := roundTripCookies(w).GetSignedCookie("session")
+	if err != nil || value != "user:42" {
+		t.Errorf(errCookieValue, "user:42", value, err)
+	}
+}
+
+func TestSignedCookieTampered(t *testing.T) {
+	ring := setTestKeyRing(t, "secret-1")
+
+	signed := ring.Sign("session", "user:42")
+	forged := ring.Sign("session", "user:1")
+	payload, _, _ := strings.Cut(forged, ".")
+	_, sig, _ := strings.Cut(signed, ".")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: payload + "." + sig})
+	c := New(httptest.NewRecorder(), r)
+
+	if _, err := c.GetSignedCookie("session"); !errors.Is(err, ErrInvalidCookie) {
+		t.Errorf(errWrongErrorType, ErrInvalidCookie, err)
+	}
+
+	// A valid signature for another cookie name must be rejected
+	if _, err := ring.Verify("other", signed); !errors.Is(err, ErrInvalidCookie) {
+		t.Errorf(errWrongErrorType, ErrInvalidCookie, err)
+	}
+}
+
+func TestCookieKeyRotation(t *testing.T) {
+	old := setTestKeyRing(t, "old-secret")
+	signed := old.Sign("session", "user:42")
+	encrypted, err := old.Encrypt("flash", "saved")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setTestKeyRing(t, "new-secret", "old-secret")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: signed})
+	r.AddCookie(&http.Cookie{Name: "flash", Value: encrypted})
+	c := New(httptest.NewRecorder(), r)
+
+	if value, err := c.GetSignedCookie("session"); err != nil || value != "user:42" {
+		t.Errorf(errCookieValue, "user:42", value, err)
+	}
+	if value, err := c.GetEncryptedCookie("flash"); err != nil || value != "saved" {
+		t.Errorf(errCookieValue, "saved", value, err)
+	}
+}
+
+func TestEncryptedCookie(t *testing.T) {
+	setTestKeyRing(t, "secret-1")
+
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.SetEncryptedCookie("flash", "Profile saved", DefaultCookieOptions()); err != nil {
+		t.Fatalf("SetEncryptedCookie returned error: %v", err)
+	}
+
+	if strings.Contains(w.Header().Get("Set-Cookie"), "Profile") {
+		t.Error("Expected cookie value to be encrypted")
+	}
+
+	value, err := roundTripCookies(w).GetEncryptedCookie("flash")
+	if err != nil || value != "Profile saved" {
+		t.Errorf(errCookieValue, "Profile saved", value, err)
+	}
+}
+
+func TestCookieKeyRingNotSet(t *testing.T) {
+	SetCookieKeyRing(nil)
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if err := c.SetSignedCookie("session", "x", DefaultCookieOptions()); !errors.Is(err, ErrCookieKeyRingNotSet) {
+		t.Errorf(errWrongErrorType, ErrCookieKeyRingNotSet, err)
+	}
+}
+
+func TestDeleteCookie(t *testing.T) {
+	w := httptest.NewRecorder()
+	New(w, httptest.NewRequest(http.MethodGet, "/", nil)).DeleteCookie("session", CookieOptions{Path: "/"})
+
+	if header := w.Header().Get("Set-Cookie"); !strings.Contains(header, "Max-Age=0") {
+		t.Errorf(errResponseValue, "Max-Age=0", header)
+	}
+}
@@ -0,0 +1,68 @@
+package context
+
+import (
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path/filepath"
+)
+
+// HeaderContentDisposition is the Content-Disposition header name
+const HeaderContentDisposition = "Content-Disposition"
+
+// File serves a file from disk. Range and conditional requests are supported.
+// A 404 response is written if the file does not exist.
+func (c *Context) File(path string) error {
+	return c.FileFromFS(filepath.Base(path), http.Dir(filepath.Dir(path)))
+}
+
+// FileFromFS serves a file from an http.FileSystem, such as http.FS(embedFS).
+// Directories are not listed.
+func (c *Context) FileFromFS(path string, fsys http.FileSystem) error {
+	f, err := fsys.Open(path)
+	if err != nil {
+		c.fileError(err)
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		c.fileError(err)
+		return err
+	}
+	if info.IsDir() {
+		c.fileError(fs.ErrNotExist)
+		return fs.ErrNotExist
+	}
+
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), f)
+	return nil
+}
+
+// Attachment serves a file from disk as a download with the given file name
+func (c *Context) Attachment(path, filename string) error {
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	c.SetHeader(HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	return c.File(path)
+}
+
+// fileError writes the error response for a file that could not be served
+func (c *Context) fileError(err error) {
+	// Content-Disposition only makes sense for a successful download
+	c.Writer.Header().Del(HeaderContentDisposition)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		c.Error(http.StatusNotFound, "File not found")
+	case errors.Is(err, fs.ErrPermission):
+		c.Error(http.StatusForbidden, "Access denied")
+	default:
+		c.Error(http.StatusInternalServerError, "Failed to read file")
+	}
+}
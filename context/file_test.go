@@ -0,0 +1,111 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const exportCSV = "id,name\n1,John\n2,Jane\n"
+
+func writeExport(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.csv")
+	if err := os.WriteFile(path, []byte(exportCSV), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFile(t *testing.T) {
+	path := writeExport(t)
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	if err := c.File(path); err != nil {
+		t.Fatalf("File returned error: %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf(errStatusCode, http.StatusOK, w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get(HeaderContentType), "text/csv") {
+		t.Errorf(errResponseValue, "text/csv", w.Header().Get(HeaderContentType))
+	}
+	if w.Body.String() != exportCSV {
+		t.Errorf(errResponseValue, exportCSV, w.Body.String())
+	}
+}
+
+func TestFileRange(t *testing.T) {
+	path := writeExport(t)
+	r := httptest.NewRequest(http.MethodGet, "/export", nil)
+	r.Header.Set("Range", "bytes=0-6")
+	w := httptest.NewRecorder()
+
+	if err := New(w, r).File(path); err != nil {
+		t.Fatalf("File returned error: %v", err)
+	}
+
+	if w.Code != http.StatusPartialContent {
+		t.Errorf(errStatusCode, http.StatusPartialContent, w.Code)
+	}
+	if w.Body.String() != "id,name" {
+		t.Errorf(errResponseValue, "id,name", w.Body.String())
+	}
+}
+
+func TestFileNotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	if err := c.Attachment(filepath.Join(t.TempDir(), "missing.csv"), "export.csv"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+	if w.Code != http.StatusNotFound {
+		t.Errorf(errStatusCode, http.StatusNotFound, w.Code)
+	}
+	if w.Header().Get(HeaderContentDisposition) != "" {
+		t.Error("Expected Content-Disposition to be removed on error")
+	}
+}
+
+func TestAttachment(t *testing.T) {
+	path := writeExport(t)
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+	if err := c.Attachment(path, "users 2024.csv"); err != nil {
+		t.Fatalf("Attachment returned error: %v", err)
+	}
+
+	expected := `attachment; filename="users 2024.csv"`
+	if cd := w.Header().Get(HeaderContentDisposition); cd != expected {
+		t.Errorf(errResponseValue, expected, cd)
+	}
+}
+
+func TestFileFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/001_init.sql": {Data: []byte("CREATE TABLE users (id INTEGER);")},
+	}
+
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.FileFromFS("migrations/001_init.sql", http.FS(fsys)); err != nil {
+		t.Fatalf("FileFromFS returned error: %v", err)
+	}
+	if !strings.HasPrefix(w.Body.String(), "CREATE TABLE") {
+		t.Errorf(errResponseValue, "CREATE TABLE...", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	c = New(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.FileFromFS("migrations", http.FS(fsys)); err == nil || w.Code != http.StatusNotFound {
+		t.Errorf(errStatusCode, http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,19 @@
+package context
+
+// Translator translates message keys for a locale, e.g. *i18n.Bundle
+type Translator interface {
+	Translate(locale, key string, args ...any) string
+}
+
+// TranslatorKey is the typed key under which the i18n middleware stores its Translator
+var TranslatorKey = NewKey[Translator]("translator")
+
+// T translates key into the request locale using the Translator set by the i18n
+// middleware. Without a Translator the key is returned unchanged.
+func (c *Context) T(key string, args ...any) string {
+	translator, ok := Get(c, TranslatorKey)
+	if !ok {
+		return key
+	}
+	return translator.Translate(c.Locale(), key, args...)
+}
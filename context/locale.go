@@ -0,0 +1,123 @@
+package context
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HeaderAcceptLanguage is the Accept-Language header name
+const HeaderAcceptLanguage = "Accept-Language"
+
+// LocaleKey is the typed key under which Locale stores the negotiated locale
+var LocaleKey = NewKey[string]("locale")
+
+// LanguagePreference is a language range from an Accept-Language header
+type LanguagePreference struct {
+	Tag     string
+	Quality float64
+}
+
+// ParseAcceptLanguage parses an Accept-Language header into preferences ordered by
+// descending quality. Ranges with q=0 or malformed q-values are dropped.
+func ParseAcceptLanguage(header string) []LanguagePreference {
+	var prefs []LanguagePreference
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		valid := true
+		for _, param := range fields[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			quality = q
+		}
+		if !valid || quality == 0 {
+			continue
+		}
+
+		prefs = append(prefs, LanguagePreference{Tag: tag, Quality: quality})
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].Quality > prefs[j].Quality
+	})
+	return prefs
+}
+
+// Locale negotiates the best locale from the Accept-Language header among the
+// supported locales and stores it in the context under LocaleKey. The first
+// supported locale is the fallback when nothing matches. Called without
+// arguments, it returns the locale already stored (for example by the i18n
+// middleware) or the client's top preference.
+func (c *Context) Locale(supported ...string) string {
+	if len(supported) == 0 {
+		if locale, ok := Get(c, LocaleKey); ok {
+			return locale
+		}
+	}
+
+	locale := MatchLocale(ParseAcceptLanguage(c.GetHeader(HeaderAcceptLanguage)), supported)
+	if locale != "" {
+		Set(c, LocaleKey, locale)
+	}
+	return locale
+}
+
+// MatchLocale returns the supported locale that best satisfies the preferences.
+// An exact match wins over a match on the base language (e.g. "en-US" and "en").
+// With no supported locales the top preference is returned as is.
+func MatchLocale(prefs []LanguagePreference, supported []string) string {
+	if len(supported) == 0 {
+		if len(prefs) > 0 && prefs[0].Tag != "*" {
+			return prefs[0].Tag
+		}
+		return ""
+	}
+
+	for _, pref := range prefs {
+		if pref.Tag == "*" {
+			return supported[0]
+		}
+		if match := matchTag(pref.Tag, supported); match != "" {
+			return match
+		}
+	}
+	return supported[0]
+}
+
+// matchTag matches a single language range against the supported locales
+func matchTag(tag string, supported []string) string {
+	for _, locale := range supported {
+		if strings.EqualFold(tag, locale) {
+			return locale
+		}
+	}
+
+	base := baseLanguage(tag)
+	for _, locale := range supported {
+		if strings.EqualFold(base, baseLanguage(locale)) {
+			return locale
+		}
+	}
+	return ""
+}
+
+// baseLanguage returns the primary language subtag, e.g. "pt" for "pt-BR"
+func baseLanguage(tag string) string {
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
@@ -0,0 +1,85 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	prefs := ParseAcceptLanguage("fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5, es;q=0, it;q=abc")
+
+	expected := []LanguagePreference{
+		{"fr-CH", 1}, {"fr", 0.9}, {"en", 0.8}, {"de", 0.7}, {"*", 0.5},
+	}
+	if !reflect.DeepEqual(prefs, expected) {
+		t.Errorf(errResponseValue, expected, prefs)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	testCases := []struct {
+		name      string
+		header    string
+		supported []string
+		expected  string
+	}{
+		{"Exact match", "id-ID,en;q=0.8", []string{"en", "id-ID"}, "id-ID"},
+		{"Quality order", "en;q=0.5,id;q=0.9", []string{"en", "id"}, "id"},
+		{"Base language", "en-US", []string{"id", "en"}, "en"},
+		{"Regional supported", "pt", []string{"en", "pt-BR"}, "pt-BR"},
+		{"Fallback", "ja", []string{"en", "id"}, "en"},
+		{"Wildcard", "ja, *;q=0.1", []string{"id", "en"}, "id"},
+		{"Missing header", "", []string{"en"}, "en"},
+		{"No supported list", "de-DE,en;q=0.5", nil, "de-DE"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				r.Header.Set(HeaderAcceptLanguage, tc.header)
+			}
+			c := New(httptest.NewRecorder(), r)
+
+			if locale := c.Locale(tc.supported...); locale != tc.expected {
+				t.Errorf(errResponseValue, tc.expected, locale)
+			}
+			if stored, _ := Get(c, LocaleKey); stored != tc.expected {
+				t.Errorf(errResponseValue, tc.expected, stored)
+			}
+		})
+	}
+}
+
+func TestLocaleReturnsStoredValue(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(HeaderAcceptLanguage, "en")
+	c := New(httptest.NewRecorder(), r)
+
+	Set(c, LocaleKey, "id")
+	if locale := c.Locale(); locale != "id" {
+		t.Errorf(errResponseValue, "id", locale)
+	}
+}
+
+type upperTranslator struct{}
+
+func (upperTranslator) Translate(locale, key string, _ ...any) string {
+	return locale + ":" + strings.ToUpper(key)
+}
+
+func TestT(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := c.T("welcome"); got != "welcome" {
+		t.Errorf(errResponseValue, "welcome", got)
+	}
+
+	Set(c, LocaleKey, "id")
+	Set[Translator](c, TranslatorKey, upperTranslator{})
+	if got := c.T("welcome"); got != "id:WELCOME" {
+		t.Errorf(errResponseValue, "id:WELCOME", got)
+	}
+}
@@ -0,0 +1,179 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Pagination query parameter names
+const (
+	QueryPage    = "page"
+	QueryPerPage = "per_page"
+	QueryLimit   = "limit"
+	QueryOffset  = "offset"
+	QueryCursor  = "cursor"
+)
+
+// HeaderTotalCount is the response header carrying the total number of items
+const HeaderTotalCount = "X-Total-Count"
+
+// ErrInvalidPagination is returned when pagination query parameters are malformed
+var ErrInvalidPagination = errors.New("invalid pagination parameters")
+
+// PaginationConfig holds the defaults and bounds used when parsing pagination parameters
+type PaginationConfig struct {
+	DefaultPerPage int // Page size used when none is requested
+	MaxPerPage     int // Upper bound for the requested page size
+}
+
+// DefaultPaginationConfig returns the default pagination configuration
+func DefaultPaginationConfig() PaginationConfig {
+	return PaginationConfig{
+		DefaultPerPage: 20,
+		MaxPerPage:     100,
+	}
+}
+
+// Pagination is a parsed pagination request.
+// Offset and PerPage map directly to the ORM Skip and Take methods.
+type Pagination struct {
+	Page    int    `json:"page"`
+	PerPage int    `json:"per_page"`
+	Offset  int    `json:"offset"`
+	Cursor  string `json:"cursor,omitempty"`
+}
+
+// PageMeta describes the position of a page within a result set
+type PageMeta struct {
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+	HasPrev    bool  `json:"has_prev"`
+}
+
+// PagedResponse is the response written by SuccessPaged
+type PagedResponse struct {
+	Status     string   `json:"status"`
+	Data       any      `json:"data"`
+	Pagination PageMeta `json:"pagination"`
+}
+
+// Pagination parses page/per_page or limit/offset query parameters, plus an optional
+// cursor. Missing values fall back to the config defaults and the page size is capped
+// at MaxPerPage. Malformed or negative values return ErrInvalidPagination.
+func (c *Context) Pagination(config PaginationConfig) (Pagination, error) {
+	if config.DefaultPerPage <= 0 {
+		config.DefaultPerPage = DefaultPaginationConfig().DefaultPerPage
+	}
+
+	query := c.Request.URL.Query()
+	p := Pagination{Page: 1, PerPage: config.DefaultPerPage, Cursor: query.Get(QueryCursor)}
+
+	perPageParam := QueryPerPage
+	if !query.Has(QueryPerPage) && query.Has(QueryLimit) {
+		perPageParam = QueryLimit
+	}
+
+	var err error
+	if p.PerPage, err = positiveQueryInt(query, perPageParam, p.PerPage, 1); err != nil {
+		return Pagination{}, err
+	}
+	if config.MaxPerPage > 0 && p.PerPage > config.MaxPerPage {
+		p.PerPage = config.MaxPerPage
+	}
+
+	if query.Has(QueryOffset) && !query.Has(QueryPage) {
+		if p.Offset, err = positiveQueryInt(query, QueryOffset, 0, 0); err != nil {
+			return Pagination{}, err
+		}
+		p.Page = p.Offset/p.PerPage + 1
+		return p, nil
+	}
+
+	if p.Page, err = positiveQueryInt(query, QueryPage, 1, 1); err != nil {
+		return Pagination{}, err
+	}
+	p.Offset = (p.Page - 1) * p.PerPage
+	return p, nil
+}
+
+// positiveQueryInt parses an integer query parameter that must be at least minValue
+func positiveQueryInt(query url.Values, name string, fallback, minValue int) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < minValue {
+		return 0, fmt.Errorf("%w: %s must be an integer >= %d", ErrInvalidPagination, name, minValue)
+	}
+	return n, nil
+}
+
+// Meta builds the pagination metadata for a result set with the given total
+func (p Pagination) Meta(total int64) PageMeta {
+	totalPages := 0
+	if p.PerPage > 0 {
+		totalPages = int((total + int64(p.PerPage) - 1) / int64(p.PerPage))
+	}
+	return PageMeta{
+		Page:       p.Page,
+		PerPage:    p.PerPage,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    int64(p.Offset+p.PerPage) < total,
+		HasPrev:    p.Offset > 0,
+	}
+}
+
+// SuccessPaged sends a page of results with pagination metadata, an X-Total-Count
+// header and RFC 8288 Link headers for the first, previous, next and last pages
+func (c *Context) SuccessPaged(data any, total int64, page Pagination) {
+	meta := page.Meta(total)
+
+	c.SetHeader(HeaderTotalCount, strconv.FormatInt(total, 10))
+	if links := c.paginationLinks(meta); links != "" {
+		c.SetHeader("Link", links)
+	}
+
+	c.JSON(http.StatusOK, PagedResponse{
+		Status:     "success",
+		Data:       c.transformData(data),
+		Pagination: meta,
+	})
+}
+
+// paginationLinks builds the Link header value for the given page metadata
+func (c *Context) paginationLinks(meta PageMeta) string {
+	if meta.TotalPages == 0 {
+		return ""
+	}
+
+	link := func(page int, rel string) string {
+		u := *c.Request.URL
+		query := u.Query()
+		query.Del(QueryLimit)
+		query.Del(QueryOffset)
+		query.Set(QueryPage, strconv.Itoa(page))
+		query.Set(QueryPerPage, strconv.Itoa(meta.PerPage))
+		u.RawQuery = query.Encode()
+		return fmt.Sprintf("<%s>; rel=%q", u.RequestURI(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if meta.HasPrev {
+		links = append(links, link(max(meta.Page-1, 1), "prev"))
+	}
+	if meta.HasNext {
+		links = append(links, link(meta.Page+1, "next"))
+	}
+	links = append(links, link(meta.TotalPages, "last"))
+
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,95 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPagination(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected Pagination
+	}{
+		{"Defaults", "", Pagination{Page: 1, PerPage: 20, Offset: 0}},
+		{"Page", "?page=3&per_page=10", Pagination{Page: 3, PerPage: 10, Offset: 20}},
+		{"Capped", "?per_page=1000", Pagination{Page: 1, PerPage: 100, Offset: 0}},
+		{"LimitOffset", "?limit=25&offset=50", Pagination{Page: 3, PerPage: 25, Offset: 50}},
+		{"Cursor", "?cursor=abc&limit=5", Pagination{Page: 1, PerPage: 5, Cursor: "abc"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users"+tc.query, nil))
+
+			p, err := c.Pagination(DefaultPaginationConfig())
+			if err != nil {
+				t.Fatalf("Pagination returned error: %v", err)
+			}
+			if p != tc.expected {
+				t.Errorf(errResponseValue, tc.expected, p)
+			}
+		})
+	}
+}
+
+func TestPaginationInvalid(t *testing.T) {
+	for _, query := range []string{"?page=0", "?page=abc", "?per_page=-1", "?offset=-5"} {
+		c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users"+query, nil))
+		if _, err := c.Pagination(DefaultPaginationConfig()); !errors.Is(err, ErrInvalidPagination) {
+			t.Errorf("%s: "+errWrongErrorType, query, ErrInvalidPagination, err)
+		}
+	}
+}
+
+func TestSuccessPaged(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/users?page=2&per_page=10&sort=name", nil))
+
+	p, err := c.Pagination(DefaultPaginationConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SuccessPaged([]string{"a", "b"}, 35, p)
+
+	if total := w.Header().Get(HeaderTotalCount); total != "35" {
+		t.Errorf(errResponseValue, "35", total)
+	}
+
+	link := w.Header().Get("Link")
+	for _, expected := range []string{
+		`</users?page=1&per_page=10&sort=name>; rel="first"`,
+		`</users?page=1&per_page=10&sort=name>; rel="prev"`,
+		`</users?page=3&per_page=10&sort=name>; rel="next"`,
+		`</users?page=4&per_page=10&sort=name>; rel="last"`,
+	} {
+		if !strings.Contains(link, expected) {
+			t.Errorf(errResponseValue, expected, link)
+		}
+	}
+
+	var resp PagedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(errUnmarshalResponse, err)
+	}
+	expected := PageMeta{Page: 2, PerPage: 10, Total: 35, TotalPages: 4, HasNext: true, HasPrev: true}
+	if resp.Pagination != expected {
+		t.Errorf(errResponseValue, expected, resp.Pagination)
+	}
+}
+
+func TestSuccessPagedLastPage(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/users?page=4&per_page=10", nil))
+
+	p, _ := c.Pagination(DefaultPaginationConfig())
+	c.SuccessPaged([]string{}, 35, p)
+
+	if link := w.Header().Get("Link"); strings.Contains(link, `rel="next"`) {
+		t.Errorf("Expected no next link on the last page, got %s", link)
+	}
+}
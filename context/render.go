@@ -0,0 +1,33 @@
+package context
+
+import (
+	"log"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// YAML sends a YAML response
+func (c *Context) YAML(status int, obj any) {
+	c.Writer.Header().Set(HeaderContentType, ContentTypeYAML)
+	c.Writer.WriteHeader(status)
+
+	encoder := yaml.NewEncoder(c.Writer)
+	if err := encoder.Encode(obj); err != nil {
+		log.Printf("Error encoding YAML: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		log.Printf("Error encoding YAML: %v", err)
+	}
+}
+
+// TOML sends a TOML response. The value must encode to a TOML table,
+// i.e. be a struct or a map with string keys.
+func (c *Context) TOML(status int, obj any) {
+	c.Writer.Header().Set(HeaderContentType, ContentTypeTOML)
+	c.Writer.WriteHeader(status)
+
+	if err := toml.NewEncoder(c.Writer).Encode(obj); err != nil {
+		log.Printf("Error encoding TOML: %v", err)
+	}
+}
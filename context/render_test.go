@@ -0,0 +1,64 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type renderPayload struct {
+	Name  string   `yaml:"name" toml:"name"`
+	Count int      `yaml:"count" toml:"count"`
+	Tags  []string `yaml:"tags" toml:"tags"`
+}
+
+func TestYAML(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	c.YAML(http.StatusOK, renderPayload{Name: "gra", Count: 2, Tags: []string{"a", "b"}})
+
+	if w.Code != http.StatusOK {
+		t.Errorf(errStatusCode, http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get(headerContentType); ct != ContentTypeYAML {
+		t.Errorf(errResponseValue, ContentTypeYAML, ct)
+	}
+
+	var result renderPayload
+	if err := yaml.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf(errUnmarshalResponse, err)
+	}
+	if result.Name != "gra" || result.Count != 2 || len(result.Tags) != 2 {
+		t.Errorf(errResponseValue, "gra/2/[a b]", result)
+	}
+}
+
+func TestTOML(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	c.TOML(http.StatusCreated, renderPayload{Name: "gra", Count: 2})
+
+	if w.Code != http.StatusCreated {
+		t.Errorf(errStatusCode, http.StatusCreated, w.Code)
+	}
+	if ct := w.Header().Get(headerContentType); ct != ContentTypeTOML {
+		t.Errorf(errResponseValue, ContentTypeTOML, ct)
+	}
+	if !strings.Contains(w.Body.String(), `name = "gra"`) {
+		t.Errorf(errResponseValue, `name = "gra"`, w.Body.String())
+	}
+
+	var result renderPayload
+	if _, err := toml.Decode(w.Body.String(), &result); err != nil {
+		t.Fatalf(errUnmarshalResponse, err)
+	}
+	if result.Count != 2 {
+		t.Errorf(errResponseValue, 2, result.Count)
+	}
+}
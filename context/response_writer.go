@@ -0,0 +1,103 @@
+package context
+
+import (
+	"net/http"
+)
+
+// ResponseWriter wraps an http.ResponseWriter and records the status code and the
+// number of body bytes written. The router wraps every response with it so that
+// middleware can read c.StatusCode() and c.ResponseSize() after the handler runs.
+type ResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+// NewResponseWriter wraps w, returning w itself if it is already a *ResponseWriter
+func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	if rw, ok := w.(*ResponseWriter); ok {
+		return rw
+	}
+	return &ResponseWriter{ResponseWriter: w}
+}
+
+// WriteHeader records and sends the status code
+func (w *ResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written, sending a 200 status first if needed
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Status returns the status code sent, or 0 if nothing has been written yet
+func (w *ResponseWriter) Status() int {
+	return w.status
+}
+
+// Size returns the number of body bytes written
+func (w *ResponseWriter) Size() int64 {
+	return w.size
+}
+
+// Written reports whether the status code has been sent
+func (w *ResponseWriter) Written() bool {
+	return w.wroteHeader
+}
+
+// Unwrap returns the underlying ResponseWriter so http.ResponseController can reach it
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// responseRecorder finds the *ResponseWriter in c.Writer's chain of wrappers
+func (c *Context) responseRecorder() *ResponseWriter {
+	w := c.Writer
+	for w != nil {
+		if rw, ok := w.(*ResponseWriter); ok {
+			return rw
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = u.Unwrap()
+	}
+	return nil
+}
+
+// StatusCode returns the response status code sent so far, or 0 if no response has
+// been written or the writer is not wrapped in a ResponseWriter
+func (c *Context) StatusCode() int {
+	if rw := c.responseRecorder(); rw != nil {
+		return rw.Status()
+	}
+	return 0
+}
+
+// ResponseSize returns the number of response body bytes written so far
+func (c *Context) ResponseSize() int64 {
+	if rw := c.responseRecorder(); rw != nil {
+		return rw.Size()
+	}
+	return 0
+}
+
+// Written reports whether a response status has already been sent
+func (c *Context) Written() bool {
+	if rw := c.responseRecorder(); rw != nil {
+		return rw.Written()
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// wrappingWriter mimics middleware that wraps the response writer
+type wrappingWriter struct {
+	http.ResponseWriter
+}
+
+func (w *wrappingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func TestResponseWriterRecordsStatusAndSize(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := New(NewResponseWriter(rec), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if c.Written() || c.StatusCode() != 0 {
+		t.Errorf(errStatusCode, 0, c.StatusCode())
+	}
+
+	c.JSON(http.StatusCreated, map[string]string{"id": "1"})
+
+	if c.StatusCode() != http.StatusCreated {
+		t.Errorf(errStatusCode, http.StatusCreated, c.StatusCode())
+	}
+	if c.ResponseSize() != int64(rec.Body.Len()) {
+		t.Errorf(errResponseValue, rec.Body.Len(), c.ResponseSize())
+	}
+	if !c.Written() {
+		t.Error("Expected Written to be true")
+	}
+}
+
+func TestResponseWriterImplicitStatus(t *testing.T) {
+	rw := NewResponseWriter(httptest.NewRecorder())
+	if NewResponseWriter(rw) != rw {
+		t.Error("Expected NewResponseWriter not to double wrap")
+	}
+
+	// Middleware wrapping the writer must not hide the recorder
+	c := New(&wrappingWriter{rw}, httptest.NewRequest(http.MethodGet, "/", nil))
+	if _, err := c.Writer.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.StatusCode() != http.StatusOK || c.ResponseSize() != 5 {
+		t.Errorf(errResponseValue, "200 / 5 bytes", []any{c.StatusCode(), c.ResponseSize()})
+	}
+}
+
+func TestStatusCodeWithoutRecorder(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	c.Status(http.StatusAccepted)
+
+	if c.StatusCode() != 0 || c.ResponseSize() != 0 {
+		t.Errorf(errStatusCode, 0, c.StatusCode())
+	}
+}
@@ -0,0 +1,47 @@
+package context
+
+// Key is a typed key for values stored on a Context with Set and Get.
+// Each key created with NewKey is distinct, even if two keys share a name,
+// so packages cannot clash on string keys.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey creates a typed context key. The name is only used for debugging.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the key name
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// Set stores a typed value on the request context
+func Set[T any](c *Context, key *Key[T], value T) {
+	c.WithValue(key, value)
+}
+
+// Get returns the typed value stored under key and whether it was present
+func Get[T any](c *Context, key *Key[T]) (T, bool) {
+	value, ok := c.Value(key).(T)
+	return value, ok
+}
+
+// MustGet returns the typed value stored under key and panics if it is missing.
+// Use it for values that middleware guarantees, such as an authenticated user.
+func MustGet[T any](c *Context, key *Key[T]) T {
+	value, ok := Get(c, key)
+	if !ok {
+		panic("context: value for key " + key.name + " not set")
+	}
+	return value
+}
+
+// GetOr returns the typed value stored under key, or fallback if it is missing
+func GetOr[T any](c *Context, key *Key[T], fallback T) T {
+	if value, ok := Get(c, key); ok {
+		return value
+	}
+	return fallback
+}
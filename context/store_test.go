@@ -0,0 +1,59 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type tenant struct {
+	ID   int
+	Name string
+}
+
+var (
+	tenantKey   = NewKey[*tenant]("tenant")
+	tenantIDKey = NewKey[int]("tenant")
+)
+
+func TestTypedStore(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if _, ok := Get(c, tenantKey); ok {
+		t.Error("Expected missing value before Set")
+	}
+
+	Set(c, tenantKey, &tenant{ID: 7, Name: "acme"})
+	Set(c, tenantIDKey, 7)
+
+	got, ok := Get(c, tenantKey)
+	if !ok || got.Name != "acme" {
+		t.Errorf(errResponseValue, "acme", got)
+	}
+
+	// Keys sharing a name do not collide
+	if id := MustGet(c, tenantIDKey); id != 7 {
+		t.Errorf(errResponseValue, 7, id)
+	}
+
+	// Values are visible through the request context as well
+	if c.Request.Context().Value(tenantKey) == nil {
+		t.Error("Expected value on the request context")
+	}
+}
+
+func TestTypedStoreDefaults(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	localeKey := NewKey[string]("locale")
+
+	if locale := GetOr(c, localeKey, "en"); locale != "en" {
+		t.Errorf(errResponseValue, "en", locale)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustGet to panic for a missing value")
+		}
+	}()
+	MustGet(c, localeKey)
+}
@@ -0,0 +1,64 @@
+package context
+
+import (
+	"io"
+	"net/http"
+)
+
+// streamBufferSize is the chunk size used when copying a reader to the response
+const streamBufferSize = 32 * 1024
+
+// Stream writes a streaming response by calling step until it returns false or the
+// client disconnects. The response is flushed after every step, so large result sets
+// can be sent without buffering them in memory. It returns true if the client went
+// away before the stream finished.
+//
+// Set the Content-Type (and the status, if not 200) before calling Stream.
+func (c *Context) Stream(step func(w io.Writer) bool) bool {
+	done := c.Request.Context().Done()
+	for {
+		select {
+		case <-done:
+			return true
+		default:
+		}
+
+		keepOpen := step(c.Writer)
+		c.Flush()
+		if !keepOpen {
+			return false
+		}
+	}
+}
+
+// Render copies r to the response with the given status and content type,
+// flushing after every chunk
+func (c *Context) Render(status int, r io.Reader, contentType string) error {
+	if contentType != "" {
+		c.Writer.Header().Set(HeaderContentType, contentType)
+	}
+	c.Writer.WriteHeader(status)
+
+	buf := make([]byte, streamBufferSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			c.Flush()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Flush sends any buffered response data to the client.
+// Writers that do not support flushing are left as they are.
+func (c *Context) Flush() {
+	_ = http.NewResponseController(c.Writer).Flush()
+}
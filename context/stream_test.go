@@ -0,0 +1,84 @@
+package context
+
+import (
+	gocontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+	c.SetHeader(HeaderContentType, "text/csv")
+
+	rows := []string{"id,name", "1,John", "2,Jane"}
+	i := 0
+	clientGone := c.Stream(func(out io.Writer) bool {
+		fmt.Fprintln(out, rows[i])
+		i++
+		return i < len(rows)
+	})
+
+	if clientGone {
+		t.Error("Expected stream to finish normally")
+	}
+	if !w.Flushed {
+		t.Error("Expected response to be flushed")
+	}
+	if expected := "id,name\n1,John\n2,Jane\n"; w.Body.String() != expected {
+		t.Errorf(errResponseValue, expected, w.Body.String())
+	}
+}
+
+func TestStreamStopsWhenClientDisconnects(t *testing.T) {
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	r := httptest.NewRequest(http.MethodGet, "/export", nil).WithContext(ctx)
+	c := New(httptest.NewRecorder(), r)
+
+	steps := 0
+	clientGone := c.Stream(func(out io.Writer) bool {
+		steps++
+		cancel()
+		return true
+	})
+
+	if !clientGone {
+		t.Error("Expected Stream to report the client disconnect")
+	}
+	if steps != 1 {
+		t.Errorf(errExpectedCount, 1, steps)
+	}
+}
+
+func TestRender(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := New(w, httptest.NewRequest(http.MethodGet, "/script", nil))
+
+	script := strings.Repeat("CREATE TABLE t (id INTEGER);\n", 2000)
+	if err := c.Render(http.StatusOK, strings.NewReader(script), "application/sql"); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+
+	if ct := w.Header().Get(HeaderContentType); ct != "application/sql" {
+		t.Errorf(errResponseValue, "application/sql", ct)
+	}
+	if w.Body.String() != script {
+		t.Errorf(errResponseValue, len(script), w.Body.Len())
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("read failed") }
+
+func TestRenderReaderError(t *testing.T) {
+	c := New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if err := c.Render(http.StatusOK, failingReader{}, ""); err == nil {
+		t.Error("Expected Render to return the reader error")
+	}
+}
@@ -0,0 +1,13 @@
+package context
+
+// ResponseTransformKey is the typed key under which middleware, such as API versioning,
+// stores a transform applied to the data of Success and SuccessPaged responses
+var ResponseTransformKey = NewKey[func(any) any]("response-transform")
+
+// transformData applies the response transform of the request, if any
+func (c *Context) transformData(data any) any {
+	if transform, ok := Get(c, ResponseTransformKey); ok && transform != nil {
+		return transform(data)
+	}
+	return data
+}
@@ -0,0 +1,117 @@
+package context
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/lamboktulussimamora/gra/validator"
+)
+
+// ErrValidationFailed is returned by BindValidated when the bound struct fails validation
+var ErrValidationFailed = errors.New("validation failed")
+
+// AutoValidateKey enables validation in Bind, BindJSON, BindForm, BindYAML, BindTOML and
+// BindMsgpack when set to true, as done by middleware.AutoValidate. Bound structs that
+// fail validation get the same 400 response as with BindValidated.
+var AutoValidateKey = NewKey[bool]("auto-validate")
+
+// ValidationErrorResponse is the response body written by BindValidated on validation failure
+type ValidationErrorResponse struct {
+	Status string                      `json:"status"`
+	Error  string                      `json:"error"`
+	Errors []validator.ValidationError `json:"errors"`
+}
+
+// BindValidated binds the request into obj and validates it with the validator package.
+// The body (or the query string for requests without a body) is bound with Bind, then
+// fields tagged with `uri` and `header` are bound from route parameters and headers.
+// On failure a 400 response is written and the error is returned, so handlers only
+// need to return:
+//
+//	var req CreateUserRequest
+//	if err := c.BindValidated(&req); err != nil {
+//		return
+//	}
+func (c *Context) BindValidated(obj any) error {
+	if err := c.bindAll(obj); err != nil {
+		c.Error(http.StatusBadRequest, "Invalid request: "+err.Error())
+		return err
+	}
+
+	return c.validate(obj)
+}
+
+// autoValidate validates obj after a successful bind if automatic validation is enabled
+func (c *Context) autoValidate(obj any, err error) error {
+	if err != nil || !GetOr(c, AutoValidateKey, false) {
+		return err
+	}
+	return c.validate(obj)
+}
+
+// validate validates obj, writing a 400 response with the field errors on failure
+func (c *Context) validate(obj any) error {
+	if errs := c.validator().Validate(obj); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, ValidationErrorResponse{
+			Status: "error",
+			Error:  "Validation failed",
+			Errors: errs,
+		})
+		return ErrValidationFailed
+	}
+
+	return nil
+}
+
+// validator creates a validator that translates messages into the request locale with
+// the Translator set by the i18n middleware, so custom messages in validate tags can be
+// catalog keys such as `required|errors.email_required` and built-in messages can be
+// overridden with keys such as "validation.required".
+func (c *Context) validator() *validator.Validator {
+	translator, ok := Get(c, TranslatorKey)
+	if !ok {
+		return validator.New()
+	}
+	return validator.New(validator.WithTranslator(translator, c.Locale()))
+}
+
+// bindAll binds the body or query string, then any `uri` and `header` tagged fields
+func (c *Context) bindAll(obj any) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidBindTarget
+	}
+
+	if err := c.bind(obj); err != nil {
+		return err
+	}
+
+	t := rv.Elem().Type()
+	if hasTag(t, "uri") {
+		if err := c.BindUri(obj); err != nil {
+			return err
+		}
+	}
+	if hasTag(t, "header") {
+		if err := c.BindHeader(obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasTag reports whether any field of a struct type, including nested structs, carries the tag
+func hasTag(t reflect.Type, tag string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if _, ok := field.Tag.Lookup(tag); ok {
+			return true
+		}
+		if isNestedStruct(field.Type) && hasTag(field.Type, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type createUserRequest struct {
+	OrgID     int    `json:"org_id" uri:"org_id" validate:"required"`
+	RequestID string `json:"-" header:"X-Request-ID"`
+	Name      string `json:"name" validate:"required"`
+	Email     string `json:"email" validate:"required,email"`
+}
+
+func newValidatedContext(body string) (*Context, *httptest.ResponseRecorder) {
+	r := httptest.NewRequest(http.MethodPost, "/orgs/5/users", strings.NewReader(body))
+	r.Header.Set(HeaderContentType, ContentTypeJSON)
+	r.Header.Set("X-Request-ID", "req-1")
+	w := httptest.NewRecorder()
+	c := New(w, r)
+	c.Params["org_id"] = "5"
+	return c, w
+}
+
+func TestBindValidated(t *testing.T) {
+	c, w := newValidatedContext(`{"name":"John","email":"john@example.com"}`)
+
+	var req createUserRequest
+	if err := c.BindValidated(&req); err != nil {
+		t.Fatalf("BindValidated returned error: %v", err)
+	}
+
+	if req.OrgID != 5 || req.RequestID != "req-1" || req.Name != "John" {
+		t.Errorf(errResponseValue, "org 5, req-1, John", req)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected no response to be written, got %s", w.Body.String())
+	}
+}
+
+func TestBindValidatedWritesValidationErrors(t *testing.T) {
+	c, w := newValidatedContext(`{"name":"","email":"not-an-email"}`)
+
+	var req createUserRequest
+	if err := c.BindValidated(&req); !errors.Is(err, ErrValidationFailed) {
+		t.Errorf(errWrongErrorType, ErrValidationFailed, err)
+	}
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf(errStatusCode, http.StatusBadRequest, w.Code)
+	}
+
+	var resp ValidationErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf(errUnmarshalResponse, err)
+	}
+	if resp.Status != "error" || len(resp.Errors) != 2 {
+		t.Errorf(errResponseValue, "2 field errors", resp)
+	}
+}
+
+func TestBindValidatedInvalidBody(t *testing.T) {
+	c, w := newValidatedContext(`{"name":`)
+
+	var req createUserRequest
+	if err := c.BindValidated(&req); err == nil {
+		t.Error(errExpectedBindEr)
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf(errStatusCode, http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestAutoValidate(t *testing.T) {
+	type signup struct {
+		Name  string `json:"name" form:"name" validate:"required"`
+		Email string `json:"email" form:"email" validate:"required,email"`
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		bind    func(c *Context, obj any) error
+	}{
+		{name: "Bind", enabled: true, bind: (*Context).Bind},
+		{name: "BindJSON", enabled: true, bind: (*Context).BindJSON},
+		{name: "Disabled", enabled: false, bind: (*Context).BindJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newValidatedContext(`{"name":"","email":"john@example.com"}`)
+			if tt.enabled {
+				Set(c, AutoValidateKey, true)
+			}
+
+			var req signup
+			err := tt.bind(c, &req)
+			if !tt.enabled {
+				if err != nil || w.Body.Len() != 0 {
+					t.Errorf("Expected binding without validation, got %v %s", err, w.Body.String())
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrValidationFailed) {
+				t.Errorf(errWrongErrorType, ErrValidationFailed, err)
+			}
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "name is required") {
+				t.Errorf("Expected a 400 validation response, got %d %s", w.Code, w.Body.String())
+			}
+			if strings.Count(w.Body.String(), "name is required") != 1 {
+				t.Errorf("Expected the struct to be validated once, got %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -33,6 +33,13 @@ type LoginResponse struct {
 	User  User   `json:"user"`
 }
 
+// UserClaims holds the claims of an authenticated user
+type UserClaims struct {
+	UserID   string `json:"sub"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
 // Server represents the API server
 type Server struct {
 	app        *router.Router
@@ -54,8 +61,17 @@ func main() {
 
 // NewServer creates a new server instance
 func NewServer() (*Server, error) {
-	// Create JWT service with a signing key
-	jwtService, err := jwt.NewServiceWithKey([]byte("my-secret-key"))
+	// Create JWT service with the signing key from JWT_SECRET
+	config := jwt.DefaultConfig()
+	provider, err := jwt.NewEnvKeyProvider("JWT_SECRET")
+	if err != nil {
+		log.Println("JWT_SECRET is not set, using an insecure demo key")
+		config.SigningKey = []byte("my-secret-key")
+	} else {
+		config.KeyProvider = provider
+	}
+
+	jwtService, err := jwt.NewService(config)
 	if err != nil {
 		return nil, err
 	}
@@ -89,69 +105,29 @@ func NewServer() (*Server, error) {
 
 // setupRoutes configures the API routes
 func (s *Server) setupRoutes() {
-	// Create a router wrapper to handle different middleware chains
-	publicRouter := gra.New()
-	apiRouter := gra.New()
-
-	// Apply common middleware to all routers
-	publicRouter.Use(middleware.Logger())
-	publicRouter.Use(middleware.Recovery())
-	publicRouter.Use(middleware.SecureHeaders())
-
-	apiRouter.Use(middleware.Logger())
-	apiRouter.Use(middleware.Recovery())
-	apiRouter.Use(middleware.SecureHeaders())
-
-	// Apply authentication middleware only to API router
-	apiRouter.Use(middleware.Auth(s, "user"))
+	// Common middleware; public endpoints skip authentication
+	s.app.Use(
+		middleware.Logger(),
+		middleware.Recovery(),
+		middleware.SecureHeaders(),
+		middleware.AuthWithConfig(middleware.AuthConfig{
+			Authenticator: s,
+			ClaimsKey:     "user",
+			Skipper:       middleware.SkipPaths("/", "/login"),
+		}),
+	)
 
 	// Public routes - no authentication required
-	publicRouter.GET("/", s.handleHome)
-	publicRouter.POST("/login", s.handleLogin)
-
-	// User routes
-	apiRouter.GET("/profile", s.handleGetProfile)
-
-	// Admin routes with additional middleware
-	adminRouter := gra.New()
-	adminRouter.Use(middleware.Logger())
-	adminRouter.Use(middleware.Recovery())
-	adminRouter.Use(middleware.SecureHeaders())
-	adminRouter.Use(middleware.Auth(s, "user"))
-	adminRouter.Use(s.adminOnly)
-	adminRouter.GET("/dashboard", s.handleAdminDashboard)
-
-	// Direct routes for public endpoints
 	s.app.GET("/", s.handleHome)
 	s.app.POST("/login", s.handleLogin)
 
 	// API routes with authentication
-	s.app.GET("/api/profile", s.withMiddlewares(
-		s.handleGetProfile,
-		middleware.Logger(),
-		middleware.Recovery(),
-		middleware.SecureHeaders(),
-		middleware.Auth(s, "user"),
-	))
+	s.app.GET("/api/profile", s.handleGetProfile)
 
-	// Admin routes with additional middleware
-	s.app.GET("/api/admin/dashboard", s.withMiddlewares(
-		s.handleAdminDashboard,
-		middleware.Logger(),
-		middleware.Recovery(),
-		middleware.SecureHeaders(),
-		middleware.Auth(s, "user"),
-		s.adminOnly,
-	))
-}
-
-// withMiddlewares applies middleware to a handler
-func (s *Server) withMiddlewares(handler router.HandlerFunc, middlewares ...router.Middleware) router.HandlerFunc {
-	// Apply middlewares in reverse order (last middleware runs first)
-	for i := len(middlewares) - 1; i >= 0; i-- {
-		handler = middlewares[i](handler)
-	}
-	return handler
+	// Admin routes require the admin role
+	admin := s.app.Group("/api/admin")
+	admin.Use(middleware.RequireRoles("admin"))
+	admin.GET("/dashboard", s.handleAdminDashboard)
 }
 
 // ValidateToken implements the JWTAuthenticator interface
@@ -159,28 +135,6 @@ func (s *Server) ValidateToken(tokenString string) (any, error) {
 	return s.jwtService.ValidateToken(tokenString)
 }
 
-// adminOnly middleware ensures that the user has admin role
-func (s *Server) adminOnly(next router.HandlerFunc) router.HandlerFunc {
-	return func(c *context.Context) {
-		// Get user claims from context
-		userClaims, ok := c.Value("user").(map[string]interface{})
-		if !ok {
-			c.Error(http.StatusUnauthorized, "Invalid user claims")
-			return
-		}
-
-		// Check if user has admin role
-		role, ok := userClaims["role"].(string)
-		if !ok || role != "admin" {
-			c.Error(http.StatusForbidden, "Admin access required")
-			return
-		}
-
-		// Call the next handler
-		next(c)
-	}
-}
-
 // handleHome handles the home page
 func (s *Server) handleHome(c *context.Context) {
 	c.JSON(http.StatusOK, map[string]string{
@@ -228,23 +182,16 @@ func (s *Server) handleLogin(c *context.Context) {
 // handleGetProfile handles getting user profile
 func (s *Server) handleGetProfile(c *context.Context) {
 	// Get user claims from context
-	userClaims, ok := c.Value("user").(map[string]interface{})
-	if !ok {
+	claims, err := middleware.ClaimsAs[UserClaims](c)
+	if err != nil || claims.UserID == "" {
 		c.Error(http.StatusUnauthorized, "Invalid user claims")
 		return
 	}
 
-	// Get user ID from claims
-	userID, ok := userClaims["sub"].(string)
-	if !ok {
-		c.Error(http.StatusUnauthorized, "Invalid user ID")
-		return
-	}
-
 	// Find the user by ID
 	var user User
 	for _, u := range s.users {
-		if u.ID == userID {
+		if u.ID == claims.UserID {
 			user = u
 			break
 		}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/lamboktulussimamora/gra"
 	"github.com/lamboktulussimamora/gra/middleware"
-	"github.com/lamboktulussimamora/gra/validator"
 )
 
 // User represents a user model
@@ -51,20 +50,7 @@ func main() {
 
 	r.POST("/users", func(c *gra.Context) {
 		var user User
-		if err := c.BindJSON(&user); err != nil {
-			c.Error(http.StatusBadRequest, "Invalid request body")
-			return
-		}
-
-		// Validate user
-		v := validator.New()
-		errors := v.Validate(user)
-		if len(errors) > 0 {
-			c.JSON(http.StatusBadRequest, map[string]any{
-				"status": "error",
-				"error":  "Validation failed",
-				"errors": errors,
-			})
+		if err := c.BindValidated(&user); err != nil {
 			return
 		}
 
@@ -255,36 +255,21 @@ func demonstrateAdvancedQuerying(ctx *dbcontext.EnhancedDbContext) error {
 func demonstrateTransactions(ctx *dbcontext.EnhancedDbContext) error {
 	fmt.Println("\n   💳 Transaction Management")
 
-	// Begin transaction
-	tx, err := ctx.Database.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	// Create transaction context
-	txCtx := dbcontext.NewEnhancedDbContextWithTx(tx)
-
-	// Create users within transaction
+	// Create users within a transaction, committed if the function returns nil
 	user1 := &models.User{FirstName: "Trans", LastName: "User1", Email: "trans1@example.com", IsActive: true}
 	user2 := &models.User{FirstName: "Trans", LastName: "User2", Email: "trans2@example.com", IsActive: true}
 
-	txCtx.Add(user1)
-	txCtx.Add(user2)
+	err := ctx.WithTransaction(func(tx *dbcontext.EnhancedDbContext) error {
+		tx.Add(user1)
+		tx.Add(user2)
 
-	// Save changes within transaction
-	_, err = txCtx.SaveChanges()
+		_, err := tx.SaveChanges()
+		return err
+	})
 	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			log.Printf("Warning: Failed to rollback transaction: %v", rollbackErr)
-		}
 		return fmt.Errorf("failed to save changes in transaction: %w", err)
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	fmt.Println("      ✅ Transaction completed successfully")
 	fmt.Printf("      ✅ Created users: %s and %s\n", user1.FirstName, user2.FirstName)
 
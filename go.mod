@@ -7,3 +7,17 @@ require github.com/golang-jwt/jwt/v5 v5.2.2
 require github.com/lib/pq v1.10.9
 
 require github.com/mattn/go-sqlite3 v1.14.28
+
+require github.com/BurntSushi/toml v1.6.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+require github.com/vmihailenco/msgpack/v5 v5.4.1
+
+require google.golang.org/protobuf v1.36.12
+
+require github.com/go-sql-driver/mysql v1.9.3
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+
+require filippo.io/edwards25519 v1.1.0 // indirect
@@ -0,0 +1,280 @@
+// Package health provides liveness and readiness checks for the GRA framework.
+package health
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+// Check statuses reported in health responses
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// ErrNotReady is reported by readiness checks after SetReady(false)
+var ErrNotReady = errors.New("not ready")
+
+// Checker checks a single dependency
+type Checker interface {
+	Check(ctx stdcontext.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface
+type CheckerFunc func(ctx stdcontext.Context) error
+
+// Check calls f(ctx)
+func (f CheckerFunc) Check(ctx stdcontext.Context) error {
+	return f(ctx)
+}
+
+// Pinger is implemented by *sql.DB and *dbcontext.EnhancedDbContext
+type Pinger interface {
+	PingContext(ctx stdcontext.Context) error
+}
+
+// PingCheck returns a checker that pings a database
+func PingCheck(db Pinger) Checker {
+	return CheckerFunc(db.PingContext)
+}
+
+// MigrationStatus is implemented by *migrations.EFMigrationManager
+type MigrationStatus interface {
+	HasPendingMigrations() (bool, error)
+}
+
+// MigrationCheck returns a checker that fails while migrations are pending
+func MigrationCheck(m MigrationStatus) Checker {
+	return CheckerFunc(func(stdcontext.Context) error {
+		pending, err := m.HasPendingMigrations()
+		if err != nil {
+			return err
+		}
+		if pending {
+			return errors.New("pending migrations")
+		}
+		return nil
+	})
+}
+
+// Config holds configuration for health checks
+type Config struct {
+	// Timeout bounds each check
+	Timeout time.Duration
+	// CacheTTL is how long a check result is reused before the check runs again
+	CacheTTL time.Duration
+	// ExposeErrors includes the errors of failed checks in health responses. They
+	// can reveal hostnames or connection strings, so by default they are only logged.
+	ExposeErrors bool
+}
+
+// DefaultConfig returns the default health check configuration
+func DefaultConfig() Config {
+	return Config{
+		Timeout:  5 * time.Second,
+		CacheTTL: time.Second,
+	}
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report is the body of a health response
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// check is a registered checker and its cached result
+type check struct {
+	name      string
+	checker   Checker
+	readiness bool
+
+	mutex   sync.Mutex
+	result  CheckResult
+	checked time.Time
+}
+
+// Health runs registered liveness and readiness checks
+type Health struct {
+	config   Config
+	checks   []*check
+	mutex    sync.RWMutex
+	notReady atomic.Bool
+	now      func() time.Time
+}
+
+// New creates a Health with the default configuration
+func New() *Health {
+	return NewWithConfig(DefaultConfig())
+}
+
+// NewWithConfig creates a Health with custom configuration
+func NewWithConfig(config Config) *Health {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultConfig().Timeout
+	}
+	return &Health{config: config, now: time.Now}
+}
+
+// AddLivenessCheck registers a check that reports whether the process should be restarted.
+// Liveness checks are also part of readiness.
+func (h *Health) AddLivenessCheck(name string, checker Checker) *Health {
+	return h.add(name, checker, false)
+}
+
+// AddReadinessCheck registers a check that reports whether the process can serve traffic
+func (h *Health) AddReadinessCheck(name string, checker Checker) *Health {
+	return h.add(name, checker, true)
+}
+
+// add registers a check
+func (h *Health) add(name string, checker Checker, readiness bool) *Health {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.checks = append(h.checks, &check{name: name, checker: checker, readiness: readiness})
+	return h
+}
+
+// SetReady marks the process as ready or not, e.g. false during graceful shutdown
+func (h *Health) SetReady(ready bool) {
+	h.notReady.Store(!ready)
+}
+
+// Liveness runs the liveness checks
+func (h *Health) Liveness(ctx stdcontext.Context) Report {
+	return h.run(ctx, false)
+}
+
+// Readiness runs the liveness and readiness checks
+func (h *Health) Readiness(ctx stdcontext.Context) Report {
+	report := h.run(ctx, true)
+	if h.notReady.Load() {
+		report.Status = StatusUnavailable
+		report.Checks["ready"] = CheckResult{Status: StatusUnavailable, Error: ErrNotReady.Error()}
+	}
+	return report
+}
+
+// LivenessHandler serves the liveness report
+func (h *Health) LivenessHandler() func(*context.Context) {
+	return func(c *context.Context) {
+		h.writeReport(c, h.Liveness(c.Request.Context()))
+	}
+}
+
+// ReadinessHandler serves the readiness report
+func (h *Health) ReadinessHandler() func(*context.Context) {
+	return func(c *context.Context) {
+		h.writeReport(c, h.Readiness(c.Request.Context()))
+	}
+}
+
+// writeReport writes a report with 200 when healthy and 503 otherwise. The errors of
+// failed checks are logged, and left out unless ExposeErrors is set.
+func (h *Health) writeReport(c *context.Context, report Report) {
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	for name, result := range report.Checks {
+		if result.Error == "" {
+			continue
+		}
+		c.Logger().Warnf("Health check %s failed: %s", name, result.Error)
+		if !h.config.ExposeErrors {
+			result.Error = ""
+			report.Checks[name] = result
+		}
+	}
+	c.SetHeader("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
+
+// run executes the selected checks concurrently
+func (h *Health) run(ctx stdcontext.Context, readiness bool) Report {
+	h.mutex.RLock()
+	var selected []*check
+	for _, ch := range h.checks {
+		if readiness || !ch.readiness {
+			selected = append(selected, ch)
+		}
+	}
+	h.mutex.RUnlock()
+
+	results := make([]CheckResult, len(selected))
+	var wg sync.WaitGroup
+	for i, ch := range selected {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.result(ctx, ch)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(selected))}
+	for i, ch := range selected {
+		report.Checks[ch.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	return report
+}
+
+// result returns the cached result of a check or runs it
+func (h *Health) result(ctx stdcontext.Context, ch *check) CheckResult {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+
+	now := h.now()
+	if !ch.checked.IsZero() && now.Sub(ch.checked) < h.config.CacheTTL {
+		return ch.result
+	}
+
+	err := h.runCheck(ctx, ch.checker)
+	result := CheckResult{Status: StatusOK, Duration: h.now().Sub(now)}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+
+	ch.result, ch.checked = result, now
+	return result
+}
+
+// runCheck runs a checker with the configured timeout, even if it ignores ctx
+func (h *Health) runCheck(ctx stdcontext.Context, checker Checker) error {
+	ctx, cancel := stdcontext.WithTimeout(ctx, h.config.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- checker.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+}
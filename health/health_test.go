@@ -0,0 +1,136 @@
+package health
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+type fakeMigrations struct {
+	pending bool
+	err     error
+}
+
+func (m fakeMigrations) HasPendingMigrations() (bool, error) {
+	return m.pending, m.err
+}
+
+type fakePinger struct{ err error }
+
+func (p fakePinger) PingContext(stdcontext.Context) error {
+	return p.err
+}
+
+func serve(handler func(*context.Context)) (*httptest.ResponseRecorder, Report) {
+	w := httptest.NewRecorder()
+	handler(context.New(w, httptest.NewRequest(http.MethodGet, "/healthz", nil)))
+
+	var report Report
+	_ = json.Unmarshal(w.Body.Bytes(), &report)
+	return w, report
+}
+
+func TestLivenessAndReadiness(t *testing.T) {
+	h := New().
+		AddLivenessCheck("goroutines", CheckerFunc(func(stdcontext.Context) error { return nil })).
+		AddReadinessCheck("database", PingCheck(fakePinger{})).
+		AddReadinessCheck("migrations", MigrationCheck(fakeMigrations{pending: true}))
+
+	w, report := serve(h.LivenessHandler())
+	if w.Code != http.StatusOK || report.Status != StatusOK || len(report.Checks) != 1 {
+		t.Errorf("Expected healthy liveness with one check, got %d %+v", w.Code, report)
+	}
+
+	w, report = serve(h.ReadinessHandler())
+	if w.Code != http.StatusServiceUnavailable || report.Status != StatusUnavailable {
+		t.Errorf("Expected unavailable readiness, got %d %+v", w.Code, report)
+	}
+	if got := report.Checks["migrations"]; got.Status != StatusUnavailable || got.Error != "" {
+		t.Errorf("Expected failing migration check without its error, got %+v", got)
+	}
+	if got := report.Checks["database"]; got.Status != StatusOK {
+		t.Errorf("Expected passing database check, got %+v", got)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Error("Expected health responses not to be cached")
+	}
+}
+
+func TestExposeErrors(t *testing.T) {
+	config := DefaultConfig()
+	config.ExposeErrors = true
+	h := NewWithConfig(config).AddReadinessCheck("migrations", MigrationCheck(fakeMigrations{pending: true}))
+
+	if _, report := serve(h.ReadinessHandler()); report.Checks["migrations"].Error != "pending migrations" {
+		t.Errorf("Expected the check error to be exposed, got %+v", report.Checks["migrations"])
+	}
+	if report := h.Readiness(stdcontext.Background()); report.Checks["migrations"].Error != "pending migrations" {
+		t.Errorf("Expected reports to keep the check error, got %+v", report.Checks["migrations"])
+	}
+}
+
+func TestCheckTimeoutAndPanic(t *testing.T) {
+	h := NewWithConfig(Config{Timeout: 20 * time.Millisecond})
+	h.AddLivenessCheck("slow", CheckerFunc(func(stdcontext.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+	h.AddLivenessCheck("panics", CheckerFunc(func(stdcontext.Context) error {
+		panic("boom")
+	}))
+
+	start := time.Now()
+	report := h.Liveness(stdcontext.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected checks to be bounded by the timeout, took %s", elapsed)
+	}
+	if report.Checks["slow"].Status != StatusUnavailable || report.Checks["panics"].Status != StatusUnavailable {
+		t.Errorf("Expected slow and panicking checks to fail, got %+v", report.Checks)
+	}
+}
+
+func TestCachedResults(t *testing.T) {
+	var calls atomic.Int32
+	now := time.Now()
+
+	h := NewWithConfig(Config{Timeout: time.Second, CacheTTL: time.Minute})
+	h.now = func() time.Time { return now }
+	h.AddReadinessCheck("counter", CheckerFunc(func(stdcontext.Context) error {
+		calls.Add(1)
+		return errors.New("down")
+	}))
+
+	h.Readiness(stdcontext.Background())
+	h.Readiness(stdcontext.Background())
+	if calls.Load() != 1 {
+		t.Errorf("Expected cached result within TTL, got %d calls", calls.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	h.Readiness(stdcontext.Background())
+	if calls.Load() != 2 {
+		t.Errorf("Expected check to rerun after TTL, got %d calls", calls.Load())
+	}
+}
+
+func TestSetReady(t *testing.T) {
+	h := New()
+	if report := h.Readiness(stdcontext.Background()); report.Status != StatusOK {
+		t.Errorf("Expected ready by default, got %+v", report)
+	}
+
+	h.SetReady(false)
+	if report := h.Readiness(stdcontext.Background()); report.Status != StatusUnavailable {
+		t.Errorf("Expected not ready after SetReady(false), got %+v", report)
+	}
+	if report := h.Liveness(stdcontext.Background()); report.Status != StatusOK {
+		t.Errorf("Expected liveness to ignore readiness, got %+v", report)
+	}
+}
@@ -0,0 +1,199 @@
+// Package i18n provides message catalogs and translation for the GRA framework.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Bundle holds message catalogs keyed by locale
+type Bundle struct {
+	defaultLocale string
+	messages      map[string]map[string]string
+	locales       []string
+	mutex         sync.RWMutex
+}
+
+// NewBundle creates a bundle that falls back to defaultLocale for missing messages
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		defaultLocale: defaultLocale,
+		messages:      make(map[string]map[string]string),
+		locales:       []string{defaultLocale},
+	}
+}
+
+// DefaultLocale returns the fallback locale of the bundle
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// Locales returns the locales with messages, starting with the default locale
+func (b *Bundle) Locales() []string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return append([]string(nil), b.locales...)
+}
+
+// AddMessages adds flat key/message pairs to the catalog of a locale
+func (b *Bundle) AddMessages(locale string, messages map[string]string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	catalog, ok := b.messages[locale]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		b.messages[locale] = catalog
+		if locale != b.defaultLocale {
+			b.locales = append(b.locales, locale)
+		}
+	}
+	for key, message := range messages {
+		catalog[key] = message
+	}
+}
+
+// LoadJSON adds a JSON catalog. Nested objects are flattened into dotted keys,
+// so {"errors": {"not_found": "..."}} defines "errors.not_found".
+func (b *Bundle) LoadJSON(locale string, data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("i18n: invalid JSON catalog for %s: %w", locale, err)
+	}
+	b.AddMessages(locale, flatten(raw))
+	return nil
+}
+
+// LoadTOML adds a TOML catalog. Tables are flattened into dotted keys.
+func (b *Bundle) LoadTOML(locale string, data []byte) error {
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("i18n: invalid TOML catalog for %s: %w", locale, err)
+	}
+	b.AddMessages(locale, flatten(raw))
+	return nil
+}
+
+// LoadFile adds a catalog file named after its locale, e.g. "locales/id.toml"
+func (b *Bundle) LoadFile(name string) error {
+	data, err := os.ReadFile(filepath.Clean(name))
+	if err != nil {
+		return err
+	}
+	return b.load(filepath.Base(name), data)
+}
+
+// LoadFS adds every .json and .toml catalog in dir of fsys, e.g. an embed.FS
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := b.load(entry.Name(), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load parses a catalog using the file extension to pick the format
+func (b *Bundle) load(filename string, data []byte) error {
+	ext := path.Ext(filename)
+	locale := strings.TrimSuffix(filename, ext)
+	switch ext {
+	case ".json":
+		return b.LoadJSON(locale, data)
+	case ".toml":
+		return b.LoadTOML(locale, data)
+	default:
+		return fmt.Errorf("i18n: unsupported catalog format %q", ext)
+	}
+}
+
+// Has reports whether a message exists for the key in the locale or its fallbacks
+func (b *Bundle) Has(locale, key string) bool {
+	_, ok := b.lookup(locale, key)
+	return ok
+}
+
+// Translate returns the message for key in locale, falling back to the base language
+// (e.g. "pt" for "pt-BR"), then the default locale, then the key itself.
+// A single map[string]any argument fills named placeholders such as "{{name}}";
+// other arguments are applied with fmt.Sprintf.
+func (b *Bundle) Translate(locale, key string, args ...any) string {
+	message, ok := b.lookup(locale, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	if named, ok := args[0].(map[string]any); ok && len(args) == 1 {
+		for name, value := range named {
+			message = strings.ReplaceAll(message, "{{"+name+"}}", fmt.Sprint(value))
+		}
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// lookup finds a message in the locale fallback chain
+func (b *Bundle) lookup(locale, key string) (string, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, candidate := range []string{locale, baseLanguage(locale), b.defaultLocale} {
+		if message, ok := b.messages[candidate][key]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// baseLanguage returns the primary language subtag of a locale
+func baseLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+// flatten converts nested catalog maps into dotted keys
+func flatten(raw map[string]any) map[string]string {
+	messages := make(map[string]string)
+	var walk func(prefix string, values map[string]any)
+	walk = func(prefix string, values map[string]any) {
+		for key, value := range values {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			switch v := value.(type) {
+			case map[string]any:
+				walk(key, v)
+			case string:
+				messages[key] = v
+			default:
+				messages[key] = fmt.Sprint(v)
+			}
+		}
+	}
+	walk("", raw)
+	return messages
+}
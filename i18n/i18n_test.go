@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+	b := NewBundle("en")
+	if err := b.LoadJSON("en", []byte(`{"greeting": "Hello, {{name}}!", "errors": {"not_found": "%s not found"}}`)); err != nil {
+		t.Fatalf("LoadJSON failed: %v", err)
+	}
+	if err := b.LoadTOML("id", []byte("greeting = \"Halo, {{name}}!\"\n\n[errors]\nnot_found = \"%s tidak ditemukan\"\n")); err != nil {
+		t.Fatalf("LoadTOML failed: %v", err)
+	}
+	return b
+}
+
+func TestTranslate(t *testing.T) {
+	b := newTestBundle(t)
+
+	tests := []struct {
+		locale   string
+		key      string
+		args     []any
+		expected string
+	}{
+		{"en", "greeting", []any{map[string]any{"name": "Ana"}}, "Hello, Ana!"},
+		{"id", "greeting", []any{map[string]any{"name": "Ana"}}, "Halo, Ana!"},
+		{"id-ID", "errors.not_found", []any{"User"}, "User tidak ditemukan"},
+		{"fr", "errors.not_found", []any{"User"}, "User not found"},
+		{"id", "missing.key", nil, "missing.key"},
+	}
+
+	for _, tt := range tests {
+		if got := b.Translate(tt.locale, tt.key, tt.args...); got != tt.expected {
+			t.Errorf("Translate(%q, %q) = %q, expected %q", tt.locale, tt.key, got, tt.expected)
+		}
+	}
+
+	if !b.Has("id", "greeting") || b.Has("id", "missing.key") {
+		t.Error("Expected Has to report catalog entries")
+	}
+}
+
+func TestLocales(t *testing.T) {
+	b := NewBundle("en")
+	b.AddMessages("id", map[string]string{"a": "b"})
+	b.AddMessages("en", map[string]string{"a": "b"})
+	b.AddMessages("id", map[string]string{"c": "d"})
+
+	locales := b.Locales()
+	if len(locales) != 2 || locales[0] != "en" || locales[1] != "id" {
+		t.Errorf("Expected [en id], got %v", locales)
+	}
+}
+
+func TestLoadFSAndFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json":   {Data: []byte(`{"title": "Orders"}`)},
+		"locales/de.toml":   {Data: []byte(`title = "Bestellungen"`)},
+		"locales/README.md": {Data: []byte("ignored")},
+	}
+	b := NewBundle("en")
+	if err := b.LoadFS(fsys, "locales"); err != nil {
+		t.Fatalf("LoadFS failed: %v", err)
+	}
+	if got := b.Translate("de", "title"); got != "Bestellungen" {
+		t.Errorf("Expected German title, got %q", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "ja.json")
+	if err := os.WriteFile(path, []byte(`{"title": "注文"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if got := b.Translate("ja", "title"); got != "注文" {
+		t.Errorf("Expected Japanese title, got %q", got)
+	}
+
+	if err := b.LoadJSON("en", []byte("{")); err == nil {
+		t.Error("Expected error for invalid JSON catalog")
+	}
+}
@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Encryption errors
+var (
+	ErrInvalidEncryptionKey = errors.New("encryption key must be 16, 24 or 32 bytes")
+	ErrEncryptionRequired   = errors.New("token must be encrypted")
+)
+
+// jweHeader is the protected header of a compact JWE
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Cty string `json:"cty,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// encryption seals signed tokens as compact JWE (RFC 7516) using direct
+// AES-GCM encryption with a shared key
+type encryption struct {
+	aead cipher.AEAD
+	enc  string
+}
+
+// newEncryption creates the cipher for the given key; the key length selects
+// A128GCM, A192GCM or A256GCM
+func newEncryption(key []byte) (*encryption, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &encryption{aead: aead, enc: fmt.Sprintf("A%dGCM", len(key)*8)}, nil
+}
+
+// encrypt wraps a signed token in a JWE
+func (e *encryption) encrypt(signed string) (string, error) {
+	header, err := json.Marshal(jweHeader{Alg: "dir", Enc: e.enc, Cty: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(header)
+
+	iv := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", fmt.Errorf("failed to generate IV: %w", err)
+	}
+
+	sealed := e.aead.Seal(nil, iv, []byte(signed), []byte(protected))
+	tagStart := len(sealed) - e.aead.Overhead()
+
+	return strings.Join([]string{
+		protected,
+		"", // No encrypted key with direct encryption
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(sealed[:tagStart]),
+		base64.RawURLEncoding.EncodeToString(sealed[tagStart:]),
+	}, "."), nil
+}
+
+// decrypt opens a JWE and returns the signed token it carries
+func (e *encryption) decrypt(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return "", ErrEncryptionRequired
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	var header jweHeader
+	if err := json.Unmarshal(raw, &header); err != nil || header.Alg != "dir" || header.Enc != e.enc || parts[1] != "" {
+		return "", ErrInvalidToken
+	}
+
+	iv, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(iv) != e.aead.NonceSize() {
+		return "", ErrInvalidToken
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	tag, err := base64.RawURLEncoding.DecodeString(parts[4])
+	if err != nil || len(tag) != e.aead.Overhead() {
+		return "", ErrInvalidToken
+	}
+
+	plaintext, err := e.aead.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	return string(plaintext), nil
+}
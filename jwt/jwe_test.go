@@ -0,0 +1,107 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testEncryptionKey = "0123456789abcdef0123456789abcdef"
+
+func newEncryptingService(t *testing.T) *Service {
+	t.Helper()
+	config := DefaultConfig()
+	config.SigningKey = []byte(testSecretKey)
+	config.EncryptionKey = []byte(testEncryptionKey)
+	service, err := NewService(config)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	return service
+}
+
+func TestEncryptedTokens(t *testing.T) {
+	service := newEncryptingService(t)
+
+	token, err := service.GenerateToken(StandardClaims{
+		Subject: testUserIDCommon,
+		Custom:  map[string]interface{}{"email": "jane@example.com"},
+	})
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 || parts[1] != "" {
+		t.Fatalf("Expected compact JWE with direct encryption, got %q", token)
+	}
+	header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	var h map[string]string
+	if err := json.Unmarshal(header, &h); err != nil || h["alg"] != "dir" || h["enc"] != "A256GCM" || h["cty"] != "JWT" {
+		t.Errorf("Unexpected JWE header %s", header)
+	}
+	if strings.Contains(token, "jane") {
+		t.Error("Expected claims not to be readable")
+	}
+
+	t.Run("should decrypt then verify", func(t *testing.T) {
+		claims, err := service.ValidateToken(token)
+		if err != nil {
+			t.Fatalf(errMsgNoError, err)
+		}
+		if claims["email"] != "jane@example.com" {
+			t.Errorf("Expected email claim, got %v", claims["email"])
+		}
+	})
+
+	t.Run("should reject tampered ciphertext", func(t *testing.T) {
+		tampered := []byte(token)
+		i := len(parts[0]) + 1 + len(parts[2]) + 2 // first ciphertext byte
+		if tampered[i] == 'A' {
+			tampered[i] = 'B'
+		} else {
+			tampered[i] = 'A'
+		}
+		if _, err := service.ValidateToken(string(tampered)); err != ErrInvalidToken {
+			t.Errorf(errMsgInvalidToken, err)
+		}
+	})
+
+	t.Run("should reject tokens encrypted with another key", func(t *testing.T) {
+		config := DefaultConfig()
+		config.SigningKey = []byte(testSecretKey)
+		config.EncryptionKey = []byte(strings.Repeat("k", 32))
+		other, _ := NewService(config)
+		if _, err := other.ValidateToken(token); err != ErrInvalidToken {
+			t.Errorf(errMsgInvalidToken, err)
+		}
+	})
+
+	t.Run("should reject unencrypted tokens", func(t *testing.T) {
+		plain, _ := NewServiceWithKey([]byte(testSecretKey))
+		signed, _ := plain.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+		if _, err := service.ValidateToken(signed); err != ErrEncryptionRequired {
+			t.Errorf("Expected ErrEncryptionRequired, got %v", err)
+		}
+	})
+
+	t.Run("should refresh encrypted tokens", func(t *testing.T) {
+		refreshed, err := service.RefreshToken(token)
+		if err != nil {
+			t.Fatalf(errMsgNoError, err)
+		}
+		if _, err := service.ValidateToken(refreshed); err != nil {
+			t.Errorf(errMsgNoError, err)
+		}
+	})
+}
+
+func TestEncryptionKeyLength(t *testing.T) {
+	config := DefaultConfig()
+	config.SigningKey = []byte(testSecretKey)
+	config.EncryptionKey = []byte("short")
+	if _, err := NewService(config); err != ErrInvalidEncryptionKey {
+		t.Errorf("Expected ErrInvalidEncryptionKey, got %v", err)
+	}
+}
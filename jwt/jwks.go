@@ -0,0 +1,272 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWKS errors
+var (
+	ErrMissingJWKSURL = errors.New("JWKS URL is required")
+	ErrUnknownKey     = errors.New("no matching key found in JWKS")
+	ErrValidationOnly = errors.New("service can only validate tokens")
+)
+
+// JWKSOptions configures a Service backed by a remote JSON Web Key Set
+type JWKSOptions struct {
+	HTTPClient         *http.Client      // Client used to fetch the key set
+	RefreshInterval    time.Duration     // How long a fetched key set is considered fresh
+	MinRefreshInterval time.Duration     // Minimum time between refetches triggered by an unknown kid
+	Algorithms         []string          // Accepted signing algorithms
+	Validation         ValidationOptions // Checks applied to standard claims
+	Clock              Clock             // Time source; defaults to the system clock
+}
+
+// DefaultJWKSOptions returns the default JWKS options
+func DefaultJWKSOptions() JWKSOptions {
+	return JWKSOptions{
+		HTTPClient:         &http.Client{Timeout: 10 * time.Second},
+		RefreshInterval:    time.Hour,
+		MinRefreshInterval: time.Minute,
+		Algorithms:         []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"},
+	}
+}
+
+// NewServiceWithJWKS creates a validation-only JWT service that verifies tokens
+// against the JSON Web Key Set published at url, as exposed by identity
+// providers such as Auth0, Keycloak or Azure AD. The key set is fetched once up
+// front, cached for RefreshInterval and refetched when a token references an
+// unknown kid.
+func NewServiceWithJWKS(url string, opts JWKSOptions) (*Service, error) {
+	if url == "" {
+		return nil, ErrMissingJWKSURL
+	}
+
+	defaults := DefaultJWKSOptions()
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = defaults.HTTPClient
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = defaults.RefreshInterval
+	}
+	if opts.MinRefreshInterval <= 0 {
+		opts.MinRefreshInterval = defaults.MinRefreshInterval
+	}
+	if len(opts.Algorithms) == 0 {
+		opts.Algorithms = defaults.Algorithms
+	}
+
+	s := &Service{config: Config{Validation: opts.Validation, Clock: opts.Clock}}
+
+	set := &jwks{url: url, opts: opts, now: s.now}
+	if err := set.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.keyFunc = set.keyFunc
+	s.parser = opts.Validation.parser(opts.Algorithms, s.now)
+	return s, nil
+}
+
+// jwk is a single JSON Web Key as defined by RFC 7517
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwks caches the public keys of a remote key set
+type jwks struct {
+	url  string
+	opts JWKSOptions
+	now  func() time.Time
+
+	mu        sync.RWMutex
+	keys      map[string]any
+	fetchedAt time.Time
+
+	fetchMu     sync.Mutex // Serializes fetches
+	lastAttempt time.Time
+}
+
+// keyFunc resolves the verification key for a token from its kid header
+func (s *jwks) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok, fresh := s.get(kid)
+	if ok && fresh {
+		return key, nil
+	}
+
+	// The cache is stale or the provider may have rotated its keys
+	if err := s.refetch(kid); err != nil && !ok {
+		return nil, err
+	}
+
+	if key, ok, _ = s.get(kid); !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// get looks up a cached key and reports whether the cache is still fresh
+func (s *jwks) get(kid string) (key any, ok, fresh bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fresh = s.now().Sub(s.fetchedAt) < s.opts.RefreshInterval
+	if kid == "" && len(s.keys) == 1 {
+		// Tokens without a kid match a single-key set
+		for _, key := range s.keys {
+			return key, true, fresh
+		}
+	}
+	key, ok = s.keys[kid]
+	return key, ok, fresh
+}
+
+// refetch refreshes the key set unless another caller just did or the
+// previous attempt was less than MinRefreshInterval ago
+func (s *jwks) refetch(kid string) error {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	if _, ok, fresh := s.get(kid); ok && fresh {
+		return nil
+	}
+	if s.now().Sub(s.lastAttempt) < s.opts.MinRefreshInterval {
+		return nil
+	}
+	return s.refresh()
+}
+
+// refresh fetches and parses the remote key set
+func (s *jwks) refresh() error {
+	s.lastAttempt = s.now()
+
+	req, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&doc); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]any, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// Skip keys of unsupported types instead of rejecting the whole set
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = s.now()
+	s.mu.Unlock()
+	return nil
+}
+
+// publicKey converts the JWK into a crypto public key
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// decodeBigInt decodes a base64url encoded big-endian integer
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,225 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer serves a mutable key set and counts fetches
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    []map[string]string
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	t.Helper()
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func signWithKid(t *testing.T, method jwt.SigningMethod, kid string, key any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"sub": testUserIDCommon,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	return key
+}
+
+func TestNewServiceWithJWKS(t *testing.T) {
+	if _, err := NewServiceWithJWKS("", DefaultJWKSOptions()); err != ErrMissingJWKSURL {
+		t.Errorf("Expected ErrMissingJWKSURL, got %v", err)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if _, err := NewServiceWithJWKS(failing.URL, DefaultJWKSOptions()); err == nil {
+		t.Error("Expected error when the JWKS endpoint fails")
+	}
+}
+
+func TestJWKSValidateToken(t *testing.T) {
+	server := newJWKSServer(t)
+	rsaKey := generateRSAKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+
+	server.setKeys(rsaJWK("rsa-1", &rsaKey.PublicKey), map[string]string{
+		"kty": "EC",
+		"kid": "ec-1",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+	})
+
+	service, err := NewServiceWithJWKS(server.URL, DefaultJWKSOptions())
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	t.Run("should validate RSA and EC tokens", func(t *testing.T) {
+		for _, token := range []string{
+			signWithKid(t, jwt.SigningMethodRS256, "rsa-1", rsaKey),
+			signWithKid(t, jwt.SigningMethodES256, "ec-1", ecKey),
+		} {
+			claims, err := service.ValidateToken(token)
+			if err != nil {
+				t.Fatalf(errMsgNoError, err)
+			}
+			if claims["sub"] != testUserIDCommon {
+				t.Errorf(errMsgExpectedSub, testUserIDCommon, claims["sub"])
+			}
+		}
+	})
+
+	t.Run("should reject tokens signed with another key", func(t *testing.T) {
+		token := signWithKid(t, jwt.SigningMethodRS256, "rsa-1", generateRSAKey(t))
+		if _, err := service.ValidateToken(token); err != ErrInvalidToken {
+			t.Errorf(errMsgInvalidToken, err)
+		}
+	})
+
+	t.Run("should reject HMAC tokens", func(t *testing.T) {
+		token := signWithKid(t, jwt.SigningMethodHS256, "rsa-1", []byte(testSecretKey))
+		if _, err := service.ValidateToken(token); err != ErrInvalidToken {
+			t.Errorf(errMsgInvalidToken, err)
+		}
+	})
+
+	t.Run("should not generate tokens", func(t *testing.T) {
+		if _, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon}); err != ErrValidationOnly {
+			t.Errorf("Expected ErrValidationOnly, got %v", err)
+		}
+	})
+
+	t.Run("should work as middleware authenticator", func(t *testing.T) {
+		claims, err := service.Authenticator().ValidateToken(signWithKid(t, jwt.SigningMethodRS256, "rsa-1", rsaKey))
+		if err != nil {
+			t.Fatalf(errMsgNoError, err)
+		}
+		if claims.(map[string]interface{})["sub"] != testUserIDCommon {
+			t.Errorf(errMsgExpectedSub, testUserIDCommon, claims)
+		}
+		if claims, err := service.Authenticator().ValidateToken(testInvalidToken); err == nil || claims != nil {
+			t.Errorf("Expected nil claims and an error, got %v, %v", claims, err)
+		}
+	})
+}
+
+func TestJWKSRefreshOnUnknownKid(t *testing.T) {
+	server := newJWKSServer(t)
+	oldKey, newKey := generateRSAKey(t), generateRSAKey(t)
+	server.setKeys(rsaJWK("old", &oldKey.PublicKey))
+
+	opts := DefaultJWKSOptions()
+	opts.MinRefreshInterval = time.Nanosecond
+	service, err := NewServiceWithJWKS(server.URL, opts)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	// The provider rotates to a new key
+	server.setKeys(rsaJWK("old", &oldKey.PublicKey), rsaJWK("new", &newKey.PublicKey))
+	time.Sleep(time.Millisecond)
+
+	if _, err := service.ValidateToken(signWithKid(t, jwt.SigningMethodRS256, "new", newKey)); err != nil {
+		t.Fatalf("Expected token signed with rotated key to validate, got %v", err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("Expected 2 fetches, got %d", got)
+	}
+
+	// Known kids are served from the cache
+	if _, err := service.ValidateToken(signWithKid(t, jwt.SigningMethodRS256, "old", oldKey)); err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("Expected cached keys to be used, got %d fetches", got)
+	}
+}
+
+func TestJWKSRefreshIsRateLimited(t *testing.T) {
+	server := newJWKSServer(t)
+	key := generateRSAKey(t)
+	server.setKeys(rsaJWK("only", &key.PublicKey))
+
+	service, err := NewServiceWithJWKS(server.URL, DefaultJWKSOptions())
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := service.ValidateToken(signWithKid(t, jwt.SigningMethodRS256, "unknown", key))
+		if !errors.Is(err, ErrInvalidToken) {
+			t.Errorf(errMsgInvalidToken, err)
+		}
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("Expected unknown kids not to trigger refetches within MinRefreshInterval, got %d fetches", got)
+	}
+
+	// A token without kid matches a single-key set
+	if _, err := service.ValidateToken(signWithKid(t, jwt.SigningMethodRS256, "", key)); err != nil {
+		t.Errorf(errMsgNoError, err)
+	}
+}
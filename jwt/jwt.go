@@ -3,6 +3,7 @@ package jwt
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -21,10 +22,20 @@ var (
 // Config holds JWT configuration parameters
 type Config struct {
 	SigningKey      []byte
+	Keys            []Key // Keyed secrets for rotation; the last one signs new tokens
 	SigningMethod   jwt.SigningMethod
 	ExpirationTime  time.Duration
 	RefreshDuration time.Duration
 	Issuer          string
+	Validation      ValidationOptions // Checks applied to standard claims on validation
+	EncryptionKey   []byte            // Optional AES key; tokens are signed, then encrypted as JWE
+	Clock           Clock             // Time source; defaults to the system clock
+	KeyProvider     KeyProvider       // Supplies the keys instead of SigningKey and Keys
+}
+
+// Clock provides the current time, so tests can control token expiry
+type Clock interface {
+	Now() time.Time
 }
 
 // DefaultConfig returns the default JWT configuration
@@ -39,13 +50,19 @@ func DefaultConfig() Config {
 
 // Service provides JWT token generation and validation
 type Service struct {
-	config Config
+	config  Config
+	keys    *keyRing    // Signing keys; nil for validation-only services
+	keyFunc jwt.Keyfunc // Resolves the verification key
+	parser  *jwt.Parser
+	crypter *encryption // Encrypts tokens when an encryption key is configured
+	refresh *jwt.Parser // Accepts tokens expired for up to RefreshDuration
 }
 
 // NewService creates a new JWT service with the provided config
 func NewService(config Config) (*Service, error) {
-	if len(config.SigningKey) == 0 {
-		return nil, ErrMissingKey
+	ring, err := configKeyRing(config)
+	if err != nil {
+		return nil, err
 	}
 
 	// Use default signing method if not specified
@@ -53,9 +70,55 @@ func NewService(config Config) (*Service, error) {
 		config.SigningMethod = jwt.SigningMethodHS256
 	}
 
-	return &Service{
-		config: config,
-	}, nil
+	s := &Service{config: config, keys: ring}
+	algorithms := []string{config.SigningMethod.Alg()}
+	s.parser = config.Validation.parser(algorithms, s.now)
+	s.keyFunc = s.signingKeys
+
+	refresh := config.Validation
+	refresh.Leeway += config.RefreshDuration
+	s.refresh = refresh.parser(algorithms, s.now)
+
+	if len(config.EncryptionKey) > 0 {
+		if s.crypter, err = newEncryption(config.EncryptionKey); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// configKeyRing builds the key ring for the keys in config
+func configKeyRing(config Config) (*keyRing, error) {
+	if config.KeyProvider != nil {
+		ring := &keyRing{provider: config.KeyProvider}
+		if _, err := ring.signing(config.now()); err != nil {
+			return nil, err
+		}
+		return ring, nil
+	}
+
+	if len(config.SigningKey) == 0 && len(config.Keys) == 0 {
+		return nil, ErrMissingKey
+	}
+
+	keys := config.Keys
+	if len(keys) == 0 {
+		keys = []Key{{Secret: config.SigningKey}}
+	}
+	return newKeyRing(keys)
+}
+
+// signingKeys resolves the keys that may have signed an HMAC token
+func (s *Service) signingKeys(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	keys, err := s.keys.verification(kid, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrUnknownKey
+	}
+	return jwt.VerificationKeySet{Keys: keys}, nil
 }
 
 // NewServiceWithKey creates a new JWT service with a signing key
@@ -67,22 +130,33 @@ func NewServiceWithKey(signingKey []byte) (*Service, error) {
 
 // StandardClaims represents the standard JWT claims
 type StandardClaims struct {
-	ID        string
-	Subject   string
-	Audience  []string
-	ExpiresAt int64
-	IssuedAt  int64
-	Issuer    string
-	Custom    map[string]interface{}
+	ID          string
+	Subject     string
+	Audience    []string
+	ExpiresAt   int64
+	IssuedAt    int64
+	Issuer      string
+	SessionID   string // Written to the sid claim to bind the token to a session
+	Fingerprint string // Written to the dfp claim to bind the token to a device
+	Custom      map[string]interface{}
 }
 
+// Claim names used to bind tokens to a session or device
+const (
+	ClaimSessionID   = "sid"
+	ClaimFingerprint = "dfp"
+)
+
 // GenerateToken creates a new JWT token with the provided claims
 func (s *Service) GenerateToken(claims StandardClaims) (string, error) {
+	if s.keys == nil {
+		return "", ErrValidationOnly
+	}
 	if claims.Subject == "" {
 		return "", ErrMissingSubject
 	}
 
-	now := time.Now()
+	now := s.now()
 	expiresAt := now.Add(s.config.ExpirationTime)
 
 	// Create JWT claims
@@ -101,35 +175,59 @@ func (s *Service) GenerateToken(claims StandardClaims) (string, error) {
 		jwtClaims["aud"] = claims.Audience
 	}
 
+	if claims.SessionID != "" {
+		jwtClaims[ClaimSessionID] = claims.SessionID
+	}
+
+	if claims.Fingerprint != "" {
+		jwtClaims[ClaimFingerprint] = claims.Fingerprint
+	}
+
 	// Add custom claims if any
 	for k, v := range claims.Custom {
 		jwtClaims[k] = v
 	}
 
 	// Create token
+	key, err := s.keys.signing(s.now())
+	if err != nil {
+		return "", err
+	}
 	token := jwt.NewWithClaims(s.config.SigningMethod, jwtClaims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
 
 	// Sign and get the complete encoded token as a string
-	return token.SignedString(s.config.SigningKey)
+	signed, err := token.SignedString(key.signingKey())
+	if err != nil || s.crypter == nil {
+		return signed, err
+	}
+
+	// Encrypt the signed token so its claims are not readable in transit
+	return s.crypter.encrypt(signed)
 }
 
 // ValidateToken validates the JWT token and returns the parsed claims
 func (s *Service) ValidateToken(tokenString string) (map[string]interface{}, error) {
-	// Parse token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if token.Method.Alg() != s.config.SigningMethod.Alg() {
-			return nil, ErrInvalidToken
+	return s.parse(tokenString, s.parser)
+}
+
+// parse decrypts, verifies and validates a token with the given parser
+func (s *Service) parse(tokenString string, parser *jwt.Parser) (map[string]interface{}, error) {
+	// Decrypt before verifying the signature of the inner token
+	if s.crypter != nil {
+		signed, err := s.crypter.decrypt(tokenString)
+		if err != nil {
+			return nil, err
 		}
-		return s.config.SigningKey, nil
-	})
+		tokenString = signed
+	}
 
+	// Parse token
+	token, err := parser.Parse(tokenString, s.keyFunc)
 	if err != nil {
-		// Check if the error is due to token expiration
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
-		}
-		return nil, ErrInvalidToken
+		return nil, validationError(err)
 	}
 
 	// Validate token
@@ -142,6 +240,9 @@ func (s *Service) ValidateToken(tokenString string) (map[string]interface{}, err
 	if !ok {
 		return nil, ErrInvalidToken
 	}
+	if err := s.config.Validation.check(claims); err != nil {
+		return nil, err
+	}
 
 	// Convert to map[string]interface{}
 	result := make(map[string]interface{})
@@ -152,20 +253,64 @@ func (s *Service) ValidateToken(tokenString string) (map[string]interface{}, err
 	return result, nil
 }
 
-// RefreshToken generates a new token based on the claims in an existing token
+// ValidateInto validates the JWT token and decodes its claims into dst, which
+// should be a pointer to a struct with json tags matching the claim names
+func (s *Service) ValidateInto(tokenString string, dst any) error {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return fmt.Errorf("failed to encode claims: %w", err)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to decode claims: %w", err)
+	}
+	return nil
+}
+
+// Authenticator adapts a Service to middleware.JWTAuthenticator
+type Authenticator struct {
+	Service *Service
+}
+
+// ValidateToken validates the token and returns its claims
+func (a Authenticator) ValidateToken(tokenString string) (any, error) {
+	claims, err := a.Service.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Authenticator returns an adapter for use with middleware.Auth
+func (s *Service) Authenticator() Authenticator {
+	return Authenticator{Service: s}
+}
+
+// RefreshToken generates a new token based on the claims in an existing token.
+// Tokens that expired less than RefreshDuration ago can still be refreshed.
 func (s *Service) RefreshToken(tokenString string) (string, error) {
+	if s.keys == nil {
+		return "", ErrValidationOnly
+	}
+
 	// First validate the old token
-	claims, err := s.ValidateToken(tokenString)
+	claims, err := s.parse(tokenString, s.refresh)
 	if err != nil {
-		// Allow refresh for expired tokens, but not for invalid tokens
-		if err != ErrExpiredToken {
-			return "", err
-		}
+		return "", err
+	}
+
+	subject, ok := claims["sub"].(string)
+	if !ok || subject == "" {
+		return "", ErrMissingSubject
 	}
 
 	// Create a new StandardClaims object
 	newClaims := StandardClaims{
-		Subject: claims["sub"].(string),
+		Subject: subject,
 		// Add some randomness to ensure new token is different
 		ID:     generateRandomTokenID(),
 		Custom: make(map[string]interface{}),
@@ -182,6 +327,19 @@ func (s *Service) RefreshToken(tokenString string) (string, error) {
 	return s.GenerateToken(newClaims)
 }
 
+// now returns the current time of the configured clock
+func (s *Service) now() time.Time {
+	return s.config.now()
+}
+
+// now returns the current time of the clock, or the system time without one
+func (c Config) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock.Now()
+	}
+	return time.Now()
+}
+
 // generateRandomTokenID creates a random token ID for uniqueness
 func generateRandomTokenID() string {
 	b := make([]byte, 8)
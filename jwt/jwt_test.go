@@ -249,10 +249,103 @@ func TestRefreshToken(t *testing.T) {
 		}
 	})
 
-	t.Run("should allow refresh for expired token", func(_ *testing.T) {
-		// We need to manually create an expired token that we can successfully parse
-		// This is a bit tricky to test properly without modifying the code
-		// For a real implementation, you'd want to make the jwt.Parse function mockable
-		// For now, this is left as a placeholder for this test case
+	t.Run("should allow refresh for expired token", func(t *testing.T) {
+		clock := &fixedClock{now: time.Now()}
+		config := DefaultConfig()
+		config.SigningKey = []byte(testSecretKey)
+		config.Clock = clock
+		service, _ := NewService(config)
+
+		token, _ := service.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+		clock.now = clock.now.Add(config.ExpirationTime + time.Hour)
+
+		if _, err := service.ValidateToken(token); err != ErrExpiredToken {
+			t.Errorf(errMsgExpiredToken, err)
+		}
+		newToken, err := service.RefreshToken(token)
+		if err != nil {
+			t.Fatalf(errMsgNoError, err)
+		}
+		if _, err := service.ValidateToken(newToken); err != nil {
+			t.Errorf(errMsgNoError, err)
+		}
 	})
 }
+
+func TestValidateInto(t *testing.T) {
+	service, _ := NewServiceWithKey([]byte(testSecretKey))
+	token, err := service.GenerateToken(StandardClaims{
+		Subject:  testUserIDCommon,
+		Audience: []string{testAPIAudience},
+		Custom:   map[string]interface{}{"role": testRoleAdmin, "level": 3},
+	})
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	var claims struct {
+		Subject  string   `json:"sub"`
+		Audience []string `json:"aud"`
+		Role     string   `json:"role"`
+		Level    int      `json:"level"`
+		Expires  int64    `json:"exp"`
+	}
+	if err := service.ValidateInto(token, &claims); err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	if claims.Subject != testUserIDCommon {
+		t.Errorf(errMsgExpectedSubject, testUserIDCommon, claims.Subject)
+	}
+	if claims.Role != testRoleAdmin || claims.Level != 3 {
+		t.Errorf("Expected role admin and level 3, got %q and %d", claims.Role, claims.Level)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != testAPIAudience {
+		t.Errorf("Expected audience [%s], got %v", testAPIAudience, claims.Audience)
+	}
+	if claims.Expires <= time.Now().Unix() {
+		t.Errorf("Expected exp in the future, got %d", claims.Expires)
+	}
+
+	if err := service.ValidateInto(testInvalidToken, &claims); err != ErrInvalidToken {
+		t.Errorf(errMsgInvalidToken, err)
+	}
+}
+
+func TestSessionBoundClaims(t *testing.T) {
+	service, _ := NewServiceWithKey([]byte(testSecretKey))
+	token, err := service.GenerateToken(StandardClaims{
+		Subject:     testUserIDCommon,
+		SessionID:   "session-1",
+		Fingerprint: "device-hash",
+	})
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	claims, err := service.ValidateToken(token)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	if claims[ClaimSessionID] != "session-1" || claims[ClaimFingerprint] != "device-hash" {
+		t.Errorf("Expected sid and dfp claims, got %v", claims)
+	}
+
+	// The binding survives a refresh
+	refreshed, err := service.RefreshToken(token)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	claims, _ = service.ValidateToken(refreshed)
+	if claims[ClaimSessionID] != "session-1" || claims[ClaimFingerprint] != "device-hash" {
+		t.Errorf("Expected refreshed token to keep its binding, got %v", claims)
+	}
+}
+
+// fixedClock is a Clock that returns a settable time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
@@ -0,0 +1,95 @@
+// Package jwttest provides helpers for testing code that issues or accepts
+// JWT tokens, covering expiry paths without sleeping.
+package jwttest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/jwt"
+)
+
+// Clock is a jwt.Clock that only moves when told to
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock creates a clock stopped at now
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current time of the clock
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to now
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// NewService creates a jwt.Service for config whose time is controlled by
+// the returned clock, starting at the current time
+func NewService(t testing.TB, config jwt.Config) (*jwt.Service, *Clock) {
+	t.Helper()
+
+	clock := NewClock(now(config))
+	config.Clock = clock
+	service, err := jwt.NewService(config)
+	if err != nil {
+		t.Fatalf("jwttest: failed to create service: %v", err)
+	}
+	return service, clock
+}
+
+// TokenAt returns a token issued with config as if the current time were
+// issuedAt
+func TokenAt(t testing.TB, config jwt.Config, claims jwt.StandardClaims, issuedAt time.Time) string {
+	t.Helper()
+
+	config.Clock = NewClock(issuedAt)
+	service, err := jwt.NewService(config)
+	if err != nil {
+		t.Fatalf("jwttest: failed to create service: %v", err)
+	}
+
+	token, err := service.GenerateToken(claims)
+	if err != nil {
+		t.Fatalf("jwttest: failed to generate token: %v", err)
+	}
+	return token
+}
+
+// ExpiredToken returns a token issued with config that expired an hour ago
+func ExpiredToken(t testing.TB, config jwt.Config, claims jwt.StandardClaims) string {
+	t.Helper()
+	return TokenAt(t, config, claims, now(config).Add(-config.ExpirationTime-time.Hour))
+}
+
+// NotYetValidToken returns a token issued with config an hour in the future
+func NotYetValidToken(t testing.TB, config jwt.Config, claims jwt.StandardClaims) string {
+	t.Helper()
+	return TokenAt(t, config, claims, now(config).Add(time.Hour))
+}
+
+// now returns the current time of the clock configured in config
+func now(config jwt.Config) time.Time {
+	if config.Clock != nil {
+		return config.Clock.Now()
+	}
+	return time.Now()
+}
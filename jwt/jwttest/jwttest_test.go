@@ -0,0 +1,81 @@
+package jwttest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/jwt"
+)
+
+func testConfig() jwt.Config {
+	config := jwt.DefaultConfig()
+	config.SigningKey = []byte("jwttest-secret")
+	config.ExpirationTime = time.Hour
+	return config
+}
+
+var testClaims = jwt.StandardClaims{Subject: "user-1"}
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewClock(start)
+
+	clock.Advance(time.Minute)
+	if got := clock.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected %v, got %v", start.Add(time.Minute), got)
+	}
+
+	clock.Set(start)
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("Expected %v, got %v", start, got)
+	}
+}
+
+func TestServiceExpiry(t *testing.T) {
+	service, clock := NewService(t, testConfig())
+
+	token, err := service.GenerateToken(testClaims)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.ValidateToken(token); err != nil {
+		t.Fatalf("Expected fresh token to be valid, got %v", err)
+	}
+
+	clock.Advance(2 * time.Hour)
+	if _, err := service.ValidateToken(token); err != jwt.ErrExpired {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+
+	// Expired tokens can be refreshed within the refresh duration
+	refreshed, err := service.RefreshToken(token)
+	if err != nil {
+		t.Fatalf("Expected expired token to be refreshed, got %v", err)
+	}
+	if _, err := service.ValidateToken(refreshed); err != nil {
+		t.Errorf("Expected refreshed token to be valid, got %v", err)
+	}
+
+	clock.Advance(8 * 24 * time.Hour)
+	if _, err := service.RefreshToken(token); err != jwt.ErrExpired {
+		t.Errorf("Expected ErrExpired past the refresh duration, got %v", err)
+	}
+}
+
+func TestExpiredAndNotYetValidTokens(t *testing.T) {
+	config := testConfig()
+	service, err := jwt.NewService(config)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := service.ValidateToken(ExpiredToken(t, config, testClaims)); err != jwt.ErrExpired {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+	if _, err := service.ValidateToken(NotYetValidToken(t, config, testClaims)); err != jwt.ErrNotYetValid {
+		t.Errorf("Expected ErrNotYetValid, got %v", err)
+	}
+	if _, err := service.ValidateToken(TokenAt(t, config, testClaims, time.Now().Add(-time.Minute))); err != nil {
+		t.Errorf("Expected token issued a minute ago to be valid, got %v", err)
+	}
+}
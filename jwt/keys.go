@@ -0,0 +1,242 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key rotation errors
+var (
+	ErrMissingKeyID   = errors.New("key ID is required")
+	ErrDuplicateKeyID = errors.New("key ID already exists")
+	ErrActiveKey      = errors.New("the active signing key cannot be removed")
+	ErrManagedKeys    = errors.New("keys are managed by the key provider")
+	ErrExpiredKeys    = errors.New("every signing key has expired")
+)
+
+// Key is a signing key identified by the kid token header. HMAC methods use
+// Secret; RSA, ECDSA and EdDSA methods use PrivateKey to sign and PublicKey,
+// or the public half of PrivateKey, to verify.
+type Key struct {
+	ID         string           // Written to the kid header of signed tokens
+	Secret     []byte           // HMAC secret
+	PrivateKey crypto.Signer    // Asymmetric signing key
+	PublicKey  crypto.PublicKey // Asymmetric verification key, for keys that no longer sign
+	ExpiresAt  time.Time        // Stop accepting tokens signed with this key after this time; zero never expires
+}
+
+// signingKey returns the material used to sign tokens, or nil
+func (k Key) signingKey() any {
+	if len(k.Secret) > 0 {
+		return k.Secret
+	}
+	if k.PrivateKey != nil {
+		return k.PrivateKey
+	}
+	return nil
+}
+
+// verificationKey returns the material used to verify tokens, or nil
+func (k Key) verificationKey() any {
+	switch {
+	case len(k.Secret) > 0:
+		return k.Secret
+	case k.PublicKey != nil:
+		return k.PublicKey
+	case k.PrivateKey != nil:
+		return k.PrivateKey.Public()
+	}
+	return nil
+}
+
+// expired reports whether the key may no longer validate tokens
+func (k Key) expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// keyRing holds the signing keys of a service, newest last
+type keyRing struct {
+	mu       sync.RWMutex
+	keys     []Key
+	provider KeyProvider // Supplies the keys instead of keys when set
+}
+
+// newKeyRing builds a key ring from the configured keys
+func newKeyRing(keys []Key) (*keyRing, error) {
+	ring := &keyRing{}
+	for _, key := range keys {
+		if err := ring.add(key, len(keys) > 1); err != nil {
+			return nil, err
+		}
+	}
+	return ring, nil
+}
+
+// add appends a key, which becomes the signing key
+func (r *keyRing) add(key Key, requireID bool) error {
+	if key.verificationKey() == nil {
+		return ErrMissingKey
+	}
+	if requireID && key.ID == "" {
+		return ErrMissingKeyID
+	}
+	for _, existing := range r.keys {
+		if existing.ID == key.ID {
+			return ErrDuplicateKeyID
+		}
+	}
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+// current returns the keys in use, newest last
+func (r *keyRing) current() ([]Key, error) {
+	if r.provider != nil {
+		keys, err := r.provider.Keys(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			return nil, ErrMissingKey
+		}
+		return keys, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Key(nil), r.keys...), nil
+}
+
+// signing returns the newest key that hasn't expired, so new tokens aren't signed
+// with a key verification rejects
+func (r *keyRing) signing(now time.Time) (Key, error) {
+	keys, err := r.current()
+	if err != nil {
+		return Key{}, err
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i].expired(now) {
+			continue
+		}
+		if keys[i].signingKey() == nil {
+			return Key{}, ErrMissingKey
+		}
+		return keys[i], nil
+	}
+	return Key{}, ErrExpiredKeys
+}
+
+// verification returns the keys that may validate a token with the given kid
+func (r *keyRing) verification(kid string, now time.Time) ([]jwt.VerificationKey, error) {
+	all, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []jwt.VerificationKey
+	for _, key := range all {
+		if key.expired(now) {
+			continue
+		}
+		// Tokens without a kid predate rotation and may match any key
+		if kid == "" || key.ID == kid {
+			keys = append(keys, key.verificationKey())
+		}
+	}
+	return keys, nil
+}
+
+// RotateKey makes key the signing key for new tokens. The previous keys keep
+// validating tokens until they expire; a positive grace period sets the
+// expiry of the former signing key if it has none, so tokens it signed stay
+// valid while they are still in circulation.
+func (s *Service) RotateKey(key Key, grace time.Duration) error {
+	if s.keys == nil {
+		return ErrValidationOnly
+	}
+	if s.keys.provider != nil {
+		return ErrManagedKeys
+	}
+	if key.ID == "" {
+		return ErrMissingKeyID
+	}
+
+	s.keys.mu.Lock()
+	defer s.keys.mu.Unlock()
+
+	previous := len(s.keys.keys) - 1
+	if err := s.keys.add(key, true); err != nil {
+		return err
+	}
+	if grace > 0 && s.keys.keys[previous].ExpiresAt.IsZero() {
+		s.keys.keys[previous].ExpiresAt = s.now().Add(grace)
+	}
+
+	s.prune()
+	return nil
+}
+
+// RemoveKey stops accepting tokens signed with the key with the given ID
+func (s *Service) RemoveKey(id string) error {
+	if s.keys == nil {
+		return ErrValidationOnly
+	}
+	if s.keys.provider != nil {
+		return ErrManagedKeys
+	}
+
+	s.keys.mu.Lock()
+	defer s.keys.mu.Unlock()
+
+	for i, key := range s.keys.keys {
+		if key.ID != id {
+			continue
+		}
+		if i == len(s.keys.keys)-1 {
+			return ErrActiveKey
+		}
+		s.keys.keys = append(s.keys.keys[:i], s.keys.keys[i+1:]...)
+		return nil
+	}
+	return ErrUnknownKey
+}
+
+// Keys returns the IDs of the keys that still validate tokens, newest last
+func (s *Service) Keys() []string {
+	if s.keys == nil {
+		return nil
+	}
+
+	keys, err := s.keys.current()
+	if err != nil {
+		return nil
+	}
+
+	now := s.now()
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !key.expired(now) {
+			ids = append(ids, key.ID)
+		}
+	}
+	return ids
+}
+
+// prune drops expired keys other than the signing key. The caller must hold
+// the key ring lock.
+func (s *Service) prune() {
+	now := s.now()
+	last := len(s.keys.keys) - 1
+	kept := s.keys.keys[:0]
+	for i, key := range s.keys.keys {
+		if i == last || !key.expired(now) {
+			kept = append(kept, key)
+		}
+	}
+	s.keys.keys = kept
+}
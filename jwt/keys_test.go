@@ -0,0 +1,161 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func tokenKid(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("Failed to parse token: %v", err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestNewServiceWithKeys(t *testing.T) {
+	config := DefaultConfig()
+	config.Keys = []Key{{ID: "2024-01", Secret: []byte(testSecretKey)}, {Secret: []byte(testDifferentKey)}}
+	if _, err := NewService(config); err != ErrMissingKeyID {
+		t.Errorf("Expected ErrMissingKeyID, got %v", err)
+	}
+
+	config.Keys[1].ID = "2024-01"
+	if _, err := NewService(config); err != ErrDuplicateKeyID {
+		t.Errorf("Expected ErrDuplicateKeyID, got %v", err)
+	}
+
+	config.Keys[1].ID = "2024-02"
+	service, err := NewService(config)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	token, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	if kid := tokenKid(t, token); kid != "2024-02" {
+		t.Errorf("Expected token to be signed with the newest key, got kid %q", kid)
+	}
+}
+
+func TestRotateKey(t *testing.T) {
+	service, err := NewServiceWithKey([]byte(testSecretKey))
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	legacy, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	if kid := tokenKid(t, legacy); kid != "" {
+		t.Errorf("Expected no kid for a single unnamed key, got %q", kid)
+	}
+
+	if err := service.RotateKey(Key{Secret: []byte(testDifferentKey)}, 0); err != ErrMissingKeyID {
+		t.Errorf("Expected ErrMissingKeyID, got %v", err)
+	}
+	if err := service.RotateKey(Key{ID: "v2", Secret: []byte(testDifferentKey)}, time.Hour); err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	rotated, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	if kid := tokenKid(t, rotated); kid != "v2" {
+		t.Errorf("Expected kid v2, got %q", kid)
+	}
+
+	t.Run("should validate tokens from old and new keys", func(t *testing.T) {
+		for _, token := range []string{legacy, rotated} {
+			if _, err := service.ValidateToken(token); err != nil {
+				t.Errorf(errMsgNoError, err)
+			}
+		}
+	})
+
+	t.Run("should reject unknown kid", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": testUserIDCommon})
+		token.Header["kid"] = "v3"
+		signed, _ := token.SignedString([]byte(testDifferentKey))
+		if _, err := service.ValidateToken(signed); err != ErrInvalidToken {
+			t.Errorf(errMsgInvalidToken, err)
+		}
+	})
+
+	t.Run("should stop accepting expired keys", func(t *testing.T) {
+		service.keys.mu.Lock()
+		service.keys.keys[0].ExpiresAt = time.Now().Add(-time.Second)
+		service.keys.mu.Unlock()
+
+		if _, err := service.ValidateToken(legacy); err != ErrInvalidToken {
+			t.Errorf(errMsgInvalidToken, err)
+		}
+		if _, err := service.ValidateToken(rotated); err != nil {
+			t.Errorf(errMsgNoError, err)
+		}
+		if ids := service.Keys(); len(ids) != 1 || ids[0] != "v2" {
+			t.Errorf("Expected only v2 to be active, got %v", ids)
+		}
+	})
+}
+
+func TestExpiredSigningKey(t *testing.T) {
+	config := DefaultConfig()
+	config.Keys = []Key{
+		{ID: "v1", Secret: []byte(testSecretKey)},
+		{ID: "v2", Secret: []byte(testDifferentKey), ExpiresAt: time.Now().Add(-time.Second)},
+	}
+	service, err := NewService(config)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	// The newest key expired, tokens are signed with a key that still verifies
+	token, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	if kid := tokenKid(t, token); kid != "v1" {
+		t.Errorf("Expected token to be signed with v1, got kid %q", kid)
+	}
+	if _, err := service.ValidateToken(token); err != nil {
+		t.Errorf(errMsgNoError, err)
+	}
+
+	service.keys.mu.Lock()
+	service.keys.keys[0].ExpiresAt = time.Now().Add(-time.Second)
+	service.keys.mu.Unlock()
+	if _, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon}); err != ErrExpiredKeys {
+		t.Errorf("Expected ErrExpiredKeys, got %v", err)
+	}
+}
+
+func TestRemoveKey(t *testing.T) {
+	config := DefaultConfig()
+	config.Keys = []Key{{ID: "v1", Secret: []byte(testSecretKey)}, {ID: "v2", Secret: []byte(testDifferentKey)}}
+	service, err := NewService(config)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	if err := service.RemoveKey("v2"); err != ErrActiveKey {
+		t.Errorf("Expected ErrActiveKey, got %v", err)
+	}
+	if err := service.RemoveKey("missing"); err != ErrUnknownKey {
+		t.Errorf("Expected ErrUnknownKey, got %v", err)
+	}
+	if err := service.RemoveKey("v1"); err != nil {
+		t.Errorf(errMsgNoError, err)
+	}
+	if ids := service.Keys(); len(ids) != 1 || ids[0] != "v2" {
+		t.Errorf("Expected only v2 to remain, got %v", ids)
+	}
+}
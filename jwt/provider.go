@@ -0,0 +1,266 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyProvider supplies the signing keys of a Service, newest last. It is
+// consulted whenever a token is signed or validated, so implementations
+// should cache keys and may pick up rotated ones at any time.
+type KeyProvider interface {
+	Keys(ctx context.Context) ([]Key, error)
+}
+
+// KeyProviderFunc adapts a function to the KeyProvider interface
+type KeyProviderFunc func(ctx context.Context) ([]Key, error)
+
+// Keys calls f(ctx)
+func (f KeyProviderFunc) Keys(ctx context.Context) ([]Key, error) {
+	return f(ctx)
+}
+
+// KeyID derives a stable key ID from key material, so the kid of a key
+// survives restarts and changes when the key does
+func KeyID(material []byte) string {
+	sum := sha256.Sum256(material)
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewEnvKeyProvider reads HMAC secrets from environment variables, oldest
+// first, e.g. NewEnvKeyProvider("JWT_SECRET_PREVIOUS", "JWT_SECRET"). Unset
+// variables are skipped; values prefixed with "base64:" are decoded.
+func NewEnvKeyProvider(names ...string) (KeyProvider, error) {
+	var keys []Key
+	for _, name := range names {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		secret := []byte(value)
+		if encoded, ok := strings.CutPrefix(value, "base64:"); ok {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid base64 secret in %s: %w", name, err)
+			}
+			secret = decoded
+		}
+		keys = append(keys, Key{ID: KeyID(secret), Secret: secret})
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrMissingKey
+	}
+	return KeyProviderFunc(func(context.Context) ([]Key, error) {
+		return keys, nil
+	}), nil
+}
+
+// FileKeyProvider loads PEM encoded keys from files, oldest first, and
+// reloads a file when it changes. The newest file holds the private signing
+// key; older files may hold only a public key to keep validating tokens.
+type FileKeyProvider struct {
+	paths         []string
+	checkInterval time.Duration
+
+	mu        sync.Mutex
+	keys      []Key
+	modTimes  []time.Time
+	lastCheck time.Time
+}
+
+// NewFileKeyProvider loads the PEM files at paths, checking them for changes
+// at most once per second
+func NewFileKeyProvider(paths ...string) (*FileKeyProvider, error) {
+	if len(paths) == 0 {
+		return nil, ErrMissingKey
+	}
+
+	p := &FileKeyProvider{
+		paths:         paths,
+		checkInterval: time.Second,
+		modTimes:      make([]time.Time, len(paths)),
+		keys:          make([]Key, len(paths)),
+	}
+	for i := range paths {
+		if err := p.load(i); err != nil {
+			return nil, err
+		}
+	}
+	p.lastCheck = time.Now()
+	return p, nil
+}
+
+// Keys returns the loaded keys, reloading files that changed on disk. A file
+// that fails to reload, e.g. while it is being rewritten, keeps its previous
+// key.
+func (p *FileKeyProvider) Keys(context.Context) ([]Key, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if time.Since(p.lastCheck) >= p.checkInterval {
+		p.lastCheck = time.Now()
+		for i, path := range p.paths {
+			info, err := os.Stat(path)
+			if err == nil && !info.ModTime().Equal(p.modTimes[i]) {
+				_ = p.load(i)
+			}
+		}
+	}
+	return append([]Key(nil), p.keys...), nil
+}
+
+// load reads the key file at index i. The caller must hold p.mu after
+// construction.
+func (p *FileKeyProvider) load(i int) error {
+	path := p.paths[i]
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read key file: %w", err)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- key file paths come from the application
+	if err != nil {
+		return fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	key, err := ParsePEMKey(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	p.keys[i] = key
+	p.modTimes[i] = info.ModTime()
+	return nil
+}
+
+// ParsePEMKey parses a PEM encoded RSA, ECDSA or Ed25519 private or public
+// key. The key ID is derived from the public key.
+func ParsePEMKey(data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("no PEM data found")
+	}
+
+	var key Key
+	switch block.Type {
+	case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+		signer, err := parsePrivateKey(block)
+		if err != nil {
+			return Key{}, err
+		}
+		key.PrivateKey = signer
+	case "PUBLIC KEY", "RSA PUBLIC KEY":
+		public, err := parsePublicKey(block)
+		if err != nil {
+			return Key{}, err
+		}
+		key.PublicKey = public
+	default:
+		return Key{}, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key.verificationKey())
+	if err != nil {
+		return Key{}, err
+	}
+	key.ID = KeyID(der)
+	return key, nil
+}
+
+// parsePrivateKey parses a PKCS#8, PKCS#1 or SEC 1 private key
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+	return signer, nil
+}
+
+// parsePublicKey parses a PKIX or PKCS#1 public key
+func parsePublicKey(block *pem.Block) (crypto.PublicKey, error) {
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch parsed.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return parsed, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", parsed)
+}
+
+// NewCachedKeyProvider adapts a remote secret store, such as Vault, AWS KMS
+// or a cloud secret manager, by caching the keys returned by fetch for ttl.
+// If a refresh fails, the previous keys are served until fetch succeeds again.
+func NewCachedKeyProvider(fetch KeyProviderFunc, ttl time.Duration) KeyProvider {
+	return &cachedKeyProvider{fetch: fetch, ttl: ttl}
+}
+
+// cachedKeyProvider caches the keys of a remote store
+type cachedKeyProvider struct {
+	fetch KeyProviderFunc
+	ttl   time.Duration
+
+	mu        sync.Mutex
+	keys      []Key
+	fetchedAt time.Time
+}
+
+// Keys returns the cached keys, fetching them when the cache has expired
+func (p *cachedKeyProvider) Keys(ctx context.Context) ([]Key, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil && time.Since(p.fetchedAt) < p.ttl {
+		return p.keys, nil
+	}
+
+	keys, err := p.fetch(ctx)
+	if err != nil || len(keys) == 0 {
+		if p.keys != nil {
+			// Retry after another ttl instead of on every call
+			p.fetchedAt = time.Now()
+			return p.keys, nil
+		}
+		if err == nil {
+			err = ErrMissingKey
+		}
+		return nil, err
+	}
+
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	return keys, nil
+}
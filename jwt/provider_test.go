@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+}
+
+func TestEnvKeyProvider(t *testing.T) {
+	t.Setenv("TEST_JWT_PREVIOUS", testDifferentKey)
+	t.Setenv("TEST_JWT_SECRET", "base64:dGVzdC1zZWNyZXQta2V5") // test-secret-key
+
+	provider, err := NewEnvKeyProvider("TEST_JWT_PREVIOUS", "TEST_JWT_UNSET", "TEST_JWT_SECRET")
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	keys, _ := provider.Keys(context.Background())
+	if len(keys) != 2 || string(keys[1].Secret) != testSecretKey {
+		t.Fatalf("Expected two keys with the decoded secret last, got %v", keys)
+	}
+	if keys[1].ID != KeyID([]byte(testSecretKey)) {
+		t.Errorf("Expected key ID to be derived from the secret, got %s", keys[1].ID)
+	}
+
+	config := DefaultConfig()
+	config.KeyProvider = provider
+	service, err := NewService(config)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	token, _ := service.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+	if _, err := service.ValidateToken(token); err != nil {
+		t.Errorf(errMsgNoError, err)
+	}
+	if err := service.RotateKey(Key{ID: "new", Secret: []byte("x")}, 0); err != ErrManagedKeys {
+		t.Errorf("Expected ErrManagedKeys, got %v", err)
+	}
+
+	if _, err := NewEnvKeyProvider("TEST_JWT_UNSET"); err != ErrMissingKey {
+		t.Errorf(errMsgMissingKey, err)
+	}
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	dir := t.TempDir()
+	oldPath, newPath := filepath.Join(dir, "old.pem"), filepath.Join(dir, "new.pem")
+
+	oldKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	oldPublic, _ := x509.MarshalPKIXPublicKey(&oldKey.PublicKey)
+	writePEM(t, oldPath, "PUBLIC KEY", oldPublic)
+
+	newKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	newDER, _ := x509.MarshalPKCS8PrivateKey(newKey)
+	writePEM(t, newPath, "PRIVATE KEY", newDER)
+
+	provider, err := NewFileKeyProvider(oldPath, newPath)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	config := DefaultConfig()
+	config.SigningMethod = jwt.SigningMethodES256
+	config.KeyProvider = provider
+	service, err := NewService(config)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+
+	t.Run("should sign with the newest key and verify older ones", func(t *testing.T) {
+		token, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+		if err != nil {
+			t.Fatalf(errMsgNoError, err)
+		}
+		if _, err := service.ValidateToken(token); err != nil {
+			t.Errorf(errMsgNoError, err)
+		}
+
+		old := signWithKid(t, jwt.SigningMethodES256, KeyID(oldPublic), oldKey)
+		if _, err := service.ValidateToken(old); err != nil {
+			t.Errorf("Expected token signed with the old key to validate, got %v", err)
+		}
+	})
+
+	t.Run("should reload changed files", func(t *testing.T) {
+		rotated, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		rotatedDER, _ := x509.MarshalECPrivateKey(rotated)
+		writePEM(t, newPath, "EC PRIVATE KEY", rotatedDER)
+		later := time.Now().Add(time.Minute)
+		_ = os.Chtimes(newPath, later, later)
+		provider.lastCheck = time.Time{}
+
+		token, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon})
+		if err != nil {
+			t.Fatalf(errMsgNoError, err)
+		}
+		rotatedPublic, _ := x509.MarshalPKIXPublicKey(&rotated.PublicKey)
+		if kid := tokenKid(t, token); kid != KeyID(rotatedPublic) {
+			t.Errorf("Expected token to be signed with the reloaded key, got kid %s", kid)
+		}
+	})
+
+	t.Run("should keep the previous key when a reload fails", func(t *testing.T) {
+		if err := os.WriteFile(newPath, []byte("partial"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		later := time.Now().Add(2 * time.Minute)
+		_ = os.Chtimes(newPath, later, later)
+		provider.lastCheck = time.Time{}
+
+		if _, err := service.GenerateToken(StandardClaims{Subject: testUserIDCommon}); err != nil {
+			t.Errorf(errMsgNoError, err)
+		}
+	})
+
+	if _, err := NewFileKeyProvider(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Error("Expected error for a missing key file")
+	}
+}
+
+func TestCachedKeyProvider(t *testing.T) {
+	calls := 0
+	fail := false
+	provider := NewCachedKeyProvider(func(context.Context) ([]Key, error) {
+		calls++
+		if fail {
+			return nil, errors.New("vault unavailable")
+		}
+		return []Key{{ID: "vault-1", Secret: []byte(testSecretKey)}}, nil
+	}, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		keys, err := provider.Keys(context.Background())
+		if err != nil || len(keys) != 1 {
+			t.Fatalf("Expected cached key, got %v, %v", keys, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single fetch, got %d", calls)
+	}
+
+	cached := provider.(*cachedKeyProvider)
+	cached.fetchedAt = time.Time{}
+	fail = true
+	if keys, err := provider.Keys(context.Background()); err != nil || keys[0].ID != "vault-1" {
+		t.Errorf("Expected stale keys to be served on failure, got %v, %v", keys, err)
+	}
+
+	failing := NewCachedKeyProvider(func(context.Context) ([]Key, error) {
+		return nil, errors.New("vault unavailable")
+	}, time.Hour)
+	config := DefaultConfig()
+	config.KeyProvider = failing
+	if _, err := NewService(config); err == nil {
+		t.Error("Expected NewService to fail when no keys can be fetched")
+	}
+}
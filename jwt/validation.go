@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claim validation errors
+var (
+	ErrExpired      = ErrExpiredToken
+	ErrNotYetValid  = errors.New("token is not valid yet")
+	ErrIssuer       = errors.New("token has an invalid issuer")
+	ErrAudience     = errors.New("token has an invalid audience")
+	ErrMissingClaim = errors.New("token is missing a required claim")
+)
+
+// ValidationOptions configures the checks applied to standard claims
+type ValidationOptions struct {
+	Issuer         string        // Expected iss claim; empty accepts any issuer
+	Audience       []string      // Accepted aud values, any one of which must be present; empty skips the check
+	Leeway         time.Duration // Clock skew tolerated for exp, nbf and iat
+	RequiredClaims []string      // Claims that must be present, e.g. "exp" or "jti"
+}
+
+// parser builds a token parser enforcing the options for the given algorithms
+func (o ValidationOptions) parser(algorithms []string, now func() time.Time) *jwt.Parser {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(algorithms),
+		jwt.WithLeeway(o.Leeway),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(now),
+	}
+	if o.Issuer != "" {
+		options = append(options, jwt.WithIssuer(o.Issuer))
+	}
+	return jwt.NewParser(options...)
+}
+
+// check validates the claims the parser does not cover
+func (o ValidationOptions) check(claims jwt.MapClaims) error {
+	if len(o.Audience) > 0 {
+		audience, err := claims.GetAudience()
+		if err != nil || !slices.ContainsFunc(audience, func(aud string) bool {
+			return slices.Contains(o.Audience, aud)
+		}) {
+			return ErrAudience
+		}
+	}
+
+	for _, name := range o.RequiredClaims {
+		if value, ok := claims[name]; !ok || value == nil {
+			return fmt.Errorf("%w: %s", ErrMissingClaim, name)
+		}
+	}
+	return nil
+}
+
+// validationError maps parser errors to the package errors
+func validationError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return ErrNotYetValid
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return ErrIssuer
+	}
+	return ErrInvalidToken
+}
@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newValidatingService(t *testing.T, opts ValidationOptions) *Service {
+	t.Helper()
+	config := DefaultConfig()
+	config.SigningKey = []byte(testSecretKey)
+	config.Validation = opts
+	service, err := NewService(config)
+	if err != nil {
+		t.Fatalf(errMsgNoError, err)
+	}
+	return service
+}
+
+func signClaims(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecretKey))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestValidationOptions(t *testing.T) {
+	now := time.Now()
+	service := newValidatingService(t, ValidationOptions{
+		Issuer:         "auth.example.com",
+		Audience:       []string{testAPIAudience, testWebAudience},
+		Leeway:         30 * time.Second,
+		RequiredClaims: []string{"exp", "jti"},
+	})
+
+	valid := jwt.MapClaims{
+		"sub": testUserIDCommon,
+		"iss": "auth.example.com",
+		"aud": testWebAudience,
+		"exp": now.Add(time.Hour).Unix(),
+		"jti": testTokenID,
+	}
+
+	with := func(key string, value any) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		err    error
+	}{
+		{"valid", valid, nil},
+		{"audience list", with("aud", []string{"other", testAPIAudience}), nil},
+		{"expired within leeway", with("exp", now.Add(-10*time.Second).Unix()), nil},
+		{"expired", with("exp", now.Add(-time.Minute).Unix()), ErrExpired},
+		{"not yet valid", with("nbf", now.Add(time.Minute).Unix()), ErrNotYetValid},
+		{"issued in the future", with("iat", now.Add(time.Minute).Unix()), ErrNotYetValid},
+		{"wrong issuer", with("iss", "evil.example.com"), ErrIssuer},
+		{"wrong audience", with("aud", "other"), ErrAudience},
+		{"missing audience", with("aud", nil), ErrAudience},
+		{"missing required claim", with("jti", nil), ErrMissingClaim},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ValidateToken(signClaims(t, tt.claims))
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidationOptionsDefaults(t *testing.T) {
+	service := newValidatingService(t, ValidationOptions{})
+
+	// Without options any issuer and audience is accepted
+	token := signClaims(t, jwt.MapClaims{"sub": testUserIDCommon, "iss": "anyone", "aud": "anything"})
+	if _, err := service.ValidateToken(token); err != nil {
+		t.Errorf(errMsgNoError, err)
+	}
+
+	if ErrExpired != ErrExpiredToken {
+		t.Error("Expected ErrExpired to alias ErrExpiredToken")
+	}
+}
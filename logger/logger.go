@@ -3,8 +3,11 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -29,6 +32,7 @@ const (
 type Logger struct {
 	level  LogLevel
 	prefix string
+	fields string // Pre-formatted key=value pairs appended to every message
 	logger *log.Logger
 }
 
@@ -64,11 +68,54 @@ func (l *Logger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
+// SetOutput sets the destination for log output.
+// Loggers derived with With share the destination of their parent.
+func (l *Logger) SetOutput(w io.Writer) {
+	l.logger.SetOutput(w)
+}
+
 // SetPrefix sets the log prefix
 func (l *Logger) SetPrefix(prefix string) {
 	l.prefix = prefix
 }
 
+// With returns a copy of the logger that appends the given key/value pairs to every
+// message, e.g. With("request_id", id, "method", "GET"). A trailing key without a
+// value is logged with an empty value.
+func (l *Logger) With(keyvals ...any) *Logger {
+	var b strings.Builder
+	b.WriteString(l.fields)
+	for i := 0; i < len(keyvals); i += 2 {
+		var value any = ""
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		b.WriteString(" ")
+		b.WriteString(fmt.Sprint(keyvals[i]))
+		b.WriteString("=")
+		b.WriteString(formatFieldValue(value))
+	}
+
+	child := *l
+	child.fields = b.String()
+	return &child
+}
+
+// formatFieldValue quotes values containing spaces, quotes, '=' or control characters
+// so fields stay parseable and values such as paths can't forge log lines
+func formatFieldValue(value any) string {
+	s := fmt.Sprint(value)
+	if s == "" || strings.ContainsAny(s, " \"=") || strings.ContainsFunc(s, isControl) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// isControl reports whether r is an ASCII control character, e.g. a newline
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
 // log logs a message at the specified level
 func (l *Logger) log(level LogLevel, format string, args ...any) {
 	if level < l.level {
@@ -96,7 +143,7 @@ func (l *Logger) log(level LogLevel, format string, args ...any) {
 
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 	message := fmt.Sprintf(format, args...)
-	l.logger.Printf("%s %s%s: %s", timestamp, prefix, levelStr, message)
+	l.logger.Printf("%s %s%s: %s%s", timestamp, prefix, levelStr, message, l.fields)
 
 	if level == FATAL {
 		osExit(1)
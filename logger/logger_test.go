@@ -429,3 +429,26 @@ func TestNew(t *testing.T) {
 		t.Errorf("Expected output containing '%s', got: '%s'", expectedOutput, output)
 	}
 }
+
+func TestWith(t *testing.T) {
+	base, buf := createTestLogger(INFO)
+	reqLogger := base.With("request_id", "abc123", "path", "/users")
+
+	testLogOutput(t, func() {
+		reqLogger.Info(testInfoMessage)
+	}, buf, "[TEST] INFO: This is info request_id=abc123 path=/users")
+
+	testLogOutput(t, func() {
+		reqLogger.With("user", "John Doe", "dangling").Infof("Hello %s", testArgName)
+	}, buf, `INFO: Hello test request_id=abc123 path=/users user="John Doe" dangling=""`)
+
+	// Control characters are escaped, so values can't forge log lines
+	testLogOutput(t, func() {
+		base.With("path", "/users\nERROR: forged", "tab", "a\tb", "del", "a\x7f").Info(testInfoMessage)
+	}, buf, `INFO: This is info path="/users\nERROR: forged" tab="a\tb" del="a\x7f"`+"\n")
+
+	// The parent logger is not modified
+	testLogOutput(t, func() {
+		base.Info(testInfoMessage)
+	}, buf, "[TEST] INFO: This is info\n")
+}
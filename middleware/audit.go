@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/logger"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// AuditWriter persists audit entries. It is implemented by *dbcontext.EnhancedDbContext;
+// give the auditor its own context, since entries are written from a background goroutine.
+type AuditWriter interface {
+	Add(entity any)
+	SaveChanges() (int, error)
+	Detach(entity any)
+}
+
+// AuditEntry is one audited request, stored in the audit_logs table
+type AuditEntry struct {
+	ID           int64     `db:"id"`
+	Method       string    `db:"method"`
+	Path         string    `db:"path"`
+	Actor        string    `db:"actor"`
+	Status       int       `db:"status"`
+	LatencyMs    int64     `db:"latency_ms"`
+	ClientIP     string    `db:"client_ip"`
+	RequestID    string    `db:"request_id"`
+	RequestBody  string    `db:"request_body"`
+	ResponseBody string    `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// TableName returns the audit table name
+func (*AuditEntry) TableName() string {
+	return "audit_logs"
+}
+
+// AuditOptions configures the audit middleware
+type AuditOptions struct {
+	// ClaimsKey is the context key holding JWT claims used for the actor (default: "user")
+	ClaimsKey string
+	// RequestIDKey is the context key holding the request ID (default: "requestID")
+	RequestIDKey string
+	// LogRequestBody stores the request body
+	LogRequestBody bool
+	// LogResponseBody stores the response body
+	LogResponseBody bool
+	// MaxBodySize truncates stored bodies (default: 4KB)
+	MaxBodySize int
+	// BatchSize is the number of entries saved together (default: 100)
+	BatchSize int
+	// FlushInterval is the longest an entry waits before it is saved (default: 1s)
+	FlushInterval time.Duration
+	// QueueSize bounds the pending entries; entries are dropped when it is full (default: 1000)
+	QueueSize int
+	// OnError is called when entries cannot be saved or are dropped (default: logs the error)
+	OnError func(error)
+	// Skipper bypasses auditing for matching requests
+	Skipper Skipper
+}
+
+// DefaultAuditOptions returns the default audit options
+func DefaultAuditOptions() AuditOptions {
+	return AuditOptions{
+		ClaimsKey:     "user",
+		RequestIDKey:  DefaultRequestIDConfig().ContextKey,
+		MaxBodySize:   4 << 10,
+		BatchSize:     100,
+		FlushInterval: time.Second,
+		QueueSize:     1000,
+		OnError: func(err error) {
+			logger.Get().Errorf("Audit: %v", err)
+		},
+	}
+}
+
+// ErrAuditQueueFull is reported through OnError when an entry is dropped
+var ErrAuditQueueFull = errors.New("audit queue full, entry dropped")
+
+// Auditor records requests and writes them in batches from a background goroutine
+type Auditor struct {
+	writer  AuditWriter
+	options AuditOptions
+	entries chan *AuditEntry
+	done    chan struct{}
+	closed  bool
+	mutex   sync.RWMutex
+}
+
+// NewAuditor starts an auditor. Call Close during shutdown to save pending entries.
+func NewAuditor(writer AuditWriter, options AuditOptions) *Auditor {
+	defaults := DefaultAuditOptions()
+	if options.ClaimsKey == "" {
+		options.ClaimsKey = defaults.ClaimsKey
+	}
+	if options.RequestIDKey == "" {
+		options.RequestIDKey = defaults.RequestIDKey
+	}
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = defaults.MaxBodySize
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaults.BatchSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = defaults.FlushInterval
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaults.QueueSize
+	}
+	if options.OnError == nil {
+		options.OnError = defaults.OnError
+	}
+
+	a := &Auditor{
+		writer:  writer,
+		options: options,
+		entries: make(chan *AuditEntry, options.QueueSize),
+		done:    make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Audit records method, path, actor, status and latency of every request through writer
+func Audit(writer AuditWriter, options AuditOptions) router.Middleware {
+	return NewAuditor(writer, options).Middleware()
+}
+
+// Middleware returns the middleware that records requests
+func (a *Auditor) Middleware() router.Middleware {
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			if a.options.Skipper.skips(c) {
+				next(c)
+				return
+			}
+
+			start := time.Now()
+
+			var requestBody string
+			if a.options.LogRequestBody {
+				if body, err := c.BodyBytes(); err == nil {
+					requestBody = truncate(body, a.options.MaxBodySize)
+				}
+			}
+
+			var capture *bodyCapture
+			if a.options.LogResponseBody {
+				capture = &bodyCapture{ResponseWriter: c.Writer, limit: a.options.MaxBodySize}
+				c.Writer = capture
+			}
+
+			next(c)
+
+			entry := &AuditEntry{
+				Method:      c.Request.Method,
+				Path:        c.Request.URL.Path,
+				Actor:       claimsSubject(c.Value(a.options.ClaimsKey)),
+				Status:      c.StatusCode(),
+				LatencyMs:   time.Since(start).Milliseconds(),
+				ClientIP:    c.ClientIP(),
+				RequestBody: requestBody,
+				CreatedAt:   start,
+			}
+			if requestID, ok := c.Value(a.options.RequestIDKey).(string); ok {
+				entry.RequestID = requestID
+			}
+			if capture != nil {
+				c.Writer = capture.ResponseWriter
+				entry.ResponseBody = capture.body.String()
+			}
+
+			a.enqueue(entry)
+		}
+	}
+}
+
+// Close stops the auditor after saving pending entries
+func (a *Auditor) Close() error {
+	a.mutex.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.entries)
+	}
+	a.mutex.Unlock()
+
+	<-a.done
+	return nil
+}
+
+// enqueue hands an entry to the writer goroutine without blocking the request
+func (a *Auditor) enqueue(entry *AuditEntry) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if a.closed {
+		return
+	}
+
+	select {
+	case a.entries <- entry:
+	default:
+		a.options.OnError(ErrAuditQueueFull)
+	}
+}
+
+// run saves entries in batches until the auditor is closed
+func (a *Auditor) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*AuditEntry, 0, a.options.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		for _, entry := range batch {
+			a.writer.Add(entry)
+		}
+		if _, err := a.writer.SaveChanges(); err != nil {
+			a.options.OnError(err)
+		}
+		// Saved and failed entries alike are released so the tracker does not grow
+		for _, entry := range batch {
+			a.writer.Detach(entry)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case entry, ok := <-a.entries:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= a.options.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// bodyCapture copies up to limit bytes of the response body
+type bodyCapture struct {
+	http.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+// Write writes to the client and keeps a truncated copy
+func (w *bodyCapture) Write(b []byte) (int, error) {
+	if remaining := w.limit - w.body.Len(); remaining > 0 {
+		w.body.Write(b[:min(len(b), remaining)])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter
+func (w *bodyCapture) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// truncate converts a body to a string of at most limit bytes
+func truncate(body []byte, limit int) string {
+	return string(body[:min(len(body), limit)])
+}
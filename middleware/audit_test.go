@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/orm/dbcontext"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+type fakeAuditWriter struct {
+	mutex   sync.Mutex
+	pending []any
+	saved   []*AuditEntry
+	saves   int
+	err     error
+}
+
+func (w *fakeAuditWriter) Add(entity any) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.pending = append(w.pending, entity)
+}
+
+func (w *fakeAuditWriter) SaveChanges() (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.saves++
+	if w.err != nil {
+		return 0, w.err
+	}
+	for _, entity := range w.pending {
+		w.saved = append(w.saved, entity.(*AuditEntry))
+	}
+	return len(w.pending), nil
+}
+
+func (w *fakeAuditWriter) Detach(any) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.pending = nil
+}
+
+func TestAuditRecordsRequests(t *testing.T) {
+	writer := &fakeAuditWriter{}
+	opts := DefaultAuditOptions()
+	opts.ClaimsKey = claimsKey
+	opts.LogRequestBody = true
+	opts.LogResponseBody = true
+	opts.MaxBodySize = 12
+	auditor := NewAuditor(writer, opts)
+
+	auth := &MockJWTAuthenticator{ShouldSucceed: true, Claims: map[string]any{"sub": testUserID}}
+	handler := router.Chain(RequestID(), Auth(auth, claimsKey), auditor.Middleware())(func(c *context.Context) {
+		var body map[string]string
+		_ = c.BindJSON(&body)
+		c.JSON(http.StatusCreated, map[string]string{"id": "order-1", "name": body["name"]})
+	})
+
+	r := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"name":"widget order"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Authorization", validTokenHeader)
+	r.Header.Set("X-Request-ID", "req-9")
+	w := httptest.NewRecorder()
+	handler(context.New(context.NewResponseWriter(w), r))
+
+	if !strings.Contains(w.Body.String(), "widget order") {
+		t.Errorf("Expected handler to read the request body, got %s", w.Body.String())
+	}
+
+	if err := auditor.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(writer.saved) != 1 {
+		t.Fatalf("Expected 1 saved entry, got %d", len(writer.saved))
+	}
+
+	entry := writer.saved[0]
+	if entry.Method != "POST" || entry.Path != "/orders" || entry.Status != http.StatusCreated {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if entry.Actor != testUserID || entry.RequestID != "req-9" {
+		t.Errorf("Expected actor %s and request ID req-9, got %q and %q", testUserID, entry.Actor, entry.RequestID)
+	}
+	if entry.RequestBody != `{"name":"wid` || len(entry.ResponseBody) != 12 {
+		t.Errorf("Expected bodies truncated to 12 bytes, got %q and %q", entry.RequestBody, entry.ResponseBody)
+	}
+}
+
+func TestAuditBatching(t *testing.T) {
+	writer := &fakeAuditWriter{}
+	opts := DefaultAuditOptions()
+	opts.BatchSize = 3
+	opts.FlushInterval = time.Hour
+	auditor := NewAuditor(writer, opts)
+	handler := auditor.Middleware()(func(c *context.Context) { c.Status(http.StatusOK) })
+
+	for range 7 {
+		handler(context.New(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)))
+	}
+	_ = auditor.Close()
+
+	if len(writer.saved) != 7 || writer.saves != 3 {
+		t.Errorf("Expected 7 entries in 3 batches, got %d entries in %d saves", len(writer.saved), writer.saves)
+	}
+
+	// Requests after Close are ignored
+	handler(context.New(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)))
+}
+
+func TestAuditReportsErrors(t *testing.T) {
+	var mutex sync.Mutex
+	var reported []error
+	opts := DefaultAuditOptions()
+	opts.OnError = func(err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		reported = append(reported, err)
+	}
+
+	writer := &fakeAuditWriter{err: errors.New("disk full")}
+	auditor := NewAuditor(writer, opts)
+	auditor.Middleware()(func(c *context.Context) {})(context.New(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)))
+	_ = auditor.Close()
+
+	if len(reported) != 1 || reported[0].Error() != "disk full" {
+		t.Errorf("Expected save error to be reported, got %v", reported)
+	}
+	if len(writer.pending) != 0 {
+		t.Error("Expected failed entries to be detached")
+	}
+}
+
+func TestAuditWithDbContext(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", dbcontext.DefaultSQLiteOptions().DSN(filepath.Join(t.TempDir(), "audit.db")))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer sqlDB.Close()
+	ddl := `CREATE TABLE audit_logs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, method TEXT, path TEXT, actor TEXT, status INTEGER,
+		latency_ms INTEGER, client_ip TEXT, request_id TEXT, request_body TEXT, response_body TEXT, created_at DATETIME)`
+	if _, err := sqlDB.Exec(ddl); err != nil {
+		t.Fatalf("Failed to create audit table: %v", err)
+	}
+	db := dbcontext.NewEnhancedDbContextWithDB(sqlDB)
+
+	auditor := NewAuditor(db, DefaultAuditOptions())
+	handler := auditor.Middleware()(func(c *context.Context) { c.Status(http.StatusNoContent) })
+	for range 3 {
+		handler(context.New(context.NewResponseWriter(httptest.NewRecorder()), httptest.NewRequest("DELETE", "/orders/1", nil)))
+	}
+	_ = auditor.Close()
+
+	count, err := dbcontext.NewEnhancedSet[AuditEntry](db).Where("status", "=", http.StatusNoContent).Count()
+	if err != nil {
+		t.Fatalf("Failed to count audit entries: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 audit rows, got %d", count)
+	}
+}
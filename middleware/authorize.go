@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// Claim names read by RequireRoles and RequireScopes
+var (
+	RoleClaims  = []string{"role", "roles"}
+	ScopeClaims = []string{"scope", "scp", "scopes", "permissions"}
+)
+
+// AuthorizeConfig holds configuration for the Authorize middleware
+type AuthorizeConfig struct {
+	Claims     []string // Claim names holding the granted values, as a list or space-separated string
+	Values     []string // Values to look for
+	RequireAll bool     // Require every value instead of at least one
+	Message    string   // Error message for forbidden requests
+	Skipper    Skipper  // Skips the check, e.g. for public endpoints
+}
+
+// RequireRoles allows requests whose claims carry at least one of the roles.
+// It must run after Auth.
+func RequireRoles(roles ...string) router.Middleware {
+	return Authorize(AuthorizeConfig{Claims: RoleClaims, Values: roles, Message: "Insufficient role"})
+}
+
+// RequireScopes allows requests whose claims carry all of the scopes, as
+// issued in the OAuth2 scope claim or the scp and permissions claims of
+// Azure AD and Auth0. It must run after Auth.
+func RequireScopes(scopes ...string) router.Middleware {
+	return Authorize(AuthorizeConfig{Claims: ScopeClaims, Values: scopes, RequireAll: true, Message: "Insufficient scope"})
+}
+
+// Authorize checks the claims stored by the Auth middleware, responding 401
+// to unauthenticated and 403 to unauthorized requests
+func Authorize(config AuthorizeConfig) router.Middleware {
+	if config.Message == "" {
+		config.Message = "Forbidden"
+	}
+
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			if config.Skipper.skips(c) {
+				next(c)
+				return
+			}
+
+			claims, ok := context.Get(c, AuthClaimsKey)
+			if !ok || claims == nil {
+				c.AbortWithError(http.StatusUnauthorized, "Authentication required")
+				return
+			}
+
+			if !config.allows(claimValues(claims, config.Claims)) {
+				c.AbortWithError(http.StatusForbidden, config.Message)
+				return
+			}
+
+			next(c)
+		}
+	}
+}
+
+// allows reports whether the granted values satisfy the configuration
+func (config AuthorizeConfig) allows(granted []string) bool {
+	if len(config.Values) == 0 {
+		return true
+	}
+	for _, value := range config.Values {
+		has := slices.Contains(granted, value)
+		if has && !config.RequireAll {
+			return true
+		}
+		if !has && config.RequireAll {
+			return false
+		}
+	}
+	return config.RequireAll
+}
+
+// claimValues collects the values of the named claims
+func claimValues(claims any, names []string) []string {
+	m, ok := claims.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var values []string
+	for _, name := range names {
+		switch v := m[name].(type) {
+		case string:
+			values = append(values, strings.Fields(v)...)
+		case []string:
+			values = append(values, v...)
+		case []any:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+func runAuthorize(t *testing.T, claims map[string]any, mw router.Middleware) int {
+	t.Helper()
+	var chain []router.Middleware
+	if claims != nil {
+		chain = append(chain, Auth(&MockJWTAuthenticator{ShouldSucceed: true, Claims: claims}, claimsKey))
+	}
+	chain = append(chain, mw)
+
+	handler := router.Chain(chain...)(func(c *context.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	r.Header.Set("Authorization", validTokenHeader)
+	handler(context.New(w, r))
+	return w.Code
+}
+
+func TestRequireRoles(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]any
+		status int
+	}{
+		{"role claim", map[string]any{"role": "admin"}, http.StatusOK},
+		{"roles list", map[string]any{"roles": []any{"editor", "admin"}}, http.StatusOK},
+		{"other role", map[string]any{"role": "user"}, http.StatusForbidden},
+		{"no roles", map[string]any{"sub": testUserID}, http.StatusForbidden},
+		{"unauthenticated", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := runAuthorize(t, tt.claims, RequireRoles("admin", "owner")); status != tt.status {
+				t.Errorf(errStatusCodeMismatch, tt.status, status)
+			}
+		})
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]any
+		status int
+	}{
+		{"oauth scope string", map[string]any{"scope": "openid orders:read orders:write"}, http.StatusOK},
+		{"auth0 permissions", map[string]any{"permissions": []any{"orders:read", "orders:write"}}, http.StatusOK},
+		{"azure scp", map[string]any{"scp": "orders:read orders:write"}, http.StatusOK},
+		{"missing one scope", map[string]any{"scope": "orders:read"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := runAuthorize(t, tt.claims, RequireScopes("orders:read", "orders:write")); status != tt.status {
+				t.Errorf(errStatusCodeMismatch, tt.status, status)
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithConfig(t *testing.T) {
+	mw := Authorize(AuthorizeConfig{Claims: []string{"groups"}, Values: []string{"ops", "sre"}, RequireAll: true})
+	if status := runAuthorize(t, map[string]any{"groups": []string{"sre", "ops"}}, mw); status != http.StatusOK {
+		t.Errorf(errStatusCodeMismatch, http.StatusOK, status)
+	}
+	if status := runAuthorize(t, map[string]any{"groups": []string{"ops"}}, mw); status != http.StatusForbidden {
+		t.Errorf(errStatusCodeMismatch, http.StatusForbidden, status)
+	}
+}
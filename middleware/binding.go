@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+// ErrTokenBinding is returned by BindClaim when a token is used from another
+// session or device than the one it was issued to
+var ErrTokenBinding = errors.New("token is bound to another device")
+
+// DeviceFingerprint hashes the User-Agent and the given request headers into
+// a stable identifier of the client. Use the same function when issuing
+// tokens and in BindClaim.
+func DeviceFingerprint(headers ...string) func(c *context.Context) string {
+	headers = append([]string{"User-Agent"}, headers...)
+	return func(c *context.Context) string {
+		h := sha256.New()
+		for _, name := range headers {
+			h.Write([]byte(c.Request.Header.Get(name)))
+			h.Write([]byte{0})
+		}
+		return hex.EncodeToString(h.Sum(nil))
+	}
+}
+
+// BindClaim returns an AuthConfig.VerifyClaims hook that accepts a token only
+// if its claim equals the value computed from the current request, such as a
+// device fingerprint or a session cookie. Tokens without the claim are
+// rejected.
+func BindClaim(claim string, value func(c *context.Context) string) func(c *context.Context, claims any) error {
+	return func(c *context.Context, claims any) error {
+		m, ok := claims.(map[string]any)
+		if !ok {
+			return ErrTokenBinding
+		}
+		bound, _ := m[claim].(string)
+		expected := value(c)
+		if bound == "" || subtle.ConstantTimeCompare([]byte(bound), []byte(expected)) != 1 {
+			return ErrTokenBinding
+		}
+		return nil
+	}
+}
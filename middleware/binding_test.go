@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+func TestDeviceFingerprint(t *testing.T) {
+	fingerprint := DeviceFingerprint("Accept-Language")
+	newContext := func(agent, lang string) *context.Context {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("User-Agent", agent)
+		r.Header.Set("Accept-Language", lang)
+		return context.New(httptest.NewRecorder(), r)
+	}
+
+	a := fingerprint(newContext("Firefox", "en"))
+	if a != fingerprint(newContext("Firefox", "en")) {
+		t.Error("Expected fingerprint to be stable")
+	}
+	if a == fingerprint(newContext("Firefox", "de")) || a == fingerprint(newContext("Chrome", "en")) {
+		t.Error("Expected fingerprint to change with the headers")
+	}
+	if len(a) != 64 {
+		t.Errorf("Expected hex encoded sha256, got %q", a)
+	}
+}
+
+func TestAuthVerifyClaims(t *testing.T) {
+	fingerprint := DeviceFingerprint()
+
+	issued := httptest.NewRequest(http.MethodGet, "/", nil)
+	issued.Header.Set("User-Agent", "Firefox")
+	bound := fingerprint(context.New(httptest.NewRecorder(), issued))
+
+	tests := []struct {
+		name   string
+		claims map[string]any
+		agent  string
+		status int
+	}{
+		{"same device", map[string]any{"sub": testUserID, "dfp": bound}, "Firefox", http.StatusOK},
+		{"other device", map[string]any{"sub": testUserID, "dfp": bound}, "curl", http.StatusUnauthorized},
+		{"unbound token", map[string]any{"sub": testUserID}, "Firefox", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handledErr error
+			handler := AuthWithConfig(AuthConfig{
+				Authenticator: &MockJWTAuthenticator{ShouldSucceed: true, Claims: tt.claims},
+				ClaimsKey:     claimsKey,
+				VerifyClaims:  BindClaim("dfp", fingerprint),
+				ErrorHandler: func(c *context.Context, err error) {
+					handledErr = err
+					c.Error(http.StatusUnauthorized, "Invalid token")
+				},
+			})(func(c *context.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Authorization", validTokenHeader)
+			r.Header.Set("User-Agent", tt.agent)
+			handler(context.New(w, r))
+
+			if w.Code != tt.status {
+				t.Errorf(errStatusCodeMismatch, tt.status, w.Code)
+			}
+			if tt.status != http.StatusOK && !errors.Is(handledErr, ErrTokenBinding) {
+				t.Errorf("Expected ErrTokenBinding, got %v", handledErr)
+			}
+		})
+	}
+}
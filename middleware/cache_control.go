@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// Cache header names
+const (
+	HeaderCacheControl     = "Cache-Control"
+	HeaderExpires          = "Expires"
+	HeaderSurrogateControl = "Surrogate-Control"
+)
+
+// CachePolicy describes the caching headers sent for a route
+type CachePolicy struct {
+	MaxAge               time.Duration // max-age directive
+	SMaxAge              time.Duration // s-maxage directive for shared caches
+	StaleWhileRevalidate time.Duration // stale-while-revalidate directive
+	StaleIfError         time.Duration // stale-if-error directive
+	Public               bool          // public directive
+	Private              bool          // private directive
+	NoCache              bool          // no-cache directive
+	NoStore              bool          // no-store directive
+	MustRevalidate       bool          // must-revalidate directive
+	Immutable            bool          // immutable directive
+	Expires              bool          // Also send an Expires header derived from MaxAge
+	SurrogateMaxAge      time.Duration // max-age sent to CDNs in Surrogate-Control
+}
+
+// String returns the Cache-Control header value for the policy
+func (p CachePolicy) String() string {
+	var directives []string
+	flags := []struct {
+		set  bool
+		name string
+	}{
+		{p.Public, "public"},
+		{p.Private, "private"},
+		{p.NoCache, "no-cache"},
+		{p.NoStore, "no-store"},
+		{p.MustRevalidate, "must-revalidate"},
+		{p.Immutable, "immutable"},
+	}
+	for _, flag := range flags {
+		if flag.set {
+			directives = append(directives, flag.name)
+		}
+	}
+
+	durations := []struct {
+		value time.Duration
+		name  string
+	}{
+		{p.MaxAge, "max-age"},
+		{p.SMaxAge, "s-maxage"},
+		{p.StaleWhileRevalidate, "stale-while-revalidate"},
+		{p.StaleIfError, "stale-if-error"},
+	}
+	for _, d := range durations {
+		if d.value > 0 {
+			directives = append(directives, d.name+"="+seconds(d.value))
+		}
+	}
+
+	return strings.Join(directives, ", ")
+}
+
+// apply writes the policy headers to h
+func (p CachePolicy) apply(h http.Header, now time.Time) {
+	if value := p.String(); value != "" {
+		h.Set(HeaderCacheControl, value)
+	}
+	if p.Expires {
+		if p.NoStore || p.NoCache || p.MaxAge <= 0 {
+			h.Set(HeaderExpires, "0")
+		} else {
+			h.Set(HeaderExpires, now.Add(p.MaxAge).UTC().Format(http.TimeFormat))
+		}
+	}
+	if p.SurrogateMaxAge > 0 {
+		h.Set(HeaderSurrogateControl, "max-age="+seconds(p.SurrogateMaxAge))
+	}
+}
+
+// seconds formats a duration as whole seconds
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// cacheRule pairs a route pattern with its policy
+type cacheRule struct {
+	pattern string
+	parts   []string
+	policy  CachePolicy
+}
+
+// CacheControl sets Cache-Control, Expires and Surrogate-Control headers from the policy
+// of the most specific matching route pattern. Patterns use the router syntax, with
+// ":name" matching one segment and a trailing "*" matching any remainder, for example
+// "/api/users/:id" or "/static/*". Headers are only added to responses below 400 and
+// never replace a Cache-Control header set by the handler.
+func CacheControl(policies map[string]CachePolicy) router.Middleware {
+	rules := make([]cacheRule, 0, len(policies))
+	for pattern, policy := range policies {
+		rules = append(rules, cacheRule{pattern: pattern, parts: strings.Split(pattern, "/"), policy: policy})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		si, sj := patternSpecificity(rules[i].parts), patternSpecificity(rules[j].parts)
+		if si != sj {
+			return si > sj
+		}
+		return rules[i].pattern < rules[j].pattern
+	})
+
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			requestParts := strings.Split(c.Request.URL.Path, "/")
+			for _, rule := range rules {
+				if matchPattern(rule.parts, requestParts) {
+					original := c.Writer
+					c.Writer = &cachePolicyWriter{ResponseWriter: original, policy: rule.policy}
+					next(c)
+					c.Writer = original
+					return
+				}
+			}
+			next(c)
+		}
+	}
+}
+
+// patternSpecificity ranks patterns so literal segments beat parameters and wildcards
+func patternSpecificity(parts []string) int {
+	score := 0
+	for _, part := range parts {
+		switch {
+		case part == "*":
+			score--
+		case strings.HasPrefix(part, ":"):
+			score += 2
+		default:
+			score += 3
+		}
+	}
+	return score
+}
+
+// matchPattern reports whether the request path segments match a route pattern
+func matchPattern(pattern, request []string) bool {
+	for i, part := range pattern {
+		if part == "*" && i == len(pattern)-1 {
+			return len(request) >= len(pattern)
+		}
+		if i >= len(request) {
+			return false
+		}
+		if !strings.HasPrefix(part, ":") && part != request[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(request)
+}
+
+// cachePolicyWriter applies a policy just before the response headers are sent
+type cachePolicyWriter struct {
+	http.ResponseWriter
+	policy      CachePolicy
+	wroteHeader bool
+}
+
+// WriteHeader adds the policy headers for successful responses
+func (w *cachePolicyWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		header := w.ResponseWriter.Header()
+		if status < http.StatusBadRequest && header.Get(HeaderCacheControl) == "" {
+			w.policy.apply(header, time.Now())
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write sends an implicit 200 status before the first body bytes
+func (w *cachePolicyWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter
+func (w *cachePolicyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+func TestCachePolicyString(t *testing.T) {
+	tests := []struct {
+		policy   CachePolicy
+		expected string
+	}{
+		{CachePolicy{Public: true, MaxAge: time.Hour}, "public, max-age=3600"},
+		{CachePolicy{Private: true, NoCache: true}, "private, no-cache"},
+		{CachePolicy{NoStore: true}, "no-store"},
+		{CachePolicy{Public: true, Immutable: true, MaxAge: 365 * 24 * time.Hour}, "public, immutable, max-age=31536000"},
+		{CachePolicy{MaxAge: time.Minute, SMaxAge: 10 * time.Minute, StaleWhileRevalidate: 30 * time.Second}, "max-age=60, s-maxage=600, stale-while-revalidate=30"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.String(); got != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	mw := CacheControl(map[string]CachePolicy{
+		"/static/*":      {Public: true, MaxAge: 24 * time.Hour, Expires: true, SurrogateMaxAge: 7 * 24 * time.Hour},
+		"/api/*":         {NoStore: true},
+		"/api/users/:id": {Private: true, MaxAge: time.Minute},
+	})
+	handler := mw(func(c *context.Context) {
+		switch c.Request.URL.Path {
+		case "/api/override":
+			c.SetHeader(HeaderCacheControl, "max-age=5")
+		case "/api/users/missing":
+			c.Error(http.StatusNotFound, "not found")
+			return
+		}
+		c.JSON(http.StatusOK, map[string]string{})
+	})
+
+	tests := []struct {
+		path      string
+		expected  string
+		surrogate string
+	}{
+		{"/static/css/app.css", "public, max-age=86400", "max-age=604800"},
+		{"/api/orders", "no-store", ""},
+		{"/api/users/42", "private, max-age=60", ""},
+		{"/api/override", "max-age=5", ""},
+		{"/api/users/missing", "", ""},
+		{"/other", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler(context.New(w, httptest.NewRequest("GET", tt.path, nil)))
+
+			if got := w.Header().Get(HeaderCacheControl); got != tt.expected {
+				t.Errorf(errHeaderMismatch, HeaderCacheControl, tt.expected, got)
+			}
+			if got := w.Header().Get(HeaderSurrogateControl); got != tt.surrogate {
+				t.Errorf(errHeaderMismatch, HeaderSurrogateControl, tt.surrogate, got)
+			}
+		})
+	}
+}
+
+func TestCacheControlExpires(t *testing.T) {
+	handler := CacheControl(map[string]CachePolicy{
+		"/static/*": {Public: true, MaxAge: time.Hour, Expires: true},
+		"/login":    {NoStore: true, Expires: true},
+	})(func(c *context.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	handler(context.New(w, httptest.NewRequest("GET", "/static/logo.png", nil)))
+	expires, err := http.ParseTime(w.Header().Get(HeaderExpires))
+	if err != nil {
+		t.Fatalf("Expected valid Expires header, got %q", w.Header().Get(HeaderExpires))
+	}
+	if until := time.Until(expires); until < 59*time.Minute || until > time.Hour+time.Second {
+		t.Errorf("Expected Expires about an hour ahead, got %s", until)
+	}
+
+	w = httptest.NewRecorder()
+	handler(context.New(w, httptest.NewRequest("GET", "/login", nil)))
+	if got := w.Header().Get(HeaderExpires); got != "0" {
+		t.Errorf(errHeaderMismatch, HeaderExpires, "0", got)
+	}
+}
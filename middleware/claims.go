@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+// AuthClaimsKey holds the claims of the authenticated request, regardless of
+// the ClaimsKey the Auth middleware was configured with
+var AuthClaimsKey = context.NewKey[any]("claims")
+
+// ErrNoClaims is returned by ClaimsAs when the request is not authenticated
+var ErrNoClaims = errors.New("no claims in context")
+
+// ClaimsAs decodes the claims stored by the Auth middleware into T, usually a
+// struct with json tags matching the claim names
+func ClaimsAs[T any](c *context.Context) (T, error) {
+	var result T
+
+	claims, ok := context.Get(c, AuthClaimsKey)
+	if !ok || claims == nil {
+		return result, ErrNoClaims
+	}
+	if typed, ok := claims.(T); ok {
+		return typed, nil
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode claims: %w", err)
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("failed to decode claims: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+type testClaims struct {
+	Subject string   `json:"sub"`
+	Roles   []string `json:"roles"`
+}
+
+func TestClaimsAs(t *testing.T) {
+	auth := &MockJWTAuthenticator{ShouldSucceed: true, Claims: map[string]any{
+		"sub":   testUserID,
+		"roles": []any{"admin", "editor"},
+	}}
+
+	var claims testClaims
+	var err error
+	handler := Auth(auth, claimsKey)(func(c *context.Context) {
+		claims, err = ClaimsAs[testClaims](c)
+		c.Status(http.StatusOK)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", validTokenHeader)
+	handler(context.New(httptest.NewRecorder(), r))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claims.Subject != testUserID {
+		t.Errorf("Expected subject %s, got %s", testUserID, claims.Subject)
+	}
+	if len(claims.Roles) != 2 || claims.Roles[0] != "admin" {
+		t.Errorf("Expected roles [admin editor], got %v", claims.Roles)
+	}
+}
+
+func TestClaimsAsWithoutAuth(t *testing.T) {
+	c := context.New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if _, err := ClaimsAs[testClaims](c); err != ErrNoClaims {
+		t.Errorf("Expected ErrNoClaims, got %v", err)
+	}
+}
+
+func TestClaimsAsReturnsStoredType(t *testing.T) {
+	c := context.New(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	context.Set[any](c, AuthClaimsKey, &testClaims{Subject: testUserID})
+
+	claims, err := ClaimsAs[*testClaims](c)
+	if err != nil || claims.Subject != testUserID {
+		t.Errorf("Expected stored claims to be returned as is, got %v, %v", claims, err)
+	}
+}
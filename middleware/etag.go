@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/lamboktulussimamora/gra/cache"
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// ETagConfig configures the ETag middleware
+type ETagConfig struct {
+	// Weak emits weak validators (W/"...") instead of strong ones
+	Weak bool
+	// MaxBodySize is the largest response body that is buffered and hashed (default: 1MB).
+	// Larger responses are sent without an ETag.
+	MaxBodySize int
+	// Skipper bypasses the middleware for matching requests
+	Skipper Skipper
+}
+
+// DefaultETagConfig returns the default ETag configuration
+func DefaultETagConfig() ETagConfig {
+	return ETagConfig{
+		MaxBodySize: 1 << 20,
+	}
+}
+
+// ETag hashes successful GET and HEAD response bodies, sets the ETag header and
+// answers matching If-None-Match requests with 304 Not Modified. It uses the same
+// hash as the cache middleware, so either can validate the other's tags.
+func ETag() router.Middleware {
+	return ETagWithConfig(DefaultETagConfig())
+}
+
+// ETagWithConfig returns the ETag middleware with custom configuration
+func ETagWithConfig(config ETagConfig) router.Middleware {
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultETagConfig().MaxBodySize
+	}
+
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			if config.Skipper.skips(c) || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+				next(c)
+				return
+			}
+
+			original := c.Writer
+			writer := &etagWriter{ResponseWriter: original, limit: config.MaxBodySize}
+			c.Writer = writer
+			next(c)
+			c.Writer = original
+
+			if writer.passthrough {
+				return
+			}
+
+			if writer.status == 0 {
+				writer.status = http.StatusOK
+			}
+			if writer.status == http.StatusOK && original.Header().Get(context.HeaderETag) == "" {
+				etag := cache.GenerateETag(writer.body.Bytes())
+				if config.Weak {
+					c.SetWeakETag(etag)
+				} else {
+					c.SetETag(etag)
+				}
+			}
+
+			if writer.status == http.StatusOK && c.IsFresh() {
+				c.NotModified()
+				return
+			}
+
+			original.WriteHeader(writer.status)
+			if _, err := original.Write(writer.body.Bytes()); err != nil {
+				c.Logger().Errorf("Error writing response: %v", err)
+			}
+		}
+	}
+}
+
+// etagWriter buffers the response so it can be hashed before the headers are sent.
+// Once the body exceeds limit, buffered data is flushed and the rest is written through.
+type etagWriter struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	limit       int
+	passthrough bool
+}
+
+// WriteHeader records the status code until the response is sent
+func (w *etagWriter) WriteHeader(status int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write buffers the body, switching to pass-through for large responses
+func (w *etagWriter) Write(b []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.body.Len()+len(b) <= w.limit {
+		return w.body.Write(b)
+	}
+
+	w.passthrough = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if _, err := w.ResponseWriter.Write(w.body.Bytes()); err != nil {
+		return 0, err
+	}
+	w.body.Reset()
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter
+func (w *etagWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
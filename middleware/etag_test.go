@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/cache"
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+func serveETag(handler router.HandlerFunc, method, ifNoneMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, "/items", nil)
+	if ifNoneMatch != "" {
+		r.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	handler(context.New(w, r))
+	return w
+}
+
+func TestETag(t *testing.T) {
+	calls := 0
+	handler := ETag()(func(c *context.Context) {
+		calls++
+		c.JSON(http.StatusOK, map[string]string{"name": "widget"})
+	})
+
+	first := serveETag(handler, http.MethodGet, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Expected 200 with ETag, got %d and %q", first.Code, etag)
+	}
+	if expected := `"` + cache.GenerateETag(first.Body.Bytes()) + `"`; etag != expected {
+		t.Errorf("Expected ETag %s, got %s", expected, etag)
+	}
+
+	second := serveETag(handler, http.MethodGet, etag)
+	if second.Code != http.StatusNotModified {
+		t.Errorf(errStatusCodeMismatch, http.StatusNotModified, second.Code)
+	}
+	if second.Body.Len() != 0 || second.Header().Get("Content-Type") != "" {
+		t.Errorf("Expected empty 304 without content headers, got body %q", second.Body.String())
+	}
+
+	stale := serveETag(handler, http.MethodGet, `"other"`)
+	if stale.Code != http.StatusOK || !strings.Contains(stale.Body.String(), "widget") {
+		t.Errorf("Expected full response for stale ETag, got %d %q", stale.Code, stale.Body.String())
+	}
+	if calls != 3 {
+		t.Errorf("Expected handler to run 3 times, got %d", calls)
+	}
+}
+
+func TestETagSkipsUnsafeMethodsAndErrors(t *testing.T) {
+	handler := ETag()(func(c *context.Context) {
+		if c.Request.Method == http.MethodPost {
+			c.JSON(http.StatusCreated, map[string]string{"id": "1"})
+			return
+		}
+		c.JSON(http.StatusNotFound, map[string]string{"error": "missing"})
+	})
+
+	if w := serveETag(handler, http.MethodPost, "*"); w.Code != http.StatusCreated || w.Header().Get("ETag") != "" {
+		t.Errorf("Expected POST to pass through untouched, got %d with ETag %q", w.Code, w.Header().Get("ETag"))
+	}
+	if w := serveETag(handler, http.MethodGet, "*"); w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+		t.Errorf("Expected 404 without ETag, got %d with ETag %q", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestETagWithConfig(t *testing.T) {
+	body := strings.Repeat("x", 64)
+	handler := ETagWithConfig(ETagConfig{Weak: true})(func(c *context.Context) {
+		_ = c.Render(http.StatusOK, strings.NewReader(body), "text/plain")
+	})
+	if etag := serveETag(handler, http.MethodGet, "").Header().Get("ETag"); !strings.HasPrefix(etag, `W/"`) {
+		t.Errorf("Expected weak ETag, got %q", etag)
+	}
+
+	large := ETagWithConfig(ETagConfig{MaxBodySize: 16})(func(c *context.Context) {
+		_ = c.Render(http.StatusOK, strings.NewReader(body), "text/plain")
+	})
+	w := serveETag(large, http.MethodGet, "")
+	if w.Header().Get("ETag") != "" || w.Body.String() != body {
+		t.Errorf("Expected large body to be streamed without ETag, got ETag %q and %d bytes", w.Header().Get("ETag"), w.Body.Len())
+	}
+}
+
+func TestETagWithCache(t *testing.T) {
+	calls := 0
+	handler := router.Chain(cache.New(), ETag())(func(c *context.Context) {
+		calls++
+		c.JSON(http.StatusOK, map[string]string{"name": "widget"})
+	})
+
+	etag := serveETag(handler, http.MethodGet, "").Header().Get("ETag")
+	w := serveETag(handler, http.MethodGet, etag)
+	if w.Code != http.StatusNotModified {
+		t.Errorf(errStatusCodeMismatch, http.StatusNotModified, w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("Expected cached revalidation to skip the handler, got %d calls", calls)
+	}
+}
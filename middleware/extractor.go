@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+// Token extraction errors
+var (
+	ErrMissingToken   = errors.New("authentication token is required")
+	ErrMalformedToken = errors.New("malformed authentication token")
+)
+
+// TokenExtractor pulls an authentication token from the request. It returns
+// an empty string if the request carries no token in that location, and
+// ErrMalformedToken if one is present but unusable.
+type TokenExtractor func(c *context.Context) (string, error)
+
+// FromBearer extracts the token from an "Authorization: Bearer <token>" header
+func FromBearer() TokenExtractor {
+	return func(c *context.Context) (string, error) {
+		header := c.Request.Header.Get("Authorization")
+		if header == "" {
+			return "", nil
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" || strings.Contains(token, " ") {
+			return "", ErrMalformedToken
+		}
+		return token, nil
+	}
+}
+
+// FromHeader extracts the token from the value of a custom header
+func FromHeader(name string) TokenExtractor {
+	return func(c *context.Context) (string, error) {
+		return strings.TrimSpace(c.Request.Header.Get(name)), nil
+	}
+}
+
+// FromCookie extracts the token from a cookie, for browser sessions
+func FromCookie(name string) TokenExtractor {
+	return func(c *context.Context) (string, error) {
+		cookie, err := c.Request.Cookie(name)
+		if err != nil {
+			return "", nil
+		}
+		return cookie.Value, nil
+	}
+}
+
+// FromQuery extracts the token from a query parameter, for clients such as
+// browser WebSockets that cannot set headers
+func FromQuery(param string) TokenExtractor {
+	return func(c *context.Context) (string, error) {
+		return c.Request.URL.Query().Get(param), nil
+	}
+}
+
+// extractToken tries the extractors in order and returns the first token found
+func extractToken(c *context.Context, extractors []TokenExtractor) (string, error) {
+	missing := ErrMissingToken
+	for _, extract := range extractors {
+		token, err := extract(c)
+		if err != nil {
+			missing = err
+			continue
+		}
+		if token != "" {
+			return token, nil
+		}
+	}
+	return "", missing
+}
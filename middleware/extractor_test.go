@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+func TestTokenExtractors(t *testing.T) {
+	tests := []struct {
+		name      string
+		extractor TokenExtractor
+		setup     func(r *http.Request)
+		token     string
+		err       error
+	}{
+		{"bearer", FromBearer(), func(r *http.Request) { r.Header.Set("Authorization", "Bearer abc") }, "abc", nil},
+		{"bearer lowercase scheme", FromBearer(), func(r *http.Request) { r.Header.Set("Authorization", "bearer abc") }, "abc", nil},
+		{"bearer missing", FromBearer(), func(r *http.Request) {}, "", nil},
+		{"bearer malformed", FromBearer(), func(r *http.Request) { r.Header.Set("Authorization", "Basic abc") }, "", ErrMalformedToken},
+		{"header", FromHeader("X-API-Token"), func(r *http.Request) { r.Header.Set("X-API-Token", " abc ") }, "abc", nil},
+		{"cookie", FromCookie("session"), func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "session", Value: "abc"}) }, "abc", nil},
+		{"cookie missing", FromCookie("session"), func(r *http.Request) {}, "", nil},
+		{"query", FromQuery("token"), func(r *http.Request) { r.URL.RawQuery = "token=abc" }, "abc", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			tt.setup(r)
+			token, err := tt.extractor(context.New(httptest.NewRecorder(), r))
+			if token != tt.token || err != tt.err {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.token, tt.err, token, err)
+			}
+		})
+	}
+}
+
+func TestAuthWithExtractors(t *testing.T) {
+	auth := &MockJWTAuthenticator{ShouldSucceed: true, Claims: map[string]any{"sub": testUserID}}
+	handler := AuthWithConfig(AuthConfig{
+		Authenticator: auth,
+		ClaimsKey:     claimsKey,
+		Extractors:    []TokenExtractor{FromBearer(), FromCookie("token"), FromQuery("access_token")},
+	})(func(c *context.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name    string
+		setup   func(r *http.Request)
+		status  int
+		message string
+	}{
+		{"cookie", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "token", Value: "abc"}) }, http.StatusOK, ""},
+		{"query", func(r *http.Request) { r.URL.RawQuery = "access_token=abc" }, http.StatusOK, ""},
+		{"malformed header falls through", func(r *http.Request) {
+			r.Header.Set("Authorization", "Token abc")
+			r.URL.RawQuery = "access_token=abc"
+		}, http.StatusOK, ""},
+		{"malformed only", func(r *http.Request) { r.Header.Set("Authorization", "Token abc") }, http.StatusUnauthorized, "Malformed authentication token"},
+		{"missing", func(r *http.Request) {}, http.StatusUnauthorized, "Authentication token is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+			tt.setup(r)
+			handler(context.New(w, r))
+
+			if w.Code != tt.status {
+				t.Errorf(errStatusCodeMismatch, tt.status, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.message) {
+				t.Errorf("Expected body to contain %q, got %s", tt.message, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/i18n"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// I18nConfig configures the i18n middleware
+type I18nConfig struct {
+	// QueryParam is the query parameter that selects a locale; empty disables it (default: "lang")
+	QueryParam string
+	// CookieName is the cookie that remembers a locale; empty disables it (default: "lang")
+	CookieName string
+	// Skipper bypasses locale resolution for matching requests
+	Skipper Skipper
+}
+
+// DefaultI18nConfig returns the default i18n configuration
+func DefaultI18nConfig() I18nConfig {
+	return I18nConfig{
+		QueryParam: "lang",
+		CookieName: "lang",
+	}
+}
+
+// I18n resolves the request locale from the query string, a cookie and the
+// Accept-Language header, in that order, falling back to the bundle's default
+// locale. The locale is available through c.Locale() and messages through c.T().
+func I18n(bundle *i18n.Bundle) router.Middleware {
+	return I18nWithConfig(bundle, DefaultI18nConfig())
+}
+
+// I18nWithConfig returns the i18n middleware with custom configuration
+func I18nWithConfig(bundle *i18n.Bundle, config I18nConfig) router.Middleware {
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			if config.Skipper.skips(c) {
+				next(c)
+				return
+			}
+
+			var prefs []context.LanguagePreference
+			if config.QueryParam != "" {
+				if lang := c.GetQuery(config.QueryParam); lang != "" {
+					prefs = append(prefs, context.LanguagePreference{Tag: lang, Quality: 1})
+				}
+			}
+			if config.CookieName != "" {
+				if lang, err := c.GetCookie(config.CookieName); err == nil && lang != "" {
+					prefs = append(prefs, context.LanguagePreference{Tag: lang, Quality: 1})
+				}
+			}
+			prefs = append(prefs, context.ParseAcceptLanguage(c.GetHeader(context.HeaderAcceptLanguage))...)
+
+			locale := context.MatchLocale(prefs, bundle.Locales())
+			context.Set(c, context.LocaleKey, locale)
+			context.Set[context.Translator](c, context.TranslatorKey, bundle)
+			c.SetHeader("Content-Language", locale)
+
+			next(c)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/i18n"
+)
+
+func newI18nBundle() *i18n.Bundle {
+	bundle := i18n.NewBundle("en")
+	bundle.AddMessages("en", map[string]string{"welcome": "Welcome", "errors.email_required": "Email is required"})
+	bundle.AddMessages("id", map[string]string{"welcome": "Selamat datang", "errors.email_required": "Email wajib diisi"})
+	bundle.AddMessages("de", map[string]string{"welcome": "Willkommen"})
+	return bundle
+}
+
+func TestI18nLocaleResolution(t *testing.T) {
+	handler := I18n(newI18nBundle())(func(c *context.Context) {
+		c.JSON(http.StatusOK, map[string]string{"locale": c.Locale(), "message": c.T("welcome")})
+	})
+
+	tests := []struct {
+		name     string
+		url      string
+		cookie   string
+		accept   string
+		expected string
+	}{
+		{"accept language", "/", "", "de-DE,de;q=0.9", "Willkommen"},
+		{"cookie beats header", "/", "id", "de", "Selamat datang"},
+		{"query beats cookie", "/?lang=de", "id", "", "Willkommen"},
+		{"unsupported query falls through", "/?lang=fr", "", "id", "Selamat datang"},
+		{"default locale", "/", "", "fr", "Welcome"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+			}
+			if tt.accept != "" {
+				r.Header.Set("Accept-Language", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			handler(context.New(w, r))
+
+			if !strings.Contains(w.Body.String(), tt.expected) {
+				t.Errorf("Expected %q in response, got %s", tt.expected, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestI18nValidationMessages(t *testing.T) {
+	type signup struct {
+		Email string `json:"email" validate:"required|errors.email_required"`
+	}
+
+	handler := I18n(newI18nBundle())(func(c *context.Context) {
+		var req signup
+		_ = c.BindValidated(&req)
+	})
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept-Language", "id")
+	w := httptest.NewRecorder()
+	handler(context.New(w, r))
+
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Email wajib diisi") {
+		t.Errorf("Expected translated validation message, got %d %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Language"); got != "id" {
+		t.Errorf(errHeaderMismatch, "Content-Language", "id", got)
+	}
+}
+
+func TestI18nBuiltinValidationMessages(t *testing.T) {
+	type signup struct {
+		Name string `json:"name" label:"Nama" validate:"required"`
+	}
+
+	bundle := newI18nBundle()
+	bundle.AddMessages("id", map[string]string{"validation.required": "{{field}} wajib diisi"})
+	handler := I18n(bundle)(func(c *context.Context) {
+		var req signup
+		_ = c.BindValidated(&req)
+	})
+
+	r := httptest.NewRequest("POST", "/?lang=id", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(context.New(w, r))
+
+	if !strings.Contains(w.Body.String(), "Nama wajib diisi") {
+		t.Errorf("Expected localized built-in message, got %s", w.Body.String())
+	}
+}
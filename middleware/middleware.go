@@ -3,7 +3,9 @@ package middleware
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,7 +13,6 @@ import (
 	"time"
 
 	"github.com/lamboktulussimamora/gra/context"
-	"github.com/lamboktulussimamora/gra/logger"
 	"github.com/lamboktulussimamora/gra/router"
 )
 
@@ -20,36 +21,87 @@ type JWTAuthenticator interface {
 	ValidateToken(tokenString string) (any, error)
 }
 
+// AuthConfig holds configuration for the JWT authentication middleware
+type AuthConfig struct {
+	Authenticator JWTAuthenticator // Validates tokens
+	ClaimsKey     string           // Context key under which the claims are stored
+	Skipper       Skipper          // Skips authentication, e.g. for public endpoints
+
+	// Extractors are tried in order to find the token.
+	// Defaults to the Authorization bearer header.
+	Extractors []TokenExtractor
+
+	// ErrorHandler responds to missing, malformed and rejected tokens, e.g. to
+	// map expired tokens and wrong audiences to distinct responses.
+	// Defaults to 401 with a short message.
+	ErrorHandler func(c *context.Context, err error)
+
+	// VerifyClaims runs after the token is validated and rejects it by
+	// returning an error, e.g. for a revoked session or a token replayed
+	// from another device. See BindClaim.
+	VerifyClaims func(c *context.Context, claims any) error
+}
+
+// defaultExtractors read the token from the Authorization bearer header
+var defaultExtractors = []TokenExtractor{FromBearer()}
+
+// extractionMessage describes a token extraction failure to the client
+func extractionMessage(err error, bearerOnly bool) string {
+	switch {
+	case bearerOnly && errors.Is(err, ErrMissingToken):
+		return "Authorization header is required"
+	case bearerOnly && errors.Is(err, ErrMalformedToken):
+		return "Authorization header format must be Bearer <token>"
+	case errors.Is(err, ErrMalformedToken):
+		return "Malformed authentication token"
+	}
+	return "Authentication token is required"
+}
+
 // Auth authenticates requests using JWT
 func Auth(jwtService JWTAuthenticator, claimsKey string) router.Middleware {
+	return AuthWithConfig(AuthConfig{Authenticator: jwtService, ClaimsKey: claimsKey})
+}
+
+// AuthWithConfig authenticates requests using JWT with custom configuration
+func AuthWithConfig(config AuthConfig) router.Middleware {
 	return func(next router.HandlerFunc) router.HandlerFunc {
 		return func(c *context.Context) {
-			// Get the Authorization header
-			authHeader := c.Request.Header.Get("Authorization")
-			if authHeader == "" {
-				c.Error(http.StatusUnauthorized, "Authorization header is required")
+			if config.Skipper.skips(c) {
+				next(c)
 				return
 			}
 
-			// Check if the header has the correct format (Bearer <token>)
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				c.Error(http.StatusUnauthorized, "Authorization header format must be Bearer <token>")
-				return
+			extractors := config.Extractors
+			if len(extractors) == 0 {
+				extractors = defaultExtractors
 			}
 
-			// Extract the token
-			tokenString := parts[1]
+			tokenString, err := extractToken(c, extractors)
+			if err != nil {
+				config.fail(c, err, extractionMessage(err, len(config.Extractors) == 0))
+				return
+			}
 
 			// Validate the token
-			claims, err := jwtService.ValidateToken(tokenString)
+			claims, err := config.Authenticator.ValidateToken(tokenString)
 			if err != nil {
-				c.Error(http.StatusUnauthorized, "Invalid token")
+				config.fail(c, err, "Invalid token")
 				return
 			}
+			if config.VerifyClaims != nil {
+				if err := config.VerifyClaims(c, claims); err != nil {
+					config.fail(c, err, "Invalid token")
+					return
+				}
+			}
 
 			// Add claims to context
-			c.WithValue(claimsKey, claims)
+			c.WithValue(config.ClaimsKey, claims)
+			context.Set(c, AuthClaimsKey, claims)
+			if subject := claimsSubject(claims); subject != "" {
+				c.SetLogger(c.Logger().With("user", subject))
+			}
 
 			// Call the next handler
 			next(c)
@@ -57,23 +109,64 @@ func Auth(jwtService JWTAuthenticator, claimsKey string) router.Middleware {
 	}
 }
 
-// Logger logs incoming requests
+// fail aborts an unauthenticated request through the ErrorHandler, or with a
+// 401 carrying message
+func (config AuthConfig) fail(c *context.Context, err error, message string) {
+	if config.ErrorHandler != nil {
+		config.ErrorHandler(c, err)
+		c.Abort()
+		return
+	}
+	c.AbortWithError(http.StatusUnauthorized, message)
+}
+
+// claimsSubject extracts a user identifier from JWT claims for logging
+func claimsSubject(claims any) string {
+	m, ok := claims.(map[string]any)
+	if !ok {
+		return ""
+	}
+	for _, key := range []string{"sub", "user_id", "id"} {
+		if value, ok := m[key]; ok && value != nil {
+			return fmt.Sprint(value)
+		}
+	}
+	return ""
+}
+
+// LoggerConfig holds configuration for the logger middleware
+type LoggerConfig struct {
+	Skipper Skipper // Skips logging, e.g. for health checks
+}
+
+// Logger logs incoming requests and makes the request-scoped logger
+// available to handlers through c.Logger()
 func Logger() router.Middleware {
+	return LoggerWithConfig(LoggerConfig{})
+}
+
+// LoggerWithConfig logs incoming requests with custom configuration
+func LoggerWithConfig(config LoggerConfig) router.Middleware {
 	return func(next router.HandlerFunc) router.HandlerFunc {
 		return func(c *context.Context) {
+			if config.Skipper.skips(c) {
+				next(c)
+				return
+			}
+
 			// Log the request
 			method := c.Request.Method
 			path := c.Request.URL.Path
 
 			// Log before handling
-			log := logger.Get()
-			log.Infof("Request: %s %s", method, path)
+			start := time.Now()
+			c.Logger().Infof("Request: %s %s", method, path)
 
 			// Call the next handler
 			next(c)
 
-			// Log after handling
-			log.Infof("Completed: %s %s", method, path)
+			// Log after handling; the logger may have gained fields such as the user
+			c.Logger().Infof("Completed: %s %s %d %dB in %s", method, path, c.StatusCode(), c.ResponseSize(), time.Since(start))
 		}
 	}
 }
@@ -84,9 +177,8 @@ func Recovery() router.Middleware {
 		return func(c *context.Context) {
 			defer func() {
 				if err := recover(); err != nil {
-					log := logger.Get()
-					log.Errorf("Panic recovered: %v", err)
-					c.Error(http.StatusInternalServerError, "Internal server error")
+					c.Logger().Errorf("Panic recovered: %v", err)
+					c.AbortWithError(http.StatusInternalServerError, "Internal server error")
 				}
 			}()
 
@@ -95,6 +187,19 @@ func Recovery() router.Middleware {
 	}
 }
 
+// Timeout bounds the request context with a deadline. Handlers and the database calls
+// and HTTP clients they make must use c.RequestContext() to observe it.
+func Timeout(d time.Duration) router.Middleware {
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			cancel := c.WithTimeout(d)
+			defer cancel()
+
+			next(c)
+		}
+	}
+}
+
 // CORSConfig contains configuration options for the CORS middleware
 type CORSConfig struct {
 	AllowOrigins     []string // List of allowed origins (e.g. "http://example.com")
@@ -103,6 +208,7 @@ type CORSConfig struct {
 	ExposeHeaders    []string // List of headers that are safe to expose
 	AllowCredentials bool     // Indicates whether the request can include user credentials
 	MaxAge           int      // Indicates how long the results of a preflight request can be cached (in seconds)
+	Skipper          Skipper  // Skips CORS handling for matching requests
 }
 
 // DefaultCORSConfig returns a default CORS configuration
@@ -203,6 +309,11 @@ func contains(slice []string, item string) bool {
 func CORSWithConfig(config CORSConfig) router.Middleware {
 	return func(next router.HandlerFunc) router.HandlerFunc {
 		return func(c *context.Context) {
+			if config.Skipper.skips(c) {
+				next(c)
+				return
+			}
+
 			// Apply all CORS headers
 			setCORSHeaders(c, config)
 
@@ -278,11 +389,13 @@ func (s *InMemoryStore) Increment(key string, limit int, windowSeconds int) (int
 // RateLimiterConfig contains configuration for the rate limiter
 type RateLimiterConfig struct {
 	Store        RateLimiterStore              // Store for tracking request counts
+	LimiterStore LimiterStore                  // Shared store (e.g. Redis); takes precedence over Store
 	Limit        int                           // Maximum number of requests in the time window
 	Window       int                           // Time window in seconds
 	KeyFunc      func(*context.Context) string // Function to generate a key from the request
 	ExcludeFunc  func(*context.Context) bool   // Function to exclude certain requests from rate limiting
 	ErrorMessage string                        // Error message when rate limit is exceeded
+	Skipper      Skipper                       // Skips rate limiting for matching requests
 }
 
 // RateLimit creates a middleware that limits the number of requests
@@ -294,8 +407,8 @@ func RateLimit(limit int, windowSeconds int) router.Middleware {
 		Limit:  limit,
 		Window: windowSeconds,
 		KeyFunc: func(c *context.Context) string {
-			// Default to IP-based rate limiting
-			return c.Request.RemoteAddr
+			// Default to IP-based rate limiting, honoring trusted proxies
+			return c.ClientIP()
 		},
 		ErrorMessage: "Rate limit exceeded. Try again later.",
 	}
@@ -308,7 +421,7 @@ func RateLimitWithConfig(config RateLimiterConfig) router.Middleware {
 	return func(next router.HandlerFunc) router.HandlerFunc {
 		return func(c *context.Context) {
 			// Check if this request should be excluded from rate limiting
-			if config.ExcludeFunc != nil && config.ExcludeFunc(c) {
+			if config.Skipper.skips(c) || (config.ExcludeFunc != nil && config.ExcludeFunc(c)) {
 				next(c)
 				return
 			}
@@ -316,6 +429,13 @@ func RateLimitWithConfig(config RateLimiterConfig) router.Middleware {
 			// Generate key for this request
 			key := config.KeyFunc(c)
 
+			if config.LimiterStore != nil {
+				if allowRequest(c, config, key) {
+					next(c)
+				}
+				return
+			}
+
 			// Increment counter and check if limit exceeded
 			count, exceeded := config.Store.Increment(key, config.Limit, config.Window)
 
@@ -325,7 +445,7 @@ func RateLimitWithConfig(config RateLimiterConfig) router.Middleware {
 			c.Writer.Header().Set("X-RateLimit-Reset", strconv.Itoa(int(time.Now().Unix())+config.Window))
 
 			if exceeded {
-				c.Error(http.StatusTooManyRequests, config.ErrorMessage)
+				c.AbortWithError(http.StatusTooManyRequests, config.ErrorMessage)
 				return
 			}
 
@@ -334,6 +454,43 @@ func RateLimitWithConfig(config RateLimiterConfig) router.Middleware {
 	}
 }
 
+// RateLimitWithStore creates a rate limiting middleware backed by a LimiterStore,
+// keyed by client IP
+func RateLimitWithStore(store LimiterStore, limit int, window time.Duration) router.Middleware {
+	return RateLimitWithConfig(RateLimiterConfig{
+		LimiterStore: store,
+		Limit:        limit,
+		Window:       int(window.Seconds()),
+		KeyFunc: func(c *context.Context) string {
+			return c.ClientIP()
+		},
+		ErrorMessage: "Rate limit exceeded. Try again later.",
+	})
+}
+
+// allowRequest checks a request against config.LimiterStore, sets the rate limit
+// headers and writes the error response when the limit is exceeded. Store failures
+// let the request through so an unavailable backend does not take the API down.
+func allowRequest(c *context.Context, config RateLimiterConfig, key string) bool {
+	result, err := config.LimiterStore.Allow(c.RequestContext(), key, config.Limit, time.Duration(config.Window)*time.Second)
+	if err != nil {
+		c.Logger().Errorf("Rate limiter unavailable: %v", err)
+		return true
+	}
+
+	c.Writer.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Writer.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Writer.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(result.ResetAfter).Unix(), 10))
+
+	if !result.Allowed {
+		retryAfter := int64(math.Ceil(result.RetryAfter.Seconds()))
+		c.Writer.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+		c.AbortWithError(http.StatusTooManyRequests, config.ErrorMessage)
+		return false
+	}
+	return true
+}
+
 // RequestIDConfig contains configuration for the request ID middleware
 type RequestIDConfig struct {
 	// Generator is a function that generates a request ID
@@ -344,6 +501,8 @@ type RequestIDConfig struct {
 	ContextKey string
 	// ResponseHeader determines if the request ID is included in the response headers
 	ResponseHeader bool
+	// Skipper skips assigning a request ID for matching requests
+	Skipper Skipper
 }
 
 // DefaultRequestIDConfig returns a default request ID configuration
@@ -373,6 +532,11 @@ func RequestID() router.Middleware {
 func RequestIDWithConfig(config RequestIDConfig) router.Middleware {
 	return func(next router.HandlerFunc) router.HandlerFunc {
 		return func(c *context.Context) {
+			if config.Skipper.skips(c) {
+				next(c)
+				return
+			}
+
 			// Check if there's already a request ID in the headers
 			reqID := c.GetHeader(config.HeaderName)
 
@@ -381,8 +545,9 @@ func RequestIDWithConfig(config RequestIDConfig) router.Middleware {
 				reqID = config.Generator()
 			}
 
-			// Store the request ID in the context
+			// Store the request ID in the context and the request logger
 			c.WithValue(config.ContextKey, reqID)
+			c.SetLogger(c.Logger().With("request_id", reqID))
 
 			// Add the request ID to the response header if configured
 			if config.ResponseHeader {
@@ -397,18 +562,19 @@ func RequestIDWithConfig(config RequestIDConfig) router.Middleware {
 
 // SecureHeadersConfig holds configuration for secure headers middleware
 type SecureHeadersConfig struct {
-	XSSProtection             string // X-XSS-Protection header
-	ContentTypeNosniff        string // X-Content-Type-Options header
-	XFrameOptions             string // X-Frame-Options header
-	HSTSMaxAge                int    // Strict-Transport-Security max age in seconds
-	HSTSIncludeSubdomains     bool   // Strict-Transport-Security includeSubdomains flag
-	HSTSPreload               bool   // Strict-Transport-Security preload flag
-	ContentSecurityPolicy     string // Content-Security-Policy header
-	ReferrerPolicy            string // Referrer-Policy header
-	PermissionsPolicy         string // Permissions-Policy header
-	CrossOriginEmbedderPolicy string // Cross-Origin-Embedder-Policy header
-	CrossOriginOpenerPolicy   string // Cross-Origin-Opener-Policy header
-	CrossOriginResourcePolicy string // Cross-Origin-Resource-Policy header
+	XSSProtection             string  // X-XSS-Protection header
+	ContentTypeNosniff        string  // X-Content-Type-Options header
+	XFrameOptions             string  // X-Frame-Options header
+	HSTSMaxAge                int     // Strict-Transport-Security max age in seconds
+	HSTSIncludeSubdomains     bool    // Strict-Transport-Security includeSubdomains flag
+	HSTSPreload               bool    // Strict-Transport-Security preload flag
+	ContentSecurityPolicy     string  // Content-Security-Policy header
+	ReferrerPolicy            string  // Referrer-Policy header
+	PermissionsPolicy         string  // Permissions-Policy header
+	CrossOriginEmbedderPolicy string  // Cross-Origin-Embedder-Policy header
+	CrossOriginOpenerPolicy   string  // Cross-Origin-Opener-Policy header
+	CrossOriginResourcePolicy string  // Cross-Origin-Resource-Policy header
+	Skipper                   Skipper // Skips the headers for matching requests
 }
 
 // DefaultSecureHeadersConfig returns a default configuration for secure headers
@@ -439,7 +605,9 @@ func SecureHeadersWithConfig(config SecureHeadersConfig) router.Middleware {
 	return func(next router.HandlerFunc) router.HandlerFunc {
 		return func(c *context.Context) {
 			// Set security headers before processing the request
-			setSecurityHeaders(c.Writer, config)
+			if !config.Skipper.skips(c) {
+				setSecurityHeaders(c.Writer, config)
+			}
 
 			// Call the next handler
 			next(c)
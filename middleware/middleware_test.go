@@ -1,12 +1,18 @@
 package middleware
 
 import (
+	"bytes"
+	stdcontext "context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/logger"
 	"github.com/lamboktulussimamora/gra/router"
 )
 
@@ -96,6 +102,33 @@ func TestAuth(t *testing.T) {
 	}
 }
 
+// TestAuthErrorHandler tests that rejected tokens are passed to the error handler
+func TestAuthErrorHandler(t *testing.T) {
+	var handledErr error
+	handler := AuthWithConfig(AuthConfig{
+		Authenticator: &MockJWTAuthenticator{ShouldSucceed: false},
+		ClaimsKey:     claimsKey,
+		ErrorHandler: func(c *context.Context, err error) {
+			handledErr = err
+			c.Error(http.StatusForbidden, err.Error())
+		},
+	})(func(c *context.Context) {
+		t.Error("Expected handler not to be called")
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/protected", nil)
+	r.Header.Set("Authorization", validTokenHeader)
+	handler(context.New(w, r))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf(errStatusCodeMismatch, http.StatusForbidden, w.Code)
+	}
+	if handledErr == nil || handledErr.Error() != "invalid token" {
+		t.Errorf("Expected validation error to be passed to the handler, got %v", handledErr)
+	}
+}
+
 // runAuthTest executes a single Auth middleware test case
 func runAuthTest(t *testing.T, authHeader string, shouldSucceed bool, expectedStatus int, claims map[string]any) {
 	// Create test variables
@@ -223,6 +256,35 @@ func TestLogger(t *testing.T) {
 	}
 }
 
+func TestRequestLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger.Get().SetOutput(&buf)
+	defer logger.Get().SetOutput(os.Stdout)
+
+	auth := &MockJWTAuthenticator{ShouldSucceed: true, Claims: map[string]any{"sub": testUserID}}
+	handler := router.Chain(RequestID(), Logger(), Auth(auth, claimsKey))(func(c *context.Context) {
+		c.Logger().Info("handling")
+		c.Status(http.StatusOK)
+	})
+
+	r := httptest.NewRequest("GET", "/orders", nil)
+	r.Header.Set("X-Request-ID", "req-42")
+	r.Header.Set("Authorization", validTokenHeader)
+	handler(context.New(context.NewResponseWriter(httptest.NewRecorder()), r))
+
+	output := buf.String()
+	for _, expected := range []string{
+		"INFO: Request: GET /orders method=GET path=/orders request_id=req-42",
+		"INFO: handling method=GET path=/orders request_id=req-42 user=" + testUserID,
+		"INFO: Completed: GET /orders 200 0B in ",
+		"method=GET path=/orders request_id=req-42 user=" + testUserID,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
 func TestRecovery(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -270,6 +332,33 @@ func TestRecovery(t *testing.T) {
 	}
 }
 
+func TestTimeout(t *testing.T) {
+	var deadlineSet bool
+	var ctxErr error
+
+	handler := Timeout(10 * time.Millisecond)(func(c *context.Context) {
+		_, deadlineSet = c.RequestContext().Deadline()
+		select {
+		case <-c.RequestContext().Done():
+			ctxErr = c.RequestContext().Err()
+		case <-time.After(time.Second):
+		}
+	})
+
+	c := context.New(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	handler(c)
+
+	if !deadlineSet {
+		t.Error("Expected request context to carry a deadline")
+	}
+	if !errors.Is(ctxErr, stdcontext.DeadlineExceeded) {
+		t.Errorf("Expected %v, got %v", stdcontext.DeadlineExceeded, ctxErr)
+	}
+	if _, ok := c.Request.Context().Deadline(); !ok {
+		t.Error("Expected *http.Request context to be replaced as well")
+	}
+}
+
 func TestCORS(t *testing.T) {
 	testCases := []struct {
 		name             string
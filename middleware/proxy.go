@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// Proxy header names rewritten by ProxyHeaders
+const (
+	HeaderXForwardedProto  = "X-Forwarded-Proto"
+	HeaderXForwardedScheme = "X-Forwarded-Scheme"
+	HeaderXForwardedHost   = "X-Forwarded-Host"
+)
+
+// ProxyHeaders sets the request scheme, host and remote address from the
+// Forwarded and X-Forwarded-* headers, but only when the direct peer is in one of
+// trustedCIDRs. Afterwards c.Scheme(), c.IsSecure(), c.Request.Host and
+// c.Request.RemoteAddr describe the original client request, so redirects, URL
+// generation and secure cookies behave as if there were no proxy.
+// It panics if a CIDR is invalid.
+func ProxyHeaders(trustedCIDRs ...string) router.Middleware {
+	proxies, err := context.ParseTrustedProxies(trustedCIDRs...)
+	if err != nil {
+		panic("middleware: " + err.Error())
+	}
+
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			r := c.Request
+			if !proxies.TrustsPeer(r) {
+				next(c)
+				return
+			}
+
+			forwarded := parseForwarded(proxies, r.Header.Values(context.HeaderForwarded))
+
+			if scheme := firstValue(forwarded["proto"], forwardedValue(proxies, r.Header, HeaderXForwardedProto), forwardedValue(proxies, r.Header, HeaderXForwardedScheme)); scheme != "" {
+				if scheme = strings.ToLower(scheme); scheme == "http" || scheme == "https" {
+					c.SetScheme(scheme)
+				}
+			}
+			if host := firstValue(forwarded["host"], forwardedValue(proxies, r.Header, HeaderXForwardedHost)); host != "" {
+				r.Host = host
+				r.URL.Host = host
+			}
+			if ip := proxies.ClientIP(r); ip != "" {
+				r.RemoteAddr = net.JoinHostPort(ip, "0")
+			}
+
+			next(c)
+		}
+	}
+}
+
+// parseForwarded returns the parameters of the RFC 7239 Forwarded element added by
+// the proxy nearest to the client. Elements are walked from right to left, past the
+// ones forwarded for a trusted proxy, like ClientIP does, so the elements a client
+// sends itself are ignored.
+func parseForwarded(proxies context.TrustedProxies, values []string) map[string]string {
+	elements := splitList(values)
+	params := make(map[string]string)
+	for i := len(elements) - 1; i >= 0; i-- {
+		clear(params)
+		for _, pair := range strings.Split(elements[i], ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok {
+				params[strings.ToLower(key)] = strings.Trim(value, `"`)
+			}
+		}
+		if !trustedHop(proxies, params["for"]) {
+			break
+		}
+	}
+	return params
+}
+
+// forwardedValue returns the entry of an X-Forwarded-* header added by the proxy
+// nearest to the client. With an entry per X-Forwarded-For hop, it is the entry of
+// the hop ClientIP returns; otherwise the rightmost one, added by the direct peer.
+func forwardedValue(proxies context.TrustedProxies, header http.Header, name string) string {
+	entries := splitList(header.Values(name))
+	if len(entries) == 0 {
+		return ""
+	}
+	hops := splitList(header.Values(context.HeaderXForwardedFor))
+	if len(hops) != len(entries) {
+		return entries[len(entries)-1]
+	}
+	i := len(hops) - 1
+	for i > 0 && trustedHop(proxies, hops[i]) {
+		i--
+	}
+	return entries[i]
+}
+
+// trustedHop reports whether a forwarded address, possibly with a port or IPv6
+// brackets, is a trusted proxy
+func trustedHop(proxies context.TrustedProxies, hop string) bool {
+	if addrPort, err := netip.ParseAddrPort(hop); err == nil {
+		return proxies.Contains(addrPort.Addr())
+	}
+	addr, err := netip.ParseAddr(strings.Trim(hop, "[]"))
+	return err == nil && proxies.Contains(addr)
+}
+
+// splitList returns the non-empty entries of comma separated header values
+func splitList(values []string) []string {
+	var entries []string
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+// firstValue returns the first non-empty value
+func firstValue(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+func TestProxyHeaders(t *testing.T) {
+	type result struct {
+		scheme, host, clientIP string
+		secure                 bool
+	}
+	var got result
+	handler := ProxyHeaders("10.0.0.0/8")(func(c *context.Context) {
+		got = result{c.Scheme(), c.Request.Host, c.ClientIP(), c.IsSecure()}
+	})
+
+	tests := []struct {
+		name     string
+		remote   string
+		headers  map[string]string
+		expected result
+	}{
+		{
+			name:     "x-forwarded from trusted proxy",
+			remote:   "10.0.0.5:4321",
+			headers:  map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "shop.example.com", "X-Forwarded-For": "203.0.113.7, 10.0.0.9"},
+			expected: result{"https", "shop.example.com", "203.0.113.7", true},
+		},
+		{
+			name:     "forwarded header",
+			remote:   "10.1.2.3:80",
+			headers:  map[string]string{"Forwarded": `for="198.51.100.2";proto=https;host=api.example.com, for=10.0.0.9`},
+			expected: result{"https", "api.example.com", "198.51.100.2", true},
+		},
+		{
+			name:     "forwarded elements sent by the client are ignored",
+			remote:   "10.0.0.5:4321",
+			headers:  map[string]string{"Forwarded": `for=192.0.2.1;proto=https;host=evil.example.com, for=203.0.113.7;proto=http;host=shop.example.com`},
+			expected: result{"http", "shop.example.com", "203.0.113.7", false},
+		},
+		{
+			name:     "x-forwarded entries sent by the client are ignored",
+			remote:   "10.0.0.5:4321",
+			headers:  map[string]string{"X-Forwarded-Proto": "http, https", "X-Forwarded-Host": "evil.example.com, shop.example.com"},
+			expected: result{"https", "shop.example.com", "10.0.0.5", true},
+		},
+		{
+			name:   "x-forwarded entries of the client hop",
+			remote: "10.0.0.5:4321",
+			headers: map[string]string{
+				"X-Forwarded-For":   "192.0.2.1, 203.0.113.7, 10.0.0.9",
+				"X-Forwarded-Proto": "http, https, http",
+				"X-Forwarded-Host":  "evil.example.com, shop.example.com, internal.example.com",
+			},
+			expected: result{"https", "shop.example.com", "203.0.113.7", true},
+		},
+		{
+			name:     "untrusted peer is ignored",
+			remote:   "203.0.113.50:5555",
+			headers:  map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "evil.example.com", "X-Forwarded-For": "1.2.3.4"},
+			expected: result{"http", "example.com", "203.0.113.50", false},
+		},
+		{
+			name:     "invalid scheme is ignored",
+			remote:   "10.0.0.5:4321",
+			headers:  map[string]string{"X-Forwarded-Proto": "javascript"},
+			expected: result{"http", "example.com", "10.0.0.5", false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			handler(context.New(httptest.NewRecorder(), r))
+
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProxyHeadersAbsoluteTarget(t *testing.T) {
+	var scheme string
+	handler := ProxyHeaders("10.0.0.0/8")(func(c *context.Context) {
+		scheme = c.Scheme()
+	})
+
+	// The scheme of an absolute request target is chosen by the client
+	r := httptest.NewRequest(http.MethodGet, "https://shop.example.com/", nil)
+	r.RemoteAddr = "203.0.113.50:5555"
+	r.TLS = nil // Set by httptest for https targets
+	handler(context.New(httptest.NewRecorder(), r))
+	if scheme != "http" {
+		t.Errorf("Expected http without TLS or a trusted proxy, got %s", scheme)
+	}
+}
+
+func TestProxyHeadersInvalidCIDR(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for invalid CIDR")
+		}
+	}()
+	ProxyHeaders("10.0.0.0/99")
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// LimitResult describes the outcome of a rate limit check
+type LimitResult struct {
+	Allowed    bool          // Whether the request may proceed
+	Limit      int           // Maximum number of requests per window
+	Remaining  int           // Requests left before the limit is reached
+	RetryAfter time.Duration // When denied, how long until a request will be allowed
+	ResetAfter time.Duration // How long until the limit fully resets
+}
+
+// LimiterStore is a rate limiter backend shared by all instances of an application.
+// Implementations must be safe for concurrent use.
+type LimiterStore interface {
+	// Allow records a request for key and reports whether it is within limit per window
+	Allow(ctx stdcontext.Context, key string, limit int, window time.Duration) (LimitResult, error)
+}
+
+// gcra applies the generic cell rate algorithm. tat is the stored theoretical
+// arrival time (zero if unknown); it returns the new value to store (zero when the
+// request is denied and nothing changes). Limit requests are allowed in a burst,
+// after which requests are admitted at a steady rate of limit per window.
+func gcra(now, tat time.Time, limit int, window time.Duration) (time.Time, LimitResult) {
+	interval := window / time.Duration(limit)
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	allowAt := newTAT.Add(-window)
+	result := LimitResult{Limit: limit}
+
+	if now.Before(allowAt) {
+		result.RetryAfter = allowAt.Sub(now)
+		result.ResetAfter = tat.Sub(now)
+		return time.Time{}, result
+	}
+
+	result.Allowed = true
+	result.ResetAfter = newTAT.Sub(now)
+	result.Remaining = int((window - result.ResetAfter) / interval)
+	return newTAT, result
+}
+
+// GCRAMemoryStore is an in-process LimiterStore using the generic cell rate algorithm.
+// It suits single instances and tests; use RedisLimiterStore across instances.
+type GCRAMemoryStore struct {
+	mu   sync.Mutex
+	tats map[string]time.Time
+	now  func() time.Time
+}
+
+// NewGCRAMemoryStore creates an in-memory GCRA limiter store
+func NewGCRAMemoryStore() *GCRAMemoryStore {
+	return &GCRAMemoryStore{
+		tats: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+// Allow implements LimiterStore
+func (s *GCRAMemoryStore) Allow(_ stdcontext.Context, key string, limit int, window time.Duration) (LimitResult, error) {
+	if limit <= 0 || window <= 0 {
+		return LimitResult{}, errors.New("rate limit and window must be positive")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	newTAT, result := gcra(now, s.tats[key], limit, window)
+	if !newTAT.IsZero() {
+		s.tats[key] = newTAT
+	}
+
+	// Drop expired entries opportunistically to bound memory
+	if len(s.tats) > 10000 {
+		for k, tat := range s.tats {
+			if tat.Before(now) {
+				delete(s.tats, k)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// RedisScripter runs a Lua script on Redis. It is satisfied by a small adapter
+// around any Redis client, e.g. for go-redis:
+//
+//	type scripter struct{ rdb *redis.Client }
+//
+//	func (s scripter) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+//		return s.rdb.Eval(ctx, script, keys, args...).Result()
+//	}
+type RedisScripter interface {
+	Eval(ctx stdcontext.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// gcraScript implements gcra atomically on Redis using the server clock.
+// Times are in microseconds. Returns {allowed, remaining, retry_after, reset_after}.
+const gcraScript = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local interval = window / limit
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+  tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - window
+if now < allow_at then
+  return {0, 0, math.ceil(allow_at - now), math.ceil(tat - now)}
+end
+
+local reset_after = new_tat - now
+redis.call('SET', KEYS[1], string.format('%d', new_tat), 'PX', math.ceil(reset_after / 1000))
+return {1, math.floor((window - reset_after) / interval), 0, math.ceil(reset_after)}
+`
+
+// RedisLimiterStore is a LimiterStore backed by Redis, so limits hold across
+// every instance behind a load balancer. It uses GCRA, which needs a single key
+// per client and no cleanup.
+type RedisLimiterStore struct {
+	client RedisScripter
+	prefix string
+}
+
+// NewRedisLimiterStore creates a Redis limiter store. Keys are stored as prefix+key;
+// an empty prefix defaults to "gra:ratelimit:".
+func NewRedisLimiterStore(client RedisScripter, prefix string) *RedisLimiterStore {
+	if prefix == "" {
+		prefix = "gra:ratelimit:"
+	}
+	return &RedisLimiterStore{client: client, prefix: prefix}
+}
+
+// Allow implements LimiterStore
+func (s *RedisLimiterStore) Allow(ctx stdcontext.Context, key string, limit int, window time.Duration) (LimitResult, error) {
+	if limit <= 0 || window <= 0 {
+		return LimitResult{}, errors.New("rate limit and window must be positive")
+	}
+
+	reply, err := s.client.Eval(ctx, gcraScript, []string{s.prefix + key}, limit, window.Microseconds())
+	if err != nil {
+		return LimitResult{}, fmt.Errorf("rate limit script failed: %w", err)
+	}
+
+	values, err := int64Slice(reply, 4)
+	if err != nil {
+		return LimitResult{}, err
+	}
+
+	return LimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
+
+// int64Slice converts a Redis array reply into n integers
+func int64Slice(reply any, n int) ([]int64, error) {
+	items, ok := reply.([]any)
+	if !ok || len(items) != n {
+		return nil, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+
+	values := make([]int64, n)
+	for i, item := range items {
+		switch v := item.(type) {
+		case int64:
+			values[i] = v
+		case int:
+			values[i] = int64(v)
+		case float64:
+			values[i] = int64(math.Round(v))
+		default:
+			return nil, fmt.Errorf("unexpected rate limit reply value %v", item)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	stdcontext "context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+)
+
+func TestGCRAMemoryStore(t *testing.T) {
+	store := NewGCRAMemoryStore()
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+	ctx := stdcontext.Background()
+
+	// The full limit is available as a burst
+	for i := 0; i < 3; i++ {
+		result, err := store.Allow(ctx, "client", 3, time.Minute)
+		if err != nil || !result.Allowed {
+			t.Fatalf("Expected request %d to be allowed, got %+v (%v)", i+1, result, err)
+		}
+		if result.Remaining != 2-i {
+			t.Errorf("Expected remaining %d, got %d", 2-i, result.Remaining)
+		}
+	}
+
+	result, _ := store.Allow(ctx, "client", 3, time.Minute)
+	if result.Allowed {
+		t.Fatal("Expected fourth request to be denied")
+	}
+	if result.RetryAfter != 20*time.Second {
+		t.Errorf("Expected retry after 20s, got %s", result.RetryAfter)
+	}
+
+	// Other keys are limited independently
+	if result, _ := store.Allow(ctx, "other", 3, time.Minute); !result.Allowed {
+		t.Error("Expected a different key to be allowed")
+	}
+
+	// One interval later a single request is admitted again
+	now = now.Add(20 * time.Second)
+	if result, _ := store.Allow(ctx, "client", 3, time.Minute); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Expected request to be allowed after refill, got %+v", result)
+	}
+}
+
+func TestGCRAMemoryStoreInvalidLimit(t *testing.T) {
+	if _, err := NewGCRAMemoryStore().Allow(stdcontext.Background(), "k", 0, time.Second); err == nil {
+		t.Error("Expected error for a zero limit")
+	}
+}
+
+// fakeRedis emulates the GCRA script on top of the in-memory algorithm
+type fakeRedis struct {
+	keys  []string
+	args  []any
+	store *GCRAMemoryStore
+	err   error
+}
+
+func (f *fakeRedis) Eval(ctx stdcontext.Context, script string, keys []string, args ...any) (any, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if !strings.Contains(script, "redis.call('TIME')") {
+		return nil, errors.New("unexpected script")
+	}
+	f.keys, f.args = keys, args
+
+	limit := args[0].(int)
+	window := time.Duration(args[1].(int64)) * time.Microsecond
+	result, _ := f.store.Allow(ctx, keys[0], limit, window)
+
+	allowed := int64(0)
+	if result.Allowed {
+		allowed = 1
+	}
+	return []any{allowed, int64(result.Remaining), result.RetryAfter.Microseconds(), result.ResetAfter.Microseconds()}, nil
+}
+
+func TestRedisLimiterStore(t *testing.T) {
+	redis := &fakeRedis{store: NewGCRAMemoryStore()}
+	store := NewRedisLimiterStore(redis, "")
+
+	result, err := store.Allow(stdcontext.Background(), "1.2.3.4", 2, time.Second)
+	if err != nil {
+		t.Fatalf("Allow returned error: %v", err)
+	}
+	if !result.Allowed || result.Remaining != 1 || result.Limit != 2 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if redis.keys[0] != "gra:ratelimit:1.2.3.4" {
+		t.Errorf("Expected prefixed key, got %s", redis.keys[0])
+	}
+	if redis.args[1].(int64) != time.Second.Microseconds() {
+		t.Errorf("Expected window in microseconds, got %v", redis.args[1])
+	}
+
+	redis.err = errors.New("connection refused")
+	if _, err := store.Allow(stdcontext.Background(), "1.2.3.4", 2, time.Second); err == nil {
+		t.Error("Expected Redis errors to be returned")
+	}
+}
+
+func TestRateLimitWithStore(t *testing.T) {
+	handler := RateLimitWithStore(NewGCRAMemoryStore(), 1, time.Minute)(func(c *context.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "203.0.113.1:1234"
+		handler(context.New(w, r))
+		return w
+	}
+
+	if w := serve(); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf(errStatusCodeMismatch, http.StatusOK, w.Code)
+	}
+
+	w := serve()
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf(errStatusCodeMismatch, http.StatusTooManyRequests, w.Code)
+	}
+	if w.Header().Get("Retry-After") != "60" {
+		t.Errorf(errHeaderMismatch, "Retry-After", "60", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestRateLimitStoreFailureAllowsRequest(t *testing.T) {
+	store := NewRedisLimiterStore(&fakeRedis{err: errors.New("down")}, "app:")
+	handlerCalled := false
+	handler := RateLimitWithStore(store, 1, time.Minute)(func(c *context.Context) {
+		handlerCalled = true
+	})
+
+	handler(context.New(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)))
+	if !handlerCalled {
+		t.Error(errExpectedHandlerCalled)
+	}
+}
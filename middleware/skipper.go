@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// Skipper reports whether a middleware should be bypassed for a request.
+// Middleware configs accept a Skipper field, and Skip adds one to any middleware.
+type Skipper func(*context.Context) bool
+
+// skips reports whether the skipper is set and returns true for the request
+func (s Skipper) skips(c *context.Context) bool {
+	return s != nil && s(c)
+}
+
+// Skip wraps a middleware so that it is bypassed when skipper returns true
+func Skip(skipper Skipper, middleware router.Middleware) router.Middleware {
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		wrapped := middleware(next)
+		return func(c *context.Context) {
+			if skipper.skips(c) {
+				next(c)
+				return
+			}
+			wrapped(c)
+		}
+	}
+}
+
+// SkipPaths returns a Skipper for the given request paths, e.g. "/healthz".
+// Entries ending in "*" match by prefix, e.g. "/static/*".
+func SkipPaths(paths ...string) Skipper {
+	return func(c *context.Context) bool {
+		return skipPath(c.Request.URL.Path, paths)
+	}
+}
+
+// SkipMethods returns a Skipper for the given HTTP methods, e.g. http.MethodOptions
+func SkipMethods(methods ...string) Skipper {
+	return func(c *context.Context) bool {
+		return contains(methods, c.Request.Method)
+	}
+}
+
+// skipPath reports whether path matches one of the excluded paths
+func skipPath(path string, skipPaths []string) bool {
+	for _, skip := range skipPaths {
+		if prefix, ok := strings.CutSuffix(skip, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == skip {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+func TestSkipPathsAndMethods(t *testing.T) {
+	paths := SkipPaths("/healthz", "/static/*")
+	methods := SkipMethods(http.MethodOptions)
+
+	tests := []struct {
+		method, path string
+		skipPath     bool
+		skipMethod   bool
+	}{
+		{http.MethodGet, "/healthz", true, false},
+		{http.MethodGet, "/healthz/live", false, false},
+		{http.MethodGet, "/static/css/app.css", true, false},
+		{http.MethodOptions, "/api", false, true},
+	}
+
+	for _, tt := range tests {
+		c := context.New(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+		if got := paths(c); got != tt.skipPath {
+			t.Errorf("SkipPaths(%s) = %v, expected %v", tt.path, got, tt.skipPath)
+		}
+		if got := methods(c); got != tt.skipMethod {
+			t.Errorf("SkipMethods(%s) = %v, expected %v", tt.method, got, tt.skipMethod)
+		}
+	}
+}
+
+func TestSkip(t *testing.T) {
+	blocking := func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			c.AbortWithStatus(http.StatusForbidden)
+		}
+	}
+	handler := router.Chain(Skip(SkipPaths("/public"), blocking))(func(c *context.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for path, expected := range map[string]int{"/public": http.StatusOK, "/private": http.StatusForbidden} {
+		w := httptest.NewRecorder()
+		handler(context.New(w, httptest.NewRequest(http.MethodGet, path, nil)))
+		if w.Code != expected {
+			t.Errorf("Expected %s to return %d, got %d", path, expected, w.Code)
+		}
+	}
+}
+
+func TestSkipperOnShippedMiddleware(t *testing.T) {
+	public := SkipPaths("/login")
+	ok := func(c *context.Context) { c.Status(http.StatusOK) }
+
+	auth := AuthWithConfig(AuthConfig{Authenticator: &MockJWTAuthenticator{}, ClaimsKey: claimsKey, Skipper: public})(ok)
+	limited := RateLimitWithConfig(RateLimiterConfig{Store: NewInMemoryStore(), Limit: 0, Window: 60, Skipper: public,
+		KeyFunc: func(c *context.Context) string { return c.ClientIP() }})(ok)
+	headers := SecureHeadersWithConfig(SecureHeadersConfig{XFrameOptions: "DENY", Skipper: public})(ok)
+
+	for name, handler := range map[string]router.HandlerFunc{"auth": auth, "ratelimit": limited, "secureheaders": headers} {
+		w := httptest.NewRecorder()
+		handler(context.New(w, httptest.NewRequest(http.MethodPost, "/login", nil)))
+		if w.Code != http.StatusOK || w.Header().Get("X-Frame-Options") != "" {
+			t.Errorf("Expected %s to be skipped, got %d", name, w.Code)
+		}
+
+		w = httptest.NewRecorder()
+		handler(context.New(w, httptest.NewRequest(http.MethodPost, "/orders", nil)))
+		if w.Code == http.StatusOK && w.Header().Get("X-Frame-Options") == "" {
+			t.Errorf("Expected %s to apply to /orders", name)
+		}
+	}
+}
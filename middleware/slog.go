@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	stdcontext "context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// SlogOptions configures the structured logging middleware
+type SlogOptions struct {
+	// Message is the log message for each request (default: "request")
+	Message string
+	// SkipPaths lists request paths that are never logged, e.g. "/healthz".
+	// Entries ending in "*" match by prefix.
+	SkipPaths []string
+	// SampleRate is the fraction of successful requests that are logged, between 0 and 1.
+	// Zero logs every request. Client and server errors are always logged.
+	SampleRate float64
+	// RequestIDKey is the context key holding the request ID (default: "requestID")
+	RequestIDKey string
+	// ClaimsKey is the context key holding JWT claims used for the user field (default: "user")
+	ClaimsKey string
+	// Skipper bypasses logging for matching requests
+	Skipper Skipper
+}
+
+// DefaultSlogOptions returns the default structured logging options
+func DefaultSlogOptions() SlogOptions {
+	return SlogOptions{
+		Message:      "request",
+		SkipPaths:    []string{"/healthz", "/readyz"},
+		RequestIDKey: DefaultRequestIDConfig().ContextKey,
+		ClaimsKey:    "user",
+	}
+}
+
+// SlogLogger logs one structured record per request with method, path, status,
+// bytes, latency, client IP, request ID and user fields. 5xx responses are logged
+// at error level and 4xx at warn level.
+func SlogLogger(handler slog.Handler, opts SlogOptions) router.Middleware {
+	defaults := DefaultSlogOptions()
+	if opts.Message == "" {
+		opts.Message = defaults.Message
+	}
+	if opts.RequestIDKey == "" {
+		opts.RequestIDKey = defaults.RequestIDKey
+	}
+	if opts.ClaimsKey == "" {
+		opts.ClaimsKey = defaults.ClaimsKey
+	}
+	log := slog.New(handler)
+
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			if opts.Skipper.skips(c) || skipPath(c.Request.URL.Path, opts.SkipPaths) {
+				next(c)
+				return
+			}
+
+			start := time.Now()
+			next(c)
+			latency := time.Since(start)
+
+			status := c.StatusCode()
+			level := slog.LevelInfo
+			switch {
+			case status >= 500:
+				level = slog.LevelError
+			case status >= 400:
+				level = slog.LevelWarn
+			}
+
+			if level == slog.LevelInfo && opts.SampleRate > 0 && opts.SampleRate < 1 && rand.Float64() >= opts.SampleRate {
+				return
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.Int("status", status),
+				slog.Int64("bytes", c.ResponseSize()),
+				slog.Duration("latency", latency),
+				slog.String("client_ip", c.ClientIP()),
+			}
+			if requestID, ok := c.Value(opts.RequestIDKey).(string); ok && requestID != "" {
+				attrs = append(attrs, slog.String("request_id", requestID))
+			}
+			if user := claimsSubject(c.Value(opts.ClaimsKey)); user != "" {
+				attrs = append(attrs, slog.String("user", user))
+			}
+
+			log.LogAttrs(stdcontext.Background(), level, opts.Message, attrs...)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+func serveSlog(mw router.Middleware, path string, status int) {
+	handler := router.Chain(RequestID(), mw)(func(c *context.Context) {
+		c.JSON(status, map[string]string{"ok": "yes"})
+	})
+	r := httptest.NewRequest("GET", path, nil)
+	r.Header.Set("X-Request-ID", "req-7")
+	handler(context.New(context.NewResponseWriter(httptest.NewRecorder()), r))
+}
+
+func TestSlogLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	opts := DefaultSlogOptions()
+	opts.ClaimsKey = claimsKey
+
+	auth := &MockJWTAuthenticator{ShouldSucceed: true, Claims: map[string]any{"sub": testUserID}}
+	handler := router.Chain(RequestID(), SlogLogger(slog.NewJSONHandler(&buf, nil), opts), Auth(auth, claimsKey))(func(c *context.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	r := httptest.NewRequest("POST", "/orders", nil)
+	r.Header.Set("X-Request-ID", "req-42")
+	r.Header.Set("Authorization", validTokenHeader)
+	r.RemoteAddr = "203.0.113.9:1234"
+	handler(context.New(context.NewResponseWriter(httptest.NewRecorder()), r))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode log record %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{
+		"level":      "INFO",
+		"msg":        "request",
+		"method":     "POST",
+		"path":       "/orders",
+		"status":     float64(http.StatusCreated),
+		"client_ip":  "203.0.113.9",
+		"request_id": "req-42",
+		"user":       testUserID,
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, record[key])
+		}
+	}
+	for _, key := range []string{"bytes", "latency"} {
+		if _, ok := record[key]; !ok {
+			t.Errorf("Expected field %s in log record", key)
+		}
+	}
+}
+
+func TestSlogLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	mw := SlogLogger(slog.NewJSONHandler(&buf, nil), DefaultSlogOptions())
+
+	serveSlog(mw, "/missing", http.StatusNotFound)
+	serveSlog(mw, "/broken", http.StatusInternalServerError)
+
+	output := buf.String()
+	if !strings.Contains(output, `"level":"WARN"`) || !strings.Contains(output, `"level":"ERROR"`) {
+		t.Errorf("Expected WARN and ERROR records, got:\n%s", output)
+	}
+}
+
+func TestSlogLoggerSkipPaths(t *testing.T) {
+	var buf bytes.Buffer
+	opts := DefaultSlogOptions()
+	opts.SkipPaths = append(opts.SkipPaths, "/static/*")
+	mw := SlogLogger(slog.NewJSONHandler(&buf, nil), opts)
+
+	serveSlog(mw, "/healthz", http.StatusOK)
+	serveSlog(mw, "/static/app.js", http.StatusOK)
+	if buf.Len() != 0 {
+		t.Errorf("Expected skipped paths not to be logged, got:\n%s", buf.String())
+	}
+
+	serveSlog(mw, "/staticfile", http.StatusOK)
+	if buf.Len() == 0 {
+		t.Error("Expected non-matching path to be logged")
+	}
+}
+
+func TestSlogLoggerSampling(t *testing.T) {
+	var buf bytes.Buffer
+	opts := DefaultSlogOptions()
+	opts.SampleRate = 0.000001
+	mw := SlogLogger(slog.NewJSONHandler(&buf, nil), opts)
+
+	for range 50 {
+		serveSlog(mw, "/orders", http.StatusOK)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected successful requests to be sampled out, got:\n%s", buf.String())
+	}
+
+	serveSlog(mw, "/orders", http.StatusBadGateway)
+	if !strings.Contains(buf.String(), `"status":502`) {
+		t.Errorf("Expected errors to bypass sampling, got:\n%s", buf.String())
+	}
+}
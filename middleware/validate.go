@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// AutoValidateConfig configures the AutoValidate middleware
+type AutoValidateConfig struct {
+	// Skipper leaves validation to the handler for matching requests
+	Skipper Skipper
+}
+
+// AutoValidate makes Bind, BindJSON, BindForm, BindYAML, BindTOML and BindMsgpack
+// validate the bound struct. On failure they write a 400 response with the field
+// errors and return context.ErrValidationFailed, so handlers only need to return.
+func AutoValidate() router.Middleware {
+	return AutoValidateWithConfig(AutoValidateConfig{})
+}
+
+// AutoValidateWithConfig returns the AutoValidate middleware with custom configuration
+func AutoValidateWithConfig(config AutoValidateConfig) router.Middleware {
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			if !config.Skipper.skips(c) {
+				context.Set(c, context.AutoValidateKey, true)
+			}
+			next(c)
+		}
+	}
+}
+
+// SkipAutoValidate opts a route or group out of AutoValidate, e.g.
+// r.POST("/import", middleware.SkipAutoValidate()(importHandler))
+func SkipAutoValidate() router.Middleware {
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			context.Set(c, context.AutoValidateKey, false)
+			next(c)
+		}
+	}
+}
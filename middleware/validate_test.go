@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+func TestAutoValidate(t *testing.T) {
+	type signup struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	handler := func(c *context.Context) {
+		var req signup
+		if err := c.BindJSON(&req); err != nil {
+			return
+		}
+		c.Success(http.StatusOK, "ok", nil)
+	}
+
+	r := router.New()
+	r.Use(AutoValidateWithConfig(AutoValidateConfig{Skipper: SkipPaths("/skipped")}))
+	r.POST("/signup", handler)
+	r.POST("/raw", SkipAutoValidate()(handler))
+	r.POST("/skipped", handler)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/signup", http.StatusBadRequest},
+		{"/raw", http.StatusOK},
+		{"/skipped", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"email":"invalid"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf(errStatusCodeMismatch, tt.status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package dbcontext
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// CountAs selects the number of rows of each group as alias. Like the other
+// aggregate selectors, it builds a select expression of a grouped projection
+// that scans into the DTO field whose db tag is alias:
+//
+//	type CategoryRevenue struct {
+//		CategoryID int64   `db:"category_id"`
+//		Orders     int64   `db:"orders"`
+//		Revenue    float64 `db:"revenue"`
+//	}
+//	rows, err := dbcontext.Select[Order, CategoryRevenue](
+//		orders.GroupBy("category_id").Having("SUM(total) > ?", 1000),
+//		"category_id", dbcontext.CountAs("orders"), dbcontext.SumAs("total", "revenue"),
+//	).ToList()
+func CountAs(alias string) string {
+	return aggregate("COUNT", "*", alias)
+}
+
+// SumAs selects the sum of column for each group as alias
+func SumAs(column, alias string) string {
+	return aggregate("SUM", column, alias)
+}
+
+// AvgAs selects the average of column for each group as alias
+func AvgAs(column, alias string) string {
+	return aggregate("AVG", column, alias)
+}
+
+// MinAs selects the smallest value of column for each group as alias
+func MinAs(column, alias string) string {
+	return aggregate("MIN", column, alias)
+}
+
+// MaxAs selects the largest value of column for each group as alias
+func MaxAs(column, alias string) string {
+	return aggregate("MAX", column, alias)
+}
+
+// aggregate builds an aggregate select expression
+func aggregate(function, column, alias string) string {
+	return fmt.Sprintf("%s(%s) AS %s", function, column, alias)
+}
+
+// Sum returns the sum of column over the entities matching the query, 0 if none match
+func (set *EnhancedDbSet[T]) Sum(column string) (float64, error) {
+	return set.SumContext(context.Background(), column)
+}
+
+// SumContext returns the sum of column over the entities matching the query, honoring c
+func (set *EnhancedDbSet[T]) SumContext(c context.Context, column string) (float64, error) {
+	return set.scalar(c, "SUM", column)
+}
+
+// Avg returns the average of column over the entities matching the query, 0 if none match
+func (set *EnhancedDbSet[T]) Avg(column string) (float64, error) {
+	return set.AvgContext(context.Background(), column)
+}
+
+// AvgContext returns the average of column over the entities matching the query, honoring c
+func (set *EnhancedDbSet[T]) AvgContext(c context.Context, column string) (float64, error) {
+	return set.scalar(c, "AVG", column)
+}
+
+// Min returns the smallest value of column among the entities matching the query,
+// 0 if none match
+func (set *EnhancedDbSet[T]) Min(column string) (float64, error) {
+	return set.MinContext(context.Background(), column)
+}
+
+// MinContext returns the smallest value of column among the entities matching the
+// query, honoring c
+func (set *EnhancedDbSet[T]) MinContext(c context.Context, column string) (float64, error) {
+	return set.scalar(c, "MIN", column)
+}
+
+// Max returns the largest value of column among the entities matching the query,
+// 0 if none match
+func (set *EnhancedDbSet[T]) Max(column string) (float64, error) {
+	return set.MaxContext(context.Background(), column)
+}
+
+// MaxContext returns the largest value of column among the entities matching the
+// query, honoring c
+func (set *EnhancedDbSet[T]) MaxContext(c context.Context, column string) (float64, error) {
+	return set.scalar(c, "MAX", column)
+}
+
+// scalar runs an aggregate function over the rows matching the WHERE clause of the
+// query, ignoring its grouping, order and paging
+func (set *EnhancedDbSet[T]) scalar(c context.Context, function, column string) (float64, error) {
+	if err := set.checkQuery(); err != nil {
+		return 0, err
+	}
+
+	// Safe: table and column names are trusted, user data is parameterized (see whereArgs...)
+	//nolint:gosec // G201: Identifiers are not user-controlled; all user data is parameterized.
+	query := fmt.Sprintf("SELECT %s(%s) FROM %s", function, column, set.tableName)
+	query += set.buildWhere()
+
+	var value sql.NullFloat64
+	if err := set.ctx.queryRow(c, query, set.whereParams()...).Scan(&value); err != nil {
+		return 0, fmt.Errorf("failed to compute %s(%s): %w", function, column, err)
+	}
+	return value.Float64, nil
+}
@@ -0,0 +1,110 @@
+package dbcontext
+
+import "testing"
+
+// testOrder is an entity grouped by the aggregate tests
+type testOrder struct {
+	ID       int64   `db:"id"`
+	Category string  `db:"category"`
+	Total    float64 `db:"total"`
+}
+
+func (testOrder) TableName() string { return "orders" }
+
+// categoryRevenue is a grouped row of testOrder
+type categoryRevenue struct {
+	Category string  `db:"category"`
+	Orders   int64   `db:"orders"`
+	Revenue  float64 `db:"revenue"`
+	Largest  float64 `db:"largest"`
+}
+
+func TestGroupByHaving(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY AUTOINCREMENT, category TEXT, total REAL)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	for _, o := range []testOrder{{Category: "books", Total: 10}, {Category: "books", Total: 30}, {Category: "games", Total: 60}, {Category: "toys", Total: 5}} {
+		if _, err := ctx.db.Exec(`INSERT INTO orders (category, total) VALUES (?, ?)`, o.Category, o.Total); err != nil {
+			t.Fatalf("Failed to insert order: %v", err)
+		}
+	}
+
+	grouped := NewEnhancedDbSet[testOrder](ctx).
+		Where("total > ?", 1).
+		GroupBy("category").
+		Having("SUM(total) >= ?", 40).
+		OrderBy("category")
+
+	rows, err := Select[testOrder, categoryRevenue](grouped,
+		"category", CountAs("orders"), SumAs("total", "revenue"), MaxAs("total", "largest"),
+	).ToList()
+	if err != nil {
+		t.Fatalf("ToList failed: %v", err)
+	}
+
+	expected := []categoryRevenue{{"books", 2, 40, 30}, {"games", 1, 60, 60}}
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d groups, got %d", len(expected), len(rows))
+	}
+	for i, row := range rows {
+		if *row != expected[i] {
+			t.Errorf("Expected group %+v, got %+v", expected[i], *row)
+		}
+	}
+
+	count, err := grouped.Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected Count to count 2 groups, got %d", count)
+	}
+}
+
+func TestHavingPlaceholdersForPostgres(t *testing.T) {
+	set := &EnhancedDbSet[testOrder]{ctx: &EnhancedDbContext{driver: driverPostgres}, tableName: "orders"}
+
+	query := set.GroupBy("category").Having("SUM(total) > ?", 40).Where("total > ?", 1).buildQuery()
+	if expected := "SELECT * FROM orders WHERE total > $1 GROUP BY category HAVING SUM(total) > $2"; query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+}
+
+func TestScalarAggregates(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY AUTOINCREMENT, category TEXT, total REAL)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	for _, total := range []float64{10, 30, 60} {
+		if _, err := ctx.db.Exec(`INSERT INTO orders (category, total) VALUES ('books', ?)`, total); err != nil {
+			t.Fatalf("Failed to insert order: %v", err)
+		}
+	}
+
+	orders := NewEnhancedDbSet[testOrder](ctx).Where("total > ?", 15)
+	tests := []struct {
+		name      string
+		aggregate func(string) (float64, error)
+		expected  float64
+	}{
+		{"Sum", orders.Sum, 90},
+		{"Avg", orders.Avg, 45},
+		{"Min", orders.Min, 30},
+		{"Max", orders.Max, 60},
+	}
+	for _, tt := range tests {
+		value, err := tt.aggregate("total")
+		if err != nil {
+			t.Fatalf("%s failed: %v", tt.name, err)
+		}
+		if value != tt.expected {
+			t.Errorf("Expected %s to be %v, got %v", tt.name, tt.expected, value)
+		}
+	}
+
+	// No matching rows yields 0 instead of a NULL scan error
+	if sum, err := orders.Where("category = ?", "none").Sum("total"); err != nil || sum != 0 {
+		t.Errorf("Expected Sum of no rows to be 0, got %v, %v", sum, err)
+	}
+}
@@ -0,0 +1,84 @@
+package dbcontext
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// principalKey is the context key of the principal set by WithPrincipal
+type principalKey struct{}
+
+// WithPrincipal returns a copy of c carrying the principal that SaveChangesContext
+// records in audit fields, e.g. the subject of the JWT claims of a request:
+//
+//	c := dbcontext.WithPrincipal(ctx.RequestContext(), claims.Subject)
+//	_, err := db.SaveChangesContext(c)
+//
+// Inserts set the CreatedBy and UpdatedBy fields of entities and updates set UpdatedBy.
+// Other fields can be tagged audit:"created_by" or audit:"updated_by", and a
+// CreatedBy or UpdatedBy field tagged audit:"-" is left alone. The principal is
+// converted to the type of the fields, e.g. int64 for user IDs; a pointer field is
+// set to a new value.
+func WithPrincipal(c context.Context, principal interface{}) context.Context {
+	return context.WithValue(c, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal set by WithPrincipal, if any
+func PrincipalFromContext(c context.Context) (interface{}, bool) {
+	principal := c.Value(principalKey{})
+	return principal, principal != nil
+}
+
+// setAuditFields sets the audit fields of an entity to the principal of c, if any
+func setAuditFields(c context.Context, entity interface{}, isCreate bool) error {
+	principal, ok := PrincipalFromContext(c)
+	if !ok {
+		return nil
+	}
+
+	v := reflect.ValueOf(entity).Elem()
+	meta := metadataFor(v.Type())
+
+	if isCreate {
+		if field, ok := settableField(v, meta.createdBy); ok {
+			if err := setPrincipal(field, principal); err != nil {
+				return err
+			}
+		}
+	}
+	if field, ok := settableField(v, meta.updatedBy); ok {
+		if err := setPrincipal(field, principal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPrincipal sets an audit field to a principal, converting it to the field type
+func setPrincipal(field reflect.Value, principal interface{}) error {
+	target := field.Type()
+	if target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+
+	value := reflect.ValueOf(principal)
+	switch {
+	case value.Type().AssignableTo(target):
+	case target.Kind() == reflect.String:
+		// Converting numbers to strings would make runes of them
+		value = reflect.ValueOf(fmt.Sprint(principal)).Convert(target)
+	case value.Type().ConvertibleTo(target) && value.Kind() != reflect.String:
+		value = value.Convert(target)
+	default:
+		return fmt.Errorf("cannot record principal of type %T in audit field of type %s", principal, field.Type())
+	}
+
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(target)
+		ptr.Elem().Set(value)
+		value = ptr
+	}
+	field.Set(value)
+	return nil
+}
@@ -0,0 +1,90 @@
+package dbcontext
+
+import (
+	"context"
+	"testing"
+)
+
+// testNote is an entity with audit fields
+type testNote struct {
+	ID        int64  `db:"id"`
+	Body      string `db:"body"`
+	CreatedBy string `db:"created_by"`
+	UpdatedBy string `db:"updated_by"`
+}
+
+func (testNote) TableName() string { return "notes" }
+
+// testTicket is an entity with audit fields configured by tags
+type testTicket struct {
+	ID        int64  `db:"id"`
+	Title     string `db:"title"`
+	OpenedBy  *int64 `db:"opened_by" audit:"created_by"`
+	CreatedBy string `db:"created_by" audit:"-"`
+}
+
+func (testTicket) TableName() string { return "tickets" }
+
+func TestAuditFields(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE notes (id INTEGER PRIMARY KEY AUTOINCREMENT, body TEXT, created_by TEXT, updated_by TEXT)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	note := &testNote{Body: "Draft"}
+	ctx.Add(note)
+	if _, err := ctx.SaveChangesContext(WithPrincipal(context.Background(), "ana")); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if note.CreatedBy != "ana" || note.UpdatedBy != "ana" {
+		t.Errorf("Expected the creator to be recorded, got %+v", note)
+	}
+
+	note.Body = "Final"
+	ctx.Update(note)
+	if _, err := ctx.SaveChangesContext(WithPrincipal(context.Background(), "budi")); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	found, err := NewEnhancedDbSet[testNote](ctx).Find(note.ID)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if found.CreatedBy != "ana" || found.UpdatedBy != "budi" {
+		t.Errorf("Expected created by ana and updated by budi, got %+v", found)
+	}
+
+	// Without a principal the fields are left alone
+	note.Body = "Edited"
+	ctx.Update(note)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if note.UpdatedBy != "budi" {
+		t.Errorf("Expected UpdatedBy to be kept, got %q", note.UpdatedBy)
+	}
+}
+
+func TestAuditFieldTags(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE tickets (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT, opened_by INTEGER, created_by TEXT)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	ticket := &testTicket{Title: "Broken link"}
+	ctx.Add(ticket)
+	if _, err := ctx.SaveChangesContext(WithPrincipal(context.Background(), 42)); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if ticket.OpenedBy == nil || *ticket.OpenedBy != 42 {
+		t.Errorf("Expected OpenedBy to be 42, got %v", ticket.OpenedBy)
+	}
+	if ticket.CreatedBy != "" {
+		t.Errorf("Expected CreatedBy tagged audit:\"-\" to be left alone, got %q", ticket.CreatedBy)
+	}
+
+	ctx.Add(&testTicket{Title: "Typo"})
+	if _, err := ctx.SaveChangesContext(WithPrincipal(context.Background(), []string{"ana"})); err == nil {
+		t.Error("Expected a principal of another type to fail")
+	}
+}
@@ -0,0 +1,165 @@
+package dbcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Param is a placeholder argument of a compiled query, bound to the argument at its
+// index when the query runs, see Compile
+type Param int
+
+// CompiledQuery is a query built once by Compile and run with different arguments
+type CompiledQuery[T any] struct {
+	build func(*EnhancedDbSet[T]) *EnhancedDbSet[T]
+	plans sync.Map // *compiledPlan[T] by compiledKey
+}
+
+// compiledKey identifies the contexts a query compiles to the same SQL for. The
+// tenant is bound on every run, so plans are shared by the tenants of a context.
+type compiledKey struct {
+	driver   string
+	tenancy  *TenancyConfig
+	tenanted bool
+}
+
+// compiledTenant stands for the tenant while a query is compiled, so it is found in
+// the arguments and table names of the plan. It isn't a valid tenant.
+const compiledTenant = "\x00tenant\x00"
+
+// tenantParam is the argument of a compiled query bound to the tenant on every run
+type tenantParam struct{}
+
+// compiledPlan is the SQL of a compiled query with the bindings of its arguments
+type compiledPlan[T any] struct {
+	set    EnhancedDbSet[T] // Built set, its context replaced on every run
+	query  string
+	schema bool          // The query names the tenant schema
+	args   []interface{} // Arguments, Param and tenantParam for the ones bound on every run
+	params int           // Number of arguments needed
+	err    error
+}
+
+// Compile captures the query built by build, so it is run with different arguments
+// without building its SQL again, e.g. on hot paths:
+//
+//	var userByEmail = dbcontext.Compile(func(users *dbcontext.EnhancedDbSet[User]) *dbcontext.EnhancedDbSet[User] {
+//		return users.Where("email = ?", dbcontext.Param(0)).Take(1)
+//	})
+//	...
+//	user, err := userByEmail.FirstOrDefault(db, "john@example.com")
+//
+// Arguments passed as Param are bound on every run, others are fixed. build runs
+// once per driver and tenancy, so it must not depend on anything else. AfterCursor
+// isn't supported, as the cursor changes the SQL.
+func Compile[T any](build func(*EnhancedDbSet[T]) *EnhancedDbSet[T]) *CompiledQuery[T] {
+	return &CompiledQuery[T]{build: build}
+}
+
+// ToList runs the query with args on ctx and returns all results
+func (q *CompiledQuery[T]) ToList(ctx *EnhancedDbContext, args ...interface{}) ([]*T, error) {
+	return q.ToListContext(context.Background(), ctx, args...)
+}
+
+// ToListContext runs the query with args on ctx and returns all results, canceling
+// it when c is done
+func (q *CompiledQuery[T]) ToListContext(c context.Context, ctx *EnhancedDbContext, args ...interface{}) ([]*T, error) {
+	plan := q.plan(ctx)
+	if plan.err != nil {
+		return nil, plan.err
+	}
+	if len(args) < plan.params {
+		return nil, fmt.Errorf("compiled query needs %d arguments, got %d", plan.params, len(args))
+	}
+
+	set := plan.set
+	set.ctx = ctx
+	if err := set.checkQuery(); err != nil {
+		return nil, err
+	}
+	query := plan.query
+	if plan.schema {
+		query = strings.ReplaceAll(query, compiledTenant, ctx.tenant)
+	}
+	return set.list(c, query, plan.bind(ctx, args))
+}
+
+// FirstOrDefault runs the query with args on ctx and returns the first result or nil
+// if none found. Compile the query with Take(1) to fetch a single row.
+func (q *CompiledQuery[T]) FirstOrDefault(ctx *EnhancedDbContext, args ...interface{}) (*T, error) {
+	return q.FirstOrDefaultContext(context.Background(), ctx, args...)
+}
+
+// FirstOrDefaultContext runs the query with args on ctx and returns the first result
+// or nil if none found, honoring c
+func (q *CompiledQuery[T]) FirstOrDefaultContext(c context.Context, ctx *EnhancedDbContext, args ...interface{}) (*T, error) {
+	results, err := q.ToListContext(c, ctx, args...)
+	if err != nil || len(results) == 0 {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// plan returns the plan of the query for ctx, compiling it on first use
+func (q *CompiledQuery[T]) plan(ctx *EnhancedDbContext) *compiledPlan[T] {
+	key := compiledKey{driver: ctx.driver, tenancy: ctx.tenancy, tenanted: ctx.tenant != ""}
+	if plan, ok := q.plans.Load(key); ok {
+		return plan.(*compiledPlan[T])
+	}
+
+	// Compiled for any tenant, see compiledTenant
+	if key.tenanted {
+		ctx = ctx.derive()
+		ctx.tenant = compiledTenant
+	}
+
+	plan := &compiledPlan[T]{}
+	set := q.build(NewEnhancedDbSet[T](ctx))
+	switch {
+	case set == nil:
+		plan.err = errors.New("compiled query builder returned no set")
+	case set.cursor != "":
+		plan.err = errors.New("AfterCursor is not supported in compiled queries")
+	default:
+		plan.set = *set
+		plan.set.ctx = nil // Not kept alive by the plan
+		plan.query = set.buildQuery()
+		plan.schema = strings.Contains(plan.query, compiledTenant)
+		plan.args = append([]interface{}(nil), set.queryArgs()...)
+		for i, arg := range plan.args {
+			if tenant, ok := arg.(string); ok && tenant == compiledTenant {
+				plan.args[i] = tenantParam{}
+				continue
+			}
+			param, ok := arg.(Param)
+			switch {
+			case ok && param < 0:
+				plan.err = fmt.Errorf("invalid compiled query parameter %d", param)
+			case ok && int(param) >= plan.params:
+				plan.params = int(param) + 1
+			}
+		}
+	}
+
+	actual, _ := q.plans.LoadOrStore(key, plan)
+	return actual.(*compiledPlan[T])
+}
+
+// bind returns the arguments of a run on ctx, with the Param and tenant ones replaced
+func (plan *compiledPlan[T]) bind(ctx *EnhancedDbContext, args []interface{}) []interface{} {
+	bound := make([]interface{}, len(plan.args))
+	for i, arg := range plan.args {
+		switch arg := arg.(type) {
+		case Param:
+			bound[i] = args[arg]
+		case tenantParam:
+			bound[i] = ctx.tenant
+		default:
+			bound[i] = arg
+		}
+	}
+	return bound
+}
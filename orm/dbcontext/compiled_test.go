@@ -0,0 +1,137 @@
+package dbcontext
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompiledQuery(t *testing.T) {
+	for _, driver := range []string{"sqlite3", driverPostgres} {
+		t.Run(driver, func(t *testing.T) {
+			ctx := newTestContext(t)
+			ctx.driver = driver
+			for _, name := range []string{"Ana", "Ben", "Cy"} {
+				if _, err := ctx.db.Exec(`INSERT INTO users (email_address, name) VALUES (?, ?)`, name+"@example.com", name); err != nil {
+					t.Fatalf("Failed to insert user: %v", err)
+				}
+			}
+
+			builds := 0
+			byName := Compile(func(users *EnhancedDbSet[testUser]) *EnhancedDbSet[testUser] {
+				builds++
+				return users.AsNoTracking().
+					Where("id > ?", 0).
+					Where("name = ? OR name = ?", Param(0), Param(1)).
+					OrderBy("id")
+			})
+
+			hook := &recordingHook{}
+			ctx.AddQueryHook(hook)
+			for _, names := range [][2]string{{"Ana", "Cy"}, {"Ben", "Ben"}} {
+				users, err := byName.ToList(ctx, names[0], names[1])
+				if err != nil {
+					t.Fatalf("ToList failed: %v", err)
+				}
+				if len(users) == 0 || users[0].Name != names[0] || users[len(users)-1].Name != names[1] {
+					t.Errorf("Expected users %v, got %+v", names, users)
+				}
+			}
+			if builds != 1 {
+				t.Errorf("Expected the query to be built once, got %d builds", builds)
+			}
+			if len(hook.events) != 2 || hook.events[0].Query != hook.events[1].Query {
+				t.Errorf("Expected the same statement to run twice, got %+v", hook.events)
+			}
+
+			user, err := byName.FirstOrDefault(ctx, "Nobody", "Nobody")
+			if err != nil || user != nil {
+				t.Errorf("Expected no user, got %+v, %v", user, err)
+			}
+			if _, err := byName.ToList(ctx, "Ana"); err == nil {
+				t.Error("Expected missing arguments to fail")
+			}
+		})
+	}
+}
+
+func TestCompiledQueryPerContext(t *testing.T) {
+	ctx := newTenancyContext(t, DefaultTenancyConfig())
+	acme, _ := ctx.ForTenant("acme")
+	globex, _ := ctx.ForTenant("globex")
+	acme.Add(&testInvoice{Number: "A-1"})
+	globex.Add(&testInvoice{Number: "G-1"})
+	for _, tenantCtx := range []*EnhancedDbContext{acme, globex} {
+		if _, err := tenantCtx.SaveChanges(); err != nil {
+			t.Fatalf("SaveChanges failed: %v", err)
+		}
+	}
+
+	all := Compile(func(invoices *EnhancedDbSet[testInvoice]) *EnhancedDbSet[testInvoice] {
+		return invoices.OrderBy("id")
+	})
+	for tenant, tenantCtx := range map[string]*EnhancedDbContext{"acme": acme, "globex": globex} {
+		invoices, err := all.ToList(tenantCtx)
+		if err != nil || len(invoices) != 1 || invoices[0].TenantID != tenant {
+			t.Errorf("Expected the invoice of %s, got %+v, %v", tenant, invoices, err)
+		}
+	}
+	if _, err := all.ToList(ctx); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Expected ErrNoTenant without a tenant, got %v", err)
+	}
+
+	// Tenants share a plan, the tenant is bound on every run
+	for _, tenant := range []string{"initech", "umbrella", "hooli"} {
+		tenantCtx, _ := ctx.ForTenant(tenant)
+		if invoices, err := all.ToList(tenantCtx); err != nil || len(invoices) != 0 {
+			t.Errorf("Expected no invoices of %s, got %+v, %v", tenant, invoices, err)
+		}
+	}
+	plans := 0
+	all.plans.Range(func(_, _ interface{}) bool {
+		plans++
+		return true
+	})
+	if plans != 2 {
+		t.Errorf("Expected a plan with and without a tenant, got %d", plans)
+	}
+
+	// Tracking queries resolve rows to the tracked instances of the context
+	tracked, _ := NewEnhancedDbSet[testInvoice](acme).First()
+	if invoices, _ := all.ToList(acme); len(invoices) != 1 || invoices[0] != tracked {
+		t.Errorf("Expected the tracked invoice, got %+v", invoices)
+	}
+
+	withCursor := Compile(func(invoices *EnhancedDbSet[testInvoice]) *EnhancedDbSet[testInvoice] {
+		return invoices.OrderBy("id").AfterCursor("WzFd")
+	})
+	if _, err := withCursor.ToList(acme); err == nil {
+		t.Error("Expected AfterCursor to be rejected")
+	}
+}
+
+func TestCompiledQueryTenantSchema(t *testing.T) {
+	ctx := newTestContext(t)
+	for _, schema := range []string{"acme", "globex"} {
+		for _, stmt := range []string{
+			`ATTACH DATABASE ':memory:' AS ` + schema,
+			`CREATE TABLE ` + schema + `.users (id INTEGER PRIMARY KEY AUTOINCREMENT, email_address TEXT NOT NULL, name TEXT NOT NULL, created_at DATETIME, updated_at DATETIME)`,
+			`INSERT INTO ` + schema + `.users (email_address, name) VALUES ('admin@` + schema + `.com', '` + schema + `')`,
+		} {
+			if _, err := ctx.db.Exec(stmt); err != nil {
+				t.Fatalf("Failed to set up schema: %v", err)
+			}
+		}
+	}
+	ctx.UseTenancy(TenancyConfig{Strategy: TenantSchema})
+
+	byName := Compile(func(users *EnhancedDbSet[testUser]) *EnhancedDbSet[testUser] {
+		return users.AsNoTracking().Where("name = ?", Param(0))
+	})
+	for _, schema := range []string{"acme", "globex"} {
+		tenantCtx, _ := ctx.ForTenant(schema)
+		user, err := byName.FirstOrDefault(tenantCtx, schema)
+		if err != nil || user == nil || user.Email != "admin@"+schema+".com" {
+			t.Errorf("Expected the user of the %s schema, got %+v, %v", schema, user, err)
+		}
+	}
+}
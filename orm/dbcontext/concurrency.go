@@ -0,0 +1,73 @@
+package dbcontext
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrConcurrencyConflict is returned by SaveChanges when an entity with a version
+// field was changed or deleted by someone else since it was read:
+//
+//	type Product struct {
+//		ID      int64 `db:"id"`
+//		Stock   int   `db:"stock"`
+//		Version int64 `db:"version" concurrency:"version"`
+//	}
+//
+// Updates of such entities only match the row at the version read and increment
+// it, instead of silently overwriting concurrent edits. On conflict, reload the
+// entity and retry or report the conflict to the client.
+var ErrConcurrencyConflict = errors.New("concurrency conflict")
+
+// incrementVersion increments the version field of an entity, returning the
+// previous version and whether the entity has one
+func incrementVersion(entity interface{}) (int64, bool) {
+	v := reflect.ValueOf(entity).Elem()
+	field, ok := settableField(v, metadataFor(v.Type()).version)
+	if !ok {
+		return 0, false
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		version := field.Int()
+		field.SetInt(version + 1)
+		return version, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		version := field.Uint()
+		field.SetUint(version + 1)
+		return int64(version), true //nolint:gosec // G115: versions don't reach 2^63
+	}
+	return 0, false
+}
+
+// restoreVersion sets back the version read of an entity whose update failed
+func restoreVersion(entity interface{}, version int64) {
+	v := reflect.ValueOf(entity).Elem()
+	if field, ok := settableField(v, metadataFor(v.Type()).version); ok {
+		if field.CanInt() {
+			field.SetInt(version)
+		} else {
+			field.SetUint(uint64(version)) //nolint:gosec // G115: read from the unsigned field
+		}
+	}
+}
+
+// checkVersionedUpdate returns ErrConcurrencyConflict if a versioned update
+// matched no row
+func checkVersionedUpdate(result sql.Result, err error, table string, id interface{}, version int64) error {
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s %v is no longer at version %d", ErrConcurrencyConflict, table, id, version)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package dbcontext
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testProduct is an entity with a version field
+type testProduct struct {
+	ID      int64  `db:"id"`
+	Name    string `db:"name"`
+	Version int64  `db:"version" concurrency:"version"`
+}
+
+func (testProduct) TableName() string { return "products" }
+
+func TestOptimisticConcurrency(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE products (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, version INTEGER NOT NULL DEFAULT 0)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := ctx.db.Exec(`INSERT INTO products (name) VALUES ('Lamp')`); err != nil {
+		t.Fatalf("Failed to insert product: %v", err)
+	}
+
+	// Two requests read the product at version 0
+	first, err := NewEnhancedDbSet[testProduct](ctx).AsNoTracking().Find(1)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	second := *first
+
+	hook := &recordingHook{}
+	ctx.AddQueryHook(hook)
+	first.Name = "Desk lamp"
+	ctx.Update(first)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if len(hook.events) == 0 || !strings.HasSuffix(hook.events[0].Query, `AND "version" = ?`) {
+		t.Errorf("Expected the update to check the quoted version column, got %+v", hook.events)
+	}
+	if first.Version != 1 {
+		t.Errorf("Expected version to be incremented to 1, got %d", first.Version)
+	}
+
+	// The second edit was made on a stale version
+	second.Name = "Floor lamp"
+	ctx.Update(&second)
+	if _, err := ctx.SaveChanges(); !errors.Is(err, ErrConcurrencyConflict) {
+		t.Fatalf("Expected ErrConcurrencyConflict, got %v", err)
+	}
+	if second.Version != 0 {
+		t.Errorf("Expected version to be restored to 0 after a conflict, got %d", second.Version)
+	}
+	if state := ctx.ChangeTracker.GetEntityState(&second); state != EntityStateModified {
+		t.Errorf("Expected conflicting entity to stay Modified, got %v", state)
+	}
+
+	stored, _ := NewEnhancedDbSet[testProduct](ctx).AsNoTracking().Find(1)
+	if stored.Name != "Desk lamp" || stored.Version != 1 {
+		t.Errorf("Expected the first edit to be kept, got %+v", stored)
+	}
+}
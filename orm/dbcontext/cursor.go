@@ -0,0 +1,188 @@
+package dbcontext
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a keyset cursor can't be decoded for the query
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorPage is a page of keyset paginated results
+type CursorPage[T any] struct {
+	Items      []*T   `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor of the last item, empty on the last page
+	HasNext    bool   `json:"has_next"`
+}
+
+// orderColumn is a column of the ORDER BY clause of a query
+type orderColumn struct {
+	name string
+	desc bool
+}
+
+// AfterCursor starts the results after the entity a cursor was made from, see
+// Cursor. Unlike Skip, the rows before it are not read, so pages stay fast deep
+// into large tables:
+//
+//	page, err := users.AfterCursor(c.Query("cursor")).
+//		OrderByDescending("created_at").ThenBy("id").
+//		ToCursorPage(20)
+//
+// The order must be unique, e.g. end with the ID, and on columns of fields of the
+// entity that are never NULL. The cursor is checked against the order when the query
+// runs; an empty cursor starts from the first result. Count ignores the cursor like
+// it ignores Skip.
+func (set *EnhancedDbSet[T]) AfterCursor(cursor string) *EnhancedDbSet[T] {
+	newSet := *set
+	newSet.cursor = cursor
+	return &newSet
+}
+
+// Cursor returns an opaque cursor for the results after an entity, holding the
+// values of its order columns
+func (set *EnhancedDbSet[T]) Cursor(entity *T) (string, error) {
+	columns := orderColumns(set.orderClause)
+	if len(columns) == 0 {
+		return "", errors.New("keyset pagination requires an OrderBy clause")
+	}
+
+	v := reflect.ValueOf(entity).Elem()
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		index, err := cursorField(v.Type(), column.name)
+		if err != nil {
+			return "", err
+		}
+		values[i] = v.FieldByIndex(index).Interface()
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// ToCursorPage executes the query for up to size results after the cursor, if any,
+// with the cursor of the next page
+func (set *EnhancedDbSet[T]) ToCursorPage(size int) (*CursorPage[T], error) {
+	return set.ToCursorPageContext(context.Background(), size)
+}
+
+// ToCursorPageContext executes the query for up to size results after the cursor,
+// canceling it when c is done
+func (set *EnhancedDbSet[T]) ToCursorPageContext(c context.Context, size int) (*CursorPage[T], error) {
+	if size < 1 {
+		return nil, fmt.Errorf("%w: page size %d", ErrInvalidPage, size)
+	}
+
+	// Read one more result to know if there is a next page
+	items, err := set.Take(size + 1).ToListContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CursorPage[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []*T{}
+	}
+	if len(items) > size {
+		page.Items, page.HasNext = items[:size], true
+		if page.NextCursor, err = set.Cursor(items[size-1]); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// applyCursor returns the query with the keyset predicate of its cursor added to
+// the WHERE clause, e.g. for ORDER BY created_at DESC, id:
+//
+//	created_at < ? OR (created_at = ? AND id > ?)
+func (set *EnhancedDbSet[T]) applyCursor() (*EnhancedDbSet[T], error) {
+	if set.cursor == "" {
+		return set, nil
+	}
+
+	columns := orderColumns(set.orderClause)
+	if len(columns) == 0 {
+		return nil, errors.New("keyset pagination requires an OrderBy clause")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(set.cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || len(raw) != len(columns) {
+		return nil, ErrInvalidCursor
+	}
+
+	// Decode values into the types of the fields, e.g. time.Time, for comparisons
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		index, err := cursorField(t, column.name)
+		if err != nil {
+			return nil, err
+		}
+		value := reflect.New(t.FieldByIndex(index).Type)
+		if err := json.Unmarshal(raw[i], value.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidCursor, column.name, err)
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	var terms []string
+	var args []interface{}
+	for i, column := range columns {
+		var conditions []string
+		for j := range i {
+			conditions = append(conditions, columns[j].name+" = ?")
+			args = append(args, values[j])
+		}
+		operator := " > ?"
+		if column.desc {
+			operator = " < ?"
+		}
+		conditions = append(conditions, column.name+operator)
+		args = append(args, values[i])
+		terms = append(terms, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	newSet := set.Where("("+strings.Join(terms, " OR ")+")", args...)
+	newSet.cursor = ""
+	return newSet, nil
+}
+
+// orderColumns parses an ORDER BY clause, e.g. "created_at DESC, id"
+func orderColumns(orderClause string) []orderColumn {
+	var columns []orderColumn
+	for _, part := range strings.Split(orderClause, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		column := orderColumn{name: fields[0]}
+		if len(fields) > 1 {
+			column.desc = strings.EqualFold(fields[1], "DESC")
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// cursorField returns the index of the field of an order column
+func cursorField(t reflect.Type, column string) ([]int, error) {
+	index := metadataFor(t).columnIndex(t, column)
+	if index == nil {
+		return nil, fmt.Errorf("order column %s has no field in %s", column, t.Name())
+	}
+	return index, nil
+}
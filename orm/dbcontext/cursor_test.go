@@ -0,0 +1,88 @@
+package dbcontext
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorPagination(t *testing.T) {
+	for _, driver := range []string{"sqlite3", driverPostgres} {
+		t.Run(driver, func(t *testing.T) {
+			ctx := newTestContext(t)
+			ctx.driver = driver
+
+			// Two users share each creation time, so pages must break ties on the ID
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i, name := range []string{"Ana", "Budi", "Citra", "Dewi", "Eka"} {
+				created := start.Add(time.Duration(i/2) * time.Hour)
+				if _, err := ctx.db.Exec(`INSERT INTO users (email_address, name, created_at) VALUES ($1, $2, $3)`, name+"@example.com", name, created); err != nil {
+					t.Fatalf("Failed to insert user: %v", err)
+				}
+			}
+			users := NewEnhancedDbSet[testUser](ctx).Where("name <> ?", "Nobody")
+
+			var names []string
+			cursor := ""
+			for pages := 0; ; pages++ {
+				if pages > 5 {
+					t.Fatal("Expected pagination to end")
+				}
+				page, err := users.AfterCursor(cursor).OrderByDescending("created_at").ThenBy("id").ToCursorPage(2)
+				if err != nil {
+					t.Fatalf("ToCursorPage failed: %v", err)
+				}
+				for _, user := range page.Items {
+					names = append(names, user.Name)
+				}
+				if !page.HasNext {
+					if page.NextCursor != "" {
+						t.Error("Expected no cursor on the last page")
+					}
+					break
+				}
+				cursor = page.NextCursor
+			}
+
+			expected := []string{"Eka", "Citra", "Dewi", "Ana", "Budi"}
+			if len(names) != len(expected) {
+				t.Fatalf("Expected %v, got %v", expected, names)
+			}
+			for i := range expected {
+				if names[i] != expected[i] {
+					t.Fatalf("Expected %v, got %v", expected, names)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidCursor(t *testing.T) {
+	ctx := newTestContext(t)
+	users := NewEnhancedDbSet[testUser](ctx)
+
+	cursor, err := users.OrderBy("id").Cursor(&testUser{ID: 3})
+	if err != nil {
+		t.Fatalf("Cursor failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		set  *EnhancedDbSet[testUser]
+	}{
+		{"Malformed", users.AfterCursor("not a cursor!").OrderBy("id")},
+		{"OtherOrder", users.AfterCursor(cursor).OrderBy("name").ThenBy("id")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.set.ToList(); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+
+	if _, err := users.AfterCursor(cursor).ToList(); err == nil {
+		t.Error("Expected a cursor without order to fail")
+	}
+}
@@ -2,10 +2,13 @@
 package dbcontext
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,22 +16,42 @@ import (
 
 const driverPostgres = "postgres"
 
-// detectDatabaseDriver detects the database driver type
-func detectDatabaseDriver(db *sql.DB) string {
+// querier runs queries on a database or in a transaction
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// detectDatabaseDriver detects the database driver type. The PostgreSQL probe runs
+// first, as a failed statement aborts a PostgreSQL transaction.
+func detectDatabaseDriver(db querier) string {
 	// Test queries to detect database type
-	if _, err := db.Query("SELECT 1::integer"); err == nil {
+	if probeQuery(db, "SELECT 1::integer") {
 		return driverPostgres
 	}
-	if _, err := db.Query("SELECT sqlite_version()"); err == nil {
+	if probeQuery(db, "SELECT sqlite_version()") {
 		return "sqlite3"
 	}
-	if _, err := db.Query("SELECT VERSION()"); err == nil {
-		return "mysql"
+	if probeQuery(db, "SELECT VERSION()") {
+		return driverMySQL
 	}
 	// Default to sqlite3 if detection fails
 	return "sqlite3"
 }
 
+// probeQuery reports whether a query succeeds, releasing its connection afterwards.
+// Leaving the rows open would pin a pooled connection and, on SQLite, hold a
+// read lock that blocks writers.
+func probeQuery(db querier, query string) bool {
+	rows, err := db.Query(query)
+	if err != nil {
+		return false
+	}
+	if closeErr := rows.Close(); closeErr != nil {
+		log.Printf("Warning: Failed to close rows: %v", closeErr)
+	}
+	return true
+}
+
 // convertQueryPlaceholders converts query placeholders based on database driver
 func convertQueryPlaceholders(query string, driver string) string {
 	if driver != driverPostgres {
@@ -56,6 +79,7 @@ func convertQueryPlaceholders(query string, driver string) string {
 //   - EntityStateAdded
 //   - EntityStateModified
 //   - EntityStateDeleted
+//   - EntityStateUpsert
 type EntityState int
 
 const (
@@ -67,6 +91,9 @@ const (
 	EntityStateModified
 	// EntityStateDeleted indicates the entity has been marked for deletion.
 	EntityStateDeleted
+	// EntityStateUpsert indicates the entity should be inserted, or update the row
+	// it conflicts with.
+	EntityStateUpsert
 )
 
 // String returns the string representation of EntityState
@@ -80,6 +107,8 @@ func (s EntityState) String() string {
 		return "Modified"
 	case EntityStateDeleted:
 		return "Deleted"
+	case EntityStateUpsert:
+		return "Upsert"
 	default:
 		return "Unknown"
 	}
@@ -87,19 +116,29 @@ func (s EntityState) String() string {
 
 // ChangeTracker manages entity states and changes
 type ChangeTracker struct {
-	entities map[interface{}]EntityState
+	entities   map[interface{}]EntityState
+	conflicts  map[interface{}][]string      // Conflict columns of upserted entities
+	originals  map[interface{}][]interface{} // Field values of unchanged entities, see snapshot
+	identities map[identityKey]interface{}   // Tracked instance of each row, see identify
 }
 
 // NewChangeTracker creates a new change tracker
 func NewChangeTracker() *ChangeTracker {
 	return &ChangeTracker{
-		entities: make(map[interface{}]EntityState),
+		entities:   make(map[interface{}]EntityState),
+		conflicts:  make(map[interface{}][]string),
+		originals:  make(map[interface{}][]interface{}),
+		identities: make(map[identityKey]interface{}),
 	}
 }
 
-// GetEntityState returns the current state of an entity
+// GetEntityState returns the current state of an entity. Unchanged entities whose
+// fields changed since they were loaded or saved are Modified.
 func (ct *ChangeTracker) GetEntityState(entity interface{}) EntityState {
 	if state, exists := ct.entities[entity]; exists {
+		if state == EntityStateUnchanged && len(ct.ChangedColumns(entity)) > 0 {
+			return EntityStateModified
+		}
 		return state
 	}
 	return EntityStateUnchanged
@@ -108,11 +147,25 @@ func (ct *ChangeTracker) GetEntityState(entity interface{}) EntityState {
 // SetEntityState sets the state of an entity
 func (ct *ChangeTracker) SetEntityState(entity interface{}, state EntityState) {
 	ct.entities[entity] = state
+	ct.identify(entity)
 }
 
-// TrackEntity adds an entity to tracking with specified state
+// TrackEntity adds an entity to tracking with specified state. The field values of
+// unchanged entities are recorded to detect changes.
 func (ct *ChangeTracker) TrackEntity(entity interface{}, state EntityState) {
 	ct.entities[entity] = state
+	ct.identify(entity)
+	if state == EntityStateUnchanged {
+		ct.snapshot(entity)
+	}
+}
+
+// Detach stops tracking an entity
+func (ct *ChangeTracker) Detach(entity interface{}) {
+	delete(ct.entities, entity)
+	delete(ct.conflicts, entity)
+	delete(ct.originals, entity)
+	ct.forget(entity)
 }
 
 // Database provides transaction support
@@ -130,6 +183,11 @@ func (d *Database) Begin() (*sql.Tx, error) {
 	return d.db.Begin()
 }
 
+// BeginTx starts a new transaction that is rolled back if c is canceled
+func (d *Database) BeginTx(c context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return d.db.BeginTx(c, opts)
+}
+
 // EnhancedDbContext provides Entity Framework Core-like functionality
 type EnhancedDbContext struct {
 	db            *sql.DB
@@ -137,6 +195,10 @@ type EnhancedDbContext struct {
 	ChangeTracker *ChangeTracker
 	Database      *Database
 	driver        string
+	hooks         []QueryHook
+	tenancy       *TenancyConfig // Multi-tenancy settings, nil if disabled
+	tenant        string         // Tenant the context is scoped to, see ForTenant
+	noTracking    bool           // Whether queries are untracked unless AsTracking is used
 }
 
 // NewEnhancedDbContext creates a new enhanced database context
@@ -167,18 +229,25 @@ func NewEnhancedDbContextWithDB(db *sql.DB) *EnhancedDbContext {
 	}
 }
 
-// NewEnhancedDbContextWithTx creates a new enhanced database context with transaction
+// NewEnhancedDbContextWithTx creates a new enhanced database context with transaction,
+// detecting the driver within it. Prefer WithTransaction, which reuses the driver of
+// the parent context.
 func NewEnhancedDbContextWithTx(tx *sql.Tx) *EnhancedDbContext {
-	// Note: for transactions, we can't easily detect the driver type
-	// so we default to sqlite3. In practice, this constructor is used
-	// within an existing context that already has the driver detected.
 	return &EnhancedDbContext{
 		tx:            tx,
 		ChangeTracker: NewChangeTracker(),
-		driver:        "sqlite3", // default, should be set by parent context
+		driver:        detectDatabaseDriver(tx),
 	}
 }
 
+// PingContext verifies the database connection is alive, e.g. for health checks
+func (ctx *EnhancedDbContext) PingContext(c context.Context) error {
+	if ctx.db == nil {
+		return errors.New("no database connection")
+	}
+	return ctx.db.PingContext(c)
+}
+
 // Add marks an entity for insertion
 func (ctx *EnhancedDbContext) Add(entity interface{}) {
 	ctx.ChangeTracker.SetEntityState(entity, EntityStateAdded)
@@ -194,34 +263,60 @@ func (ctx *EnhancedDbContext) Delete(entity interface{}) {
 	ctx.ChangeTracker.SetEntityState(entity, EntityStateDeleted)
 }
 
+// Detach stops tracking an entity so it is neither saved nor kept in memory
+func (ctx *EnhancedDbContext) Detach(entity interface{}) {
+	ctx.ChangeTracker.Detach(entity)
+}
+
 // SaveChanges persists all pending changes to the database
 func (ctx *EnhancedDbContext) SaveChanges() (int, error) {
+	return ctx.SaveChangesContext(context.Background())
+}
+
+// SaveChangesContext persists all pending changes to the database, stopping at the
+// first statement that fails once c is canceled or its deadline passes
+func (ctx *EnhancedDbContext) SaveChangesContext(c context.Context) (int, error) {
 	affected := 0
 
+	ctx.ChangeTracker.DetectChanges()
 	for entity, state := range ctx.ChangeTracker.entities {
 		switch state {
 		case EntityStateAdded:
-			err := ctx.insertEntity(entity)
+			err := ctx.insertEntity(c, entity)
 			if err != nil {
 				return affected, err
 			}
-			ctx.ChangeTracker.SetEntityState(entity, EntityStateUnchanged)
+			ctx.ChangeTracker.TrackEntity(entity, EntityStateUnchanged)
 			affected++
 
 		case EntityStateModified:
-			err := ctx.updateEntity(entity)
+			if changed := ctx.ChangeTracker.ChangedColumns(entity); changed != nil && len(changed) == 0 {
+				// Loaded or saved entity without changes, nothing to write
+				ctx.ChangeTracker.TrackEntity(entity, EntityStateUnchanged)
+				continue
+			}
+			err := ctx.updateEntity(c, entity)
 			if err != nil {
 				return affected, err
 			}
-			ctx.ChangeTracker.SetEntityState(entity, EntityStateUnchanged)
+			ctx.ChangeTracker.TrackEntity(entity, EntityStateUnchanged)
 			affected++
 
 		case EntityStateDeleted:
-			err := ctx.deleteEntity(entity)
+			err := ctx.deleteEntity(c, entity)
+			if err != nil {
+				return affected, err
+			}
+			ctx.ChangeTracker.Detach(entity)
+			affected++
+
+		case EntityStateUpsert:
+			err := ctx.upsertEntity(c, entity, ctx.ChangeTracker.conflicts[entity])
 			if err != nil {
 				return affected, err
 			}
-			delete(ctx.ChangeTracker.entities, entity)
+			ctx.ChangeTracker.TrackEntity(entity, EntityStateUnchanged)
+			delete(ctx.ChangeTracker.conflicts, entity)
 			affected++
 		}
 	}
@@ -230,27 +325,31 @@ func (ctx *EnhancedDbContext) SaveChanges() (int, error) {
 }
 
 // insertEntity inserts a new entity into the database
-func (ctx *EnhancedDbContext) insertEntity(entity interface{}) error {
-	// Set timestamps before inserting
+func (ctx *EnhancedDbContext) insertEntity(c context.Context, entity interface{}) error {
+	// Set timestamps and audit fields before inserting
 	setTimestamps(entity, true) // true = create timestamps
+	if err := setAuditFields(c, entity, true); err != nil {
+		return err
+	}
+	if err := ctx.setTenant(entity); err != nil {
+		return err
+	}
 
-	tableName := getTableName(entity)
+	tableName := ctx.tableOf(metadataOf(entity))
 	columns, values, placeholders := getInsertData(entity, ctx.driver)
 
 	// Safe: table/column names are trusted, user data is parameterized (see values...)
 	//nolint:gosec // G201: Identifiers are not user-controlled; all user data is parameterized.
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		tableName, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+		tableName, strings.Join(quoteIdentifiers(ctx.driver, columns), ", "), strings.Join(placeholders, ", "))
 
-	var err error
-	var result sql.Result
-
-	if ctx.tx != nil {
-		result, err = ctx.tx.Exec(query, values...)
-	} else {
-		result, err = ctx.db.Exec(query, values...)
+	if ctx.driver == driverPostgres {
+		// lib/pq doesn't support LastInsertId, read the generated ID back instead,
+		// along with server-side defaults
+		return ctx.insertReturning(c, query+" RETURNING *", values, entity)
 	}
 
+	result, err := ctx.exec(c, query, values...)
 	if err != nil {
 		return err
 	}
@@ -263,83 +362,148 @@ func (ctx *EnhancedDbContext) insertEntity(entity interface{}) error {
 	return nil
 }
 
-// updateEntity updates an existing entity in the database
-func (ctx *EnhancedDbContext) updateEntity(entity interface{}) error {
+// errNoRowReturned is returned by insertReturning when the statement returns no row,
+// e.g. an upsert whose update condition doesn't hold
+var errNoRowReturned = errors.New("insert returned no row")
+
+// insertReturning runs an INSERT ... RETURNING statement and scans the returned row
+// into the entity
+func (ctx *EnhancedDbContext) insertReturning(c context.Context, query string, values []interface{}, entity interface{}) error {
+	rows, err := ctx.query(c, query, values...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Warning: Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return errNoRowReturned
+	}
+
+	scanner, err := newRowScanner(rows, reflect.TypeOf(entity).Elem())
+	if err != nil {
+		return err
+	}
+	if err := scanner.scan(rows, entity); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// updateEntity updates an existing entity in the database. Entities with a
+// concurrency:"version" field are only updated at the version they were read at,
+// see ErrConcurrencyConflict.
+func (ctx *EnhancedDbContext) updateEntity(c context.Context, entity interface{}) error {
 	// Set UpdatedAt timestamp before updating
 	setTimestamps(entity, false) // false = update timestamp only
+	if err := setAuditFields(c, entity, false); err != nil {
+		return err
+	}
+	// Rows can't be moved to another tenant
+	if err := ctx.setTenant(entity); err != nil {
+		return err
+	}
+
+	// Store the next version, restoring the read one if the update fails
+	version, versioned := incrementVersion(entity)
 
-	tableName := getTableName(entity)
-	setPairs, values, idValue := getUpdateData(entity, ctx.driver)
+	tableName := ctx.tableOf(metadataOf(entity))
+	// Only write the columns that changed since the entity was loaded, if it was
+	setPairs, values, idValue := getUpdateData(entity, ctx.driver, ctx.ChangeTracker.ChangedColumns(entity))
 
 	// Safe: table/column names are trusted, user data is parameterized (see values...)
 	//nolint:gosec // G201: Identifiers are not user-controlled; all user data is parameterized.
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = ?",
-		tableName, strings.Join(setPairs, ", "))
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = %s",
+		tableName, strings.Join(setPairs, ", "), getPlaceholder(ctx.driver, len(values)))
+	values = append(values, idValue)
 
-	// Convert placeholders for PostgreSQL
-	query = convertQueryPlaceholders(query, ctx.driver)
+	if versioned {
+		query += fmt.Sprintf(" AND %s = %s", quoteIdentifier(ctx.driver, metadataOf(entity).versionColumn), getPlaceholder(ctx.driver, len(values)))
+		values = append(values, version)
+	}
+	if condition, ok := ctx.tenantCondition(entity, len(values)); ok {
+		query += condition
+		values = append(values, ctx.tenant)
+	}
 
-	values = append(values, idValue)
+	result, err := ctx.exec(c, query, values...)
 
-	if ctx.tx != nil {
-		_, err := ctx.tx.Exec(query, values...)
-		return err
+	if versioned {
+		err = checkVersionedUpdate(result, err, tableName, idValue, version)
+		if err != nil {
+			restoreVersion(entity, version)
+		}
 	}
-	_, err := ctx.db.Exec(query, values...)
 	return err
 }
 
 // deleteEntity removes an entity from the database
-func (ctx *EnhancedDbContext) deleteEntity(entity interface{}) error {
-	tableName := getTableName(entity)
-	idValue := getIDValue(entity)
+func (ctx *EnhancedDbContext) deleteEntity(c context.Context, entity interface{}) error {
+	if metadataOf(entity).softDeleteColumn != "" {
+		return ctx.softDeleteEntity(c, entity)
+	}
 
-	// Safe: table/column names are trusted, user data is parameterized (see idValue)
+	tableName := ctx.tableOf(metadataOf(entity))
+	args := []interface{}{getIDValue(entity)}
+
+	// Safe: table/column names are trusted, user data is parameterized (see args)
 	//nolint:gosec // G201: Identifiers are not user-controlled; all user data is parameterized.
 	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", tableName)
 
 	// Convert placeholders for PostgreSQL
 	query = convertQueryPlaceholders(query, ctx.driver)
 
-	// Debug output
-	fmt.Printf("DEBUG DELETE: tableName=%s, idValue=%v, query=%s\n", tableName, idValue, query)
-
-	if ctx.tx != nil {
-		result, err := ctx.tx.Exec(query, idValue)
-		if err == nil {
-			rowsAffected, _ := result.RowsAffected()
-			fmt.Printf("DEBUG DELETE TX: rowsAffected=%d\n", rowsAffected)
-		}
-		return err
-	}
-	result, err := ctx.db.Exec(query, idValue)
-	if err == nil {
-		rowsAffected, _ := result.RowsAffected()
-		fmt.Printf("DEBUG DELETE DB: rowsAffected=%d\n", rowsAffected)
+	if condition, ok := ctx.tenantCondition(entity, len(args)); ok {
+		query += condition
+		args = append(args, ctx.tenant)
 	}
+
+	_, err := ctx.exec(c, query, args...)
 	return err
 }
 
 // EnhancedDbSet provides LINQ-style querying capabilities
 type EnhancedDbSet[T any] struct {
-	ctx         *EnhancedDbContext
-	tableName   string
-	whereClause string
-	whereArgs   []interface{}
-	orderClause string
-	limitValue  int
-	offsetValue int
-	noTracking  bool
+	ctx          *EnhancedDbContext
+	tableName    string
+	columns      []string // Selected columns, all when empty
+	distinct     bool
+	distinctOn   []string // PostgreSQL DISTINCT ON expressions
+	whereClause  string
+	whereArgs    []interface{} // Led by the arguments of a FromSQL base query
+	groupBy      []string
+	having       string // HAVING condition with ? placeholders, numbered after whereArgs on build
+	havingArgs   []interface{}
+	orderClause  string
+	limitValue   int
+	offsetValue  int
+	cursor       string // Keyset cursor the results start after, see AfterCursor
+	noTracking   bool
+	tracking     bool   // Whether AsTracking overrides the no-tracking default of the context
+	fromSQL      bool   // Whether tableName is a FromSQL subquery
+	softDelete   string // Soft delete column filtered on, empty if none or ignored
+	tenantColumn string // Tenant column filtered on, empty if none or ignored
 }
 
 // NewEnhancedDbSet creates a new enhanced database set
 func NewEnhancedDbSet[T any](ctx *EnhancedDbContext) *EnhancedDbSet[T] {
 	var entity T
-	tableName := getTableName(&entity)
-	return &EnhancedDbSet[T]{
-		ctx:       ctx,
-		tableName: tableName,
+	meta := metadataOf(&entity)
+	set := &EnhancedDbSet[T]{
+		ctx:        ctx,
+		tableName:  ctx.tableOf(meta),
+		softDelete: meta.softDeleteColumn,
+	}
+	if field, ok := ctx.tenantField(meta); ok {
+		set.tenantColumn = field.column
 	}
+	return set
 }
 
 // Where adds a WHERE clause to the query
@@ -419,6 +583,48 @@ func (set *EnhancedDbSet[T]) WhereOr(condition string, args ...interface{}) *Enh
 	return &newSet
 }
 
+// Distinct removes duplicate rows from the results, usually with Select, e.g.
+// Select[Order, Country](orders.Distinct(), "country")
+func (set *EnhancedDbSet[T]) Distinct() *EnhancedDbSet[T] {
+	newSet := *set
+	newSet.distinct = true
+	return &newSet
+}
+
+// DistinctOn keeps the first row of each set of rows with the same values of
+// columns, in the order of the query, e.g. the latest order of each customer:
+//
+//	orders.DistinctOn("customer_id").OrderBy("customer_id").ThenByDescending("created_at")
+//
+// It is only supported on PostgreSQL, which requires the order to start with columns.
+func (set *EnhancedDbSet[T]) DistinctOn(columns ...string) *EnhancedDbSet[T] {
+	newSet := *set
+	newSet.distinctOn = append(append([]string(nil), set.distinctOn...), columns...)
+	return &newSet
+}
+
+// GroupBy groups the rows of the query by columns or expressions, e.g.
+// GroupBy("category_id") or GroupBy("date(created_at)"), usually with Select
+// and aggregate selectors like SumAs
+func (set *EnhancedDbSet[T]) GroupBy(columns ...string) *EnhancedDbSet[T] {
+	newSet := *set
+	newSet.groupBy = append(append([]string(nil), set.groupBy...), columns...)
+	return &newSet
+}
+
+// Having adds a HAVING condition filtering the groups of the query, e.g.
+// Having("SUM(total) > ?", 1000)
+func (set *EnhancedDbSet[T]) Having(condition string, args ...interface{}) *EnhancedDbSet[T] {
+	newSet := *set
+	if newSet.having != "" {
+		newSet.having += " AND " + condition
+	} else {
+		newSet.having = condition
+	}
+	newSet.havingArgs = append(append([]interface{}(nil), set.havingArgs...), args...)
+	return &newSet
+}
+
 // OrderBy adds an ORDER BY clause to the query
 func (set *EnhancedDbSet[T]) OrderBy(column string) *EnhancedDbSet[T] {
 	newSet := *set
@@ -433,6 +639,22 @@ func (set *EnhancedDbSet[T]) OrderByDescending(column string) *EnhancedDbSet[T]
 	return &newSet
 }
 
+// ThenBy orders the results with the same value of the previous order columns
+func (set *EnhancedDbSet[T]) ThenBy(column string) *EnhancedDbSet[T] {
+	if set.orderClause == "" {
+		return set.OrderBy(column)
+	}
+	newSet := *set
+	newSet.orderClause += ", " + column
+	return &newSet
+}
+
+// ThenByDescending orders the results with the same value of the previous order
+// columns, in descending order
+func (set *EnhancedDbSet[T]) ThenByDescending(column string) *EnhancedDbSet[T] {
+	return set.ThenBy(column + " DESC")
+}
+
 // Take limits the number of results
 func (set *EnhancedDbSet[T]) Take(count int) *EnhancedDbSet[T] {
 	newSet := *set
@@ -451,22 +673,45 @@ func (set *EnhancedDbSet[T]) Skip(count int) *EnhancedDbSet[T] {
 func (set *EnhancedDbSet[T]) AsNoTracking() *EnhancedDbSet[T] {
 	newSet := *set
 	newSet.noTracking = true
+	newSet.tracking = false
 	return &newSet
 }
 
+// AsTracking enables change tracking for the query, on a context that doesn't track
+// by default, see UseNoTrackingByDefault
+func (set *EnhancedDbSet[T]) AsTracking() *EnhancedDbSet[T] {
+	newSet := *set
+	newSet.noTracking = false
+	newSet.tracking = true
+	return &newSet
+}
+
+// tracks reports whether the query tracks the entities it returns
+func (set *EnhancedDbSet[T]) tracks() bool {
+	return set.tracking || (!set.noTracking && !set.ctx.noTracking)
+}
+
 // ToList executes the query and returns all results
 func (set *EnhancedDbSet[T]) ToList() ([]*T, error) {
-	query := set.buildQuery()
-
-	var rows *sql.Rows
-	var err error
+	return set.ToListContext(context.Background())
+}
 
-	if set.ctx.tx != nil {
-		rows, err = set.ctx.tx.Query(query, set.whereArgs...)
-	} else {
-		rows, err = set.ctx.db.Query(query, set.whereArgs...)
+// ToListContext executes the query and returns all results, canceling it when c is done
+func (set *EnhancedDbSet[T]) ToListContext(c context.Context) ([]*T, error) {
+	if err := set.checkQuery(); err != nil {
+		return nil, err
+	}
+	set, err := set.applyCursor()
+	if err != nil {
+		return nil, err
 	}
+	return set.list(c, set.buildQuery(), set.queryArgs())
+}
 
+// list runs a query built from the set and scans its rows, tracking them unless the
+// set doesn't track
+func (set *EnhancedDbSet[T]) list(c context.Context, query string, args []interface{}) ([]*T, error) {
+	rows, err := set.ctx.query(c, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -477,15 +722,27 @@ func (set *EnhancedDbSet[T]) ToList() ([]*T, error) {
 		}
 	}()
 
+	scanner, err := newRowScanner(rows, reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
 	var results []*T
 	for rows.Next() {
 		entity := new(T)
-		err := scanEntity(rows, entity)
+		err := scanner.scan(rows, entity)
 		if err != nil {
 			return nil, err
 		}
 
-		if !set.noTracking {
+		if set.tracks() {
+			// Rows already tracked return the tracked instance, keeping its changes
+			if tracked, ok := set.ctx.ChangeTracker.tracked(entity); ok {
+				if instance, ok := tracked.(*T); ok {
+					results = append(results, instance)
+					continue
+				}
+			}
 			set.ctx.ChangeTracker.TrackEntity(entity, EntityStateUnchanged)
 		}
 
@@ -497,7 +754,12 @@ func (set *EnhancedDbSet[T]) ToList() ([]*T, error) {
 
 // FirstOrDefault returns the first result or nil if none found
 func (set *EnhancedDbSet[T]) FirstOrDefault() (*T, error) {
-	results, err := set.Take(1).ToList()
+	return set.FirstOrDefaultContext(context.Background())
+}
+
+// FirstOrDefaultContext returns the first result or nil if none found, honoring c
+func (set *EnhancedDbSet[T]) FirstOrDefaultContext(c context.Context) (*T, error) {
+	results, err := set.Take(1).ToListContext(c)
 	if err != nil {
 		return nil, err
 	}
@@ -509,42 +771,72 @@ func (set *EnhancedDbSet[T]) FirstOrDefault() (*T, error) {
 
 // Count returns the number of entities matching the query
 func (set *EnhancedDbSet[T]) Count() (int, error) {
+	return set.CountContext(context.Background())
+}
+
+// CountContext returns the number of entities matching the query, honoring c
+func (set *EnhancedDbSet[T]) CountContext(c context.Context) (int, error) {
+	if err := set.checkQuery(); err != nil {
+		return 0, err
+	}
+
 	// Safe: table name is trusted, user data is parameterized (see whereArgs...)
 	//nolint:gosec // G201: Identifiers are not user-controlled; all user data is parameterized.
 	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", set.tableName)
-	if set.whereClause != "" {
-		query += " WHERE " + set.whereClause
+	query += set.buildWhere()
+	if set.distinct || len(set.distinctOn) > 0 {
+		// Count the distinct rows rather than all of them
+		query = fmt.Sprintf("SELECT COUNT(*) FROM (%s FROM %s", set.buildSelect(), set.tableName)
+		query += set.buildWhere()
+		query += set.buildGroupBy() + ") AS counted"
+	} else if len(set.groupBy) > 0 {
+		// Count the groups rather than the rows of each group
+		query = fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s", set.tableName)
+		query += set.buildWhere()
+		query += set.buildGroupBy() + ") AS grouped"
 	}
 
 	var count int
-	var err error
-
-	if set.ctx.tx != nil {
-		err = set.ctx.tx.QueryRow(query, set.whereArgs...).Scan(&count)
-	} else {
-		err = set.ctx.db.QueryRow(query, set.whereArgs...).Scan(&count)
-	}
-
+	err := set.ctx.queryRow(c, query, set.queryArgs()...).Scan(&count)
 	return count, err
 }
 
 // Any checks if any records match the query
 func (set *EnhancedDbSet[T]) Any() (bool, error) {
-	count, err := set.Count()
+	return set.AnyContext(context.Background())
+}
+
+// AnyContext checks if any records match the query, honoring c
+func (set *EnhancedDbSet[T]) AnyContext(c context.Context) (bool, error) {
+	count, err := set.CountContext(c)
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-// Find finds an entity by its primary key
+// Find finds an entity by its primary key. An entity already tracked by the context
+// is returned without querying the database.
 func (set *EnhancedDbSet[T]) Find(id interface{}) (*T, error) {
-	return set.Where("id = ?", id).FirstOrDefault()
+	return set.FindContext(context.Background(), id)
+}
+
+// FindContext finds an entity by its primary key, honoring c
+func (set *EnhancedDbSet[T]) FindContext(c context.Context, id interface{}) (*T, error) {
+	if entity, ok := set.cached(id); ok {
+		return entity, nil
+	}
+	return set.Where("id = ?", id).FirstOrDefaultContext(c)
 }
 
 // First returns the first result (errors if no results)
 func (set *EnhancedDbSet[T]) First() (*T, error) {
-	results, err := set.Take(1).ToList()
+	return set.FirstContext(context.Background())
+}
+
+// FirstContext returns the first result (errors if no results), honoring c
+func (set *EnhancedDbSet[T]) FirstContext(c context.Context) (*T, error) {
+	results, err := set.Take(1).ToListContext(c)
 	if err != nil {
 		return nil, err
 	}
@@ -556,7 +848,12 @@ func (set *EnhancedDbSet[T]) First() (*T, error) {
 
 // Single returns a single result (errors if 0 or >1 results)
 func (set *EnhancedDbSet[T]) Single() (*T, error) {
-	results, err := set.Take(2).ToList()
+	return set.SingleContext(context.Background())
+}
+
+// SingleContext returns a single result (errors if 0 or >1 results), honoring c
+func (set *EnhancedDbSet[T]) SingleContext(c context.Context) (*T, error) {
+	results, err := set.Take(2).ToListContext(c)
 	if err != nil {
 		return nil, err
 	}
@@ -571,11 +868,11 @@ func (set *EnhancedDbSet[T]) Single() (*T, error) {
 
 // buildQuery constructs the SQL query string
 func (set *EnhancedDbSet[T]) buildQuery() string {
-	query := fmt.Sprintf("SELECT * FROM %s", set.tableName)
+	query := fmt.Sprintf("%s FROM %s", set.buildSelect(), set.tableName)
 
-	if set.whereClause != "" {
-		query += " WHERE " + set.whereClause
-	}
+	query += set.buildWhere()
+
+	query += set.buildGroupBy()
 
 	if set.orderClause != "" {
 		query += " ORDER BY " + set.orderClause
@@ -592,21 +889,115 @@ func (set *EnhancedDbSet[T]) buildQuery() string {
 	return query
 }
 
-// Helper functions
+// buildSelect constructs the SELECT clause with the selected columns
+func (set *EnhancedDbSet[T]) buildSelect() string {
+	columns := "*"
+	if len(set.columns) > 0 {
+		columns = strings.Join(set.columns, ", ")
+	}
 
-// getTableName extracts table name from entity type
-func getTableName(entity interface{}) string {
-	// Check if entity has TableName method
-	if tn, ok := entity.(interface{ TableName() string }); ok {
-		return tn.TableName()
+	switch {
+	case len(set.distinctOn) > 0:
+		return fmt.Sprintf("SELECT DISTINCT ON (%s) %s", strings.Join(set.distinctOn, ", "), columns)
+	case set.distinct:
+		return "SELECT DISTINCT " + columns
+	default:
+		return "SELECT " + columns
+	}
+}
+
+// checkQuery returns an error if DistinctOn is used on a database without it, or
+// tenant entities are queried without a tenant
+func (set *EnhancedDbSet[T]) checkQuery() error {
+	if len(set.distinctOn) > 0 && set.ctx.driver != driverPostgres {
+		return fmt.Errorf("DISTINCT ON is not supported by %s", set.ctx.driver)
+	}
+	if set.tenantColumn != "" && set.ctx.tenant == "" {
+		return fmt.Errorf("%w to query %s, see ForTenant", ErrNoTenant, set.tableName)
+	}
+	return nil
+}
+
+// buildWhere constructs the WHERE clause, if any, leaving soft deleted rows and the
+// rows of other tenants out
+func (set *EnhancedDbSet[T]) buildWhere() string {
+	var filters []string
+	if set.softDelete != "" {
+		filters = append(filters, quoteIdentifier(set.ctx.driver, set.softDelete)+" IS NULL")
+	}
+	if set.tenantColumn != "" {
+		filters = append(filters, quoteIdentifier(set.ctx.driver, set.tenantColumn)+" = "+
+			getPlaceholder(set.ctx.driver, len(set.whereArgs)))
+	}
+
+	where := set.whereClause
+	if len(filters) > 0 {
+		filter := strings.Join(filters, " AND ")
+		if where != "" {
+			where = "(" + where + ") AND " + filter
+		} else {
+			where = filter
+		}
+	}
+
+	if where == "" {
+		return ""
+	}
+	return " WHERE " + where
+}
+
+// whereParams returns the arguments of the WHERE clause placeholders, in order
+func (set *EnhancedDbSet[T]) whereParams() []interface{} {
+	if set.tenantColumn == "" {
+		return set.whereArgs
+	}
+	return append(append([]interface{}(nil), set.whereArgs...), set.ctx.tenant)
+}
+
+// buildGroupBy constructs the GROUP BY and HAVING clauses, if any
+func (set *EnhancedDbSet[T]) buildGroupBy() string {
+	if len(set.groupBy) == 0 {
+		return ""
+	}
+
+	clause := " GROUP BY " + strings.Join(set.groupBy, ", ")
+	if set.having != "" {
+		having := set.having
+		if set.ctx.driver == driverPostgres {
+			having = numberPlaceholders(having, len(set.whereParams()))
+		}
+		clause += " HAVING " + having
 	}
+	return clause
+}
 
-	// Fall back to struct name converted to snake_case
-	t := reflect.TypeOf(entity)
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
+// queryArgs returns the arguments of the query placeholders, in order
+func (set *EnhancedDbSet[T]) queryArgs() []interface{} {
+	if len(set.havingArgs) == 0 {
+		return set.whereParams()
 	}
-	return toSnakeCase(t.Name())
+	return append(append([]interface{}(nil), set.whereParams()...), set.havingArgs...)
+}
+
+// numberPlaceholders converts ? placeholders to $N, starting after the given count
+func numberPlaceholders(condition string, count int) string {
+	result := ""
+	for _, char := range condition {
+		if char == '?' {
+			count++
+			result += fmt.Sprintf("$%d", count)
+		} else {
+			result += string(char)
+		}
+	}
+	return result
+}
+
+// Helper functions
+
+// getTableName extracts table name from entity type
+func getTableName(entity interface{}) string {
+	return metadataOf(entity).tableName
 }
 
 // getInsertData extracts columns, values, and placeholders for INSERT
@@ -631,13 +1022,6 @@ func shouldSkipField(field reflect.StructField, excludeID bool) bool {
 	return false
 }
 
-// handleEmbeddedStruct extracts field data from an embedded struct
-func handleEmbeddedStruct(field reflect.StructField, value reflect.Value, excludeID bool, driver string) ([]string, []interface{}, []string) {
-	embeddedPtr := reflect.New(field.Type)
-	embeddedPtr.Elem().Set(value)
-	return getFieldData(embeddedPtr.Interface(), excludeID, driver)
-}
-
 // getPlaceholder returns the correct placeholder for the driver
 func getPlaceholder(driver string, idx int) string {
 	if driver == driverPostgres {
@@ -646,46 +1030,37 @@ func getPlaceholder(driver string, idx int) string {
 	return "?"
 }
 
-// getFieldData extracts field data recursively, handling embedded structs
+// getFieldData extracts the columns, values and placeholders of the persisted fields,
+// including those of embedded structs
 func getFieldData(entity interface{}, excludeID bool, driver string) ([]string, []interface{}, []string) {
 	v := reflect.ValueOf(entity).Elem()
-	t := v.Type()
-
-	var columns []string
-	var values []interface{}
-	var placeholders []string
+	meta := metadataFor(v.Type())
 
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		value := v.Field(i)
+	columns := make([]string, 0, len(meta.fields))
+	values := make([]interface{}, 0, len(meta.fields))
+	placeholders := make([]string, 0, len(meta.fields))
 
-		if shouldSkipField(field, excludeID) {
+	for _, field := range meta.fields {
+		if excludeID && field.id {
 			continue
 		}
 
-		if field.Anonymous && field.Type.Kind() == reflect.Struct {
-			embeddedCols, embeddedVals, embeddedPlaceholders := handleEmbeddedStruct(field, value, excludeID, driver)
-			columns = append(columns, embeddedCols...)
-			values = append(values, embeddedVals...)
-			placeholders = append(placeholders, embeddedPlaceholders...)
-			continue
+		value := v.FieldByIndex(field.index).Interface()
+		if t, ok := value.(time.Time); ok && field.softDelete && t.IsZero() {
+			value = nil // Not deleted
 		}
 
-		columnName := field.Tag.Get("db")
-		if columnName == "" {
-			columnName = toSnakeCase(field.Name)
-		}
-
-		columns = append(columns, columnName)
-		values = append(values, value.Interface())
+		columns = append(columns, field.column)
+		values = append(values, value)
 		placeholders = append(placeholders, getPlaceholder(driver, len(placeholders)))
 	}
 
 	return columns, values, placeholders
 }
 
-// getUpdateData extracts SET clauses and values for UPDATE
-func getUpdateData(entity interface{}, driver string) ([]string, []interface{}, interface{}) {
+// getUpdateData extracts SET clauses and values for UPDATE, of all columns or, if
+// not nil, only of the given ones
+func getUpdateData(entity interface{}, driver string, only []string) ([]string, []interface{}, interface{}) {
 	columns, values, _ := getFieldData(entity, false, driver) // false = include all fields
 
 	var setPairs []string
@@ -697,11 +1072,10 @@ func getUpdateData(entity interface{}, driver string) ([]string, []interface{},
 			idValue = values[i]
 			continue
 		}
-		if driver == driverPostgres {
-			setPairs = append(setPairs, fmt.Sprintf("%s = $%d", col, len(updateValues)+1))
-		} else {
-			setPairs = append(setPairs, col+" = ?")
+		if only != nil && !slices.Contains(only, col) {
+			continue
 		}
+		setPairs = append(setPairs, quoteIdentifier(driver, col)+" = "+getPlaceholder(driver, len(updateValues)))
 		updateValues = append(updateValues, values[i])
 	}
 
@@ -710,68 +1084,28 @@ func getUpdateData(entity interface{}, driver string) ([]string, []interface{},
 
 // getIDValue extracts the ID value from an entity, including embedded structs
 func getIDValue(entity interface{}) interface{} {
-	return findFieldValue(entity, "ID")
+	v := reflect.ValueOf(entity).Elem()
+	id := metadataFor(v.Type()).id
+	if id == nil {
+		return nil
+	}
+	return v.FieldByIndex(id).Interface()
 }
 
 // setIDField sets the ID field of an entity, including embedded structs
 func setIDField(entity interface{}, id int64) {
-	setEntityIDValue(entity, "ID", id)
-}
-
-// findFieldValue recursively finds a field value in struct and embedded structs
-func findFieldValue(entity interface{}, fieldName string) interface{} {
 	v := reflect.ValueOf(entity).Elem()
-	t := v.Type()
-
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		value := v.Field(i)
-
-		// Check if this is the field we're looking for
-		if field.Name == fieldName {
-			return value.Interface()
-		}
-
-		// Check embedded structs
-		if field.Anonymous && field.Type.Kind() == reflect.Struct {
-			embeddedPtr := reflect.New(field.Type)
-			embeddedPtr.Elem().Set(value)
-			if result := findFieldValue(embeddedPtr.Interface(), fieldName); result != nil {
-				return result
-			}
-		}
+	field, ok := settableField(v, metadataFor(v.Type()).id)
+	if !ok {
+		return
 	}
-	return nil
-}
 
-// setEntityIDValue recursively sets a field value in struct and embedded structs
-func setEntityIDValue(entity interface{}, fieldName string, value int64) {
-	v := reflect.ValueOf(entity).Elem()
-	t := v.Type()
-
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		fieldValue := v.Field(i)
-
-		// Check if this is the field we're looking for
-		if field.Name == fieldName && fieldValue.CanSet() {
-			switch fieldValue.Kind() {
-			case reflect.Int, reflect.Int32, reflect.Int64:
-				fieldValue.SetInt(value)
-			case reflect.Uint, reflect.Uint32, reflect.Uint64:
-				if value >= 0 {
-					fieldValue.SetUint(uint64(value))
-				}
-			}
-			return
-		}
-
-		// Check embedded structs
-		if field.Anonymous && field.Type.Kind() == reflect.Struct && fieldValue.CanSet() {
-			embeddedPtr := reflect.New(field.Type)
-			embeddedPtr.Elem().Set(fieldValue)
-			setEntityIDValue(embeddedPtr.Interface(), fieldName, value)
-			fieldValue.Set(embeddedPtr.Elem())
+	switch field.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		field.SetInt(id)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if id >= 0 {
+			field.SetUint(uint64(id))
 		}
 	}
 }
@@ -779,85 +1113,79 @@ func setEntityIDValue(entity interface{}, fieldName string, value int64) {
 // setTimestamps sets CreatedAt and UpdatedAt timestamps on an entity
 func setTimestamps(entity interface{}, isCreate bool) {
 	now := time.Now()
-
-	if isCreate {
-		setTimestampField(entity, "CreatedAt", now)
-	}
-	setTimestampField(entity, "UpdatedAt", now)
-}
-
-// setTimestampField recursively sets a timestamp field in struct and embedded structs
-func setTimestampField(entity interface{}, fieldName string, value time.Time) {
 	v := reflect.ValueOf(entity).Elem()
-	t := v.Type()
-
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		fieldValue := v.Field(i)
+	meta := metadataFor(v.Type())
 
-		// Check if this is the field we're looking for
-		if field.Name == fieldName && fieldValue.CanSet() {
-			if fieldValue.Type() == reflect.TypeOf(time.Time{}) {
-				fieldValue.Set(reflect.ValueOf(value))
-			}
-			return
-		}
-
-		// Check embedded structs
-		if field.Anonymous && field.Type.Kind() == reflect.Struct && fieldValue.CanSet() {
-			embeddedPtr := reflect.New(field.Type)
-			embeddedPtr.Elem().Set(fieldValue)
-			setTimestampField(embeddedPtr.Interface(), fieldName, value)
-			fieldValue.Set(embeddedPtr.Elem())
+	if isCreate {
+		if field, ok := settableField(v, meta.createdAt); ok {
+			field.Set(reflect.ValueOf(now))
 		}
 	}
+	if field, ok := settableField(v, meta.updatedAt); ok {
+		field.Set(reflect.ValueOf(now))
+	}
 }
 
-// scanEntity scans database row into entity
-func scanEntity(rows *sql.Rows, entity interface{}) error {
-	v := reflect.ValueOf(entity).Elem()
+// rowScanner scans the rows of a query into entities of one type, resolving the
+// field of each column once per query
+type rowScanner struct {
+	columns   []string
+	fields    [][]int // Field index by column, nil when no field matches
+	values    []interface{}
+	valuePtrs []interface{}
+}
 
+// newRowScanner creates a scanner for the columns of rows into entities of type t
+func newRowScanner(rows *sql.Rows, t reflect.Type) (*rowScanner, error) {
 	columns, err := rows.Columns()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Create slice of interface{} to hold column values
-	values := make([]interface{}, len(columns))
-	valuePtrs := make([]interface{}, len(columns))
-
-	for i := range columns {
-		valuePtrs[i] = &values[i]
+	meta := metadataFor(t)
+	s := &rowScanner{
+		columns:   columns,
+		fields:    make([][]int, len(columns)),
+		values:    make([]interface{}, len(columns)),
+		valuePtrs: make([]interface{}, len(columns)),
+	}
+	for i, column := range columns {
+		s.fields[i] = meta.columnIndex(t, column)
+		s.valuePtrs[i] = &s.values[i]
 	}
+	return s, nil
+}
 
-	err = rows.Scan(valuePtrs...)
-	if err != nil {
+// scan scans the current row into entity
+func (s *rowScanner) scan(rows *sql.Rows, entity interface{}) error {
+	if err := rows.Scan(s.valuePtrs...); err != nil {
 		return err
 	}
 
-	// Map columns to struct fields
-	for i, column := range columns {
-		fieldName := toCamelCase(column)
-		field := v.FieldByName(fieldName)
-
-		if !field.IsValid() || !field.CanSet() {
+	v := reflect.ValueOf(entity).Elem()
+	for i, value := range s.values {
+		field, ok := settableField(v, s.fields[i])
+		if !ok {
 			continue
 		}
 
-		value := values[i]
-		if value == nil {
-			continue
-		}
-
-		err := setFieldValue(field, value)
-		if err != nil {
-			return err
+		if err := setFieldValue(field, value); err != nil {
+			return fmt.Errorf("failed to scan column %s: %w", s.columns[i], err)
 		}
 	}
 
 	return nil
 }
 
+// scanEntity scans database row into entity
+func scanEntity(rows *sql.Rows, entity interface{}) error {
+	scanner, err := newRowScanner(rows, reflect.TypeOf(entity).Elem())
+	if err != nil {
+		return err
+	}
+	return scanner.scan(rows, entity)
+}
+
 // Helper for setting string fields
 func setStringField(field reflect.Value, value interface{}) {
 	if str, ok := value.(string); ok {
@@ -893,6 +1221,10 @@ func setUintField(field reflect.Value, value interface{}) {
 func setFloatField(field reflect.Value, value interface{}) {
 	if num, ok := value.(float64); ok {
 		field.SetFloat(num)
+	} else if num, ok := value.(float32); ok {
+		field.SetFloat(float64(num))
+	} else if num, ok := value.(int64); ok {
+		field.SetFloat(float64(num))
 	} else if str, ok := value.(string); ok {
 		if num, err := strconv.ParseFloat(str, 64); err == nil {
 			field.SetFloat(num)
@@ -906,24 +1238,66 @@ func setBoolField(field reflect.Value, value interface{}) {
 		field.SetBool(b)
 	} else if num, ok := value.(int64); ok {
 		field.SetBool(num != 0)
+	} else if str, ok := value.(string); ok {
+		if b, err := strconv.ParseBool(str); err == nil {
+			field.SetBool(b)
+		}
 	}
 }
 
+// timeLayouts lists the textual timestamp formats returned by supported drivers
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
 // Helper for setting time.Time fields
 func setTimeField(field reflect.Value, value interface{}) {
-	if str, ok := value.(string); ok {
-		if t, err := time.Parse("2006-01-02 15:04:05", str); err == nil {
-			field.Set(reflect.ValueOf(t))
+	switch v := value.(type) {
+	case time.Time:
+		field.Set(reflect.ValueOf(v))
+	case []byte:
+		setTimeField(field, string(v))
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				field.Set(reflect.ValueOf(t))
+				return
+			}
 		}
 	}
 }
 
-// setFieldValue sets a field value with type conversion
+// setFieldValue sets a field value with type conversion. Fields implementing
+// sql.Scanner, like sql.NullString, scan the value themselves; pointer fields are
+// set to a new value, or nil for NULL.
 func setFieldValue(field reflect.Value, value interface{}) error {
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
 	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := setFieldValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
 		return nil
 	}
 
+	// MySQL returns text for DECIMAL and DATETIME columns, and for every column of
+	// queries without arguments
+	if bytes, ok := value.([]byte); ok && field.Kind() != reflect.Slice {
+		value = string(bytes)
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		setStringField(field, value)
@@ -0,0 +1,166 @@
+package dbcontext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// testUser is a tagged entity used by the dbcontext tests
+type testUser struct {
+	ID        int64     `db:"id"`
+	Email     string    `db:"email_address"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (testUser) TableName() string { return "users" }
+
+// newTestContext creates a context backed by an in-memory SQLite database
+func newTestContext(t testing.TB) *EnhancedDbContext {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	// A single connection keeps the in-memory database alive across queries
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		email_address TEXT NOT NULL,
+		name TEXT NOT NULL,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	return NewEnhancedDbContextWithDB(db)
+}
+
+func TestScanEntityUsesDbTags(t *testing.T) {
+	ctx := newTestContext(t)
+
+	user := &testUser{Email: "john@example.com", Name: "John"}
+	ctx.Add(user)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if user.ID == 0 {
+		t.Fatal("Expected ID to be set after insert")
+	}
+
+	found, err := NewEnhancedDbSet[testUser](ctx).Find(user.ID)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if found == nil {
+		t.Fatal("Expected user to be found")
+	}
+	if found.ID != user.ID {
+		t.Errorf("Expected ID %d, got %d", user.ID, found.ID)
+	}
+	if found.Email != "john@example.com" {
+		t.Errorf("Expected email to be mapped via db tag, got %q", found.Email)
+	}
+	if found.CreatedAt.IsZero() {
+		t.Error("Expected CreatedAt to be scanned")
+	}
+}
+
+func TestDetach(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := NewEnhancedDbContextWithDB(db)
+	user := &testUser{Name: "Ana"}
+
+	ctx.Add(user)
+	ctx.Detach(user)
+
+	if len(ctx.ChangeTracker.entities) != 0 {
+		t.Errorf("Expected detached entity to be untracked, got %d tracked", len(ctx.ChangeTracker.entities))
+	}
+	if affected, err := ctx.SaveChanges(); err != nil || affected != 0 {
+		t.Errorf("Expected nothing to save, got %d, %v", affected, err)
+	}
+}
+
+func TestContextVariantsHonorCancellation(t *testing.T) {
+	ctx := newTestContext(t)
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	user := &testUser{Email: "ana@example.com", Name: "Ana"}
+	ctx.Add(user)
+	if _, err := ctx.SaveChangesContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected SaveChangesContext to fail with context.Canceled, got %v", err)
+	}
+	if state := ctx.ChangeTracker.GetEntityState(user); state != EntityStateAdded {
+		t.Errorf("Expected entity to stay Added after a canceled save, got %v", state)
+	}
+
+	if affected, err := ctx.SaveChangesContext(t.Context()); err != nil || affected != 1 {
+		t.Fatalf("Expected 1 entity saved, got %d, %v", affected, err)
+	}
+
+	users := NewEnhancedDbSet[testUser](ctx)
+	if _, err := users.ToListContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ToListContext to fail with context.Canceled, got %v", err)
+	}
+	if _, err := users.CountContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected CountContext to fail with context.Canceled, got %v", err)
+	}
+	if _, err := NewEnhancedSet[testUser](ctx).ToListContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected EnhancedSet.ToListContext to fail with context.Canceled, got %v", err)
+	}
+
+	found, err := users.FindContext(t.Context(), user.ID)
+	if err != nil || found == nil || found.Email != user.Email {
+		t.Errorf("Expected FindContext to return the saved user, got %v, %v", found, err)
+	}
+}
+
+// testMember is a user whose role is defaulted by the database
+type testMember struct {
+	ID    int64  `db:"id"`
+	Email string `db:"email_address"`
+	Name  string `db:"name"`
+	Role  string `db:"role" sql:"-"` // Left out of inserts
+}
+
+func (testMember) TableName() string { return "users" }
+
+func TestInsertReturningForPostgres(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'member'`); err != nil {
+		t.Fatalf("Failed to add column: %v", err)
+	}
+
+	// SQLite also supports RETURNING and $N parameters, exercising the PostgreSQL path
+	ctx.driver = driverPostgres
+
+	member := &testMember{Email: "ana@example.com", Name: "Ana"}
+	if err := ctx.insertEntity(t.Context(), member); err != nil {
+		t.Fatalf("insertEntity failed: %v", err)
+	}
+	if member.ID == 0 {
+		t.Error("Expected the generated ID to be read back")
+	}
+	if member.Role != "member" {
+		t.Errorf("Expected the server-side default to be read back, got %q", member.Role)
+	}
+}
@@ -0,0 +1,48 @@
+package dbcontext
+
+import "testing"
+
+func TestDistinct(t *testing.T) {
+	ctx := newTestContext(t)
+	for _, name := range []string{"Ana", "Budi", "Ana", "Citra", "Budi"} {
+		ctx.Add(&testUser{Email: name + "@example.com", Name: name})
+	}
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	users := NewEnhancedDbSet[testUser](ctx)
+
+	names, err := Select[testUser, testUser](users.Distinct().OrderBy("name"), "name").ToList()
+	if err != nil {
+		t.Fatalf("ToList failed: %v", err)
+	}
+	if len(names) != 3 || names[0].Name != "Ana" || names[1].Name != "Budi" || names[2].Name != "Citra" {
+		t.Errorf("Expected Ana, Budi and Citra once each, got %+v", names)
+	}
+
+	count, err := Select[testUser, testUser](users.Where("name <> ?", "Citra").Distinct(), "name").Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 distinct names, got %d", count)
+	}
+}
+
+func TestDistinctOn(t *testing.T) {
+	ctx := newTestContext(t)
+	latest := NewEnhancedDbSet[testUser](ctx).DistinctOn("name").OrderBy("name").ThenByDescending("created_at")
+
+	if _, err := latest.ToList(); err == nil {
+		t.Error("Expected DistinctOn to fail on SQLite")
+	}
+	if _, err := latest.Count(); err == nil {
+		t.Error("Expected DistinctOn count to fail on SQLite")
+	}
+
+	ctx.driver = driverPostgres
+	expected := `SELECT DISTINCT ON (name) * FROM "users" ORDER BY name, created_at DESC`
+	if query := latest.buildQuery(); query != expected {
+		t.Errorf("Expected %q, got %q", expected, query)
+	}
+}
@@ -2,6 +2,7 @@
 package dbcontext
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -230,27 +231,15 @@ func (es *EnhancedSet[T]) RightJoin(table string, condition string) *EnhancedSet
 
 // ToList executes the query and returns all results
 func (es *EnhancedSet[T]) ToList() ([]T, error) {
-	query, args := es.builder.buildSelectQuery()
+	return es.ToListContext(context.Background())
+}
 
-	var db *sql.DB
-	if es.builder.ctx.tx != nil {
-		// Use transaction if available
-		rows, err := es.builder.ctx.tx.Query(query, args...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute query: %w", err)
-		}
-		defer func() {
-			if closeErr := rows.Close(); closeErr != nil {
-				// Log but don't affect return value
-				fmt.Printf("Warning: Failed to close rows: %v\n", closeErr)
-			}
-		}()
+// ToListContext executes the query and returns all results, canceling it when ctx is done
+func (es *EnhancedSet[T]) ToListContext(ctx context.Context) ([]T, error) {
+	query, args := es.builder.buildSelectQuery()
 
-		return es.scanRows(rows)
-	}
-	// Use regular database connection
-	db = es.builder.ctx.Database.db
-	rows, err := db.Query(query, args...)
+	// Use the transaction of the context if available
+	rows, err := es.builder.ctx.query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -266,8 +255,13 @@ func (es *EnhancedSet[T]) ToList() ([]T, error) {
 
 // First executes the query and returns the first result
 func (es *EnhancedSet[T]) First() (T, error) {
+	return es.FirstContext(context.Background())
+}
+
+// FirstContext executes the query and returns the first result, honoring ctx
+func (es *EnhancedSet[T]) FirstContext(ctx context.Context) (T, error) {
 	es.builder.limit = 1
-	results, err := es.ToList()
+	results, err := es.ToListContext(ctx)
 
 	var zero T
 	if err != nil {
@@ -283,8 +277,14 @@ func (es *EnhancedSet[T]) First() (T, error) {
 
 // FirstOrDefault executes the query and returns the first result or default value
 func (es *EnhancedSet[T]) FirstOrDefault() (T, error) {
+	return es.FirstOrDefaultContext(context.Background())
+}
+
+// FirstOrDefaultContext executes the query and returns the first result or default
+// value, honoring ctx
+func (es *EnhancedSet[T]) FirstOrDefaultContext(ctx context.Context) (T, error) {
 	es.builder.limit = 1
-	results, err := es.ToList()
+	results, err := es.ToListContext(ctx)
 
 	var zero T
 	if err != nil {
@@ -300,7 +300,13 @@ func (es *EnhancedSet[T]) FirstOrDefault() (T, error) {
 
 // Single executes the query and returns a single result (errors if 0 or >1 results)
 func (es *EnhancedSet[T]) Single() (T, error) {
-	results, err := es.ToList()
+	return es.SingleContext(context.Background())
+}
+
+// SingleContext executes the query and returns a single result (errors if 0 or >1
+// results), honoring ctx
+func (es *EnhancedSet[T]) SingleContext(ctx context.Context) (T, error) {
+	results, err := es.ToListContext(ctx)
 
 	var zero T
 	if err != nil {
@@ -320,6 +326,11 @@ func (es *EnhancedSet[T]) Single() (T, error) {
 
 // Count returns the count of records matching the query
 func (es *EnhancedSet[T]) Count() (int64, error) {
+	return es.CountContext(context.Background())
+}
+
+// CountContext returns the count of records matching the query, honoring ctx
+func (es *EnhancedSet[T]) CountContext(ctx context.Context) (int64, error) {
 	// Create a copy of the builder for count query
 	countBuilder := &QueryBuilder{
 		ctx:          es.builder.ctx,
@@ -335,15 +346,7 @@ func (es *EnhancedSet[T]) Count() (int64, error) {
 	query, args := countBuilder.buildSelectQuery()
 
 	var count int64
-	var err error
-
-	if es.builder.ctx.tx != nil {
-		err = es.builder.ctx.tx.QueryRow(query, args...).Scan(&count)
-	} else {
-		err = es.builder.ctx.Database.db.QueryRow(query, args...).Scan(&count)
-	}
-
-	if err != nil {
+	if err := es.builder.ctx.queryRow(ctx, query, args...).Scan(&count); err != nil {
 		return 0, fmt.Errorf("failed to count records: %w", err)
 	}
 
@@ -352,7 +355,12 @@ func (es *EnhancedSet[T]) Count() (int64, error) {
 
 // Any returns true if any records match the query
 func (es *EnhancedSet[T]) Any() (bool, error) {
-	count, err := es.Count()
+	return es.AnyContext(context.Background())
+}
+
+// AnyContext returns true if any records match the query, honoring ctx
+func (es *EnhancedSet[T]) AnyContext(ctx context.Context) (bool, error) {
+	count, err := es.CountContext(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -361,7 +369,12 @@ func (es *EnhancedSet[T]) Any() (bool, error) {
 
 // Find finds an entity by its primary key
 func (es *EnhancedSet[T]) Find(id interface{}) (T, error) {
-	return es.Where("id", "=", id).First()
+	return es.FindContext(context.Background(), id)
+}
+
+// FindContext finds an entity by its primary key, honoring ctx
+func (es *EnhancedSet[T]) FindContext(ctx context.Context, id interface{}) (T, error) {
+	return es.Where("id", "=", id).FirstContext(ctx)
 }
 
 // scanRows scans database rows into entities
@@ -373,15 +386,21 @@ func (es *EnhancedSet[T]) scanRows(rows *sql.Rows) ([]T, error) {
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
+	// Resolve the field of each column by db tag once for all rows
+	tagged := metadataFor(es.builder.entityType).tagged
+	fields := make([][]int, len(columns))
+	for i, col := range columns {
+		fields[i] = tagged[col]
+	}
+
 	for rows.Next() {
 		entity := reflect.New(es.builder.entityType).Interface()
 		valuePtrs := make([]interface{}, len(columns))
 
 		// Map columns to struct fields
 		entityVal := reflect.ValueOf(entity).Elem()
-		for i, col := range columns {
-			field := es.findFieldByDbTag(entityVal, col)
-			if field.IsValid() && field.CanSet() {
+		for i := range columns {
+			if field, ok := settableField(entityVal, fields[i]); ok {
 				valuePtrs[i] = field.Addr().Interface()
 			} else {
 				var temp interface{}
@@ -410,18 +429,6 @@ func (es *EnhancedSet[T]) scanRows(rows *sql.Rows) ([]T, error) {
 	return results, nil
 }
 
-// findFieldByDbTag finds a struct field by its db tag
-func (es *EnhancedSet[T]) findFieldByDbTag(val reflect.Value, dbTag string) reflect.Value {
-	typ := val.Type()
-	for i := 0; i < val.NumField(); i++ {
-		field := typ.Field(i)
-		if field.Tag.Get("db") == dbTag {
-			return val.Field(i)
-		}
-	}
-	return reflect.Value{}
-}
-
 // buildSelectQuery builds the complete SELECT query
 func (qb *QueryBuilder) buildSelectQuery() (string, []interface{}) {
 	var query strings.Builder
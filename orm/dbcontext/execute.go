@@ -0,0 +1,109 @@
+package dbcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExecuteDelete deletes the rows matching the query in a single statement, without
+// loading them, and returns the number of rows deleted. Without a Where clause it
+// deletes the whole table. Rows of soft delete entities are soft deleted.
+//
+// Unchanged tracked entities of T are detached, so they are loaded again by the next
+// queries, see Invalidate.
+func (set *EnhancedDbSet[T]) ExecuteDelete() (int, error) {
+	return set.ExecuteDeleteContext(context.Background())
+}
+
+// ExecuteDeleteContext deletes the rows matching the query in a single statement,
+// honoring c
+func (set *EnhancedDbSet[T]) ExecuteDeleteContext(c context.Context) (int, error) {
+	if column := metadataOf(new(T)).softDeleteColumn; column != "" {
+		return set.ExecuteUpdateContext(c, map[string]interface{}{column: time.Now()})
+	}
+
+	// Safe: table name is trusted, user data is parameterized (see whereArgs...)
+	//nolint:gosec // G201: Identifiers are not user-controlled; all user data is parameterized.
+	query := fmt.Sprintf("DELETE FROM %s", set.tableName)
+	query += set.buildWhere()
+
+	return set.execute(c, query, set.whereParams())
+}
+
+// ExecuteUpdate sets columns of the rows matching the query in a single statement,
+// without loading them, and returns the number of rows updated, e.g.
+//
+//	users.Where("is_active = ?", false).ExecuteUpdate(map[string]any{"status": "archived"})
+//
+// Timestamps aren't set automatically and tracked entities aren't updated.
+func (set *EnhancedDbSet[T]) ExecuteUpdate(values map[string]interface{}) (int, error) {
+	return set.ExecuteUpdateContext(context.Background(), values)
+}
+
+// ExecuteUpdateContext sets columns of the rows matching the query in a single
+// statement, honoring c
+func (set *EnhancedDbSet[T]) ExecuteUpdateContext(c context.Context, values map[string]interface{}) (int, error) {
+	if len(values) == 0 {
+		return 0, errors.New("no columns to update")
+	}
+	if _, ok := values[set.tenantColumn]; ok && set.tenantColumn != "" && set.ctx.tenant != "" {
+		return 0, fmt.Errorf("tenant column %s can't be updated, rows can't be moved to another tenant", set.tenantColumn)
+	}
+
+	// Sort columns so the statement is the same for the same values
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+
+	setPairs := make([]string, len(columns))
+	setValues := make([]interface{}, len(columns))
+	for i, column := range columns {
+		setPairs[i] = quoteIdentifier(set.ctx.driver, column) + " = ?"
+		setValues[i] = values[column]
+	}
+
+	var args []interface{}
+	if set.ctx.driver == driverPostgres {
+		// WHERE placeholders are already numbered from $1, number SET ones after them
+		for i, column := range columns {
+			setPairs[i] = quoteIdentifier(set.ctx.driver, column) + " = " + getPlaceholder(set.ctx.driver, len(set.whereParams())+i)
+		}
+		args = append(append(args, set.whereParams()...), setValues...)
+	} else {
+		args = append(append(args, setValues...), set.whereParams()...)
+	}
+
+	// Safe: table and column names are trusted, user data is parameterized (see args...)
+	//nolint:gosec // G201: Identifiers are not user-controlled; all user data is parameterized.
+	query := fmt.Sprintf("UPDATE %s SET %s", set.tableName, strings.Join(setPairs, ", "))
+	query += set.buildWhere()
+
+	return set.execute(c, query, args)
+}
+
+// execute runs a statement in the transaction of the context, if any, and returns
+// the number of rows affected
+func (set *EnhancedDbSet[T]) execute(c context.Context, query string, args []interface{}) (int, error) {
+	if set.fromSQL {
+		return 0, errors.New("set-based updates and deletes are not supported on FromSQL queries")
+	}
+	if err := set.checkQuery(); err != nil {
+		return 0, err
+	}
+
+	result, err := set.ctx.exec(c, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	Invalidate[T](set.ctx)
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
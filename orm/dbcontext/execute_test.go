@@ -0,0 +1,59 @@
+package dbcontext
+
+import "testing"
+
+func TestExecuteUpdateAndDelete(t *testing.T) {
+	ctx := newTestContext(t)
+	for _, name := range []string{"Ana", "Budi", "Citra"} {
+		ctx.Add(&testUser{Email: name + "@example.com", Name: name})
+	}
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	users := NewEnhancedDbSet[testUser](ctx)
+
+	updated, err := users.Where("name <> ?", "Ana").ExecuteUpdate(map[string]interface{}{"email_address": "archived@example.com"})
+	if err != nil {
+		t.Fatalf("ExecuteUpdate failed: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("Expected 2 rows updated, got %d", updated)
+	}
+	if archived, _ := users.Where("email_address = ?", "archived@example.com").Count(); archived != 2 {
+		t.Errorf("Expected 2 archived users, got %d", archived)
+	}
+
+	if _, err := users.ExecuteUpdate(nil); err == nil {
+		t.Error("Expected ExecuteUpdate without values to fail")
+	}
+
+	deleted, err := users.Where("email_address = ?", "archived@example.com").ExecuteDelete()
+	if err != nil {
+		t.Fatalf("ExecuteDelete failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Expected 2 rows deleted, got %d", deleted)
+	}
+	if remaining, _ := users.Count(); remaining != 1 {
+		t.Errorf("Expected 1 remaining user, got %d", remaining)
+	}
+}
+
+func TestExecuteUpdateQuotesColumnsForPostgres(t *testing.T) {
+	ctx := newTestContext(t)
+	ctx.driver = driverPostgres
+	hook := &recordingHook{}
+	ctx.AddQueryHook(hook)
+
+	// Only the statement is checked, SQLite binds $N placeholders in order of appearance
+	_, _ = NewEnhancedDbSet[testUser](ctx).Where("name = ?", "Ana").ExecuteUpdate(map[string]interface{}{"name": "Ann"})
+	expected := `UPDATE "users" SET "name" = $2 WHERE name = $1`
+	if len(hook.events) != 1 || hook.events[0].Query != expected {
+		t.Errorf("Expected %q, got %+v", expected, hook.events)
+	}
+
+	setPairs, _, _ := getUpdateData(&testUser{ID: 1, Name: "Ann"}, driverPostgres, []string{"name"})
+	if len(setPairs) != 1 || setPairs[0] != `"name" = $1` {
+		t.Errorf("Expected the SET column to be quoted, got %v", setPairs)
+	}
+}
@@ -0,0 +1,126 @@
+package dbcontext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+// QueryHook is notified of every statement the context runs, e.g. to log slow
+// queries, record tracing spans or assert on the generated SQL in tests
+type QueryHook interface {
+	// BeforeQuery is called before a statement runs. The returned context is passed
+	// to the driver and to AfterQuery, e.g. with a tracing span.
+	BeforeQuery(c context.Context, query string, args []interface{}) context.Context
+	// AfterQuery is called once the statement ran. For queries, the duration leaves
+	// out reading the rows.
+	AfterQuery(c context.Context, event QueryEvent)
+}
+
+// QueryEvent describes a statement run by the context
+type QueryEvent struct {
+	Query    string
+	Args     []interface{}
+	Duration time.Duration
+	Err      error
+}
+
+// AddQueryHook registers a hook notified of the statements the context runs, in
+// the order hooks are added
+func (ctx *EnhancedDbContext) AddQueryHook(hook QueryHook) {
+	ctx.hooks = append(ctx.hooks, hook)
+}
+
+// SlowQueryLogger is a QueryHook logging the statements that take longer than
+// Threshold, or fail
+type SlowQueryLogger struct {
+	Threshold time.Duration
+	Logger    *log.Logger // Defaults to the standard logger
+}
+
+// BeforeQuery does nothing
+func (l *SlowQueryLogger) BeforeQuery(c context.Context, _ string, _ []interface{}) context.Context {
+	return c
+}
+
+// AfterQuery logs slow or failed statements
+func (l *SlowQueryLogger) AfterQuery(_ context.Context, event QueryEvent) {
+	printf := log.Printf
+	if l.Logger != nil {
+		printf = l.Logger.Printf
+	}
+
+	switch {
+	case event.Err != nil && !errors.Is(event.Err, context.Canceled):
+		printf("Query failed after %s: %s: %v", event.Duration, event.Query, event.Err)
+	case event.Duration >= l.Threshold:
+		printf("Slow query took %s: %s", event.Duration, event.Query)
+	}
+}
+
+// exec runs a statement in the transaction of the context, if any, notifying hooks
+func (ctx *EnhancedDbContext) exec(c context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := ctx.withHooks(c, query, args, func(c context.Context) error {
+		var err error
+		if ctx.tx != nil {
+			result, err = ctx.tx.ExecContext(c, query, args...)
+		} else {
+			result, err = ctx.db.ExecContext(c, query, args...)
+		}
+		return err
+	})
+	return result, err
+}
+
+// query runs a query in the transaction of the context, if any, notifying hooks
+func (ctx *EnhancedDbContext) query(c context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := ctx.withHooks(c, query, args, func(c context.Context) error {
+		var err error
+		if ctx.tx != nil {
+			rows, err = ctx.tx.QueryContext(c, query, args...)
+		} else {
+			rows, err = ctx.db.QueryContext(c, query, args...)
+		}
+		return err
+	})
+	return rows, err
+}
+
+// queryRow runs a query returning at most one row in the transaction of the
+// context, if any, notifying hooks
+func (ctx *EnhancedDbContext) queryRow(c context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	_ = ctx.withHooks(c, query, args, func(c context.Context) error {
+		if ctx.tx != nil {
+			row = ctx.tx.QueryRowContext(c, query, args...)
+		} else {
+			row = ctx.db.QueryRowContext(c, query, args...)
+		}
+		return row.Err()
+	})
+	return row
+}
+
+// withHooks runs a statement between the BeforeQuery and AfterQuery calls of hooks
+func (ctx *EnhancedDbContext) withHooks(c context.Context, query string, args []interface{}, run func(context.Context) error) error {
+	if len(ctx.hooks) == 0 {
+		return run(c)
+	}
+
+	for _, hook := range ctx.hooks {
+		c = hook.BeforeQuery(c, query, args)
+	}
+
+	start := time.Now()
+	err := run(c)
+	event := QueryEvent{Query: query, Args: args, Duration: time.Since(start), Err: err}
+
+	for i := len(ctx.hooks) - 1; i >= 0; i-- {
+		ctx.hooks[i].AfterQuery(c, event)
+	}
+	return err
+}
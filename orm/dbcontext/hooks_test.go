@@ -0,0 +1,75 @@
+package dbcontext
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+// hookKey is the context key set by recordingHook
+type hookKey struct{}
+
+// recordingHook records the statements it is notified of
+type recordingHook struct {
+	events []QueryEvent
+	spans  int // AfterQuery calls that got the context of BeforeQuery
+}
+
+func (h *recordingHook) BeforeQuery(c context.Context, _ string, _ []interface{}) context.Context {
+	return context.WithValue(c, hookKey{}, true)
+}
+
+func (h *recordingHook) AfterQuery(c context.Context, event QueryEvent) {
+	if c.Value(hookKey{}) != nil {
+		h.spans++
+	}
+	h.events = append(h.events, event)
+}
+
+func TestQueryHooks(t *testing.T) {
+	ctx := newTestContext(t)
+	hook := &recordingHook{}
+	ctx.AddQueryHook(hook)
+
+	ctx.Add(&testUser{Email: "ana@example.com", Name: "Ana"})
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if _, err := NewEnhancedDbSet[testUser](ctx).Where("name = ?", "Ana").ToList(); err != nil {
+		t.Fatalf("ToList failed: %v", err)
+	}
+	if _, err := NewEnhancedDbSet[testUser](ctx).Where("missing = ?", 1).Count(); err == nil {
+		t.Fatal("Expected Count on a missing column to fail")
+	}
+
+	if len(hook.events) != 3 || hook.spans != 3 {
+		t.Fatalf("Expected 3 events with the BeforeQuery context, got %d and %d", len(hook.events), hook.spans)
+	}
+	if !strings.HasPrefix(hook.events[0].Query, `INSERT INTO "users"`) {
+		t.Errorf("Expected an insert, got %q", hook.events[0].Query)
+	}
+	if query := hook.events[1].Query; query != `SELECT * FROM "users" WHERE name = ?` {
+		t.Errorf("Expected the select, got %q", query)
+	}
+	if args := hook.events[1].Args; len(args) != 1 || args[0] != "Ana" {
+		t.Errorf("Expected the select arguments, got %v", args)
+	}
+	if hook.events[1].Err != nil || hook.events[2].Err == nil {
+		t.Errorf("Expected only the count to fail, got %v and %v", hook.events[1].Err, hook.events[2].Err)
+	}
+}
+
+func TestSlowQueryLogger(t *testing.T) {
+	ctx := newTestContext(t)
+	var buf bytes.Buffer
+	ctx.AddQueryHook(&SlowQueryLogger{Threshold: 0, Logger: log.New(&buf, "", 0)})
+
+	if _, err := NewEnhancedDbSet[testUser](ctx).Count(); err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Slow query took") || !strings.Contains(buf.String(), `SELECT COUNT(*) FROM "users"`) {
+		t.Errorf("Expected the query to be logged, got %q", buf.String())
+	}
+}
@@ -0,0 +1,138 @@
+package dbcontext
+
+import "reflect"
+
+// identityKey identifies a row: the entity type and its primary key
+type identityKey struct {
+	t  reflect.Type
+	id interface{}
+}
+
+// identityOf returns the identity key of an entity, false if it has no ID yet
+func identityOf(entity interface{}) (identityKey, bool) {
+	v, ok := entityValue(entity)
+	if !ok {
+		return identityKey{}, false
+	}
+	index := metadataFor(v.Type()).id
+	if index == nil {
+		return identityKey{}, false
+	}
+
+	id := v.FieldByIndex(index)
+	if id.IsZero() || !id.Comparable() {
+		return identityKey{}, false
+	}
+	return identityKey{t: v.Type(), id: id.Interface()}, true
+}
+
+// identify records a tracked entity as the instance of its row. An unchanged
+// instance it replaces, e.g. the copy of a row upserted or attached with Update, is
+// stale and stops being tracked.
+func (ct *ChangeTracker) identify(entity interface{}) {
+	key, ok := identityOf(entity)
+	if !ok {
+		return
+	}
+	if previous, exists := ct.identities[key]; exists && previous != entity &&
+		ct.entities[previous] == EntityStateUnchanged && len(ct.ChangedColumns(previous)) == 0 {
+		delete(ct.entities, previous)
+		delete(ct.originals, previous)
+	}
+	ct.identities[key] = entity
+}
+
+// forget removes an entity from the identity map, if it is the instance of its row
+func (ct *ChangeTracker) forget(entity interface{}) {
+	if key, ok := identityOf(entity); ok && ct.identities[key] == entity {
+		delete(ct.identities, key)
+		return
+	}
+	// The ID changed since the entity was tracked
+	for key, tracked := range ct.identities {
+		if tracked == entity {
+			delete(ct.identities, key)
+			return
+		}
+	}
+}
+
+// tracked returns the tracked instance of the same row as entity, if any
+func (ct *ChangeTracker) tracked(entity interface{}) (interface{}, bool) {
+	key, ok := identityOf(entity)
+	if !ok {
+		return nil, false
+	}
+	tracked, exists := ct.identities[key]
+	return tracked, exists
+}
+
+// cached returns the tracked entity of type T with an ID, if the query can be
+// answered from the change tracker: it tracks entities, has no conditions and the
+// entity isn't deleted
+func (set *EnhancedDbSet[T]) cached(id interface{}) (*T, bool) {
+	if !set.tracks() || set.fromSQL || set.whereClause != "" {
+		return nil, false
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	meta := metadataFor(t)
+	if meta.id == nil || id == nil {
+		return nil, false
+	}
+	key, ok := identityFor(t.FieldByIndex(meta.id).Type, id)
+	if !ok {
+		return nil, false
+	}
+	key.t = t
+
+	tracked, exists := set.ctx.ChangeTracker.identities[key]
+	if !exists || set.ctx.ChangeTracker.entities[tracked] == EntityStateDeleted {
+		return nil, false
+	}
+	entity, ok := tracked.(*T)
+	if !ok {
+		return nil, false
+	}
+	if meta.softDelete != nil && set.softDelete != "" && !reflect.ValueOf(entity).Elem().FieldByIndex(meta.softDelete).IsZero() {
+		return nil, false
+	}
+	return entity, true
+}
+
+// identityFor returns the identity key of an ID passed to Find, converted to the
+// type of the ID field, e.g. an int to an int64. The key type is left to the caller.
+func identityFor(field reflect.Type, id interface{}) (identityKey, bool) {
+	v := reflect.ValueOf(id)
+	if v.Type() != field {
+		if !convertibleID(v.Kind(), field.Kind()) {
+			return identityKey{}, false
+		}
+		v = v.Convert(field)
+	}
+	if !v.Comparable() {
+		return identityKey{}, false
+	}
+	return identityKey{id: v.Interface()}, true
+}
+
+// convertibleID reports whether an ID of kind from converts to kind to without
+// changing its meaning, unlike an int converted to a string
+func convertibleID(from, to reflect.Kind) bool {
+	isInt := func(k reflect.Kind) bool {
+		return k >= reflect.Int && k <= reflect.Uint64
+	}
+	return (isInt(from) && isInt(to)) || (from == reflect.String && to == reflect.String)
+}
+
+// Invalidate removes the unchanged entities of type T from the change tracker, so
+// the next queries and Find calls load them from the database again. Use it after
+// their rows are changed outside the context, e.g. by raw SQL; ExecuteUpdate and
+// ExecuteDelete do it themselves. Entities with pending changes stay tracked.
+func Invalidate[T any](ctx *EnhancedDbContext) {
+	for entity, state := range ctx.ChangeTracker.entities {
+		if _, ok := entity.(*T); ok && state == EntityStateUnchanged && len(ctx.ChangeTracker.ChangedColumns(entity)) == 0 {
+			ctx.ChangeTracker.Detach(entity)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package dbcontext
+
+import "testing"
+
+func TestIdentityResolution(t *testing.T) {
+	ctx := newTestContext(t)
+	for _, name := range []string{"Ana", "Ben"} {
+		if _, err := ctx.db.Exec(`INSERT INTO users (email_address, name) VALUES (?, ?)`, name+"@example.com", name); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+	users := NewEnhancedDbSet[testUser](ctx)
+
+	ana, err := users.Find(1)
+	if err != nil || ana == nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	ana.Name = "Ana Maria"
+
+	// Queries return the tracked instance, local changes included
+	all, err := users.OrderBy("id").ToList()
+	if err != nil || len(all) != 2 {
+		t.Fatalf("Expected 2 users, got %v, %v", all, err)
+	}
+	if all[0] != ana {
+		t.Error("Expected the tracked instance of a row to be returned")
+	}
+	if ana.Name != "Ana Maria" {
+		t.Errorf("Expected local changes to be kept, got %q", ana.Name)
+	}
+	if again, _ := users.Find(2); again != all[1] {
+		t.Error("Expected rows tracked by a query to resolve to the same instance")
+	}
+	if len(ctx.ChangeTracker.entities) != 2 {
+		t.Errorf("Expected each row to be tracked once, got %d entities", len(ctx.ChangeTracker.entities))
+	}
+
+	// Untracked queries load fresh instances
+	fresh, err := users.AsNoTracking().Find(1)
+	if err != nil || fresh == ana || fresh.Name != "Ana" {
+		t.Errorf("Expected AsNoTracking to load the stored row, got %+v, %v", fresh, err)
+	}
+
+	// Detached entities are loaded again
+	ctx.Detach(ana)
+	if reloaded, _ := users.Find(1); reloaded == ana || reloaded.Name != "Ana" {
+		t.Errorf("Expected a detached row to be loaded again, got %+v", reloaded)
+	}
+}
+
+func TestIdentityOfInsertedEntities(t *testing.T) {
+	ctx := newTestContext(t)
+	added := &testUser{Email: "cy@example.com", Name: "Cy"}
+	ctx.Add(added)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	found, err := NewEnhancedDbSet[testUser](ctx).Find(added.ID)
+	if err != nil || found != added {
+		t.Errorf("Expected the inserted instance to be returned, got %+v, %v", found, err)
+	}
+
+	ctx.Delete(added)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if len(ctx.ChangeTracker.identities) != 0 {
+		t.Errorf("Expected deleted entities to leave the identity map, got %v", ctx.ChangeTracker.identities)
+	}
+}
+
+func TestFindFromChangeTracker(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`INSERT INTO users (email_address, name) VALUES ('ana@example.com', 'Ana')`); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	users := NewEnhancedDbSet[testUser](ctx)
+	ana, err := users.Find(1)
+	if err != nil || ana == nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+
+	hook := &recordingHook{}
+	ctx.AddQueryHook(hook)
+	for _, id := range []interface{}{1, int64(1), uint8(1)} {
+		if found, err := users.Find(id); err != nil || found != ana {
+			t.Errorf("Expected Find(%T) to return the tracked user, got %+v, %v", id, found, err)
+		}
+	}
+	if len(hook.events) != 0 {
+		t.Errorf("Expected tracked users to be found without queries, got %d", len(hook.events))
+	}
+
+	// Conditions and untracked queries still go to the database
+	if found, _ := users.Where("name = ?", "Bob").Find(1); found != nil {
+		t.Errorf("Expected the condition to be applied, got %+v", found)
+	}
+	if _, err := users.AsNoTracking().Find(1); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(hook.events) != 2 {
+		t.Errorf("Expected 2 queries, got %d", len(hook.events))
+	}
+
+	// Set-based updates invalidate the tracked users
+	if _, err := users.ExecuteUpdate(map[string]interface{}{"name": "Ana Maria"}); err != nil {
+		t.Fatalf("ExecuteUpdate failed: %v", err)
+	}
+	if found, _ := users.Find(1); found == ana || found.Name != "Ana Maria" {
+		t.Errorf("Expected the updated user to be loaded again, got %+v", found)
+	}
+
+	// Changed outside the context
+	if _, err := ctx.db.Exec(`UPDATE users SET name = 'Ann'`); err != nil {
+		t.Fatalf("Failed to update user: %v", err)
+	}
+	Invalidate[testUser](ctx)
+	if found, _ := users.Find(1); found.Name != "Ann" {
+		t.Errorf("Expected Invalidate to reload the user, got %+v", found)
+	}
+}
+
+func TestIdentityReplacedOnSave(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`INSERT INTO users (email_address, name) VALUES ('ana@example.com', 'Ana')`); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	users := NewEnhancedDbSet[testUser](ctx)
+	stale, _ := users.Find(1)
+
+	// An upsert of the same row replaces the loaded copy
+	fresh := &testUser{ID: 1, Email: "ana@example.com", Name: "Ana Maria"}
+	ctx.AddOrUpdate(fresh)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if found, _ := users.Find(1); found != fresh {
+		t.Errorf("Expected the upserted user to be found, got %+v", found)
+	}
+	if _, tracked := ctx.ChangeTracker.entities[stale]; tracked {
+		t.Error("Expected the stale copy to stop being tracked")
+	}
+}
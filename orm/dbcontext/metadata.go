@@ -0,0 +1,216 @@
+package dbcontext
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entityMetadata holds the mapping of an entity type to its table, computed once
+// per type instead of reflecting over its fields on every insert, update and scan
+type entityMetadata struct {
+	tableName        string
+	fields           []fieldMetadata // Persisted fields, embedded structs flattened
+	id               []int           // Index of the ID field, nil if none
+	createdAt        []int           // Index of the CreatedAt field, nil if none
+	updatedAt        []int           // Index of the UpdatedAt field, nil if none
+	createdBy        []int           // Index of the CreatedBy audit field, nil if none
+	createdByColumn  string
+	updatedBy        []int // Index of the UpdatedBy audit field, nil if none
+	version          []int // Index of the concurrency:"version" field, nil if none
+	versionColumn    string
+	softDelete       []int // Index of the softdelete field, nil if none
+	softDeleteColumn string
+	tagged           map[string][]int // Field index by db tag name
+	columns          sync.Map         // Field index by scanned column, nil if no field matches
+}
+
+// fieldMetadata holds the mapping of a persisted field to its column
+type fieldMetadata struct {
+	index      []int
+	column     string
+	id         bool // Whether the field is the ID, left out of inserts
+	softDelete bool // Whether the field is the soft delete timestamp, NULL when zero
+}
+
+// metadataCache caches entityMetadata by struct type
+var metadataCache sync.Map
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// metadataFor returns the metadata of an entity type
+func metadataFor(t reflect.Type) *entityMetadata {
+	if cached, ok := metadataCache.Load(t); ok {
+		return cached.(*entityMetadata)
+	}
+
+	cached, _ := metadataCache.LoadOrStore(t, parseMetadata(t))
+	return cached.(*entityMetadata)
+}
+
+// metadataOf returns the metadata of the type of an entity or pointer to an entity
+func metadataOf(entity interface{}) *entityMetadata {
+	t := reflect.TypeOf(entity)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return metadataFor(t)
+}
+
+// parseMetadata computes the metadata of an entity type. The table name comes from
+// a TableName method, or else the snake_case type name.
+func parseMetadata(t reflect.Type) *entityMetadata {
+	meta := &entityMetadata{tableName: toSnakeCase(t.Name()), tagged: make(map[string][]int)}
+	if tn, ok := reflect.New(t).Interface().(interface{ TableName() string }); ok {
+		meta.tableName = tn.TableName()
+	}
+
+	if t.Kind() == reflect.Struct {
+		meta.parseFields(t, nil)
+		meta.parseTagged(t, nil)
+		meta.id = findFieldIndex(t, nil, "ID", nil)
+		meta.createdAt = findFieldIndex(t, nil, "CreatedAt", timeType)
+		meta.updatedAt = findFieldIndex(t, nil, "UpdatedAt", timeType)
+		if meta.createdBy == nil {
+			meta.createdBy, meta.createdByColumn = meta.auditField(t, "CreatedBy")
+		}
+		if meta.updatedBy == nil {
+			meta.updatedBy, _ = meta.auditField(t, "UpdatedBy")
+		}
+	}
+	return meta
+}
+
+// auditField returns the index and column of the persisted field with a name,
+// unless it is tagged audit:"-"
+func (meta *entityMetadata) auditField(t reflect.Type, name string) ([]int, string) {
+	index := findFieldIndex(t, nil, name, nil)
+	if index == nil || t.FieldByIndex(index).Tag.Get("audit") == "-" {
+		return nil, ""
+	}
+	for _, field := range meta.fields {
+		if slices.Equal(field.index, index) {
+			return index, field.column
+		}
+	}
+	return nil, ""
+}
+
+// parseFields collects the persisted fields of a struct, recursing into embedded
+// structs: exported fields not tagged db:"-" or sql:"-"
+func (meta *entityMetadata) parseFields(t reflect.Type, parent []int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if shouldSkipField(field, false) {
+			continue
+		}
+
+		index := append(append([]int(nil), parent...), i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			meta.parseFields(field.Type, index)
+			continue
+		}
+
+		column := field.Tag.Get("db")
+		if column == "" {
+			column = toSnakeCase(field.Name)
+		}
+		softDelete := field.Tag.Get("softdelete") != "" && meta.softDelete == nil
+		if softDelete {
+			meta.softDelete, meta.softDeleteColumn = index, field.Tag.Get("softdelete")
+		}
+
+		meta.fields = append(meta.fields, fieldMetadata{
+			index:      index,
+			column:     column,
+			id:         strings.ToLower(field.Name) == "id",
+			softDelete: softDelete,
+		})
+		if field.Tag.Get("concurrency") == "version" && meta.version == nil {
+			meta.version, meta.versionColumn = index, column
+		}
+		switch field.Tag.Get("audit") {
+		case "created_by":
+			if meta.createdBy == nil {
+				meta.createdBy, meta.createdByColumn = index, column
+			}
+		case "updated_by":
+			if meta.updatedBy == nil {
+				meta.updatedBy = index
+			}
+		}
+	}
+}
+
+// parseTagged indexes the fields of a struct by db tag name, recursing into
+// embedded structs; the first field with a tag wins
+func (meta *entityMetadata) parseTagged(t reflect.Type, parent []int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			meta.parseTagged(field.Type, index)
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("db"), ",")[0]
+		if _, exists := meta.tagged[name]; name != "" && !exists {
+			meta.tagged[name] = index
+		}
+	}
+}
+
+// findFieldIndex finds the first field with a name, and a type unless typ is nil,
+// recursing into embedded structs
+func findFieldIndex(t reflect.Type, parent []int, name string, typ reflect.Type) []int {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+		if field.Name == name {
+			if typ != nil && field.Type != typ {
+				return nil
+			}
+			return index
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if found := findFieldIndex(field.Type, index, name, typ); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// columnIndex returns the index of the field a column is scanned into, using the db
+// tag and falling back to a case-insensitive match on the CamelCase column name
+func (meta *entityMetadata) columnIndex(t reflect.Type, column string) []int {
+	if index, ok := meta.columns.Load(column); ok {
+		return index.([]int)
+	}
+
+	index, ok := meta.tagged[column]
+	if !ok {
+		fieldName := toCamelCase(column)
+		if field, found := t.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, fieldName)
+		}); found {
+			index = field.Index
+		}
+	}
+	meta.columns.Store(column, index)
+	return index
+}
+
+// settableField returns the field of v at index if it can be set
+func settableField(v reflect.Value, index []int) (reflect.Value, bool) {
+	if index == nil {
+		return reflect.Value{}, false
+	}
+	field, err := v.FieldByIndexErr(index)
+	if err != nil || !field.CanSet() {
+		return reflect.Value{}, false
+	}
+	return field, true
+}
@@ -0,0 +1,45 @@
+package dbcontext
+
+import (
+	"context"
+	"testing"
+)
+
+func BenchmarkEntityMetadata(b *testing.B) {
+	user := &testUser{ID: 1, Email: "ana@example.com", Name: "Ana"}
+
+	b.Run("InsertData", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			getTableName(user)
+			getInsertData(user, driverPostgres)
+		}
+	})
+
+	b.Run("UpdateData", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			getUpdateData(user, driverPostgres, nil)
+			setTimestamps(user, false)
+		}
+	})
+}
+
+func BenchmarkToList(b *testing.B) {
+	ctx := newTestContext(b)
+	for range 100 {
+		ctx.Add(&testUser{Email: "ana@example.com", Name: "Ana"})
+	}
+	if _, err := ctx.SaveChanges(); err != nil {
+		b.Fatalf("SaveChanges failed: %v", err)
+	}
+	users := NewEnhancedDbSet[testUser](ctx).AsNoTracking()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := users.ToListContext(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package dbcontext
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// AuditStamp is embedded after other fields by testDocument, exported as only
+// exported embedded structs are persisted
+type AuditStamp struct {
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time
+}
+
+// testDocument is an entity with an embedded struct and skipped fields
+type testDocument struct {
+	ID    int64
+	Title string `db:"title"`
+	AuditStamp
+	Draft  bool   `db:"-"`
+	secret string //nolint:unused // Unexported fields aren't persisted
+}
+
+func TestEntityMetadata(t *testing.T) {
+	meta := metadataFor(reflect.TypeOf(testDocument{}))
+
+	if meta.tableName != "test_document" {
+		t.Errorf("Expected table test_document, got %s", meta.tableName)
+	}
+	if meta != metadataOf(&testDocument{}) {
+		t.Error("Expected metadata to be cached per type")
+	}
+
+	columns, _, placeholders := getInsertData(&testDocument{}, driverPostgres)
+	if expected := []string{"title", "created_at", "updated_at"}; !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Expected insert columns %v, got %v", expected, columns)
+	}
+	if expected := []string{"$1", "$2", "$3"}; !reflect.DeepEqual(placeholders, expected) {
+		t.Errorf("Expected embedded fields to continue numbering, got %v", placeholders)
+	}
+
+	doc := &testDocument{}
+	setIDField(doc, 7)
+	setTimestamps(doc, true)
+	if getIDValue(doc) != int64(7) || doc.CreatedAt.IsZero() || doc.UpdatedAt.IsZero() {
+		t.Errorf("Expected ID and timestamps to be set, got %+v", doc)
+	}
+
+	if index := meta.columnIndex(reflect.TypeOf(testDocument{}), "updated_at"); !reflect.DeepEqual(index, []int{2, 1}) {
+		t.Errorf("Expected updated_at to resolve to the embedded field by name, got %v", index)
+	}
+}
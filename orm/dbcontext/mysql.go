@@ -0,0 +1,34 @@
+package dbcontext
+
+import "strings"
+
+const driverMySQL = "mysql"
+
+// quoteIdentifier quotes a table or column name for the driver, with backticks on
+// MySQL and double quotes on others, so names like order or key that are reserved
+// words can be used. Qualified names are quoted per part, e.g. "acme"."invoices".
+// Names already quoted are left unchanged. Quoted names are case sensitive on
+// PostgreSQL, so they must match the case the tables were created with.
+func quoteIdentifier(driver, name string) string {
+	quote := `"`
+	if driver == driverMySQL {
+		quote = "`"
+	}
+	if strings.Contains(name, quote) {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote + part + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteIdentifiers quotes table or column names, see quoteIdentifier
+func quoteIdentifiers(driver string, names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdentifier(driver, name)
+	}
+	return quoted
+}
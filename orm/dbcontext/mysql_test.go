@@ -0,0 +1,187 @@
+package dbcontext
+
+import (
+	"database/sql"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// testPayment is an entity with a reserved word column and MySQL typed columns
+type testPayment struct {
+	ID       int64     `db:"id"`
+	Key      string    `db:"key"`
+	Total    float64   `db:"total"`
+	Paid     bool      `db:"paid"`
+	PlacedAt time.Time `db:"placed_at"`
+}
+
+func (testPayment) TableName() string { return "payments" }
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		driver, name, expected string
+	}{
+		{driverMySQL, "order", "`order`"},
+		{driverMySQL, "acme.invoices", "`acme`.`invoices`"},
+		{driverMySQL, "`key`", "`key`"},
+		{driverPostgres, "order", `"order"`},
+		{driverPostgres, `"key"`, `"key"`},
+		{"sqlite3", "acme.invoices", `"acme"."invoices"`},
+	}
+
+	for _, tt := range tests {
+		if quoted := quoteIdentifier(tt.driver, tt.name); quoted != tt.expected {
+			t.Errorf("quoteIdentifier(%s, %s) = %s, expected %s", tt.driver, tt.name, quoted, tt.expected)
+		}
+	}
+}
+
+func TestScanMySQLText(t *testing.T) {
+	var payment testPayment
+	v := reflect.ValueOf(&payment).Elem()
+	for field, value := range map[string][]byte{
+		"ID":       []byte("42"),
+		"Key":      []byte("A-1"),
+		"Total":    []byte("19.90"),
+		"Paid":     []byte("1"),
+		"PlacedAt": []byte("2024-03-01 12:30:45.123456"),
+	} {
+		if err := setFieldValue(v.FieldByName(field), value); err != nil {
+			t.Fatalf("setFieldValue(%s) failed: %v", field, err)
+		}
+	}
+
+	placedAt := time.Date(2024, 3, 1, 12, 30, 45, 123456000, time.UTC)
+	if payment.ID != 42 || payment.Key != "A-1" || payment.Total != 19.9 || !payment.Paid || !payment.PlacedAt.Equal(placedAt) {
+		t.Errorf("Expected text values to be parsed, got %+v", payment)
+	}
+}
+
+// TestMySQLStatements checks the statements generated for MySQL on SQLite, which
+// accepts backtick quoted names too
+func TestMySQLStatements(t *testing.T) {
+	ctx := newTestContext(t)
+	ctx.driver = driverMySQL
+	if _, err := ctx.db.Exec("CREATE TABLE payments (id INTEGER PRIMARY KEY AUTOINCREMENT, `key` TEXT, total REAL, paid BOOLEAN, placed_at DATETIME)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	hook := &recordingHook{}
+	ctx.AddQueryHook(hook)
+
+	payment := &testPayment{Key: "A-1", Total: 10, PlacedAt: time.Now().UTC()}
+	ctx.Add(payment)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if payment.ID == 0 {
+		t.Error("Expected the ID to be set from LastInsertId")
+	}
+
+	payment.Paid = true
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	payments := NewEnhancedDbSet[testPayment](ctx).AsNoTracking()
+	if _, err := payments.Where("total > ?", 5).ExecuteUpdate(map[string]interface{}{"key": "A-2"}); err != nil {
+		t.Fatalf("ExecuteUpdate failed: %v", err)
+	}
+	found, err := payments.Find(payment.ID)
+	if err != nil || found == nil || found.Key != "A-2" || !found.Paid {
+		t.Fatalf("Expected the updated payment, got %+v, %v", found, err)
+	}
+
+	for i, expected := range []string{
+		"INSERT INTO `payments` (`key`, `total`, `paid`, `placed_at`) VALUES (?, ?, ?, ?)",
+		"UPDATE `payments` SET `paid` = ? WHERE id = ?",
+		"UPDATE `payments` SET `key` = ? WHERE total > ?",
+		"SELECT * FROM `payments` WHERE id = ?",
+	} {
+		if i >= len(hook.events) || !strings.HasPrefix(hook.events[i].Query, expected) {
+			t.Errorf("Expected statement %d to start with %q, got %+v", i, expected, hook.events)
+		}
+	}
+}
+
+// TestMySQL runs against the MySQL server of GRA_MYSQL_DSN, e.g.
+// "root:root@tcp(localhost:3306)/gra_test", and is skipped without it
+func TestMySQL(t *testing.T) {
+	dsn := os.Getenv("GRA_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("GRA_MYSQL_DSN is not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	for _, stmt := range []string{
+		"DROP TABLE IF EXISTS payments",
+		"CREATE TABLE payments (id BIGINT AUTO_INCREMENT PRIMARY KEY, `key` VARCHAR(32) NOT NULL UNIQUE, total DECIMAL(10,2), paid TINYINT(1), placed_at DATETIME(6))",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+	}
+	t.Cleanup(func() { _, _ = db.Exec("DROP TABLE payments") })
+
+	ctx := NewEnhancedDbContextWithDB(db)
+	if ctx.driver != driverMySQL {
+		t.Fatalf("Expected the mysql driver to be detected, got %s", ctx.driver)
+	}
+
+	placedAt := time.Date(2024, 3, 1, 12, 30, 45, 123456000, time.UTC)
+	first, second := &testPayment{Key: "A-1", Total: 19.9, PlacedAt: placedAt}, &testPayment{Key: "A-2", Total: 5}
+	ctx.Add(first)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	ctx.Add(second)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if first.ID == 0 || second.ID != first.ID+1 {
+		t.Errorf("Expected auto-increment IDs from LastInsertId, got %d and %d", first.ID, second.ID)
+	}
+
+	payments := NewEnhancedDbSet[testPayment](ctx).AsNoTracking()
+	// Without arguments, MySQL returns every column as text
+	all, err := payments.OrderBy("id").ToList()
+	if err != nil || len(all) != 2 {
+		t.Fatalf("Expected 2 payments, got %v, %v", all, err)
+	}
+	if all[0].Total != 19.9 || !all[0].PlacedAt.Equal(placedAt) {
+		t.Errorf("Expected DECIMAL and DATETIME columns to be parsed, got %+v", all[0])
+	}
+
+	first.Paid = true
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if found, err := payments.Where("`key` = ?", "A-1").FirstOrDefault(); err != nil || found == nil || !found.Paid {
+		t.Errorf("Expected the payment to be paid, got %+v, %v", found, err)
+	}
+
+	// Upserting an existing key updates the row and reads its ID back
+	again := &testPayment{Key: "A-2", Total: 7}
+	ctx.AddOrUpdate(again, "key")
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if again.ID != second.ID {
+		t.Errorf("Expected upsert to return ID %d, got %d", second.ID, again.ID)
+	}
+
+	if total, err := payments.Sum("total"); err != nil || total != 26.9 {
+		t.Errorf("Expected a total of 26.9, got %v, %v", total, err)
+	}
+	deleted, err := payments.Where("paid = ?", false).ExecuteDelete()
+	if err != nil || deleted != 1 {
+		t.Errorf("Expected 1 unpaid payment deleted, got %d, %v", deleted, err)
+	}
+}
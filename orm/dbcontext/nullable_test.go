@@ -0,0 +1,71 @@
+package dbcontext
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// testProfile is an entity with nullable fields
+type testProfile struct {
+	ID        int64          `db:"id"`
+	Nickname  *string        `db:"nickname"`
+	BirthDate *time.Time     `db:"birth_date"`
+	Bio       sql.NullString `db:"bio"`
+	Age       sql.NullInt64  `db:"age"`
+}
+
+func (testProfile) TableName() string { return "profiles" }
+
+func TestNullableFields(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE profiles (id INTEGER PRIMARY KEY AUTOINCREMENT, nickname TEXT, birth_date DATETIME, bio TEXT, age INTEGER)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	nickname, birthDate := "ana", time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	empty := &testProfile{}
+	full := &testProfile{
+		Nickname:  &nickname,
+		BirthDate: &birthDate,
+		Bio:       sql.NullString{String: "Gopher", Valid: true},
+		Age:       sql.NullInt64{Int64: 34, Valid: true},
+	}
+	ctx.Add(empty)
+	ctx.Add(full)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	var nulls int
+	if err := ctx.db.QueryRow(`SELECT COUNT(*) FROM profiles WHERE nickname IS NULL AND birth_date IS NULL AND bio IS NULL AND age IS NULL`).Scan(&nulls); err != nil {
+		t.Fatalf("Failed to count NULL rows: %v", err)
+	}
+	if nulls != 1 {
+		t.Errorf("Expected unset fields to be stored as NULL, got %d NULL rows", nulls)
+	}
+
+	profiles := NewEnhancedDbSet[testProfile](ctx).AsNoTracking()
+
+	loaded, err := profiles.Find(empty.ID)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if loaded.Nickname != nil || loaded.BirthDate != nil || loaded.Bio.Valid || loaded.Age.Valid {
+		t.Errorf("Expected NULL columns to scan as nil and invalid, got %+v", loaded)
+	}
+
+	loaded, err = profiles.Find(full.ID)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if loaded.Nickname == nil || *loaded.Nickname != nickname {
+		t.Errorf("Expected nickname %q, got %v", nickname, loaded.Nickname)
+	}
+	if loaded.BirthDate == nil || !loaded.BirthDate.Equal(birthDate) {
+		t.Errorf("Expected birth date %v, got %v", birthDate, loaded.BirthDate)
+	}
+	if loaded.Bio != full.Bio || loaded.Age != full.Age {
+		t.Errorf("Expected sql.Null fields %v and %v, got %v and %v", full.Bio, full.Age, loaded.Bio, loaded.Age)
+	}
+}
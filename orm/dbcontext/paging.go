@@ -0,0 +1,65 @@
+package dbcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPage is returned by ToPagedList when the page or page size is below 1
+var ErrInvalidPage = errors.New("invalid page")
+
+// PagedList is a page of query results with the metadata of the whole result set.
+// Its JSON field names match those of context.PageMeta.
+type PagedList[T any] struct {
+	Items      []*T  `json:"items"`
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+	HasPrev    bool  `json:"has_prev"`
+}
+
+// ToPagedList executes the query for a page of the results, numbered from 1, and
+// counts all of them. It pairs with the Context pagination helpers:
+//
+//	page, err := c.Pagination(context.DefaultPaginationConfig())
+//	...
+//	list, err := users.OrderBy("name").ToPagedList(page.Page, page.PerPage)
+//	...
+//	c.SuccessPaged(list.Items, list.Total, page)
+func (set *EnhancedDbSet[T]) ToPagedList(page, size int) (*PagedList[T], error) {
+	return set.ToPagedListContext(context.Background(), page, size)
+}
+
+// ToPagedListContext executes the query for a page of the results and counts all of
+// them, canceling the queries when c is done
+func (set *EnhancedDbSet[T]) ToPagedListContext(c context.Context, page, size int) (*PagedList[T], error) {
+	if page < 1 || size < 1 {
+		return nil, fmt.Errorf("%w: page %d of size %d", ErrInvalidPage, page, size)
+	}
+
+	total, err := set.CountContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * size
+	items := []*T{}
+	if offset < total {
+		if items, err = set.Skip(offset).Take(size).ToListContext(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return &PagedList[T]{
+		Items:      items,
+		Page:       page,
+		PerPage:    size,
+		Total:      int64(total),
+		TotalPages: (total + size - 1) / size,
+		HasNext:    offset+size < total,
+		HasPrev:    page > 1,
+	}, nil
+}
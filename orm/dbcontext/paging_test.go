@@ -0,0 +1,65 @@
+package dbcontext
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToPagedList(t *testing.T) {
+	ctx := newTestContext(t)
+	for _, name := range []string{"Ana", "Budi", "Citra", "Dewi", "Eka"} {
+		ctx.Add(&testUser{Email: name + "@example.com", Name: name})
+	}
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	users := NewEnhancedDbSet[testUser](ctx).OrderBy("name")
+
+	tests := []struct {
+		name       string
+		page, size int
+		expected   []string
+		hasNext    bool
+		hasPrev    bool
+	}{
+		{"First", 1, 2, []string{"Ana", "Budi"}, true, false},
+		{"Middle", 2, 2, []string{"Citra", "Dewi"}, true, true},
+		{"Last", 3, 2, []string{"Eka"}, false, true},
+		{"PastEnd", 4, 2, nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := users.ToPagedList(tt.page, tt.size)
+			if err != nil {
+				t.Fatalf("ToPagedList failed: %v", err)
+			}
+			if list.Total != 5 || list.TotalPages != 3 {
+				t.Errorf("Expected 5 users in 3 pages, got %d in %d", list.Total, list.TotalPages)
+			}
+			if list.HasNext != tt.hasNext || list.HasPrev != tt.hasPrev {
+				t.Errorf("Expected HasNext %v and HasPrev %v, got %v and %v", tt.hasNext, tt.hasPrev, list.HasNext, list.HasPrev)
+			}
+			if list.Items == nil || len(list.Items) != len(tt.expected) {
+				t.Fatalf("Expected %d items, got %v", len(tt.expected), list.Items)
+			}
+			for i, user := range list.Items {
+				if user.Name != tt.expected[i] {
+					t.Errorf("Expected item %d to be %s, got %s", i, tt.expected[i], user.Name)
+				}
+			}
+		})
+	}
+
+	filtered, err := users.Where("name <> ?", "Ana").ToPagedList(1, 10)
+	if err != nil {
+		t.Fatalf("ToPagedList failed: %v", err)
+	}
+	if filtered.Total != 4 || len(filtered.Items) != 4 {
+		t.Errorf("Expected the filter to apply to the count and the items, got %d and %d", filtered.Total, len(filtered.Items))
+	}
+
+	if _, err := users.ToPagedList(0, 10); !errors.Is(err, ErrInvalidPage) {
+		t.Errorf("Expected ErrInvalidPage, got %v", err)
+	}
+}
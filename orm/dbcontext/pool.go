@@ -0,0 +1,87 @@
+package dbcontext
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PoolConfig holds the connection pool settings of a database. Zero values leave
+// the database/sql defaults: unlimited open connections, 2 idle connections and no
+// connection lifetime.
+type PoolConfig struct {
+	MaxOpenConns    int           // Maximum open connections, in use or idle
+	MaxIdleConns    int           // Maximum idle connections kept for reuse, negative for none
+	ConnMaxLifetime time.Duration // Connections are closed after this long, e.g. before a proxy drops them
+	ConnMaxIdleTime time.Duration // Idle connections are closed after this long
+}
+
+// DefaultPoolConfig returns pool settings suited for web workloads: 25 connections,
+// all kept idle between bursts, recycled every 5 minutes
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    25,
+		MaxIdleConns:    25,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}
+}
+
+// Apply sets the pool settings of a database
+func (p PoolConfig) Apply(db *sql.DB) {
+	if p.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(p.MaxOpenConns)
+	}
+	if p.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	if p.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	}
+	if p.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+	}
+}
+
+// OpenEnhancedDbContext opens a database with a registered driver, e.g. "postgres"
+// or "mysql", applies the pool settings and checks the connection:
+//
+//	db, err := dbcontext.OpenEnhancedDbContext("postgres", dsn, dbcontext.DefaultPoolConfig())
+func OpenEnhancedDbContext(driverName, dataSourceName string, pool PoolConfig) (*EnhancedDbContext, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	pool.Apply(db)
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return NewEnhancedDbContextWithDB(db), nil
+}
+
+// ConfigurePool sets the pool settings of the database of the context
+func (ctx *EnhancedDbContext) ConfigurePool(pool PoolConfig) {
+	if ctx.db != nil {
+		pool.Apply(ctx.db)
+	}
+}
+
+// Stats returns the connection pool statistics of the database, e.g. to export
+// in-use and waiting connections as metrics
+func (ctx *EnhancedDbContext) Stats() sql.DBStats {
+	if ctx.db == nil {
+		return sql.DBStats{}
+	}
+	return ctx.db.Stats()
+}
+
+// Close closes the database of the context and its connections
+func (ctx *EnhancedDbContext) Close() error {
+	if ctx.db == nil {
+		return nil
+	}
+	return ctx.db.Close()
+}
@@ -0,0 +1,53 @@
+package dbcontext
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenEnhancedDbContextAppliesPool(t *testing.T) {
+	pool := DefaultPoolConfig()
+	pool.MaxOpenConns = 4
+
+	ctx, err := OpenEnhancedDbContext("sqlite3", filepath.Join(t.TempDir(), "pool.db"), pool)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() { _ = ctx.Close() }()
+
+	if err := ctx.PingContext(context.Background()); err != nil {
+		t.Fatalf("PingContext failed: %v", err)
+	}
+	stats := ctx.Stats()
+	if stats.MaxOpenConnections != 4 {
+		t.Errorf("Expected 4 max open connections, got %d", stats.MaxOpenConnections)
+	}
+	if stats.OpenConnections != 1 || stats.Idle != 1 {
+		t.Errorf("Expected the ping connection to be kept idle, got %+v", stats)
+	}
+
+	ctx.ConfigurePool(PoolConfig{MaxOpenConns: 2})
+	if maxOpen := ctx.Stats().MaxOpenConnections; maxOpen != 2 {
+		t.Errorf("Expected ConfigurePool to set 2 max open connections, got %d", maxOpen)
+	}
+}
+
+func TestOpenEnhancedDbContextUnknownDriver(t *testing.T) {
+	if _, err := OpenEnhancedDbContext("unknown", "", DefaultPoolConfig()); err == nil {
+		t.Error("Expected an unregistered driver to fail")
+	}
+}
+
+func TestPoolWithoutDatabase(t *testing.T) {
+	ctx := &EnhancedDbContext{ChangeTracker: NewChangeTracker()}
+	if err := ctx.PingContext(context.Background()); err == nil {
+		t.Error("Expected PingContext without a database to fail")
+	}
+	if stats := ctx.Stats(); stats.OpenConnections != 0 {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+	if err := ctx.Close(); err != nil {
+		t.Errorf("Expected Close without a database to succeed, got %v", err)
+	}
+}
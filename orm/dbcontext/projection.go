@@ -0,0 +1,40 @@
+package dbcontext
+
+// Select projects the rows of a query into TDto, fetching only the given columns
+// instead of SELECT *. Without columns, the columns of TDto's fields are selected,
+// by db tag or snake_case name like for entities:
+//
+//	type UserSummary struct {
+//		ID    int64  `db:"id"`
+//		Email string `db:"email"`
+//	}
+//	summaries, err := dbcontext.Select[User, UserSummary](users.Where("is_active = ?", true)).ToList()
+//
+// The projection keeps the filters, grouping, order and paging of set and can be
+// refined further. Its results are not tracked, as DTOs can't be saved.
+func Select[TEntity, TDto any](set *EnhancedDbSet[TEntity], columns ...string) *EnhancedDbSet[TDto] {
+	if len(columns) == 0 {
+		columns, _, _ = getFieldData(new(TDto), false, set.ctx.driver)
+	}
+
+	return &EnhancedDbSet[TDto]{
+		ctx:          set.ctx,
+		tableName:    set.tableName,
+		columns:      columns,
+		distinct:     set.distinct,
+		distinctOn:   set.distinctOn,
+		whereClause:  set.whereClause,
+		whereArgs:    set.whereArgs,
+		groupBy:      set.groupBy,
+		having:       set.having,
+		havingArgs:   set.havingArgs,
+		orderClause:  set.orderClause,
+		limitValue:   set.limitValue,
+		offsetValue:  set.offsetValue,
+		cursor:       set.cursor,
+		noTracking:   true,
+		fromSQL:      set.fromSQL,
+		softDelete:   set.softDelete,
+		tenantColumn: set.tenantColumn,
+	}
+}
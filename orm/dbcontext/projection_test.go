@@ -0,0 +1,48 @@
+package dbcontext
+
+import "testing"
+
+// userSummary is a DTO projected from testUser
+type userSummary struct {
+	ID    int64  `db:"id"`
+	Email string `db:"email_address"`
+}
+
+func TestSelectProjectsColumns(t *testing.T) {
+	ctx := newTestContext(t)
+	for _, name := range []string{"Ana", "Budi", "Citra"} {
+		ctx.Add(&testUser{Email: name + "@example.com", Name: name})
+	}
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	users := NewEnhancedDbSet[testUser](ctx).Where("name <> ?", "Budi").OrderBy("name")
+
+	summaries, err := Select[testUser, userSummary](users).ToList()
+	if err != nil {
+		t.Fatalf("ToList failed: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 summaries, got %d", len(summaries))
+	}
+	if summaries[0].ID == 0 || summaries[0].Email != "Ana@example.com" || summaries[1].Email != "Citra@example.com" {
+		t.Errorf("Expected projected id and email, got %+v, %+v", *summaries[0], *summaries[1])
+	}
+
+	// Explicit columns select only those
+	names, err := Select[testUser, testUser](users, "name").Take(1).ToList()
+	if err != nil {
+		t.Fatalf("ToList failed: %v", err)
+	}
+	if len(names) != 1 || names[0].Name != "Ana" || names[0].ID != 0 || names[0].Email != "" {
+		t.Errorf("Expected only the name column, got %+v", names)
+	}
+
+	if query := Select[testUser, userSummary](users).buildQuery(); query != `SELECT id, email_address FROM "users" WHERE name <> ? ORDER BY name` {
+		t.Errorf("Unexpected projection query %q", query)
+	}
+	if len(ctx.ChangeTracker.entities) != 3 {
+		t.Errorf("Expected projections not to be tracked, got %d tracked", len(ctx.ChangeTracker.entities))
+	}
+}
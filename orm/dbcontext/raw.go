@@ -0,0 +1,30 @@
+package dbcontext
+
+// FromSQL runs a raw SQL query and scans its rows into T, mapping columns to fields
+// like other queries. The query is used as a subquery, so the set can be refined:
+//
+//	users, err := dbcontext.FromSQL[User](ctx,
+//		"SELECT u.* FROM users u JOIN orders o ON o.user_id = u.id WHERE o.total > ?", 100,
+//	).Where("is_active = ?", true).OrderBy("email").ToList()
+//
+// Placeholders are written as ? for every driver. Results are tracked unless
+// AsNoTracking is used. ExecuteUpdate and ExecuteDelete are not supported. Rows of
+// tenant entities are filtered on the tenant column like other queries, so the
+// query must select it.
+func FromSQL[T any](ctx *EnhancedDbContext, query string, args ...interface{}) *EnhancedDbSet[T] {
+	query = convertQueryPlaceholders(query, ctx.driver)
+
+	var entity T
+	meta := metadataOf(&entity)
+	set := &EnhancedDbSet[T]{
+		ctx:        ctx,
+		tableName:  "(" + query + ") AS from_sql",
+		whereArgs:  args,
+		fromSQL:    true,
+		softDelete: meta.softDeleteColumn,
+	}
+	if field, ok := ctx.tenantField(meta); ok {
+		set.tenantColumn = field.column
+	}
+	return set
+}
@@ -0,0 +1,48 @@
+package dbcontext
+
+import "testing"
+
+func TestFromSQL(t *testing.T) {
+	ctx := newTestContext(t)
+	for _, name := range []string{"Ana", "Budi", "Citra"} {
+		ctx.Add(&testUser{Email: name + "@example.com", Name: name})
+	}
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	base := FromSQL[testUser](ctx, "SELECT * FROM users WHERE name <> ?", "Budi")
+
+	users, err := base.OrderByDescending("name").ToList()
+	if err != nil {
+		t.Fatalf("ToList failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "Citra" || users[1].Email != "Ana@example.com" {
+		t.Fatalf("Expected Citra and Ana scanned by db tag, got %+v", users)
+	}
+
+	// Filters compose on top of the raw query, after its arguments
+	filtered, err := base.Where("name LIKE ?", "C%").Count()
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if filtered != 1 {
+		t.Errorf("Expected 1 user matching both filters, got %d", filtered)
+	}
+
+	if _, err := base.ExecuteDelete(); err == nil {
+		t.Error("Expected ExecuteDelete on a FromSQL query to fail")
+	}
+}
+
+func TestFromSQLPlaceholdersForPostgres(t *testing.T) {
+	ctx := &EnhancedDbContext{driver: driverPostgres}
+
+	set := FromSQL[testUser](ctx, "SELECT * FROM users WHERE name <> ?", "Budi").Where("email_address LIKE ?", "%@example.com")
+	if expected := "SELECT * FROM (SELECT * FROM users WHERE name <> $1) AS from_sql WHERE email_address LIKE $2"; set.buildQuery() != expected {
+		t.Errorf("Expected %q, got %q", expected, set.buildQuery())
+	}
+	if len(set.whereArgs) != 2 || set.whereArgs[0] != "Budi" {
+		t.Errorf("Expected raw arguments before filter arguments, got %v", set.whereArgs)
+	}
+}
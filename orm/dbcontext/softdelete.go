@@ -0,0 +1,69 @@
+package dbcontext
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// IgnoreQueryFilters includes soft deleted rows and, on a context without a tenant,
+// the rows of every tenant in the query. Soft delete entities
+// have a timestamp field tagged with the name of its column:
+//
+//	type Post struct {
+//		ID        int64     `db:"id"`
+//		Title     string    `db:"title"`
+//		DeletedAt time.Time `db:"deleted_at" softdelete:"deleted_at"`
+//	}
+//
+// Delete sets the timestamp instead of deleting the row, and queries leave rows
+// with a timestamp out. A zero timestamp is stored as NULL.
+func (set *EnhancedDbSet[T]) IgnoreQueryFilters() *EnhancedDbSet[T] {
+	newSet := *set
+	newSet.softDelete = ""
+	if set.ctx.tenant == "" {
+		newSet.tenantColumn = ""
+	}
+	return &newSet
+}
+
+// Restore marks a soft deleted entity to be undeleted on SaveChanges
+func (ctx *EnhancedDbContext) Restore(entity interface{}) {
+	v := reflect.ValueOf(entity).Elem()
+	if field, ok := settableField(v, metadataFor(v.Type()).softDelete); ok {
+		field.Set(reflect.Zero(field.Type()))
+	}
+	ctx.Update(entity)
+}
+
+// softDeleteEntity sets the soft delete timestamp of an entity
+func (ctx *EnhancedDbContext) softDeleteEntity(c context.Context, entity interface{}) error {
+	meta := metadataOf(entity)
+	now := time.Now()
+
+	// Safe: table/column names are trusted, user data is parameterized (see now and id)
+	//nolint:gosec // G201: Identifiers are not user-controlled; all user data is parameterized.
+	query := fmt.Sprintf("UPDATE %s SET %s = %s WHERE id = %s", ctx.tableOf(meta), quoteIdentifier(ctx.driver, meta.softDeleteColumn),
+		getPlaceholder(ctx.driver, 0), getPlaceholder(ctx.driver, 1))
+	args := []interface{}{now, getIDValue(entity)}
+	if condition, ok := ctx.tenantCondition(entity, len(args)); ok {
+		query += condition
+		args = append(args, ctx.tenant)
+	}
+
+	if _, err := ctx.exec(c, query, args...); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(entity).Elem()
+	if field, ok := settableField(v, meta.softDelete); ok {
+		switch field.Type() {
+		case timeType:
+			field.Set(reflect.ValueOf(now))
+		case reflect.PointerTo(timeType):
+			field.Set(reflect.ValueOf(&now))
+		}
+	}
+	return nil
+}
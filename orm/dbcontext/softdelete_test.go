@@ -0,0 +1,67 @@
+package dbcontext
+
+import (
+	"testing"
+	"time"
+)
+
+// testPost is a soft delete entity
+type testPost struct {
+	ID        int64     `db:"id"`
+	Title     string    `db:"title"`
+	DeletedAt time.Time `db:"deleted_at" softdelete:"deleted_at"`
+}
+
+func (testPost) TableName() string { return "posts" }
+
+func TestSoftDelete(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT, deleted_at DATETIME)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	hello, bye := &testPost{Title: "Hello"}, &testPost{Title: "Bye"}
+	ctx.Add(hello)
+	ctx.Add(bye)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	ctx.Delete(bye)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if bye.DeletedAt.IsZero() {
+		t.Error("Expected DeletedAt to be set on the entity")
+	}
+
+	posts := NewEnhancedDbSet[testPost](ctx).AsNoTracking()
+	if count, _ := posts.Count(); count != 1 {
+		t.Errorf("Expected soft deleted posts to be filtered out, got %d posts", count)
+	}
+	if found, _ := posts.WhereOr("title = ?", "Bye").Find(bye.ID); found != nil {
+		t.Errorf("Expected Find to skip the soft deleted post, got %+v", found)
+	}
+	if count, _ := posts.IgnoreQueryFilters().Count(); count != 2 {
+		t.Errorf("Expected IgnoreQueryFilters to include soft deleted posts, got %d", count)
+	}
+
+	ctx.Restore(bye)
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if count, _ := posts.Count(); count != 2 {
+		t.Errorf("Expected restored post to be queried again, got %d posts", count)
+	}
+
+	deleted, err := posts.Where("title = ?", "Hello").ExecuteDelete()
+	if err != nil || deleted != 1 {
+		t.Fatalf("Expected ExecuteDelete to soft delete 1 post, got %d, %v", deleted, err)
+	}
+	if count, _ := posts.IgnoreQueryFilters().Count(); count != 2 {
+		t.Errorf("Expected ExecuteDelete to keep the rows, got %d", count)
+	}
+	if count, _ := posts.Count(); count != 1 {
+		t.Errorf("Expected 1 post left after ExecuteDelete, got %d", count)
+	}
+}
@@ -0,0 +1,128 @@
+package dbcontext
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLite journal modes
+const (
+	SQLiteJournalDelete = "DELETE"
+	SQLiteJournalWAL    = "WAL"
+	SQLiteJournalMemory = "MEMORY"
+)
+
+// SQLite synchronous settings
+const (
+	SQLiteSyncOff    = "OFF"
+	SQLiteSyncNormal = "NORMAL"
+	SQLiteSyncFull   = "FULL"
+	SQLiteSyncExtra  = "EXTRA"
+)
+
+// SQLiteOptions holds the pragmas applied to every SQLite connection.
+// The options are encoded into the connection string so that the
+// go-sqlite3 driver applies them whenever the pool opens a new connection;
+// executing PRAGMA statements once would only configure a single connection.
+type SQLiteOptions struct {
+	JournalMode  string        // journal_mode pragma (e.g. WAL)
+	BusyTimeout  time.Duration // busy_timeout pragma; how long a writer waits for a lock
+	ForeignKeys  bool          // foreign_keys pragma
+	Synchronous  string        // synchronous pragma (OFF, NORMAL, FULL, EXTRA)
+	TxLock       string        // transaction locking behavior (deferred, immediate, exclusive)
+	MaxOpenConns int           // maximum open connections; 0 leaves the pool default
+}
+
+// DefaultSQLiteOptions returns options suited for concurrent web workloads:
+// WAL journaling, a 5 second busy timeout, foreign keys enforced, NORMAL
+// synchronous mode, and immediate transactions to avoid lock upgrade deadlocks.
+func DefaultSQLiteOptions() SQLiteOptions {
+	return SQLiteOptions{
+		JournalMode: SQLiteJournalWAL,
+		BusyTimeout: 5 * time.Second,
+		ForeignKeys: true,
+		Synchronous: SQLiteSyncNormal,
+		TxLock:      "immediate",
+	}
+}
+
+// DSN returns the connection string for path with the pragmas applied
+func (o SQLiteOptions) DSN(path string) string {
+	params := url.Values{}
+
+	if o.JournalMode != "" {
+		params.Set("_journal_mode", strings.ToUpper(o.JournalMode))
+	}
+	if o.BusyTimeout > 0 {
+		params.Set("_busy_timeout", strconv.FormatInt(o.BusyTimeout.Milliseconds(), 10))
+	}
+	if o.ForeignKeys {
+		params.Set("_foreign_keys", "on")
+	}
+	if o.Synchronous != "" {
+		params.Set("_synchronous", strings.ToUpper(o.Synchronous))
+	}
+	if o.TxLock != "" {
+		params.Set("_txlock", strings.ToLower(o.TxLock))
+	}
+
+	if len(params) == 0 {
+		return path
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + params.Encode()
+}
+
+// OpenSQLite opens a SQLite database with the given pragma options.
+// The go-sqlite3 driver must be registered by the caller (import _ "github.com/mattn/go-sqlite3").
+func OpenSQLite(path string, opts SQLiteOptions) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", opts.DSN(path))
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+
+	// Force a connection so invalid pragmas surface immediately
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	return db, nil
+}
+
+// NewEnhancedDbContextWithSQLiteOptions creates a new enhanced database context
+// backed by SQLite with the given pragma options
+func NewEnhancedDbContextWithSQLiteOptions(path string, opts SQLiteOptions) (*EnhancedDbContext, error) {
+	db, err := OpenSQLite(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EnhancedDbContext{
+		db:            db,
+		ChangeTracker: NewChangeTracker(),
+		Database:      NewDatabase(db),
+		driver:        "sqlite3",
+	}, nil
+}
+
+// SQLitePragma returns the current value of a pragma on one pooled connection.
+// It is mainly useful for verifying that options were applied.
+func SQLitePragma(db *sql.DB, name string) (string, error) {
+	var value string
+	//nolint:gosec // G202: pragma names are supplied by the application, not by users.
+	err := db.QueryRow("PRAGMA " + name).Scan(&value)
+	return value, err
+}
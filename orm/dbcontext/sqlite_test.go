@@ -0,0 +1,69 @@
+package dbcontext
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSQLiteOptionsDSN(t *testing.T) {
+	dsn := DefaultSQLiteOptions().DSN("app.db")
+
+	expected := []string{"_journal_mode=WAL", "_busy_timeout=5000", "_foreign_keys=on", "_synchronous=NORMAL", "_txlock=immediate"}
+	for _, param := range expected {
+		if !strings.Contains(dsn, param) {
+			t.Errorf("Expected DSN %q to contain %q", dsn, param)
+		}
+	}
+
+	if !strings.HasPrefix(dsn, "app.db?") {
+		t.Errorf("Expected DSN to start with path and '?', got %q", dsn)
+	}
+
+	if dsn := DefaultSQLiteOptions().DSN("file:app.db?cache=shared"); !strings.HasPrefix(dsn, "file:app.db?cache=shared&") {
+		t.Errorf("Expected existing query to be extended, got %q", dsn)
+	}
+
+	if dsn := (SQLiteOptions{}).DSN("app.db"); dsn != "app.db" {
+		t.Errorf("Expected empty options to leave DSN unchanged, got %q", dsn)
+	}
+}
+
+func TestOpenSQLiteAppliesPragmas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pragmas.db")
+	opts := DefaultSQLiteOptions()
+	opts.BusyTimeout = 2 * time.Second
+
+	ctx, err := NewEnhancedDbContextWithSQLiteOptions(path, opts)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer ctx.db.Close()
+
+	pragmas := map[string]string{
+		"journal_mode": "wal",
+		"busy_timeout": "2000",
+		"foreign_keys": "1",
+		"synchronous":  "1", // NORMAL
+	}
+	for name, expected := range pragmas {
+		value, err := SQLitePragma(ctx.db, name)
+		if err != nil {
+			t.Fatalf("Failed to read pragma %s: %v", name, err)
+		}
+		if value != expected {
+			t.Errorf("Expected pragma %s to be %s, got %s", name, expected, value)
+		}
+	}
+
+	if err := ctx.PingContext(t.Context()); err != nil {
+		t.Errorf("Expected ping to succeed, got %v", err)
+	}
+
+	if ctx.driver != "sqlite3" {
+		t.Errorf("Expected driver sqlite3, got %s", ctx.driver)
+	}
+}
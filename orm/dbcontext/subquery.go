@@ -0,0 +1,83 @@
+package dbcontext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Subquery is a query that can be embedded in the conditions of another, like an
+// EnhancedDbSet of any entity type
+type Subquery interface {
+	subquery() (string, []interface{})
+}
+
+// subquery returns the SQL and arguments of the query, ignoring its cursor
+func (set *EnhancedDbSet[T]) subquery() (string, []interface{}) {
+	return set.buildQuery(), set.queryArgs()
+}
+
+// WhereExists keeps the entities for which a subquery returns rows. The subquery
+// can refer to the columns of the outer query, e.g. the users with an order:
+//
+//	orders := dbcontext.NewEnhancedDbSet[Order](ctx)
+//	buyers, err := users.WhereExists(orders.Where("orders.user_id = users.id")).ToList()
+func (set *EnhancedDbSet[T]) WhereExists(subquery Subquery) *EnhancedDbSet[T] {
+	return set.whereSubquery("EXISTS", subquery)
+}
+
+// WhereInSubquery keeps the entities whose column is one of the values returned by
+// a subquery selecting a single column, e.g. with Select:
+//
+//	paid := dbcontext.Select[Order, Order](orders.Where("status = ?", "paid"), "user_id")
+//	buyers, err := users.WhereInSubquery("id", paid).ToList()
+func (set *EnhancedDbSet[T]) WhereInSubquery(column string, subquery Subquery) *EnhancedDbSet[T] {
+	return set.whereSubquery(column+" IN", subquery)
+}
+
+// whereSubquery adds a condition on a subquery to the WHERE clause
+func (set *EnhancedDbSet[T]) whereSubquery(operator string, subquery Subquery) *EnhancedDbSet[T] {
+	newSet := *set
+	query, args := subquery.subquery()
+
+	// The subquery placeholders are numbered from $1, number them after ours
+	if set.ctx.driver == driverPostgres {
+		query = shiftPlaceholders(query, len(set.whereArgs))
+	}
+
+	condition := fmt.Sprintf("%s (%s)", operator, query)
+	if newSet.whereClause != "" {
+		newSet.whereClause += " AND " + condition
+	} else {
+		newSet.whereClause = condition
+	}
+	newSet.whereArgs = append(append([]interface{}(nil), set.whereArgs...), args...)
+	return &newSet
+}
+
+// shiftPlaceholders adds offset to the number of every $N placeholder of a query
+func shiftPlaceholders(query string, offset int) string {
+	if offset == 0 {
+		return query
+	}
+
+	var result strings.Builder
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		result.WriteRune(runes[i])
+		if runes[i] != '$' {
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && unicode.IsDigit(runes[j]) {
+			j++
+		}
+		if j > i+1 {
+			n, _ := strconv.Atoi(string(runes[i+1 : j]))
+			result.WriteString(strconv.Itoa(n + offset))
+			i = j - 1
+		}
+	}
+	return result.String()
+}
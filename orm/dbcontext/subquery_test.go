@@ -0,0 +1,70 @@
+package dbcontext
+
+import "testing"
+
+// testPurchase is an entity referring to testUser
+type testPurchase struct {
+	ID     int64   `db:"id"`
+	UserID int64   `db:"user_id"`
+	Total  float64 `db:"total"`
+}
+
+func (testPurchase) TableName() string { return "purchases" }
+
+func TestSubqueries(t *testing.T) {
+	for _, driver := range []string{"sqlite3", driverPostgres} {
+		t.Run(driver, func(t *testing.T) {
+			ctx := newTestContext(t)
+			ctx.driver = driver
+			if _, err := ctx.db.Exec(`CREATE TABLE purchases (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER, total REAL)`); err != nil {
+				t.Fatalf("Failed to create table: %v", err)
+			}
+			for _, name := range []string{"Ana", "Budi", "Citra", "Dewi"} {
+				if _, err := ctx.db.Exec(`INSERT INTO users (email_address, name) VALUES ($1, $2)`, name+"@example.com", name); err != nil {
+					t.Fatalf("Failed to insert user: %v", err)
+				}
+			}
+			// Ana and Citra bought something, Citra for more than 50
+			if _, err := ctx.db.Exec(`INSERT INTO purchases (user_id, total) VALUES (1, 20), (3, 80), (3, 10)`); err != nil {
+				t.Fatalf("Failed to insert purchases: %v", err)
+			}
+
+			users := NewEnhancedDbSet[testUser](ctx).Where("name <> ?", "Nobody")
+			purchases := NewEnhancedDbSet[testPurchase](ctx)
+
+			tests := []struct {
+				name     string
+				set      *EnhancedDbSet[testUser]
+				expected []string
+			}{
+				{"Exists", users.WhereExists(purchases.Where("purchases.user_id = users.id")), []string{"Ana", "Citra"}},
+				{"ExistsWithArgs", users.WhereExists(purchases.Where("purchases.user_id = users.id AND total > ?", 50)), []string{"Citra"}},
+				{"InSubquery", users.WhereInSubquery("id", Select[testPurchase, testPurchase](purchases.Where("total < ?", 50), "user_id")), []string{"Ana", "Citra"}},
+				{"ThenWhere", users.WhereInSubquery("id", Select[testPurchase, testPurchase](purchases, "user_id")).Where("name <> ?", "Ana"), []string{"Citra"}},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					results, err := tt.set.OrderBy("name").ToList()
+					if err != nil {
+						t.Fatalf("ToList failed: %v", err)
+					}
+					if len(results) != len(tt.expected) {
+						t.Fatalf("Expected %v, got %d users", tt.expected, len(results))
+					}
+					for i, user := range results {
+						if user.Name != tt.expected[i] {
+							t.Errorf("Expected user %d to be %s, got %s", i, tt.expected[i], user.Name)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestShiftPlaceholders(t *testing.T) {
+	if got := shiftPlaceholders("a = $1 AND b IN ($2, $10) AND c = '$'", 3); got != "a = $4 AND b IN ($5, $13) AND c = '$'" {
+		t.Errorf("Unexpected shifted query %q", got)
+	}
+}
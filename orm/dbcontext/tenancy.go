@@ -0,0 +1,209 @@
+package dbcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// ErrNoTenant is returned when a tenant is needed but none was resolved
+var ErrNoTenant = errors.New("no tenant")
+
+// TenancyStrategy selects how the data of tenants is separated
+type TenancyStrategy int
+
+const (
+	// TenantColumn keeps the rows of all tenants in the same tables, filtered on a
+	// tenant column
+	TenantColumn TenancyStrategy = iota
+	// TenantSchema keeps the tables of each tenant in a schema named after it
+	TenantSchema
+)
+
+// TenantResolver returns the tenant of a request context, e.g. read from a JWT claim
+type TenantResolver func(c context.Context) (string, error)
+
+// TenancyConfig holds the multi-tenancy settings of a context
+type TenancyConfig struct {
+	Strategy TenancyStrategy
+	// Column is the tenant column of TenantColumn (default: "tenant_id"). Entities
+	// with a field for it are scoped to the tenant, others are shared.
+	Column string
+	// Resolver returns the tenant of TenantContext (default: TenantFromContext)
+	Resolver TenantResolver
+}
+
+// DefaultTenancyConfig returns the default multi-tenancy settings: rows filtered on
+// a tenant_id column, with the tenant set by WithTenant
+func DefaultTenancyConfig() TenancyConfig {
+	return TenancyConfig{
+		Strategy: TenantColumn,
+		Column:   "tenant_id",
+		Resolver: resolveTenantFromContext,
+	}
+}
+
+// tenantKey is the context key of the tenant set by WithTenant
+type tenantKey struct{}
+
+// schemaName matches the tenants that can be used as schema names
+var schemaName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithTenant returns a copy of c carrying a tenant, e.g. set by a middleware from
+// the claims of a request
+func WithTenant(c context.Context, tenant string) context.Context {
+	return context.WithValue(c, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, if any
+func TenantFromContext(c context.Context) (string, bool) {
+	tenant, ok := c.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// resolveTenantFromContext is the default TenantResolver
+func resolveTenantFromContext(c context.Context) (string, error) {
+	if tenant, ok := TenantFromContext(c); ok {
+		return tenant, nil
+	}
+	return "", ErrNoTenant
+}
+
+// UseTenancy enables multi-tenancy. Data is then accessed through the contexts of
+// ForTenant or TenantContext, scoped to a tenant:
+//
+//	db.UseTenancy(dbcontext.DefaultTenancyConfig())
+//	...
+//	tenantDb, err := db.TenantContext(c.RequestContext())
+//	invoices, err := dbcontext.NewEnhancedDbSet[Invoice](tenantDb).ToList()
+//
+// With TenantColumn, queries of tenant entities only return the rows of the tenant
+// and inserts set their tenant field, a string. Updates and deletes only match the
+// rows of the tenant, and return an error rather than move rows to another tenant.
+// Without a tenant, queries of tenant entities return
+// ErrNoTenant unless IgnoreQueryFilters is used, and inserts need the tenant field
+// set.
+//
+// With TenantSchema, table names are qualified with the tenant schema, e.g.
+// acme.invoices. Unlike switching search_path, this can't leak between pooled
+// connections.
+func (ctx *EnhancedDbContext) UseTenancy(config TenancyConfig) {
+	if config.Column == "" {
+		config.Column = DefaultTenancyConfig().Column
+	}
+	if config.Resolver == nil {
+		config.Resolver = resolveTenantFromContext
+	}
+	ctx.tenancy = &config
+}
+
+// ForTenant returns a context scoped to a tenant, sharing the database, driver and
+// hooks of ctx with a change tracker of its own
+func (ctx *EnhancedDbContext) ForTenant(tenant string) (*EnhancedDbContext, error) {
+	if ctx.tenancy == nil {
+		return nil, errors.New("multi-tenancy is not enabled, see UseTenancy")
+	}
+	if tenant == "" {
+		return nil, ErrNoTenant
+	}
+	if ctx.tenancy.Strategy == TenantSchema && !schemaName.MatchString(tenant) {
+		return nil, fmt.Errorf("invalid tenant schema name %q", tenant)
+	}
+
+	scoped := ctx.derive()
+	scoped.tenant = tenant
+	return scoped, nil
+}
+
+// TenantContext returns a context scoped to the tenant the TenantResolver returns for c
+func (ctx *EnhancedDbContext) TenantContext(c context.Context) (*EnhancedDbContext, error) {
+	if ctx.tenancy == nil {
+		return nil, errors.New("multi-tenancy is not enabled, see UseTenancy")
+	}
+	tenant, err := ctx.tenancy.Resolver(c)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.ForTenant(tenant)
+}
+
+// Tenant returns the tenant the context is scoped to, empty if none
+func (ctx *EnhancedDbContext) Tenant() string {
+	return ctx.tenant
+}
+
+// derive returns a copy of the context with a change tracker of its own
+func (ctx *EnhancedDbContext) derive() *EnhancedDbContext {
+	return &EnhancedDbContext{
+		db:            ctx.db,
+		tx:            ctx.tx,
+		ChangeTracker: NewChangeTracker(),
+		Database:      ctx.Database,
+		driver:        ctx.driver,
+		hooks:         ctx.hooks,
+		tenancy:       ctx.tenancy,
+		tenant:        ctx.tenant,
+		noTracking:    ctx.noTracking,
+	}
+}
+
+// tableOf returns the table of an entity, qualified with the tenant schema if any
+// and quoted for the driver
+func (ctx *EnhancedDbContext) tableOf(meta *entityMetadata) string {
+	if ctx.tenancy != nil && ctx.tenancy.Strategy == TenantSchema && ctx.tenant != "" {
+		return quoteIdentifier(ctx.driver, ctx.tenant+"."+meta.tableName)
+	}
+	return quoteIdentifier(ctx.driver, meta.tableName)
+}
+
+// tenantField returns the tenant field of an entity type with TenantColumn, if any
+func (ctx *EnhancedDbContext) tenantField(meta *entityMetadata) (*fieldMetadata, bool) {
+	if ctx.tenancy == nil || ctx.tenancy.Strategy != TenantColumn {
+		return nil, false
+	}
+	for i := range meta.fields {
+		if meta.fields[i].column == ctx.tenancy.Column {
+			return &meta.fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// setTenant sets the tenant field of an entity being inserted or updated, and
+// returns an error if it belongs to another tenant
+func (ctx *EnhancedDbContext) setTenant(entity interface{}) error {
+	v := reflect.ValueOf(entity).Elem()
+	field, ok := ctx.tenantField(metadataFor(v.Type()))
+	if !ok {
+		return nil
+	}
+
+	value := v.FieldByIndex(field.index)
+	if value.Kind() != reflect.String {
+		return fmt.Errorf("tenant field %s of %s must be a string", field.column, v.Type().Name())
+	}
+	switch current := value.String(); {
+	case ctx.tenant == "" && current == "":
+		return fmt.Errorf("%w for %s", ErrNoTenant, v.Type().Name())
+	case ctx.tenant == "":
+		return nil
+	case current != "" && current != ctx.tenant:
+		return fmt.Errorf("%s belongs to tenant %s, not %s", v.Type().Name(), current, ctx.tenant)
+	}
+	if value.CanSet() {
+		value.SetString(ctx.tenant)
+	}
+	return nil
+}
+
+// tenantCondition returns the condition scoping an update or delete of an entity to
+// the tenant, with its placeholder numbered after count, if any
+func (ctx *EnhancedDbContext) tenantCondition(entity interface{}, count int) (string, bool) {
+	column, ok := ctx.tenantField(metadataOf(entity))
+	if !ok || ctx.tenant == "" {
+		return "", false
+	}
+	return fmt.Sprintf(" AND %s = %s", quoteIdentifier(ctx.driver, column.column), getPlaceholder(ctx.driver, count)), true
+}
@@ -0,0 +1,280 @@
+package dbcontext
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// testInvoice is a tenant entity
+type testInvoice struct {
+	ID       int64  `db:"id"`
+	TenantID string `db:"tenant_id"`
+	Number   string `db:"number"`
+}
+
+func (testInvoice) TableName() string { return "invoices" }
+
+func newTenancyContext(t *testing.T, config TenancyConfig) *EnhancedDbContext {
+	t.Helper()
+
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE invoices (id INTEGER PRIMARY KEY AUTOINCREMENT, tenant_id TEXT NOT NULL, number TEXT)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	ctx.UseTenancy(config)
+	return ctx
+}
+
+func TestTenantColumn(t *testing.T) {
+	for _, driver := range []string{"sqlite3", driverPostgres} {
+		t.Run(driver, func(t *testing.T) {
+			ctx := newTenancyContext(t, DefaultTenancyConfig())
+			ctx.driver = driver
+
+			acme, err := ctx.ForTenant("acme")
+			if err != nil {
+				t.Fatalf("ForTenant failed: %v", err)
+			}
+			globex, _ := ctx.ForTenant("globex")
+
+			first := &testInvoice{Number: "A-1"}
+			acme.Add(first)
+			acme.Add(&testInvoice{Number: "A-2"})
+			globex.Add(&testInvoice{Number: "G-1"})
+			if _, err := acme.SaveChanges(); err != nil {
+				t.Fatalf("SaveChanges failed: %v", err)
+			}
+			if _, err := globex.SaveChanges(); err != nil {
+				t.Fatalf("SaveChanges failed: %v", err)
+			}
+			if first.TenantID != "acme" {
+				t.Errorf("Expected inserts to set the tenant, got %q", first.TenantID)
+			}
+
+			invoices := NewEnhancedDbSet[testInvoice](acme).AsNoTracking()
+			if count, err := invoices.Count(); err != nil || count != 2 {
+				t.Errorf("Expected 2 acme invoices, got %d, %v", count, err)
+			}
+			list, err := invoices.Where("number = ?", "G-1").ToList()
+			if err != nil || len(list) != 0 {
+				t.Errorf("Expected the invoices of other tenants to be filtered out, got %v, %v", list, err)
+			}
+			if count, _ := invoices.IgnoreQueryFilters().Count(); count != 2 {
+				t.Errorf("Expected IgnoreQueryFilters to keep the tenant filter of a tenant context, got %d", count)
+			}
+
+			// Updates and deletes of another tenant don't match its rows
+			stolen := *first
+			stolen.Number = "stolen"
+			globex.Update(&stolen)
+			if _, err := globex.SaveChanges(); err == nil {
+				t.Error("Expected updating the invoice of another tenant to fail")
+			}
+			globex.Detach(&stolen)
+			stolen.TenantID = ""
+			globex.Update(&stolen)
+			if _, err := globex.SaveChanges(); err != nil {
+				t.Fatalf("SaveChanges failed: %v", err)
+			}
+			if driver != driverPostgres {
+				// SQLite numbers $N placeholders by appearance, not by N
+				updated, err := NewEnhancedDbSet[testInvoice](globex).ExecuteUpdate(map[string]interface{}{"number": "X"})
+				if err != nil || updated != 1 {
+					t.Errorf("Expected ExecuteUpdate to update 1 globex invoice, got %d, %v", updated, err)
+				}
+			}
+			deleted, err := NewEnhancedDbSet[testInvoice](globex).Where("number = ?", "A-2").ExecuteDelete()
+			if err != nil || deleted != 0 {
+				t.Errorf("Expected ExecuteDelete to leave acme invoices alone, got %d, %v", deleted, err)
+			}
+
+			unscoped := NewEnhancedDbSet[testInvoice](ctx).AsNoTracking()
+			if _, err := unscoped.ToList(); !errors.Is(err, ErrNoTenant) {
+				t.Errorf("Expected ErrNoTenant without a tenant, got %v", err)
+			}
+			if _, err := unscoped.ExecuteDelete(); !errors.Is(err, ErrNoTenant) {
+				t.Errorf("Expected ErrNoTenant for ExecuteDelete without a tenant, got %v", err)
+			}
+			if count, err := unscoped.IgnoreQueryFilters().Count(); err != nil || count != 3 {
+				t.Errorf("Expected IgnoreQueryFilters to query every tenant, got %d, %v", count, err)
+			}
+			found, err := unscoped.IgnoreQueryFilters().Where("number = ?", "A-1").FirstOrDefault()
+			if err != nil || found == nil || found.Number != "A-1" {
+				t.Errorf("Expected the update of another tenant to be discarded, got %+v, %v", found, err)
+			}
+		})
+	}
+}
+
+func TestTenantColumnInsertChecks(t *testing.T) {
+	ctx := newTenancyContext(t, DefaultTenancyConfig())
+	acme, _ := ctx.ForTenant("acme")
+
+	other := &testInvoice{TenantID: "globex", Number: "G-1"}
+	acme.Add(other)
+	if _, err := acme.SaveChanges(); err == nil {
+		t.Error("Expected inserting the invoice of another tenant to fail")
+	}
+	acme.Detach(other)
+
+	untenanted := &testInvoice{Number: "X-1"}
+	ctx.Add(untenanted)
+	if _, err := ctx.SaveChanges(); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Expected ErrNoTenant inserting without a tenant, got %v", err)
+	}
+	ctx.Detach(untenanted)
+
+	// Shared entities aren't scoped
+	acme.Add(&testUser{Name: "Ann", Email: "ann@example.com"})
+	if _, err := acme.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if count, err := NewEnhancedDbSet[testUser](ctx).Count(); err != nil || count != 1 {
+		t.Errorf("Expected shared entities to be queried without a tenant, got %d, %v", count, err)
+	}
+}
+
+func TestTenantSchema(t *testing.T) {
+	ctx := newTestContext(t)
+	for _, stmt := range []string{
+		`ATTACH DATABASE ':memory:' AS acme`,
+		`CREATE TABLE acme.users (id INTEGER PRIMARY KEY AUTOINCREMENT, email_address TEXT NOT NULL, name TEXT NOT NULL, created_at DATETIME, updated_at DATETIME)`,
+	} {
+		if _, err := ctx.db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to set up schema: %v", err)
+		}
+	}
+	ctx.UseTenancy(TenancyConfig{Strategy: TenantSchema})
+
+	if _, err := ctx.ForTenant("acme; DROP TABLE users"); err == nil {
+		t.Error("Expected an invalid schema name to be rejected")
+	}
+
+	acme, err := ctx.ForTenant("acme")
+	if err != nil {
+		t.Fatalf("ForTenant failed: %v", err)
+	}
+	acme.Add(&testUser{Name: "Ann", Email: "ann@example.com"})
+	if _, err := acme.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	if count, _ := NewEnhancedDbSet[testUser](acme).Count(); count != 1 {
+		t.Errorf("Expected 1 user in the acme schema, got %d", count)
+	}
+	if count, _ := NewEnhancedDbSet[testUser](ctx).Count(); count != 0 {
+		t.Errorf("Expected the main schema to be left alone, got %d users", count)
+	}
+}
+
+func TestTenantContext(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.TenantContext(context.Background()); err == nil {
+		t.Error("Expected TenantContext to fail without multi-tenancy")
+	}
+
+	ctx.UseTenancy(DefaultTenancyConfig())
+	if _, err := ctx.TenantContext(context.Background()); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Expected ErrNoTenant without a tenant in the context, got %v", err)
+	}
+
+	scoped, err := ctx.TenantContext(WithTenant(context.Background(), "acme"))
+	if err != nil || scoped.Tenant() != "acme" {
+		t.Fatalf("Expected a context scoped to acme, got %v", err)
+	}
+
+	err = scoped.WithTransaction(func(tx *EnhancedDbContext) error {
+		if tx.Tenant() != "acme" {
+			t.Errorf("Expected transactions to keep the tenant, got %q", tx.Tenant())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction failed: %v", err)
+	}
+}
+
+func TestTenantColumnCantBeMoved(t *testing.T) {
+	ctx := newTenancyContext(t, DefaultTenancyConfig())
+	acme, _ := ctx.ForTenant("acme")
+	acme.Add(&testInvoice{Number: "A-1"})
+	if _, err := acme.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	invoice, err := NewEnhancedDbSet[testInvoice](acme).First()
+	if err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	invoice.TenantID = "globex"
+	if _, err := acme.SaveChanges(); err == nil {
+		t.Error("Expected moving a tracked invoice to another tenant to fail")
+	}
+
+	invoices := NewEnhancedDbSet[testInvoice](acme)
+	if _, err := invoices.ExecuteUpdate(map[string]interface{}{"tenant_id": "globex"}); err == nil {
+		t.Error("Expected ExecuteUpdate of the tenant column to fail")
+	}
+
+	if count, _ := NewEnhancedDbSet[testInvoice](ctx).IgnoreQueryFilters().Where("tenant_id = ?", "acme").Count(); count != 1 {
+		t.Errorf("Expected the invoice to stay with acme, got %d acme invoices", count)
+	}
+}
+
+func TestTenantColumnFromSQL(t *testing.T) {
+	ctx := newTenancyContext(t, DefaultTenancyConfig())
+	acme, _ := ctx.ForTenant("acme")
+	globex, _ := ctx.ForTenant("globex")
+	acme.Add(&testInvoice{Number: "A-1"})
+	globex.Add(&testInvoice{Number: "G-1"})
+	for _, tenantCtx := range []*EnhancedDbContext{acme, globex} {
+		if _, err := tenantCtx.SaveChanges(); err != nil {
+			t.Fatalf("SaveChanges failed: %v", err)
+		}
+	}
+
+	invoices, err := FromSQL[testInvoice](acme, "SELECT * FROM invoices WHERE number <> ?", "X").ToList()
+	if err != nil || len(invoices) != 1 || invoices[0].TenantID != "acme" {
+		t.Errorf("Expected the invoice of acme, got %+v, %v", invoices, err)
+	}
+	if _, err := FromSQL[testInvoice](ctx, "SELECT * FROM invoices").ToList(); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("Expected ErrNoTenant without a tenant, got %v", err)
+	}
+	if count, err := FromSQL[testInvoice](ctx, "SELECT * FROM invoices").IgnoreQueryFilters().Count(); err != nil || count != 2 {
+		t.Errorf("Expected IgnoreQueryFilters to see both invoices, got %d, %v", count, err)
+	}
+}
+
+func TestTenantColumnUpsert(t *testing.T) {
+	ctx := newTenancyContext(t, DefaultTenancyConfig())
+	if _, err := ctx.db.Exec(`CREATE UNIQUE INDEX invoices_number ON invoices (number)`); err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	acme, _ := ctx.ForTenant("acme")
+	globex, _ := ctx.ForTenant("globex")
+
+	acme.AddOrUpdate(&testInvoice{Number: "INV-1"}, "number")
+	if _, err := acme.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	// The row of another tenant isn't taken over
+	globex.AddOrUpdate(&testInvoice{Number: "INV-1"}, "number")
+	if _, err := globex.SaveChanges(); err == nil {
+		t.Error("Expected an upsert conflicting with the invoice of acme to fail")
+	}
+	if count, _ := NewEnhancedDbSet[testInvoice](acme).Where("number = ?", "INV-1").Count(); count != 1 {
+		t.Errorf("Expected the invoice to stay with acme, got %d acme invoices", count)
+	}
+
+	// The row of the same tenant is updated
+	again := &testInvoice{Number: "INV-1"}
+	acme.AddOrUpdate(again, "number")
+	if _, err := acme.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if again.ID == 0 || again.TenantID != "acme" {
+		t.Errorf("Expected the acme invoice to be updated, got %+v", again)
+	}
+}
@@ -0,0 +1,82 @@
+package dbcontext
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// snapshot records the field values of an entity, to detect the columns changed later
+func (ct *ChangeTracker) snapshot(entity interface{}) {
+	v, ok := entityValue(entity)
+	if !ok {
+		return
+	}
+
+	fields := metadataFor(v.Type()).fields
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		values[i] = snapshotValue(v.FieldByIndex(field.index))
+	}
+	ct.originals[entity] = values
+}
+
+// ChangedColumns returns the columns of an entity whose fields changed since it was
+// loaded or saved. It returns nil if the original values are unknown, e.g. for
+// entities attached with Update.
+func (ct *ChangeTracker) ChangedColumns(entity interface{}) []string {
+	original, exists := ct.originals[entity]
+	if !exists {
+		return nil
+	}
+	v, _ := entityValue(entity)
+
+	changed := []string{}
+	for i, field := range metadataFor(v.Type()).fields {
+		if !reflect.DeepEqual(original[i], snapshotValue(v.FieldByIndex(field.index))) {
+			changed = append(changed, field.column)
+		}
+	}
+	return changed
+}
+
+// DetectChanges marks the unchanged entities whose fields changed since they were
+// loaded or saved as Modified. SaveChanges calls it, so changed entities are saved
+// without calling Update.
+func (ct *ChangeTracker) DetectChanges() {
+	for entity, state := range ct.entities {
+		if state == EntityStateUnchanged && len(ct.ChangedColumns(entity)) > 0 {
+			ct.entities[entity] = EntityStateModified
+		}
+	}
+}
+
+// UseNoTrackingByDefault makes queries return untracked entities unless AsTracking
+// is used, so read-mostly services don't keep every row they fetch in the change
+// tracker. Entities passed to Add, Update or Delete are still tracked.
+func (ctx *EnhancedDbContext) UseNoTrackingByDefault() {
+	ctx.noTracking = true
+}
+
+// entityValue returns the struct an entity pointer points to
+func entityValue(entity interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(entity)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return v.Elem(), true
+}
+
+// snapshotValue copies the value of a field, following pointers and copying byte
+// slices so changes made through them are detected
+func snapshotValue(v reflect.Value) interface{} {
+	switch {
+	case v.Kind() == reflect.Ptr && !v.IsNil():
+		return snapshotValue(v.Elem())
+	case v.Kind() == reflect.Ptr:
+		return nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return bytes.Clone(v.Bytes())
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,115 @@
+package dbcontext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDirtyTracking(t *testing.T) {
+	ctx := newTestContext(t)
+	ctx.Add(&testUser{Email: "ana@example.com", Name: "Ana"})
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	hook := &recordingHook{}
+	ctx.AddQueryHook(hook)
+
+	user, err := NewEnhancedDbSet[testUser](ctx).First()
+	if err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if state := ctx.ChangeTracker.GetEntityState(user); state != EntityStateUnchanged {
+		t.Errorf("Expected a loaded user to be Unchanged, got %s", state)
+	}
+
+	// Changes are detected without calling Update
+	user.Name = "Ana Maria"
+	if state := ctx.ChangeTracker.GetEntityState(user); state != EntityStateModified {
+		t.Errorf("Expected a changed user to be Modified, got %s", state)
+	}
+	if changed := ctx.ChangeTracker.ChangedColumns(user); len(changed) != 1 || changed[0] != "name" {
+		t.Errorf("Expected only name to change, got %v", changed)
+	}
+	affected, err := ctx.SaveChanges()
+	if err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if affected != 1 {
+		t.Errorf("Expected 1 entity saved, got %d", affected)
+	}
+
+	update := hook.events[len(hook.events)-1].Query
+	if !strings.HasPrefix(update, `UPDATE "users" SET "name" = ?, "updated_at" = ? WHERE id = ?`) {
+		t.Errorf("Expected only the changed columns to be set, got %q", update)
+	}
+	if found, _ := NewEnhancedDbSet[testUser](ctx).AsNoTracking().Find(user.ID); found == nil || found.Name != "Ana Maria" {
+		t.Errorf("Expected the change to be saved, got %+v", found)
+	}
+
+	// Saved entities are compared to their saved values
+	statements := len(hook.events)
+	ctx.Update(user)
+	affected, err = ctx.SaveChanges()
+	if err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if affected != 0 || len(hook.events) != statements {
+		t.Errorf("Expected an unchanged entity not to be written, got %d saved and %d statements", affected, len(hook.events)-statements)
+	}
+}
+
+func TestUpdateWithoutOriginalValues(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`INSERT INTO users (email_address, name) VALUES ('ana@example.com', 'Ana')`); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	hook := &recordingHook{}
+	ctx.AddQueryHook(hook)
+
+	// An entity built from a request body sets every column
+	ctx.Update(&testUser{ID: 1, Email: "ana@example.org", Name: "Ana"})
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	if update := hook.events[0].Query; !strings.Contains(update, `"email_address" = ?, "name" = ?, "created_at" = ?, "updated_at" = ?`) {
+		t.Errorf("Expected every column to be set, got %q", update)
+	}
+}
+
+func TestNoTrackingByDefault(t *testing.T) {
+	ctx := newTestContext(t)
+	users := NewEnhancedDbSet[testUser](ctx)
+	ctx.UseNoTrackingByDefault()
+
+	ctx.Add(&testUser{Email: "ana@example.com", Name: "Ana"})
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+	ctx.ChangeTracker = NewChangeTracker()
+
+	if _, err := users.ToList(); err != nil {
+		t.Fatalf("ToList failed: %v", err)
+	}
+	if len(ctx.ChangeTracker.entities) != 0 {
+		t.Errorf("Expected queries not to track entities, got %d tracked", len(ctx.ChangeTracker.entities))
+	}
+
+	user, err := users.AsTracking().Where("name = ?", "Ana").First()
+	if err != nil {
+		t.Fatalf("First failed: %v", err)
+	}
+	if len(ctx.ChangeTracker.entities) != 1 {
+		t.Errorf("Expected AsTracking to track the user, got %d tracked", len(ctx.ChangeTracker.entities))
+	}
+	user.Name = "Ana Maria"
+	if affected, err := ctx.SaveChanges(); err != nil || affected != 1 {
+		t.Errorf("Expected the tracked user to be saved, got %d, %v", affected, err)
+	}
+
+	if _, err := users.AsTracking().AsNoTracking().ToList(); err != nil {
+		t.Fatalf("ToList failed: %v", err)
+	}
+	if len(ctx.ChangeTracker.entities) != 1 {
+		t.Errorf("Expected AsNoTracking to override AsTracking, got %d tracked", len(ctx.ChangeTracker.entities))
+	}
+}
@@ -0,0 +1,60 @@
+package dbcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WithTransaction runs fn in a transaction, committing it if fn returns nil and
+// rolling it back if fn returns an error or panics:
+//
+//	err := db.WithTransaction(func(tx *dbcontext.EnhancedDbContext) error {
+//		tx.Add(order)
+//		tx.Update(stock)
+//		_, err := tx.SaveChanges()
+//		return err
+//	})
+//
+// The context passed to fn shares the driver, query hooks and tenant of ctx, with a
+// change tracker of its own. Called on a transaction context, fn runs in that transaction.
+func (ctx *EnhancedDbContext) WithTransaction(fn func(tx *EnhancedDbContext) error) error {
+	return ctx.WithTransactionContext(context.Background(), fn)
+}
+
+// WithTransactionContext runs fn in a transaction that is rolled back if c is canceled
+func (ctx *EnhancedDbContext) WithTransactionContext(c context.Context, fn func(tx *EnhancedDbContext) error) error {
+	if ctx.tx != nil {
+		return fn(ctx)
+	}
+	if ctx.db == nil {
+		return errors.New("no database connection")
+	}
+
+	tx, err := ctx.db.BeginTx(c, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	txCtx := ctx.derive()
+	txCtx.tx = tx
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(txCtx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
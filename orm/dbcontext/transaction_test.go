@@ -0,0 +1,73 @@
+package dbcontext
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithTransaction(t *testing.T) {
+	ctx := newTestContext(t)
+	ctx.driver = driverPostgres
+	users := NewEnhancedDbSet[testUser](ctx)
+
+	err := ctx.WithTransaction(func(tx *EnhancedDbContext) error {
+		if tx.driver != driverPostgres {
+			t.Errorf("Expected the driver to be propagated, got %s", tx.driver)
+		}
+		tx.Add(&testUser{Email: "ana@example.com", Name: "Ana"})
+		_, err := tx.SaveChanges()
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction failed: %v", err)
+	}
+	if count, _ := users.Count(); count != 1 {
+		t.Errorf("Expected the insert to be committed, got %d users", count)
+	}
+
+	errFailed := errors.New("payment declined")
+	err = ctx.WithTransaction(func(tx *EnhancedDbContext) error {
+		tx.Add(&testUser{Email: "budi@example.com", Name: "Budi"})
+		if _, err := tx.SaveChanges(); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("Expected the error of fn, got %v", err)
+	}
+	if count, _ := users.Count(); count != 1 {
+		t.Errorf("Expected the insert to be rolled back, got %d users", count)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Expected the panic to be propagated, got %v", r)
+			}
+		}()
+		_ = ctx.WithTransaction(func(tx *EnhancedDbContext) error {
+			tx.Add(&testUser{Email: "citra@example.com", Name: "Citra"})
+			if _, err := tx.SaveChanges(); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+	if count, _ := users.Count(); count != 1 {
+		t.Errorf("Expected the insert to be rolled back on panic, got %d users", count)
+	}
+}
+
+func TestNewEnhancedDbContextWithTxDetectsDriver(t *testing.T) {
+	ctx := newTestContext(t)
+	tx, err := ctx.db.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if driver := NewEnhancedDbContextWithTx(tx).driver; driver != "sqlite3" {
+		t.Errorf("Expected sqlite3 to be detected, got %s", driver)
+	}
+}
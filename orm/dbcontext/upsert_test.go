@@ -0,0 +1,95 @@
+package dbcontext
+
+import "testing"
+
+func TestAddOrUpdate(t *testing.T) {
+	for _, driver := range []string{"sqlite3", driverPostgres} {
+		t.Run(driver, func(t *testing.T) {
+			ctx := newTestContext(t)
+			ctx.driver = driver
+			if _, err := ctx.db.Exec(`CREATE UNIQUE INDEX users_email ON users (email_address)`); err != nil {
+				t.Fatalf("Failed to create index: %v", err)
+			}
+
+			imported := &testUser{Email: "john@example.com", Name: "John"}
+			ctx.AddOrUpdate(imported, "email_address")
+			if _, err := ctx.SaveChanges(); err != nil {
+				t.Fatalf("SaveChanges failed: %v", err)
+			}
+			if imported.ID == 0 {
+				t.Fatal("Expected ID to be set after insert")
+			}
+			if state := ctx.ChangeTracker.GetEntityState(imported); state != EntityStateUnchanged {
+				t.Errorf("Expected state Unchanged, got %s", state)
+			}
+
+			// Importing the same email again updates the row
+			again := &testUser{Email: "john@example.com", Name: "John Smith"}
+			ctx.AddOrUpdate(again, "email_address")
+			if _, err := ctx.SaveChanges(); err != nil {
+				t.Fatalf("SaveChanges failed: %v", err)
+			}
+			if again.ID != imported.ID {
+				t.Errorf("Expected ID %d of the existing row, got %d", imported.ID, again.ID)
+			}
+			if !again.CreatedAt.Equal(imported.CreatedAt) {
+				t.Errorf("Expected CreatedAt %v to be kept, got %v", imported.CreatedAt, again.CreatedAt)
+			}
+
+			users, err := NewEnhancedDbSet[testUser](ctx).ToList()
+			if err != nil {
+				t.Fatalf("ToList failed: %v", err)
+			}
+			if len(users) != 1 || users[0].Name != "John Smith" {
+				t.Errorf("Expected a single updated user, got %+v", users)
+			}
+		})
+	}
+}
+
+func TestAddOrUpdateDefaultsToID(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`INSERT INTO users (id, email_address, name) VALUES (7, 'jane@example.com', 'Jane')`); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	ctx.AddOrUpdate(&testUser{ID: 7, Email: "jane@example.org", Name: "Jane"})
+	if _, err := ctx.SaveChanges(); err != nil {
+		t.Fatalf("SaveChanges failed: %v", err)
+	}
+
+	found, err := NewEnhancedDbSet[testUser](ctx).Find(7)
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if found == nil || found.Email != "jane@example.org" {
+		t.Errorf("Expected user 7 to be updated, got %+v", found)
+	}
+}
+
+// testPosition is an entity with reserved word columns
+type testPosition struct {
+	ID    int64  `db:"id"`
+	Key   string `db:"key"`
+	Order int    `db:"order"`
+}
+
+func (testPosition) TableName() string { return "positions" }
+
+func TestAddOrUpdateReservedWordColumns(t *testing.T) {
+	ctx := newTestContext(t)
+	if _, err := ctx.db.Exec(`CREATE TABLE positions (id INTEGER PRIMARY KEY AUTOINCREMENT, "key" TEXT UNIQUE, "order" INTEGER)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	for _, order := range []int{1, 2} {
+		ctx.AddOrUpdate(&testPosition{Key: "A-1", Order: order}, "key")
+		if _, err := ctx.SaveChanges(); err != nil {
+			t.Fatalf("SaveChanges failed: %v", err)
+		}
+	}
+	positions, err := NewEnhancedDbSet[testPosition](ctx).AsNoTracking().ToList()
+	if err != nil || len(positions) != 1 || positions[0].Order != 2 {
+		t.Errorf("Expected the position to be updated, got %+v, %v", positions, err)
+	}
+}
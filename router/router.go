@@ -21,9 +21,12 @@ package router
 
 import (
 	"net/http"
+	"slices"
 	"strings"
+	"sync/atomic"
 
 	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/health"
 )
 
 // HandlerFunc defines a function that processes requests using Context
@@ -32,33 +35,68 @@ type HandlerFunc func(*context.Context)
 // Middleware defines a function that runs before a request handler
 type Middleware func(HandlerFunc) HandlerFunc
 
+// Phase orders middleware independently of registration order. Middleware in a
+// lower phase wraps (runs before) middleware in a higher one; within a phase,
+// router middleware runs before group middleware, in registration order.
+// Any integer can be used as a numeric priority between the named phases.
+type Phase int
+
+// Named middleware phases
+const (
+	PhasePreRouting Phase = 100 // Request IDs, proxy headers, recovery, logging
+	PhaseDefault    Phase = 200 // Middleware added with Use
+	PhaseAuth       Phase = 300 // Authentication
+	PhasePostAuth   Phase = 400 // Authorization, per-user rate limits, auditing
+	PhaseResponse   Phase = 500 // Response transforms closest to the handler, e.g. ETags
+)
+
+// phasedMiddleware is a middleware with its phase
+type phasedMiddleware struct {
+	phase      Phase
+	middleware Middleware
+}
+
 // Route represents a URL route and its handler
 type Route struct {
 	Method  string
 	Path    string
 	Handler HandlerFunc
+	group   *Group // Group the route was registered on, if any
 }
 
 // Router handles HTTP requests and routes them to the appropriate handler
 type Router struct {
 	routes           []Route
-	middlewares      []Middleware
+	middlewares      []phasedMiddleware
+	notFound         HandlerFunc
+	methodNotAllowed HandlerFunc
+	prefix           string                      // Path prefix for the router
+	chains           atomic.Pointer[routeChains] // Handlers wrapped in their middleware, nil until the next request
+}
+
+// routeChains is a snapshot of the routes of a router with their handlers wrapped in
+// their ordered middleware, built once and reset when routes, handlers or middleware
+// change
+type routeChains struct {
+	routes           []Route       // Routes the chains were built for
+	handlers         []HandlerFunc // Chain of each route, by index
 	notFound         HandlerFunc
 	methodNotAllowed HandlerFunc
-	prefix           string // Path prefix for the router
 }
 
 // Group creates a new Router instance with a path prefix
 type Group struct {
-	router *Router // Parent router
-	prefix string  // Path prefix for this group
+	router      *Router            // Parent router
+	parent      *Group             // Enclosing group, nil for top-level groups
+	prefix      string             // Path prefix for this group
+	middlewares []phasedMiddleware // Middleware applied to routes of this group and its sub-groups
 }
 
 // New creates a new router
 func New() *Router {
 	return &Router{
 		routes:      []Route{},
-		middlewares: []Middleware{},
+		middlewares: []phasedMiddleware{},
 		notFound: func(c *context.Context) {
 			c.Error(http.StatusNotFound, "Not found")
 		},
@@ -69,18 +107,31 @@ func New() *Router {
 	}
 }
 
-// Use adds middleware to the router
+// Use adds middleware to the router in PhaseDefault
 func (r *Router) Use(middleware ...Middleware) {
-	r.middlewares = append(r.middlewares, middleware...)
+	r.UsePhase(PhaseDefault, middleware...)
+}
+
+// UsePhase adds middleware to the router in the given phase
+func (r *Router) UsePhase(phase Phase, middleware ...Middleware) {
+	r.middlewares = appendPhased(r.middlewares, phase, middleware)
+	r.chains.Store(nil)
 }
 
 // Handle registers a new route with the router
 func (r *Router) Handle(method, path string, handler HandlerFunc) {
+	r.handle(method, path, handler, nil)
+}
+
+// handle registers a route, remembering the group whose middleware applies to it
+func (r *Router) handle(method, path string, handler HandlerFunc, group *Group) {
 	r.routes = append(r.routes, Route{
 		Method:  method,
 		Path:    path,
 		Handler: handler,
+		group:   group,
 	})
+	r.chains.Store(nil)
 }
 
 // GET registers a new GET route
@@ -118,14 +169,27 @@ func (r *Router) OPTIONS(path string, handler HandlerFunc) {
 	r.Handle(http.MethodOptions, path, handler)
 }
 
+// MountHealth registers health endpoints under path and returns the Health to add checks to.
+// GET path/live runs the liveness checks; GET path and GET path/ready run the readiness checks.
+func (r *Router) MountHealth(path string) *health.Health {
+	h := health.New()
+	path = normalizePrefix(path)
+	r.GET(path, h.ReadinessHandler())
+	r.GET(path+"/live", h.LivenessHandler())
+	r.GET(path+"/ready", h.ReadinessHandler())
+	return h
+}
+
 // SetNotFound sets the not found handler
 func (r *Router) SetNotFound(handler HandlerFunc) {
 	r.notFound = handler
+	r.chains.Store(nil)
 }
 
 // SetMethodNotAllowed sets the method not allowed handler
 func (r *Router) SetMethodNotAllowed(handler HandlerFunc) {
 	r.methodNotAllowed = handler
+	r.chains.Store(nil)
 }
 
 // Group creates a new route group
@@ -136,60 +200,133 @@ func (r *Router) Group(prefix string) *Group {
 	}
 }
 
-// Use adds middleware to the group
+// Use adds middleware to the routes of the group and its sub-groups in PhaseDefault
 func (g *Group) Use(middleware ...Middleware) *Group {
-	g.router.middlewares = append(g.router.middlewares, middleware...)
+	return g.UsePhase(PhaseDefault, middleware...)
+}
+
+// UsePhase adds middleware to the routes of the group and its sub-groups in the given phase
+func (g *Group) UsePhase(phase Phase, middleware ...Middleware) *Group {
+	g.middlewares = appendPhased(g.middlewares, phase, middleware)
+	g.router.chains.Store(nil)
 	return g
 }
 
 // GET adds a GET route to the group
 func (g *Group) GET(path string, handler HandlerFunc) {
-	g.router.GET(g.prefix+path, handler)
+	g.Handle(http.MethodGet, path, handler)
 }
 
 // POST adds a POST route to the group
 func (g *Group) POST(path string, handler HandlerFunc) {
-	g.router.POST(g.prefix+path, handler)
+	g.Handle(http.MethodPost, path, handler)
 }
 
 // PUT adds a PUT route to the group
 func (g *Group) PUT(path string, handler HandlerFunc) {
-	g.router.PUT(g.prefix+path, handler)
+	g.Handle(http.MethodPut, path, handler)
 }
 
 // DELETE adds a DELETE route to the group
 func (g *Group) DELETE(path string, handler HandlerFunc) {
-	g.router.DELETE(g.prefix+path, handler)
+	g.Handle(http.MethodDelete, path, handler)
 }
 
 // PATCH adds a PATCH route to the group
 func (g *Group) PATCH(path string, handler HandlerFunc) {
-	g.router.PATCH(g.prefix+path, handler)
+	g.Handle(http.MethodPatch, path, handler)
 }
 
 // HEAD adds a HEAD route to the group
 func (g *Group) HEAD(path string, handler HandlerFunc) {
-	g.router.HEAD(g.prefix+path, handler)
+	g.Handle(http.MethodHead, path, handler)
 }
 
 // OPTIONS adds an OPTIONS route to the group
 func (g *Group) OPTIONS(path string, handler HandlerFunc) {
-	g.router.OPTIONS(g.prefix+path, handler)
+	g.Handle(http.MethodOptions, path, handler)
 }
 
 // Handle adds a route with any method to the group
 func (g *Group) Handle(method, path string, handler HandlerFunc) {
-	g.router.Handle(method, g.prefix+path, handler)
+	g.router.handle(method, g.prefix+path, handler, g)
 }
 
 // Group creates a sub-group with a prefix appended to the current group's prefix
 func (g *Group) Group(prefix string) *Group {
 	return &Group{
 		router: g.router,
+		parent: g,
 		prefix: g.prefix + normalizePrefix(prefix),
 	}
 }
 
+// appendPhased adds middleware with a phase to a list
+func appendPhased(list []phasedMiddleware, phase Phase, middleware []Middleware) []phasedMiddleware {
+	for _, m := range middleware {
+		list = append(list, phasedMiddleware{phase: phase, middleware: m})
+	}
+	return list
+}
+
+// middlewareFor returns the middleware for a route ordered by phase: router middleware
+// first, then enclosing groups from the outermost in, each in registration order
+func (r *Router) middlewareFor(group *Group) []Middleware {
+	var groups []*Group
+	for g := group; g != nil; g = g.parent {
+		groups = append(groups, g)
+	}
+
+	phased := append([]phasedMiddleware(nil), r.middlewares...)
+	for i := len(groups) - 1; i >= 0; i-- {
+		phased = append(phased, groups[i].middlewares...)
+	}
+	slices.SortStableFunc(phased, func(a, b phasedMiddleware) int {
+		return int(a.phase - b.phase)
+	})
+
+	middleware := make([]Middleware, len(phased))
+	for i, m := range phased {
+		middleware[i] = m.middleware
+	}
+	return middleware
+}
+
+// routeChains returns the handlers wrapped in their middleware, building them on the
+// first request after a change. The chain of each group is built once.
+func (r *Router) routeChains() *routeChains {
+	if chains := r.chains.Load(); chains != nil {
+		return chains
+	}
+
+	byGroup := make(map[*Group]Middleware)
+	wrap := func(handler HandlerFunc, group *Group) HandlerFunc {
+		chain, ok := byGroup[group]
+		if !ok {
+			if middleware := r.middlewareFor(group); len(middleware) > 0 {
+				chain = Chain(middleware...)
+			}
+			byGroup[group] = chain
+		}
+		if chain == nil || handler == nil {
+			return handler
+		}
+		return chain(handler)
+	}
+
+	chains := &routeChains{
+		routes:           slices.Clone(r.routes),
+		handlers:         make([]HandlerFunc, len(r.routes)),
+		notFound:         wrap(r.notFound, nil),
+		methodNotAllowed: wrap(r.methodNotAllowed, nil),
+	}
+	for i, route := range chains.routes {
+		chains.handlers[i] = wrap(route.Handler, route.group)
+	}
+	r.chains.Store(chains)
+	return chains
+}
+
 // normalizePrefix ensures the prefix starts with / and doesn't end with /
 func normalizePrefix(prefix string) string {
 	if prefix == "" {
@@ -230,16 +367,18 @@ func pathMatch(routePath, requestPath string) (bool, map[string]string) {
 
 // ServeHTTP implements the http.Handler interface
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	chains := r.routeChains()
+
 	// Find route
 	var handler HandlerFunc
 	var params map[string]string
 
 	matchedPath := false
 
-	for _, route := range r.routes {
+	for i, route := range chains.routes {
 		if match, pathParams := pathMatch(route.Path, req.URL.Path); match {
 			if route.Method == req.Method {
-				handler = route.Handler
+				handler = chains.handlers[i]
 				params = pathParams
 				break
 			}
@@ -254,33 +393,39 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// If no handler was found but we matched some routes with a different method,
 	// it's a method not allowed. This ensures proper handling of method mismatches.
 	if handler == nil && matchedPath {
-		handler = r.methodNotAllowed
+		handler = chains.methodNotAllowed
 	}
 
 	// If no handler was found at all, use the not found handler
 	if handler == nil {
-		handler = r.notFound
+		handler = chains.notFound
 	}
 
-	// Create context
-	c := context.New(w, req)
+	// Create context; the writer records the status and size for middleware
+	c := context.New(context.NewResponseWriter(w), req)
 	c.Params = params
 
-	// Apply middlewares
-	if len(r.middlewares) > 0 {
-		handler = Chain(r.middlewares...)(handler)
-	}
-
-	// Execute handler
+	// Execute handler, already wrapped in its middleware
 	handler(c)
 }
 
-// Chain creates a chain of middleware
+// Chain creates a chain of middleware.
+// Once a middleware calls c.Abort(), the remaining middleware and the handler are skipped.
 func Chain(middlewares ...Middleware) Middleware {
 	return func(next HandlerFunc) HandlerFunc {
 		for i := len(middlewares) - 1; i >= 0; i-- {
-			next = middlewares[i](next)
+			next = middlewares[i](skipIfAborted(next))
 		}
 		return next
 	}
 }
+
+// skipIfAborted wraps a handler so it does not run after the chain was aborted
+func skipIfAborted(next HandlerFunc) HandlerFunc {
+	return func(c *context.Context) {
+		if c.IsAborted() {
+			return
+		}
+		next(c)
+	}
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/lamboktulussimamora/gra/context"
@@ -298,6 +299,66 @@ func TestChain(t *testing.T) {
 	}
 }
 
+func TestChainAbort(t *testing.T) {
+	handlerCalled := false
+	laterCalled := false
+
+	// A buggy middleware that keeps calling next after writing a response
+	authFailure := func(next HandlerFunc) HandlerFunc {
+		return func(c *context.Context) {
+			c.AbortWithError(http.StatusUnauthorized, "Invalid token")
+			next(c)
+		}
+	}
+	later := func(next HandlerFunc) HandlerFunc {
+		return func(c *context.Context) {
+			laterCalled = true
+			next(c)
+		}
+	}
+
+	chained := Chain(authFailure, later)(func(c *context.Context) {
+		handlerCalled = true
+		c.Success(http.StatusOK, "ok", nil)
+	})
+
+	w := httptest.NewRecorder()
+	chained(context.New(w, httptest.NewRequest("GET", "/test", nil)))
+
+	if laterCalled || handlerCalled {
+		t.Error("Expected the chain to stop after Abort")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestServeHTTPExposesResponseStatus(t *testing.T) {
+	var status int
+	var size int64
+
+	r := New()
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *context.Context) {
+			next(c)
+			status, size = c.StatusCode(), c.ResponseSize()
+		}
+	})
+	r.GET("/teapot", func(c *context.Context) {
+		c.Error(http.StatusTeapot, "short and stout")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/teapot", nil))
+
+	if status != http.StatusTeapot {
+		t.Errorf("Expected status %d, got %d", http.StatusTeapot, status)
+	}
+	if size != int64(w.Body.Len()) {
+		t.Errorf("Expected size %d, got %d", w.Body.Len(), size)
+	}
+}
+
 func TestServeHTTP(t *testing.T) {
 	r := New()
 
@@ -538,3 +599,145 @@ func assertParamsMatch(t *testing.T, expected, actual map[string]string) {
 		}
 	}
 }
+
+func TestMountHealth(t *testing.T) {
+	r := New()
+	h := r.MountHealth("/healthz/")
+	h.SetReady(false)
+
+	tests := map[string]int{
+		"/healthz":       http.StatusServiceUnavailable,
+		"/healthz/ready": http.StatusServiceUnavailable,
+		"/healthz/live":  http.StatusOK,
+	}
+	for path, expected := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != expected {
+			t.Errorf("Expected %s to return %d, got %d", path, expected, w.Code)
+		}
+	}
+}
+
+// recordingMiddleware appends name to order when it runs
+func recordingMiddleware(order *[]string, name string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *context.Context) {
+			*order = append(*order, name)
+			next(c)
+		}
+	}
+}
+
+func TestGroupMiddlewareIsScoped(t *testing.T) {
+	var order []string
+	r := New()
+	r.Use(recordingMiddleware(&order, "global"))
+
+	admin := r.Group("/admin")
+	admin.Use(recordingMiddleware(&order, "admin"))
+	admin.GET("/dashboard", func(c *context.Context) {})
+
+	reports := admin.Group("/reports")
+	reports.GET("/daily", func(c *context.Context) {})
+	reports.Use(recordingMiddleware(&order, "reports"))
+
+	r.GET("/public", func(c *context.Context) {})
+
+	tests := map[string][]string{
+		"/public":              {"global"},
+		"/admin/dashboard":     {"global", "admin"},
+		"/admin/reports/daily": {"global", "admin", "reports"},
+		"/missing":             {"global"},
+	}
+	for path, expected := range tests {
+		order = nil
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		if !slices.Equal(order, expected) {
+			t.Errorf("Expected %s to run %v, got %v", path, expected, order)
+		}
+	}
+}
+
+func TestMiddlewarePhases(t *testing.T) {
+	var order []string
+	r := New()
+	api := r.Group("/api")
+
+	api.UsePhase(PhaseAuth, recordingMiddleware(&order, "auth"))
+	api.UsePhase(PhaseResponse, recordingMiddleware(&order, "etag"))
+	r.Use(recordingMiddleware(&order, "logger"))
+	r.UsePhase(PhasePostAuth, recordingMiddleware(&order, "audit"))
+	api.UsePhase(PhasePreRouting, recordingMiddleware(&order, "request-id"))
+	r.UsePhase(PhaseAuth+1, recordingMiddleware(&order, "after-auth"))
+	api.Use(recordingMiddleware(&order, "cors"))
+
+	api.GET("/orders", func(c *context.Context) {
+		order = append(order, "handler")
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+
+	expected := []string{"request-id", "logger", "cors", "auth", "after-auth", "audit", "etag", "handler"}
+	if !slices.Equal(order, expected) {
+		t.Errorf("Expected %v, got %v", expected, order)
+	}
+}
+
+func TestMiddlewareChainIsCached(t *testing.T) {
+	var order []string
+	builds := 0
+	counting := func(next HandlerFunc) HandlerFunc {
+		builds++
+		return next
+	}
+
+	r := New()
+	r.Use(counting)
+	api := r.Group("/api")
+	api.GET("/orders", func(c *context.Context) {
+		order = append(order, "handler")
+	})
+	api.GET("/invoices", func(c *context.Context) {})
+
+	serve := func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+	}
+	serve()
+	built := builds
+	serve()
+	serve()
+	if builds != built {
+		t.Errorf("Expected the chains to be built once, got %d builds after %d", builds, built)
+	}
+
+	// Middleware added later applies to the next requests
+	api.UsePhase(PhasePreRouting, recordingMiddleware(&order, "request-id"))
+	order = nil
+	serve()
+	if expected := []string{"request-id", "handler"}; !slices.Equal(order, expected) {
+		t.Errorf("Expected %v, got %v", expected, order)
+	}
+	if builds == built {
+		t.Error("Expected the chains to be rebuilt after UsePhase")
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() { _ = r.routeChains() }); allocs != 0 {
+		t.Errorf("Expected cached chains to be returned without allocating, got %v allocations", allocs)
+	}
+}
+
+func TestRoutesMatchTheirChainSnapshot(t *testing.T) {
+	r := New()
+	r.Use(func(next HandlerFunc) HandlerFunc { return next })
+	r.GET("/orders", func(c *context.Context) {})
+	r.routeChains()
+
+	// A route added while a request holds the previous snapshot
+	r.routes = append(r.routes, Route{Method: http.MethodGet, Path: "/invoices", Handler: func(c *context.Context) {}})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/invoices", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected the snapshot without the route to answer %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/lamboktulussimamora/gra"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// templateDescriptions describes the available project templates
+var templateDescriptions = map[string]string{
+	"basic": "Minimal JSON API with logging, recovery and validation",
+	"full":  "Auth, versioning, caching, validation, ORM with migrations, health checks, metrics and graceful shutdown",
+}
+
+// ErrDirectoryNotEmpty is returned when the target directory already contains files
+var ErrDirectoryNotEmpty = errors.New("target directory is not empty (use --force to overwrite)")
+
+// Options configures project generation
+type Options struct {
+	Name     string // Project name
+	Dir      string // Output directory
+	Module   string // Go module path of the generated project
+	Template string // Template name
+	Replace  string // Optional local path to the gra module
+	Force    bool   // Allow writing into a non-empty directory
+}
+
+// templateData is passed to every template file
+type templateData struct {
+	Name       string
+	Module     string
+	GraVersion string
+	Replace    string
+}
+
+// TemplateNames returns the available template names in sorted order
+func TemplateNames() []string {
+	names := make([]string, 0, len(templateDescriptions))
+	for name := range templateDescriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate renders the selected template into opts.Dir and returns the written files
+func Generate(opts Options) ([]string, error) {
+	if _, ok := templateDescriptions[opts.Template]; !ok {
+		return nil, fmt.Errorf("unknown template %q (available: %s)", opts.Template, strings.Join(TemplateNames(), ", "))
+	}
+
+	if err := ensureTargetDir(opts.Dir, opts.Force); err != nil {
+		return nil, err
+	}
+
+	replace := opts.Replace
+	if replace != "" {
+		abs, err := filepath.Abs(replace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve replace path: %w", err)
+		}
+		replace = filepath.ToSlash(abs)
+	}
+
+	data := templateData{
+		Name:       opts.Name,
+		Module:     opts.Module,
+		GraVersion: "v" + gra.Version,
+		Replace:    replace,
+	}
+
+	root := path.Join("templates", opts.Template)
+	var written []string
+
+	err := fs.WalkDir(templatesFS, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel := strings.TrimSuffix(strings.TrimPrefix(name, root+"/"), ".tmpl")
+		content, err := renderTemplate(name, data)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(opts.Dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0o600); err != nil {
+			return err
+		}
+
+		written = append(written, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return written, nil
+}
+
+// renderTemplate executes a single template file and gofmts Go sources
+func renderTemplate(name string, data templateData) ([]byte, error) {
+	raw, err := templatesFS.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(path.Base(name)).Parse(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	if strings.HasSuffix(name, ".go.tmpl") {
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("generated invalid Go source for %s: %w", name, err)
+		}
+		return formatted, nil
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ensureTargetDir creates the output directory or verifies it is empty
+func ensureTargetDir(dir string, force bool) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return os.MkdirAll(dir, 0o750)
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 && !force {
+		return ErrDirectoryNotEmpty
+	}
+	return nil
+}
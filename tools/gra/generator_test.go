@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTemplates(t *testing.T) {
+	for _, name := range TemplateNames() {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "app")
+			files, err := Generate(Options{
+				Name:     "app",
+				Dir:      dir,
+				Module:   "example.com/app",
+				Template: name,
+			})
+			if err != nil {
+				t.Fatalf("Generate returned error: %v", err)
+			}
+			if len(files) == 0 {
+				t.Fatal("Expected generated files")
+			}
+
+			fset := token.NewFileSet()
+			for _, file := range files {
+				path := filepath.Join(dir, file)
+				content, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", file, err)
+				}
+				if strings.Contains(string(content), "{{") {
+					t.Errorf("Unrendered template action in %s", file)
+				}
+				if strings.HasSuffix(file, ".go") {
+					if _, err := parser.ParseFile(fset, path, content, parser.AllErrors); err != nil {
+						t.Errorf("Generated %s does not parse: %v", file, err)
+					}
+				}
+			}
+
+			gomod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(gomod), "module example.com/app") {
+				t.Errorf("Unexpected go.mod: %s", gomod)
+			}
+		})
+	}
+}
+
+func TestGenerateRejectsUnknownTemplate(t *testing.T) {
+	_, err := Generate(Options{Name: "app", Dir: t.TempDir(), Template: "nope"})
+	if err == nil {
+		t.Fatal("Expected error for unknown template")
+	}
+}
+
+func TestGenerateRefusesNonEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "existing.txt"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Generate(Options{Name: "app", Dir: dir, Module: "app", Template: "basic"})
+	if !errors.Is(err, ErrDirectoryNotEmpty) {
+		t.Fatalf("Expected ErrDirectoryNotEmpty, got %v", err)
+	}
+
+	if _, err := Generate(Options{Name: "app", Dir: dir, Module: "app", Template: "basic", Force: true}); err != nil {
+		t.Fatalf("Expected --force to allow generation, got %v", err)
+	}
+}
+
+func TestReorderArgs(t *testing.T) {
+	got := reorderArgs([]string{"myapp", "--template", "full", "--force"})
+	want := []string{"--template", "full", "--force", "myapp"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,122 @@
+// GRA command line tool
+// Scaffolds new applications wired to the GRA framework
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lamboktulussimamora/gra"
+)
+
+const usage = `GRA command line tool
+
+Usage:
+  gra new [flags] <name>    Create a new project in ./<name>
+  gra templates             List the available project templates
+  gra version               Print the framework version
+
+Flags for "new":
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "new":
+		if err := runNew(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	case "templates":
+		for _, name := range TemplateNames() {
+			fmt.Printf("  %-8s %s\n", name, templateDescriptions[name])
+		}
+	case "version":
+		fmt.Printf("gra %s\n", gra.Version)
+	case "help", "-h", "--help":
+		printUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", os.Args[1])
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+// newFlagSet defines the flags accepted by the "new" command
+func newFlagSet(opts *Options) *flag.FlagSet {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	fs.StringVar(&opts.Template, "template", "basic", "Project template (basic, full)")
+	fs.StringVar(&opts.Module, "module", "", "Go module path (default: project name)")
+	fs.StringVar(&opts.Replace, "replace", "", "Path to a local gra checkout to use via a replace directive")
+	fs.BoolVar(&opts.Force, "force", false, "Write into an existing non-empty directory")
+	return fs
+}
+
+// runNew handles the "new" command
+func runNew(args []string) error {
+	var opts Options
+	fs := newFlagSet(&opts)
+	if err := fs.Parse(reorderArgs(args)); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected exactly one project name, got %d", fs.NArg())
+	}
+
+	opts.Name = filepath.Base(fs.Arg(0))
+	opts.Dir = fs.Arg(0)
+	if opts.Module == "" {
+		opts.Module = opts.Name
+	}
+
+	files, err := Generate(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Created %s using the %q template\n", opts.Dir, opts.Template)
+	for _, file := range files {
+		fmt.Printf("   %s\n", file)
+	}
+	fmt.Printf("\nNext steps:\n   cd %s\n   go mod tidy\n   go run .\n", opts.Dir)
+	return nil
+}
+
+// reorderArgs moves flags before positional arguments so that
+// "gra new myapp --template full" works like "gra new --template full myapp"
+func reorderArgs(args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) > 1 && arg[0] == '-' {
+			flags = append(flags, arg)
+			// Flags given as "--name value" carry their value in the next argument
+			if !strings.ContainsRune(arg, '=') && !isBoolFlag(arg) && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	return append(flags, positional...)
+}
+
+// isBoolFlag reports whether a flag takes no value
+func isBoolFlag(arg string) bool {
+	return arg == "-force" || arg == "--force"
+}
+
+// printUsage prints the command line help
+func printUsage() {
+	fmt.Fprint(os.Stderr, usage)
+	newFlagSet(&Options{}).PrintDefaults()
+}
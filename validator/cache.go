@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// typeRules holds the parsed validation tags of a struct type
+type typeRules struct {
+	fields      []fieldRule
+	validatable bool // Whether the struct or a pointer to it implements Validatable
+}
+
+// fieldRule holds the parsed validation tags of a struct field
+type fieldRule struct {
+	index    int
+	embedded bool
+	name     string // JSON name
+	label    string
+	rules    []Rule
+	groups   []string
+	grouped  bool // Whether the field is limited to groups
+	enum     bool // Whether the field type implements Enum
+	valuer   bool // Whether the field type implements ValueValidator
+}
+
+// rulesCache caches typeRules by struct type, so tags are parsed once per type
+var rulesCache sync.Map
+
+var (
+	validatableType = reflect.TypeOf((*Validatable)(nil)).Elem()
+	enumType        = reflect.TypeOf((*Enum)(nil)).Elem()
+	valuerType      = reflect.TypeOf((*ValueValidator)(nil)).Elem()
+)
+
+// rulesFor returns the parsed validation tags of a struct type
+func rulesFor(t reflect.Type) *typeRules {
+	if cached, ok := rulesCache.Load(t); ok {
+		return cached.(*typeRules)
+	}
+
+	cached, _ := rulesCache.LoadOrStore(t, parseTypeRules(t))
+	return cached.(*typeRules)
+}
+
+// parseTypeRules parses the tags of the embedded structs and of the fields with a
+// json tag that have a validate tag or a type implementing Enum or ValueValidator
+func parseTypeRules(t reflect.Type) *typeRules {
+	var v Validator
+	rules := &typeRules{validatable: reflect.PointerTo(t).Implements(validatableType)}
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+
+		if fieldType.Anonymous {
+			rules.fields = append(rules.fields, fieldRule{index: i, embedded: true})
+			continue
+		}
+
+		tag := fieldType.Tag.Get("json")
+		validateTag := fieldType.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		field := fieldRule{
+			index:  i,
+			name:   jsonName(tag),
+			label:  fieldType.Tag.Get("label"),
+			enum:   fieldType.IsExported() && implements(fieldType.Type, enumType),
+			valuer: fieldType.IsExported() && implements(fieldType.Type, valuerType),
+		}
+		if validateTag == "" && !field.enum && !field.valuer {
+			continue
+		}
+		for _, text := range v.parseValidationRules(validateTag) {
+			rule := parseRule(text)
+			if rule.Name == RuleGroups {
+				field.groups = strings.Fields(rule.Param)
+				field.grouped = true
+				continue
+			}
+			field.rules = append(field.rules, rule)
+		}
+		rules.fields = append(rules.fields, field)
+	}
+	return rules
+}
+
+// jsonName returns the member name of a json tag
+func jsonName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
+
+// implements reports whether t or a pointer to t implements iface
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// valueAs returns field as an interface value, copying it so methods with pointer
+// receivers can be called on unaddressable values
+func valueAs(field reflect.Value) any {
+	if field.Kind() == reflect.Ptr {
+		return field.Interface()
+	}
+
+	ptr := reflect.New(field.Type())
+	ptr.Elem().Set(field)
+	return ptr.Interface()
+}
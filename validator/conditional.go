@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// validateRequiredWith requires a field when any of the space separated sibling
+// fields is set, e.g. `validate:"required_with=street city"`
+func (v *Validator) validateRequiredWith(parent, field reflect.Value, fieldName, others, customMessage string) {
+	names := strings.Fields(others)
+	for _, name := range names {
+		if sibling, ok := siblingField(parent, name); ok && hasValue(sibling) {
+			if !hasValue(field) {
+				v.fail(fieldName, MessageRequiredWith, map[string]any{"fields": names}, customMessage)
+			}
+			return
+		}
+	}
+}
+
+// validateRequiredWithoutAll requires a field when none of the space separated
+// sibling fields is set, e.g. `validate:"required_without_all=email phone"`
+func (v *Validator) validateRequiredWithoutAll(parent, field reflect.Value, fieldName, others, customMessage string) {
+	names := strings.Fields(others)
+	for _, name := range names {
+		if sibling, ok := siblingField(parent, name); ok && hasValue(sibling) {
+			return
+		}
+	}
+
+	if !hasValue(field) {
+		v.fail(fieldName, MessageRequiredWithoutAll, map[string]any{"fields": names}, customMessage)
+	}
+}
+
+// validateExcludedIf requires a field to be empty when a sibling field has the
+// given value, e.g. `validate:"excluded_if=type personal"`
+func (v *Validator) validateExcludedIf(parent, field reflect.Value, fieldName, condition, customMessage string) {
+	name, value, _ := strings.Cut(strings.TrimSpace(condition), " ")
+	sibling, ok := siblingField(parent, name)
+	if ok && sibling.Kind() == reflect.Ptr && !sibling.IsNil() {
+		sibling = sibling.Elem()
+	}
+	if !ok || fmt.Sprint(sibling.Interface()) != strings.TrimSpace(value) {
+		return
+	}
+
+	if hasValue(field) {
+		v.fail(fieldName, MessageExcludedIf, map[string]any{"other": name, "value": strings.TrimSpace(value)}, customMessage)
+	}
+}
+
+// siblingField finds a field of parent by its JSON name or Go name, looking into
+// embedded structs
+func siblingField(parent reflect.Value, name string) (reflect.Value, bool) {
+	if parent.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	typ := parent.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		fieldType := typ.Field(i)
+		field := parent.Field(i)
+
+		if fieldType.Anonymous {
+			if embedded, ok := structValue(field); ok {
+				if sibling, ok := siblingField(embedded, name); ok {
+					return sibling, true
+				}
+			}
+			continue
+		}
+
+		if jsonName(fieldType.Tag.Get("json")) == name || fieldType.Name == name {
+			return field, fieldType.IsExported()
+		}
+	}
+	return reflect.Value{}, false
+}
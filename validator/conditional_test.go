@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"testing"
+)
+
+type contactRequest struct {
+	Type    string `json:"type"`
+	Email   string `json:"email" validate:"required_without_all=phone"`
+	Phone   string `json:"phone" validate:"required_without_all=email"`
+	Street  string `json:"street"`
+	City    string `json:"city" validate:"required_with=street"`
+	Company string `json:"company" validate:"excluded_if=type personal"`
+}
+
+// TestConditionalRules ensures conditional rules are evaluated against sibling fields
+func TestConditionalRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    contactRequest
+		expected []ValidationError
+	}{
+		{
+			name:  "Valid",
+			input: contactRequest{Type: "company", Email: testEmail, Street: testAddress, City: testCity, Company: "ACME"},
+		},
+		{
+			name:  "Phone instead of email",
+			input: contactRequest{Phone: "5550100"},
+		},
+		{
+			name:  "Neither email nor phone",
+			input: contactRequest{},
+			expected: []ValidationError{
+				{Field: fieldEmail, Message: "email is required when none of phone is set"},
+				{Field: fieldPhone, Message: "phone is required when none of email is set"},
+			},
+		},
+		{
+			name:  "Street without city",
+			input: contactRequest{Email: testEmail, Street: testAddress},
+			expected: []ValidationError{
+				{Field: fieldCity, Message: "city is required when street is set"},
+			},
+		},
+		{
+			name:  "Company for a personal contact",
+			input: contactRequest{Type: "personal", Email: testEmail, Company: "ACME"},
+			expected: []ValidationError{
+				{Field: "company", Message: "company must be empty when type is personal"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := New().Validate(tt.input)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf(msgErrorCount, len(tt.expected), len(errs), errs)
+			}
+			for i, err := range errs {
+				if err.Field != tt.expected[i].Field || err.Message != tt.expected[i].Message {
+					t.Errorf("Expected %v, got %v", tt.expected[i], err)
+				}
+			}
+		})
+	}
+}
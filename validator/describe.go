@@ -0,0 +1,94 @@
+package validator
+
+import "reflect"
+
+// Rule is a parsed validation rule, e.g. `min=3|Too short`
+type Rule struct {
+	Name    string `json:"name"`              // Rule name, e.g. "min"
+	Param   string `json:"param,omitempty"`   // Argument after "=", e.g. "3"
+	Message string `json:"message,omitempty"` // Custom error message after "|"
+}
+
+// FieldRule describes the validation rules of a struct field
+type FieldRule struct {
+	Field    string       `json:"field"`            // JSON path; items of struct slices use "[]", e.g. "items[].name"
+	Label    string       `json:"label,omitempty"`  // Display name from the label tag
+	Type     reflect.Type `json:"-"`                // Go type of the field
+	Required bool         `json:"required"`         // Whether the field has the required rule
+	Rules    []Rule       `json:"rules,omitempty"`  // Rules applied to the field; nil for nested structs
+	Groups   []string     `json:"groups,omitempty"` // Groups the field is limited to, see ValidateGroup
+}
+
+// Rule returns the rule with the given name
+func (f FieldRule) Rule(name string) (Rule, bool) {
+	for _, rule := range f.Rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Describe returns the validation rules of the fields of T, in declaration order,
+// for tools such as OpenAPI generators. Nested structs are listed before their fields.
+func Describe[T any]() []FieldRule {
+	return DescribeType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// DescribeType returns the validation rules of the fields of a struct type, or of
+// the struct a pointer type points to
+func DescribeType(t reflect.Type) []FieldRule {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := []FieldRule{}
+	describeFields(t, "", &fields)
+	return fields
+}
+
+// describeFields appends the rules of the fields of t, mirroring validateFields
+func describeFields(t reflect.Type, prefix string, fields *[]FieldRule) {
+	var v Validator
+
+	for _, rule := range rulesFor(t).fields {
+		fieldType := t.Field(rule.index).Type
+
+		if rule.embedded {
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				describeFields(fieldType, prefix, fields)
+			}
+			continue
+		}
+
+		field := FieldRule{
+			Field:  v.getFieldName(prefix, rule.name),
+			Label:  rule.label,
+			Type:   fieldType,
+			Groups: append([]string(nil), rule.groups...),
+		}
+
+		switch {
+		case fieldType.Kind() == reflect.Struct:
+			*fields = append(*fields, field)
+			describeFields(fieldType, field.Field, fields)
+		case isStructList(fieldType):
+			elem := fieldType.Elem()
+			if elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			*fields = append(*fields, field)
+			describeFields(elem, field.Field+"[]", fields)
+		default:
+			field.Rules = append([]Rule(nil), rule.rules...)
+			_, field.Required = field.Rule(RuleRequired)
+			*fields = append(*fields, field)
+		}
+	}
+}
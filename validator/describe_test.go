@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+type describedItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1,max=100|Quantity is too large"`
+}
+
+type describedOrder struct {
+	ID       int             `json:"id" validate:"required,groups=update"`
+	Email    string          `json:"email" label:"E-mail" validate:"required,email"`
+	Code     string          `json:"code" validate:"regexp=^[A-Z]{3}$"`
+	Items    []describedItem `json:"items" validate:"required"`
+	Internal string          `json:"-" validate:"required"`
+	Notes    string          `json:"notes"`
+}
+
+// TestDescribe ensures the parsed tag metadata matches the validation rules
+func TestDescribe(t *testing.T) {
+	fields := Describe[describedOrder]()
+
+	expected := []FieldRule{
+		{Field: "id", Type: reflect.TypeOf(0), Required: true, Rules: []Rule{{Name: RuleRequired}}, Groups: []string{"update"}},
+		{Field: fieldEmail, Label: "E-mail", Type: reflect.TypeOf(""), Required: true, Rules: []Rule{{Name: RuleRequired}, {Name: RuleEmail}}},
+		{Field: fieldCode, Type: reflect.TypeOf(""), Rules: []Rule{{Name: RuleRegexp, Param: "^[A-Z]{3}$"}}},
+		{Field: "items", Type: reflect.TypeOf([]describedItem{})},
+		{Field: "items[].sku", Type: reflect.TypeOf(""), Required: true, Rules: []Rule{{Name: RuleRequired}}},
+		{Field: "items[].quantity", Type: reflect.TypeOf(0), Rules: []Rule{{Name: RuleMin, Param: "1"}, {Name: RuleMax, Param: "100", Message: "Quantity is too large"}}},
+	}
+
+	if !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, fields)
+	}
+
+	rule, ok := fields[2].Rule(RuleRegexp)
+	if !ok || rule.Param != "^[A-Z]{3}$" {
+		t.Errorf("Expected regexp rule, got %+v", rule)
+	}
+}
+
+// TestDescribeType ensures pointers are dereferenced and non-structs are ignored
+func TestDescribeType(t *testing.T) {
+	if fields := DescribeType(reflect.TypeOf(&describedItem{})); len(fields) != 2 {
+		t.Errorf("Expected 2 fields, got %+v", fields)
+	}
+	if fields := DescribeType(reflect.TypeOf("")); fields != nil {
+		t.Errorf("Expected no fields for a string, got %+v", fields)
+	}
+}
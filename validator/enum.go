@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Enum is implemented by types with a fixed set of values, such as string
+// constants. Tagged fields of such types must hold one of the values.
+//
+//	type Status string
+//
+//	func (Status) Enum() []string { return []string{"active", "archived"} }
+type Enum interface {
+	Enum() []string
+}
+
+// enums holds the value sets registered with RegisterEnum, by name
+var (
+	enums      = make(map[string][]string)
+	enumsMutex sync.RWMutex
+)
+
+// RegisterEnum registers the values of an enum under name for the enumof rule,
+// so tags stay in sync with Go constants:
+//
+//	validator.RegisterEnum("status", StatusActive, StatusArchived)
+//
+//	Status Status `json:"status" validate:"enumof=status"`
+func RegisterEnum[T ~string](name string, values ...T) {
+	allowed := make([]string, len(values))
+	for i, value := range values {
+		allowed[i] = string(value)
+	}
+
+	enumsMutex.Lock()
+	enums[name] = allowed
+	enumsMutex.Unlock()
+}
+
+// validateEnumOf checks that a field holds a value of a registered enum
+func (v *Validator) validateEnumOf(field reflect.Value, fieldName, name, customMessage string) {
+	enumsMutex.RLock()
+	allowed, ok := enums[name]
+	enumsMutex.RUnlock()
+
+	if !ok {
+		v.addError(fieldName, fmt.Sprintf("unknown enum %s for %s", name, fieldName), customMessage)
+		return
+	}
+	v.checkEnum(field, fieldName, allowed, customMessage)
+}
+
+// validateEnumType checks that a field of a type implementing Enum holds one of
+// its values
+func (v *Validator) validateEnumType(field reflect.Value, fieldName string) {
+	if field.Kind() == reflect.Ptr && field.IsNil() {
+		return
+	}
+
+	enum := valueAs(field).(Enum)
+	v.checkEnum(reflect.Indirect(field), fieldName, enum.Enum(), "")
+}
+
+// checkEnum reports set fields whose value is not allowed
+func (v *Validator) checkEnum(field reflect.Value, fieldName string, allowed []string, customMessage string) {
+	if !hasValue(field) {
+		return
+	}
+
+	var value string
+	switch {
+	case field.Kind() == reflect.String:
+		value = field.String()
+	case field.CanInterface():
+		value = fmt.Sprint(field.Interface())
+	default:
+		return
+	}
+
+	if !slices.Contains(allowed, value) {
+		v.fail(fieldName, MessageEnum, map[string]any{"values": allowed}, customMessage)
+	}
+}
@@ -0,0 +1,73 @@
+package validator
+
+import (
+	"testing"
+)
+
+type testStatus string
+
+const (
+	testStatusActive   testStatus = "active"
+	testStatusArchived testStatus = "archived"
+)
+
+func (testStatus) Enum() []string {
+	return []string{string(testStatusActive), string(testStatusArchived)}
+}
+
+type testPriority string
+
+type enumRequest struct {
+	Status   testStatus   `json:"status" validate:"required"`
+	Priority testPriority `json:"priority" validate:"enumof=test_priority"`
+}
+
+// TestEnum ensures enum membership comes from Enum methods and registered enums
+func TestEnum(t *testing.T) {
+	RegisterEnum("test_priority", testPriority("low"), testPriority("high"))
+
+	tests := []struct {
+		name     string
+		input    enumRequest
+		expected []ValidationError
+	}{
+		{
+			name:  "Valid",
+			input: enumRequest{Status: testStatusArchived, Priority: "high"},
+		},
+		{
+			name:  "Invalid values",
+			input: enumRequest{Status: "deleted", Priority: "urgent"},
+			expected: []ValidationError{
+				{Field: "status", Message: "status must be one of: active, archived"},
+				{Field: "priority", Message: "priority must be one of: low, high"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := New().Validate(tt.input)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf(msgErrorCount, len(tt.expected), len(errs), errs)
+			}
+			for i, err := range errs {
+				if err.Field != tt.expected[i].Field || err.Message != tt.expected[i].Message {
+					t.Errorf("Expected %v, got %v", tt.expected[i], err)
+				}
+			}
+		})
+	}
+}
+
+// TestUnknownEnum ensures tags naming an unregistered enum are reported
+func TestUnknownEnum(t *testing.T) {
+	type request struct {
+		Kind string `json:"kind" validate:"enumof=unknown"`
+	}
+
+	errs := New().Validate(request{Kind: "a"})
+	if len(errs) != 1 || errs[0].Message != "unknown enum unknown for kind" {
+		t.Errorf("Expected an unknown enum error, got %v", errs)
+	}
+}
@@ -0,0 +1,168 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageKeyPrefix prefixes the catalog keys of rule messages, e.g. "validation.required"
+const MessageKeyPrefix = "validation."
+
+// Message keys of the built-in rules, looked up as MessageKeyPrefix + key. Messages
+// may use the {{field}} placeholder and the rule parameters {{min}}, {{max}}, {{values}},
+// {{fields}}, {{other}} and {{value}}.
+const (
+	MessageRequired  = "required"
+	MessageEmail     = "email"
+	MessageMin       = "min"
+	MessageMinLength = "min.string"
+	MessageMax       = "max"
+	MessageMaxLength = "max.string"
+	MessageRegexp    = "regexp"
+	MessageEnum      = "enum"
+	MessageRange     = "range"
+
+	MessageRequiredWith       = "required_with"
+	MessageRequiredWithoutAll = "required_without_all"
+	MessageExcludedIf         = "excluded_if"
+)
+
+// Error codes set on ValidationError.Code, stable across locales
+const (
+	CodeRequired           = "required"
+	CodeEmail              = "format.email"
+	CodePattern            = "format.pattern"
+	CodeMin                = "min"
+	CodeMax                = "max"
+	CodeEnum               = "enum"
+	CodeRange              = "range"
+	CodeRequiredWith       = "required_with"
+	CodeRequiredWithoutAll = "required_without_all"
+	CodeExcludedIf         = "excluded_if"
+	CodeType               = "type"
+	CodeOneOf              = "one_of"
+	CodeAnyOf              = "any_of"
+	CodeInvalid            = "invalid"      // A ValueValidator rejected the value
+	CodeInvalidRule        = "rule.invalid" // The validate tag itself is invalid
+)
+
+// messageCodes maps message keys to error codes where they differ
+var messageCodes = map[string]string{
+	MessageEmail:     CodeEmail,
+	MessageRegexp:    CodePattern,
+	MessageMinLength: CodeMin,
+	MessageMaxLength: CodeMax,
+}
+
+// defaultMessages are the English messages of the built-in rules
+var defaultMessages = map[string]string{
+	MessageRequired:  "{{field}} is required",
+	MessageEmail:     "{{field}} must be a valid email address",
+	MessageMin:       "{{field}} must be at least {{min}}",
+	MessageMinLength: "{{field}} must be at least {{min}} characters",
+	MessageMax:       "{{field}} must be at most {{max}}",
+	MessageMaxLength: "{{field}} must be at most {{max}} characters",
+	MessageRegexp:    "{{field}} has an invalid format",
+	MessageEnum:      "{{field}} must be one of: {{values}}",
+	MessageRange:     "{{field}} must be between {{min}} and {{max}}",
+
+	MessageRequiredWith:       "{{field}} is required when {{fields}} is set",
+	MessageRequiredWithoutAll: "{{field}} is required when none of {{fields}} is set",
+	MessageExcludedIf:         "{{field}} must be empty when {{other}} is {{value}}",
+}
+
+// Translator translates message keys for a locale, e.g. *i18n.Bundle. Keys without
+// a translation are expected to be returned unchanged.
+type Translator interface {
+	Translate(locale, key string, args ...any) string
+}
+
+// WithTranslator translates rule messages, custom messages and field labels into
+// locale. A single map[string]any argument carrying the field label and rule
+// parameters is passed to Translate, so catalogs can define messages such as
+// "validation.required": "{{field}} wajib diisi".
+func WithTranslator(translator Translator, locale string) Option {
+	return func(v *Validator) {
+		v.translator = translator
+		v.locale = locale
+	}
+}
+
+// WithMessages overrides the default messages of rules by message key, e.g.
+// {"required": "Please fill in {{field}}"}. Translations take precedence.
+func WithMessages(messages map[string]string) Option {
+	return func(v *Validator) {
+		v.messages = messages
+	}
+}
+
+// fail adds the error of a failed rule. The message is a custom message from the
+// validate tag, a translation, an override from WithMessages or the default message,
+// in that order, with placeholders filled from params and the field label. Lists
+// in params are joined with ", " for messages and kept as is in Params.
+func (v *Validator) fail(fieldName, key string, params map[string]any, customMsg string) {
+	args := map[string]any{"field": v.label(fieldName)}
+	for name, value := range params {
+		if list, ok := value.([]string); ok {
+			value = strings.Join(list, ", ")
+		}
+		args[name] = value
+	}
+
+	code, ok := messageCodes[key]
+	if !ok {
+		code = key
+	}
+
+	v.report(ValidationError{
+		Field:   fieldName,
+		Message: v.message(key, args, customMsg),
+		Code:    code,
+		Params:  params,
+	})
+}
+
+// message resolves the message of a failed rule
+func (v *Validator) message(key string, args map[string]any, customMsg string) string {
+	if customMsg != "" {
+		return render(v.translate(customMsg, args), args)
+	}
+	if v.translator != nil {
+		catalogKey := MessageKeyPrefix + key
+		if message := v.translator.Translate(v.locale, catalogKey, args); message != catalogKey {
+			return message
+		}
+	}
+	if message, ok := v.messages[key]; ok {
+		return render(message, args)
+	}
+	return render(defaultMessages[key], args)
+}
+
+// label returns the display name of a field, translating labels set with the label tag
+func (v *Validator) label(fieldName string) string {
+	label, ok := v.labels[fieldName]
+	if !ok {
+		return fieldName
+	}
+	return v.translate(label)
+}
+
+// translate translates key when a Translator is set
+func (v *Validator) translate(key string, args ...any) string {
+	if v.translator == nil {
+		return key
+	}
+	return v.translator.Translate(v.locale, key, args...)
+}
+
+// render fills {{name}} placeholders in message
+func render(message string, args map[string]any) string {
+	if !strings.Contains(message, "{{") {
+		return message
+	}
+	for name, value := range args {
+		message = strings.ReplaceAll(message, "{{"+name+"}}", fmt.Sprint(value))
+	}
+	return message
+}
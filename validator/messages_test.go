@@ -0,0 +1,135 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lamboktulussimamora/gra/i18n"
+)
+
+type labeledSignup struct {
+	Email    string `json:"email" label:"labels.email" validate:"required,email"`
+	Password string `json:"password" label:"Password" validate:"min=8"`
+	Nickname string `json:"nickname" validate:"required|errors.nickname"`
+}
+
+func newMessageBundle() *i18n.Bundle {
+	bundle := i18n.NewBundle("en")
+	bundle.AddMessages("en", map[string]string{"labels.email": "E-mail"})
+	bundle.AddMessages("id", map[string]string{
+		"labels.email":          "Surel",
+		"validation.required":   "{{field}} wajib diisi",
+		"validation.min.string": "{{field}} minimal {{min}} karakter",
+		"errors.nickname":       "Nama panggilan wajib diisi",
+	})
+	return bundle
+}
+
+func messagesByField(errs []ValidationError) map[string]string {
+	messages := make(map[string]string, len(errs))
+	for _, err := range errs {
+		messages[err.Field] = err.Message
+	}
+	return messages
+}
+
+func TestLabels(t *testing.T) {
+	errs := New().Validate(labeledSignup{Password: "short"})
+	messages := messagesByField(errs)
+
+	expected := map[string]string{
+		fieldEmail:    "labels.email is required",
+		fieldPassword: "Password must be at least 8 characters",
+		"nickname":    "errors.nickname",
+	}
+	for field, message := range expected {
+		if messages[field] != message {
+			t.Errorf("Expected %q for %s, got %q", message, field, messages[field])
+		}
+	}
+}
+
+func TestWithTranslator(t *testing.T) {
+	bundle := newMessageBundle()
+
+	tests := []struct {
+		locale   string
+		expected map[string]string
+	}{
+		{
+			locale: "id",
+			expected: map[string]string{
+				fieldEmail:    "Surel wajib diisi",
+				fieldPassword: "Password minimal 8 karakter",
+				"nickname":    "Nama panggilan wajib diisi",
+			},
+		},
+		{
+			// Rules without a translation keep the default message
+			locale: "en",
+			expected: map[string]string{
+				fieldEmail:    "E-mail is required",
+				fieldPassword: "Password must be at least 8 characters",
+				"nickname":    "errors.nickname",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			v := New(WithTranslator(bundle, tt.locale))
+			messages := messagesByField(v.Validate(labeledSignup{Password: "short"}))
+			for field, message := range tt.expected {
+				if messages[field] != message {
+					t.Errorf("Expected %q for %s, got %q", message, field, messages[field])
+				}
+			}
+		})
+	}
+}
+
+func TestWithMessages(t *testing.T) {
+	type product struct {
+		Name  string `json:"name" validate:"required"`
+		Price int    `json:"price" validate:"max=100"`
+	}
+
+	v := New(WithMessages(map[string]string{
+		MessageRequired: "Please fill in {{field}}",
+		MessageMax:      "{{field}} cannot exceed {{max}}",
+	}))
+	messages := messagesByField(v.Validate(product{Price: 500}))
+
+	if messages[fieldName] != "Please fill in name" {
+		t.Errorf("Expected overridden required message, got %q", messages[fieldName])
+	}
+	if messages[fieldPrice] != "price cannot exceed 100" {
+		t.Errorf("Expected overridden max message, got %q", messages[fieldPrice])
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	type order struct {
+		Email  string `json:"email" validate:"required,email"`
+		Code   string `json:"code" validate:"min=3"`
+		Status string `json:"status" validate:"enum=open"`
+		Phone  string `json:"phone" validate:"required_without_all=email"`
+		Pin    string `json:"pin" validate:"regexp=^[0-9]+$"`
+	}
+
+	errs := New(WithTranslator(newMessageBundle(), "id")).Validate(order{Email: "invalid", Code: "ab", Status: "closed", Pin: "abc"})
+	expected := []ValidationError{
+		{Field: fieldEmail, Code: CodeEmail},
+		{Field: fieldCode, Code: CodeMin, Params: map[string]any{"min": 3}},
+		{Field: "status", Code: CodeEnum, Params: map[string]any{"values": []string{"open"}}},
+		{Field: "pin", Code: CodePattern},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf(msgErrorCount, len(expected), len(errs), errs)
+	}
+	for i, err := range errs {
+		if err.Field != expected[i].Field || err.Code != expected[i].Code || !reflect.DeepEqual(err.Params, expected[i].Params) {
+			t.Errorf("Expected %s %s %v, got %s %s %v", expected[i].Field, expected[i].Code, expected[i].Params, err.Field, err.Code, err.Params)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"strconv"
+	"strings"
+)
+
+// pointerEscaper escapes JSON Pointer reference tokens as defined by RFC 6901
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// WithJSONPointer reports error fields as JSON Pointers, e.g. "/products/1/name"
+// instead of "products[1].name"
+func WithJSONPointer() Option {
+	return func(v *Validator) {
+		v.pointers = true
+	}
+}
+
+// fieldPath appends a member name to a path. Names containing ".", "[", "]", `"`
+// or `\` are quoted, e.g. `meta["app.version"]`, so paths stay unambiguous.
+func fieldPath(prefix, name string) string {
+	if strings.ContainsAny(name, `.[]"\`) {
+		return prefix + "[" + strconv.Quote(name) + "]"
+	}
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// indexPath appends an array index to a path, e.g. "products[1]"
+func indexPath(prefix string, index int) string {
+	return prefix + "[" + strconv.Itoa(index) + "]"
+}
+
+// JSONPointer converts an error field path such as `products[1].name` or
+// `meta["app.version"]` to a JSON Pointer such as "/products/1/name"
+func JSONPointer(path string) string {
+	var pointer strings.Builder
+	for path != "" {
+		var token string
+		switch {
+		case strings.HasPrefix(path, `["`):
+			quoted, err := strconv.QuotedPrefix(path[1:])
+			if err != nil {
+				token, path = path[1:], ""
+				break
+			}
+			token, _ = strconv.Unquote(quoted)
+			path = strings.TrimPrefix(path[1+len(quoted):], "]")
+		case path[0] == '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				token, path = path[1:], ""
+				break
+			}
+			token, path = path[1:end], path[end+1:]
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			token, path = path[:end], path[end:]
+		}
+		path = strings.TrimPrefix(path, ".")
+
+		pointer.WriteString("/")
+		pointer.WriteString(pointerEscaper.Replace(token))
+	}
+	return pointer.String()
+}
@@ -0,0 +1,69 @@
+package validator
+
+import "testing"
+
+type lineItem struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type importPayload struct {
+	Products []*lineItem       `json:"products" validate:"required"`
+	Featured [2]lineItem       `json:"featured" validate:"required"`
+	Meta     map[string]string `json:"app.meta/v1" validate:"required"`
+}
+
+func TestJSONPointer(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"", ""},
+		{fieldName, "/name"},
+		{"address.city", "/address/city"},
+		{"products[1].name", "/products/1/name"},
+		{`meta["app.version"]`, "/meta/app.version"},
+		{`["a/b~c"].items[0]`, "/a~1b~0c/items/0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if pointer := JSONPointer(tt.path); pointer != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, pointer)
+			}
+		})
+	}
+}
+
+func TestFieldPaths(t *testing.T) {
+	payload := importPayload{Products: []*lineItem{{Name: "ok"}, {}, nil}}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name:     "dotted",
+			expected: []string{"products[1].name", "featured[0].name", "featured[1].name", `["app.meta/v1"]`},
+		},
+		{
+			name:     "json pointer",
+			opts:     []Option{WithJSONPointer()},
+			expected: []string{"/products/1/name", "/featured/0/name", "/featured/1/name", "/app.meta~1v1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := New(tt.opts...).Validate(payload)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf(msgErrorCount, len(tt.expected), len(errs), errs)
+			}
+			for i, err := range errs {
+				if err.Field != tt.expected[i] {
+					t.Errorf("Expected field %q, got %q", tt.expected[i], err.Field)
+				}
+			}
+		})
+	}
+}
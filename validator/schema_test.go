@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newWebhookSchema() *Schema {
+	address := NewSchema().
+		AddField(fieldCity, SchemaField{Type: "string", Required: true}).
+		AddField(fieldZipCode, SchemaField{Type: "string", Pattern: "^[0-9]{5}$"})
+
+	contact := SchemaField{
+		Type: "object",
+		OneOf: []SchemaField{
+			{Type: "object", Properties: NewSchema().AddField(fieldEmail, SchemaField{Type: "string", Required: true})},
+			{Type: "object", Properties: NewSchema().AddField(fieldPhone, SchemaField{Type: "string", Required: true})},
+		},
+	}
+
+	return NewSchema().
+		AddField("event", SchemaField{Type: "string", Required: true, Enum: []string{"created", "deleted"}}).
+		AddField("address", SchemaField{Type: "object", Required: true, Properties: address}).
+		AddField("tags", SchemaField{Type: "array", Items: &SchemaField{Type: "string", MaxLength: 5}}).
+		AddField("contact", contact).
+		AddField("id", SchemaField{AnyOf: []SchemaField{{Type: "string"}, {Type: "number"}}})
+}
+
+// TestSchemaNested ensures nested objects, array items and compositions are validated
+func TestSchemaNested(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]any
+		expected []ValidationError
+	}{
+		{
+			name: "Valid",
+			data: map[string]any{
+				"event":   "created",
+				"address": map[string]any{fieldCity: testCity, fieldZipCode: testZipCode},
+				"tags":    []any{"a", "b"},
+				"contact": map[string]any{fieldEmail: testEmail},
+				"id":      42,
+			},
+		},
+		{
+			name: "Invalid nested values",
+			data: map[string]any{
+				"event":   "created",
+				"address": map[string]any{fieldZipCode: "ABC"},
+				"tags":    []any{"short", "too long", 3},
+				"contact": map[string]any{fieldEmail: testEmail, fieldPhone: "5550100"},
+				"id":      true,
+			},
+			expected: []ValidationError{
+				{Field: "address.city", Message: "address.city is required"},
+				{Field: "address.zipCode", Message: "address.zipCode has an invalid format"},
+				{Field: "contact", Message: "contact must match exactly one of the allowed schemas"},
+				{Field: "id", Message: "id must match at least one of the allowed schemas"},
+				{Field: "tags[1]", Message: "tags[1] must be at most 5 characters"},
+				{Field: "tags[2]", Message: "tags[2] must be a string"},
+			},
+		},
+		{
+			name: "Missing required object",
+			data: map[string]any{"event": "created", "contact": map[string]any{}},
+			expected: []ValidationError{
+				{Field: "address", Message: "address is required"},
+				{Field: "contact", Message: "contact must match exactly one of the allowed schemas"},
+			},
+		},
+	}
+
+	schema := newWebhookSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.Validate(tt.data)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf(msgErrorCount, len(tt.expected), len(errs), errs)
+			}
+			for i, err := range errs {
+				if err.Field != tt.expected[i].Field || err.Message != tt.expected[i].Message {
+					t.Errorf("Expected %v, got %v", tt.expected[i], err)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaErrorCodes(t *testing.T) {
+	schema := NewSchema().
+		AddField(fieldName, SchemaField{Type: "string", Required: true}).
+		AddField(fieldAge, SchemaField{Type: "number", Min: 18}).
+		AddField("role", SchemaField{Type: "string", Enum: []string{"admin", "user"}})
+
+	errs := schema.Validate(map[string]any{fieldAge: 16, "role": "guest"})
+	expected := []ValidationError{
+		{Field: fieldAge, Code: CodeMin, Params: map[string]any{"min": 18.0}},
+		{Field: fieldName, Code: CodeRequired},
+		{Field: "role", Code: CodeEnum, Params: map[string]any{"values": []string{"admin", "user"}}},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf(msgErrorCount, len(expected), len(errs), errs)
+	}
+	for i, err := range errs {
+		if err.Field != expected[i].Field || err.Code != expected[i].Code || !reflect.DeepEqual(err.Params, expected[i].Params) {
+			t.Errorf("Expected %s %s %v, got %s %s %v", expected[i].Field, expected[i].Code, expected[i].Params, err.Field, err.Code, err.Params)
+		}
+	}
+}
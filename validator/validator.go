@@ -3,8 +3,11 @@ package validator
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -31,13 +34,22 @@ const (
 	RuleMax      = "max"
 	RuleRegexp   = "regexp"
 	RuleEnum     = "enum"
+	RuleEnumOf   = "enumof"
 	RuleRange    = "range"
+	RuleGroups   = "groups"
+
+	RuleRequiredWith       = "required_with"
+	RuleRequiredWithoutAll = "required_without_all"
+	RuleExcludedIf         = "excluded_if"
 )
 
 // Common validation patterns
 var (
 	// EmailRegex is a regex pattern for validating email addresses
 	EmailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+
+	// charClassRegex matches patterns defining a full string format like [chars]{min,max}
+	charClassRegex = regexp.MustCompile(`\[.*\]\{.*\}`)
 )
 
 // regexpCache caches compiled regular expressions to improve performance
@@ -73,22 +85,63 @@ func getCompiledRegexp(pattern string) (*regexp.Regexp, error) {
 	return regex, nil
 }
 
-// ValidationError represents a validation error for a specific field
+// ValidationError represents a validation error for a specific field. Code and
+// Params identify the failed rule, so clients can localize errors without parsing
+// Message, e.g. {"code": "min", "params": {"min": 3}}.
 type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
+	Field   string         `json:"field"`
+	Message string         `json:"message"`
+	Code    string         `json:"code,omitempty"`   // Failed rule, e.g. "required" or "format.email"
+	Params  map[string]any `json:"params,omitempty"` // Rule parameters, e.g. "min" or "values"
 }
 
 // Validator validates structs based on validate tags
 type Validator struct {
-	errors []ValidationError
+	errors     []ValidationError
+	labels     map[string]string
+	translator Translator
+	locale     string
+	messages   map[string]string
+	groups     []string
+	maxErrors  int
+	pointers   bool
+	workers    int
+}
+
+// Option configures a Validator
+type Option func(*Validator)
+
+// WithStopOnFirstError stops validating at the first error
+func WithStopOnFirstError() Option {
+	return WithMaxErrors(1)
+}
+
+// WithMaxErrors stops validating once n errors have been found; n <= 0 reports
+// every error
+func WithMaxErrors(n int) Option {
+	return func(v *Validator) {
+		v.maxErrors = n
+	}
+}
+
+// WithConcurrency makes ValidateBatch validate objects on up to n goroutines,
+// returning results in the same order. A translator set with WithTranslator must
+// be safe for concurrent use.
+func WithConcurrency(n int) Option {
+	return func(v *Validator) {
+		v.workers = n
+	}
 }
 
 // New creates a new validator
-func New() *Validator {
-	return &Validator{
+func New(opts ...Option) *Validator {
+	v := &Validator{
 		errors: []ValidationError{},
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
 // addError adds a validation error with support for custom message
@@ -98,16 +151,48 @@ func (v *Validator) addError(field, defaultMsg, customMsg string) {
 		message = customMsg
 	}
 
-	v.errors = append(v.errors, ValidationError{
+	v.report(ValidationError{
 		Field:   field,
 		Message: message,
+		Code:    CodeInvalidRule,
 	})
 }
 
+// report adds an error unless the error limit has been reached
+func (v *Validator) report(err ValidationError) {
+	if v.done() {
+		return
+	}
+	if v.pointers {
+		err.Field = JSONPointer(err.Field)
+	}
+	v.errors = append(v.errors, err)
+}
+
+// done reports whether the error limit has been reached, so validation can stop
+func (v *Validator) done() bool {
+	return v.maxErrors > 0 && len(v.errors) >= v.maxErrors
+}
+
 // Validate validates a struct using tags
 func (v *Validator) Validate(obj any) []ValidationError {
 	v.errors = []ValidationError{}
+	v.labels = nil
+	v.groups = nil
+	v.validateStruct("", obj)
+	return v.errors
+}
+
+// ValidateGroup validates a struct like Validate, also applying the fields whose
+// validate tag is limited to one of the given groups, e.g.
+// `validate:"required,groups=create"`. Fields may list several groups separated by
+// spaces, `groups=create update`; fields without groups are always validated.
+func (v *Validator) ValidateGroup(obj any, groups ...string) []ValidationError {
+	v.errors = []ValidationError{}
+	v.labels = nil
+	v.groups = groups
 	v.validateStruct("", obj)
+	v.groups = nil
 	return v.errors
 }
 
@@ -116,78 +201,155 @@ func (v *Validator) HasErrors() bool {
 	return len(v.errors) > 0
 }
 
-// validateStruct recursively validates a struct using validate tags
+// Validatable is implemented by structs that check invariants spanning several
+// fields. Its errors are merged into the results, with field names relative to the
+// struct.
+type Validatable interface {
+	Validate() []ValidationError
+}
+
+// validateStruct recursively validates a struct using validate tags, then its
+// Validate method if it implements Validatable
 func (v *Validator) validateStruct(prefix string, obj any) {
-	val := reflect.ValueOf(obj)
+	v.validateStructValue(prefix, reflect.ValueOf(obj))
+}
+
+// validateStructValue validates the struct held by val
+func (v *Validator) validateStructValue(prefix string, val reflect.Value) {
+	val, ok := structValue(val)
+	if !ok {
+		return
+	}
+
+	v.validateFields(prefix, val)
+	v.validateInvariants(prefix, val)
+}
 
+// structValue returns the struct held by val, dereferencing pointers
+func structValue(val reflect.Value) (reflect.Value, bool) {
 	if val.Kind() == reflect.Ptr {
 		val = val.Elem()
 	}
 
-	if val.Kind() != reflect.Struct {
+	return val, val.Kind() == reflect.Struct
+}
+
+// validateInvariants merges the errors of a Validatable struct under prefix
+func (v *Validator) validateInvariants(prefix string, val reflect.Value) {
+	if v.done() || !rulesFor(val.Type()).validatable {
 		return
 	}
 
-	typ := val.Type()
+	// Copy the struct so methods with pointer receivers are found too
+	ptr := reflect.New(val.Type())
+	ptr.Elem().Set(val)
+	validatable := ptr.Interface().(Validatable)
+
+	for _, err := range validatable.Validate() {
+		switch {
+		case prefix == "":
+		case err.Field == "":
+			err.Field = prefix
+		default:
+			err.Field = prefix + "." + err.Field
+		}
+		v.report(err)
+	}
+}
 
-	for i := 0; i < val.NumField(); i++ {
-		field := val.Field(i)
-		fieldType := typ.Field(i)
+// validateFields validates the tagged fields of a struct
+func (v *Validator) validateFields(prefix string, val reflect.Value) {
+	for _, rule := range rulesFor(val.Type()).fields {
+		if v.done() {
+			return
+		}
 
-		if fieldType.Anonymous {
-			// Handle embedded struct
-			v.validateStruct(prefix, field.Interface())
+		field := val.Field(rule.index)
+		if rule.embedded {
+			// Handle embedded struct. Its Validate method is promoted to the
+			// outer struct, so only its fields are validated here.
+			if embedded, ok := structValue(field); ok {
+				v.validateFields(prefix, embedded)
+			}
 			continue
 		}
 
-		// Process field if it has json tag
-		if tag := fieldType.Tag.Get("json"); tag != "" && tag != "-" {
-			fieldName := v.getFieldName(prefix, tag)
-			validateTag := fieldType.Tag.Get("validate")
+		if !v.inGroups(rule) {
+			continue
+		}
 
-			if validateTag == "" {
-				continue
+		fieldName := v.getFieldName(prefix, rule.name)
+		if rule.label != "" {
+			if v.labels == nil {
+				v.labels = make(map[string]string)
 			}
-
-			v.processField(field, fieldName, validateTag)
+			v.labels[fieldName] = rule.label
 		}
+
+		v.processField(val, field, fieldName, rule)
 	}
 }
 
 // getFieldName constructs the full field name with prefix if needed
 func (v *Validator) getFieldName(prefix, tag string) string {
-	fieldName := strings.Split(tag, ",")[0]
-	if prefix != "" {
-		fieldName = prefix + "." + fieldName
-	}
-	return fieldName
+	return fieldPath(prefix, jsonName(tag))
 }
 
-// processField handles validation for a specific field based on its kind
-func (v *Validator) processField(field reflect.Value, fieldName, validateTag string) {
+// processField handles validation for a specific field of parent based on its kind
+func (v *Validator) processField(parent, field reflect.Value, fieldName string, rule fieldRule) {
+	if rule.valuer {
+		v.validateValueType(field, fieldName)
+	}
+
 	// Handle struct fields
 	if field.Kind() == reflect.Struct {
-		v.validateStruct(fieldName, field.Interface())
+		v.validateStructValue(fieldName, field)
 		return
 	}
 
-	// Handle slice of structs
-	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Struct {
+	// Handle slices and arrays of structs
+	if isStructList(field.Type()) {
 		v.validateSliceOfStructs(field, fieldName)
 		return
 	}
 
-	// Parse and apply validation rules
-	rules := v.parseValidationRules(validateTag)
-	v.applyValidationRules(field, fieldName, rules)
+	if rule.enum {
+		v.validateEnumType(field, fieldName)
+	}
+	v.applyValidationRules(parent, field, fieldName, rule.rules)
+}
+
+// inGroups reports whether a field belongs to a group being validated
+func (v *Validator) inGroups(rule fieldRule) bool {
+	if !rule.grouped {
+		return true
+	}
+	for _, group := range rule.groups {
+		if slices.Contains(v.groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStructList reports whether t is a slice or array of structs or struct pointers
+func isStructList(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return false
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct
 }
 
-// validateSliceOfStructs validates each struct in a slice
+// validateSliceOfStructs validates each struct in a slice, reporting errors under
+// the item index, e.g. "products[1].name"
 func (v *Validator) validateSliceOfStructs(field reflect.Value, fieldName string) {
-	for j := 0; j < field.Len(); j++ {
-		item := field.Index(j)
-		itemFieldName := fmt.Sprintf("%s[%d]", fieldName, j)
-		v.validateStruct(itemFieldName, item.Interface())
+	for j := 0; j < field.Len() && !v.done(); j++ {
+		v.validateStructValue(indexPath(fieldName, j), field.Index(j))
 	}
 }
 
@@ -295,74 +457,84 @@ func (v *Validator) parseRegexpAsFirstRule(validateTag string) []string {
 	return rules
 }
 
-// applyValidationRules applies extracted rules to a field
-func (v *Validator) applyValidationRules(field reflect.Value, fieldName string, rules []string) {
+// applyValidationRules applies parsed rules to a field of parent
+func (v *Validator) applyValidationRules(parent, field reflect.Value, fieldName string, rules []Rule) {
 	for _, rule := range rules {
-		// Check for custom error message
-		parts := strings.Split(rule, "|")
-		ruleText := parts[0]
-
-		var customMessage string
-		if len(parts) > 1 {
-			customMessage = parts[1]
+		if v.done() {
+			return
 		}
 
-		v.validateField(field, fieldName, ruleText, customMessage)
+		v.validateField(parent, field, fieldName, rule)
 	}
 }
 
-// validateField validates a single field against a rule
-func (v *Validator) validateField(field reflect.Value, fieldName, rule, customMessage string) {
-	// Parse rule and arguments
-	parts := strings.SplitN(rule, "=", 2)
-	ruleName := parts[0]
+// parseRule splits a rule such as "min=3|Too short" into its name, argument and
+// custom error message
+func parseRule(rule string) Rule {
+	parts := strings.Split(rule, "|")
+	ruleText := parts[0]
 
-	var ruleArg string
+	var customMessage string
 	if len(parts) > 1 {
-		ruleArg = parts[1]
+		customMessage = parts[1]
 	}
 
-	// Apply the rule
-	switch ruleName {
+	name, arg, _ := strings.Cut(ruleText, "=")
+	return Rule{Name: name, Param: arg, Message: customMessage}
+}
+
+// validateField validates a single field of parent against a rule
+func (v *Validator) validateField(parent, field reflect.Value, fieldName string, rule Rule) {
+	switch rule.Name {
 	case RuleRequired:
-		v.validateRequired(field, fieldName, customMessage)
+		v.validateRequired(field, fieldName, rule.Message)
 	case RuleEmail:
-		v.validateEmail(field, fieldName, customMessage)
+		v.validateEmail(field, fieldName, rule.Message)
 	case RuleMin:
-		v.validateMin(field, fieldName, ruleArg, customMessage)
+		v.validateMin(field, fieldName, rule.Param, rule.Message)
 	case RuleMax:
-		v.validateMax(field, fieldName, ruleArg, customMessage)
+		v.validateMax(field, fieldName, rule.Param, rule.Message)
 	case RuleRegexp:
-		v.validateRegexp(field, fieldName, ruleArg, customMessage)
+		v.validateRegexp(field, fieldName, rule.Param, rule.Message)
 	case RuleEnum:
-		v.validateEnum(field, fieldName, ruleArg, customMessage)
+		v.validateEnum(field, fieldName, rule.Param, rule.Message)
+	case RuleEnumOf:
+		v.validateEnumOf(field, fieldName, rule.Param, rule.Message)
 	case RuleRange:
-		v.validateRange(field, fieldName, ruleArg, customMessage)
+		v.validateRange(field, fieldName, rule.Param, rule.Message)
+	case RuleRequiredWith:
+		v.validateRequiredWith(parent, field, fieldName, rule.Param, rule.Message)
+	case RuleRequiredWithoutAll:
+		v.validateRequiredWithoutAll(parent, field, fieldName, rule.Param, rule.Message)
+	case RuleExcludedIf:
+		v.validateExcludedIf(parent, field, fieldName, rule.Param, rule.Message)
 	}
 }
 
 // validateRequired checks if a field is not empty
 func (v *Validator) validateRequired(field reflect.Value, fieldName, customMessage string) {
-	isValid := true
+	if !hasValue(field) {
+		v.fail(fieldName, MessageRequired, nil, customMessage)
+	}
+}
 
+// hasValue reports whether a field is set, as checked by the required rule
+func hasValue(field reflect.Value) bool {
 	switch field.Kind() {
 	case reflect.String:
-		isValid = field.String() != ""
+		return field.String() != ""
 	case reflect.Ptr, reflect.Slice, reflect.Map:
-		isValid = !field.IsNil()
+		return !field.IsNil()
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		isValid = field.Int() != 0
+		return field.Int() != 0
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		isValid = field.Uint() != 0
+		return field.Uint() != 0
 	case reflect.Float32, reflect.Float64:
-		isValid = field.Float() != 0
+		return field.Float() != 0
 	case reflect.Bool:
-		isValid = field.Bool()
-	}
-
-	if !isValid {
-		v.addError(fieldName, fieldName+" is required", customMessage)
+		return field.Bool()
 	}
+	return true
 }
 
 // validateEmail checks if a field is a valid email
@@ -377,7 +549,7 @@ func (v *Validator) validateEmail(field reflect.Value, fieldName, customMessage
 	}
 
 	if !EmailRegex.MatchString(email) {
-		v.addError(fieldName, fieldName+" must be a valid email address", customMessage)
+		v.fail(fieldName, MessageEmail, nil, customMessage)
 	}
 }
 
@@ -385,31 +557,31 @@ func (v *Validator) validateEmail(field reflect.Value, fieldName, customMessage
 func (v *Validator) validateMin(field reflect.Value, fieldName, arg, customMessage string) {
 	switch field.Kind() {
 	case reflect.String:
-		minVal := 0
-		if _, err := fmt.Sscanf(arg, "%d", &minVal); err != nil {
+		minVal, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil {
 			v.addError(fieldName, fmt.Sprintf(InvalidMinValueMsg, arg), customMessage)
 			return
 		}
 		if len(field.String()) < minVal {
-			v.addError(fieldName, fmt.Sprintf("%s must be at least %d characters", fieldName, minVal), customMessage)
+			v.fail(fieldName, MessageMinLength, map[string]any{"min": minVal}, customMessage)
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		minVal := int64(0)
-		if _, err := fmt.Sscanf(arg, "%d", &minVal); err != nil {
+		minVal, err := parseInt(arg)
+		if err != nil {
 			v.addError(fieldName, fmt.Sprintf(InvalidMinValueMsg, arg), customMessage)
 			return
 		}
 		if field.Int() < minVal {
-			v.addError(fieldName, fmt.Sprintf("%s must be at least %d", fieldName, minVal), customMessage)
+			v.fail(fieldName, MessageMin, map[string]any{"min": minVal}, customMessage)
 		}
 	case reflect.Float32, reflect.Float64:
-		minVal := float64(0)
-		if _, err := fmt.Sscanf(arg, "%f", &minVal); err != nil {
+		minVal, err := parseFloat(arg)
+		if err != nil {
 			v.addError(fieldName, fmt.Sprintf(InvalidMinValueMsg, arg), customMessage)
 			return
 		}
 		if field.Float() < minVal {
-			v.addError(fieldName, fmt.Sprintf("%s must be at least %.6f", fieldName, minVal), customMessage)
+			v.fail(fieldName, MessageMin, map[string]any{"min": minVal}, customMessage)
 		}
 	}
 }
@@ -430,49 +602,49 @@ func (v *Validator) validateMax(field reflect.Value, fieldName, arg, customMessa
 
 // validateMaxString validates maximum string length
 func (v *Validator) validateMaxString(field reflect.Value, fieldName, arg, customMessage string) {
-	maxVal := 0
-	if _, err := fmt.Sscanf(arg, "%d", &maxVal); err != nil {
+	maxVal, err := strconv.Atoi(strings.TrimSpace(arg))
+	if err != nil {
 		v.addError(fieldName, fmt.Sprintf(InvalidMaxValueMsg, arg), customMessage)
 		return
 	}
 	if len(field.String()) > maxVal {
-		v.addError(fieldName, fmt.Sprintf("%s must be at most %d characters", fieldName, maxVal), customMessage)
+		v.fail(fieldName, MessageMaxLength, map[string]any{"max": maxVal}, customMessage)
 	}
 }
 
 // validateMaxInt validates maximum integer value
 func (v *Validator) validateMaxInt(field reflect.Value, fieldName, arg, customMessage string) {
-	maxVal := int64(0)
-	if _, err := fmt.Sscanf(arg, "%d", &maxVal); err != nil {
+	maxVal, err := parseInt(arg)
+	if err != nil {
 		v.addError(fieldName, fmt.Sprintf(InvalidMaxValueMsg, arg), customMessage)
 		return
 	}
 	if field.Int() > maxVal {
-		v.addError(fieldName, fmt.Sprintf("%s must be at most %d", fieldName, maxVal), customMessage)
+		v.fail(fieldName, MessageMax, map[string]any{"max": maxVal}, customMessage)
 	}
 }
 
 // validateMaxUint validates maximum unsigned integer value
 func (v *Validator) validateMaxUint(field reflect.Value, fieldName, arg, customMessage string) {
-	maxVal := uint64(0)
-	if _, err := fmt.Sscanf(arg, "%d", &maxVal); err != nil {
+	maxVal, err := parseUint(arg)
+	if err != nil {
 		v.addError(fieldName, fmt.Sprintf(InvalidMaxValueMsg, arg), customMessage)
 		return
 	}
 	if field.Uint() > maxVal {
-		v.addError(fieldName, fmt.Sprintf("%s must be at most %d", fieldName, maxVal), customMessage)
+		v.fail(fieldName, MessageMax, map[string]any{"max": maxVal}, customMessage)
 	}
 }
 
 // validateMaxFloat validates maximum float value
 func (v *Validator) validateMaxFloat(field reflect.Value, fieldName, arg, customMessage string) {
-	maxVal := float64(0)
-	if _, err := fmt.Sscanf(arg, "%f", &maxVal); err != nil {
+	maxVal, err := parseFloat(arg)
+	if err != nil {
 		v.addError(fieldName, fmt.Sprintf(InvalidMaxValueMsg, arg), customMessage)
 		return
 	}
 	if field.Float() > maxVal {
-		v.addError(fieldName, fmt.Sprintf("%s must be at most %g", fieldName, maxVal), customMessage)
+		v.fail(fieldName, MessageMax, map[string]any{"max": maxVal}, customMessage)
 	}
 }
 
@@ -529,8 +701,6 @@ func addAnchorsIfNeeded(pattern string) string {
 	if !strings.HasPrefix(pattern, "^") && !strings.HasSuffix(pattern, "$") {
 		// Only add anchors to patterns that look like they should have them
 		// i.e., patterns that define a full string format like [chars]{min,max}
-		charClassPattern := `\[.*\]\{.*\}`
-		charClassRegex := regexp.MustCompile(charClassPattern)
 		if charClassRegex.MatchString(pattern) {
 			return "^" + pattern + "$"
 		}
@@ -567,7 +737,7 @@ func (v *Validator) validateRegexp(field reflect.Value, fieldName, pattern, cust
 	}
 
 	if !regex.MatchString(value) {
-		v.addError(fieldName, fmt.Sprintf("%s has an invalid format", fieldName), customMessage)
+		v.fail(fieldName, MessageRegexp, nil, customMessage)
 	}
 }
 
@@ -585,16 +755,17 @@ func (v *Validator) validateEnum(field reflect.Value, fieldName, allowedValues,
 
 	// Split the allowed values by comma
 	allowed := strings.Split(allowedValues, ",")
+	for i := range allowed {
+		allowed[i] = strings.TrimSpace(allowed[i])
+	}
 
 	// Check if the value is in the allowed list
-	for _, allowedValue := range allowed {
-		if value == strings.TrimSpace(allowedValue) {
-			return // Value is allowed
-		}
+	if slices.Contains(allowed, value) {
+		return // Value is allowed
 	}
 
 	// Value is not in the allowed list
-	v.addError(fieldName, fmt.Sprintf("%s must be one of: %s", fieldName, allowedValues), customMessage)
+	v.fail(fieldName, MessageEnum, map[string]any{"values": allowed}, customMessage)
 }
 
 // validateIntRange validates that an int field is within the specified range
@@ -609,7 +780,7 @@ func (v *Validator) validateIntRange(field reflect.Value, fieldName, minStr, max
 
 	value := field.Int()
 	if value < minVal || value > maxVal {
-		v.addError(fieldName, fmt.Sprintf("%s must be between %d and %d", fieldName, minVal, maxVal), customMessage)
+		v.fail(fieldName, MessageRange, map[string]any{"min": minVal, "max": maxVal}, customMessage)
 	}
 }
 
@@ -625,7 +796,7 @@ func (v *Validator) validateUintRange(field reflect.Value, fieldName, minStr, ma
 
 	value := field.Uint()
 	if value < minVal || value > maxVal {
-		v.addError(fieldName, fmt.Sprintf("%s must be between %d and %d", fieldName, minVal, maxVal), customMessage)
+		v.fail(fieldName, MessageRange, map[string]any{"min": minVal, "max": maxVal}, customMessage)
 	}
 }
 
@@ -641,7 +812,7 @@ func (v *Validator) validateFloatRange(field reflect.Value, fieldName, minStr, m
 
 	value := field.Float()
 	if value < minVal || value > maxVal {
-		v.addError(fieldName, fmt.Sprintf("%s must be between %g and %g", fieldName, minVal, maxVal), customMessage)
+		v.fail(fieldName, MessageRange, map[string]any{"min": minVal, "max": maxVal}, customMessage)
 	}
 }
 
@@ -668,21 +839,15 @@ func (v *Validator) validateRange(field reflect.Value, fieldName, rangeValues, c
 
 // Helper functions for parsing numbers
 func parseInt(s string) (int64, error) {
-	var result int64
-	_, err := fmt.Sscanf(s, "%d", &result)
-	return result, err
+	return strconv.ParseInt(strings.TrimSpace(s), 10, 64)
 }
 
 func parseUint(s string) (uint64, error) {
-	var result uint64
-	_, err := fmt.Sscanf(s, "%d", &result)
-	return result, err
+	return strconv.ParseUint(strings.TrimSpace(s), 10, 64)
 }
 
 func parseFloat(s string) (float64, error) {
-	var result float64
-	_, err := fmt.Sscanf(s, "%f", &result)
-	return result, err
+	return strconv.ParseFloat(strings.TrimSpace(s), 64)
 }
 
 // BatchResult contains validation results for a batch of objects
@@ -691,9 +856,17 @@ type BatchResult struct {
 	Errors []ValidationError `json:"errors,omitempty"`
 }
 
-// ValidateBatch validates a slice of objects and returns validation results
+// ValidateBatch validates a slice of objects and returns validation results.
+// With an error limit, validation stops once the errors of the batch reach it and
+// only the results of the objects validated so far are returned.
 func (v *Validator) ValidateBatch(objects []any) []BatchResult {
+	if v.workers > 1 && len(objects) > 1 {
+		return v.validateBatchConcurrently(objects)
+	}
+
 	results := make([]BatchResult, len(objects))
+	limit := v.maxErrors
+	defer func() { v.maxErrors = limit }()
 
 	for i, obj := range objects {
 		errors := v.Validate(obj)
@@ -701,11 +874,56 @@ func (v *Validator) ValidateBatch(objects []any) []BatchResult {
 			Index:  i,
 			Errors: errors,
 		}
+
+		if limit > 0 {
+			v.maxErrors -= len(errors)
+			if v.maxErrors <= 0 {
+				return results[:i+1]
+			}
+		}
 	}
 
 	return results
 }
 
+// validateBatchConcurrently validates objects on a pool of workers, each using a
+// copy of the validator, then applies the error limit in batch order
+func (v *Validator) validateBatchConcurrently(objects []any) []BatchResult {
+	results := make([]BatchResult, len(objects))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(v.workers, len(objects)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := *v
+			for i := range indexes {
+				results[i] = BatchResult{Index: i, Errors: worker.Validate(objects[i])}
+			}
+		}()
+	}
+
+	for i := range objects {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if v.maxErrors <= 0 {
+		return results
+	}
+	remaining := v.maxErrors
+	for i := range results {
+		if len(results[i].Errors) >= remaining {
+			results[i].Errors = results[i].Errors[:remaining]
+			return results[:i+1]
+		}
+		remaining -= len(results[i].Errors)
+	}
+	return results
+}
+
 // HasBatchErrors returns true if any object in the batch has validation errors
 func (v *Validator) HasBatchErrors(results []BatchResult) bool {
 	for _, result := range results {
@@ -737,6 +955,11 @@ type SchemaField struct {
 	Max       float64
 	Pattern   string
 	Enum      []string
+
+	Properties *Schema       // Schema of the members of an object
+	Items      *SchemaField  // Schema of the items of an array
+	OneOf      []SchemaField // Alternatives of which the value must match exactly one
+	AnyOf      []SchemaField // Alternatives of which the value must match at least one
 }
 
 // Schema represents a validation schema
@@ -757,15 +980,24 @@ func (s *Schema) AddField(name string, field SchemaField) *Schema {
 	return s
 }
 
-// Validate validates data against the schema
+// Validate validates data against the schema. Errors in nested objects and
+// arrays are reported under paths such as "address.city" and "tags[1]".
 func (s *Schema) Validate(data map[string]any) []ValidationError {
 	errors := []ValidationError{}
+	s.validate("", data, &errors)
+	return errors
+}
 
-	for name, field := range s.Fields {
-		value, exists := data[name]
+// validate validates the members of an object under prefix
+func (s *Schema) validate(prefix string, data map[string]any, errors *[]ValidationError) {
+	for _, key := range slices.Sorted(maps.Keys(s.Fields)) {
+		field := s.Fields[key]
+		value, exists := data[key]
+
+		name := fieldPath(prefix, key)
 
 		// Process required fields
-		if s.handleRequiredField(name, field, exists, value, &errors) {
+		if s.handleRequiredField(name, field, exists, value, errors) {
 			continue
 		}
 
@@ -775,10 +1007,8 @@ func (s *Schema) Validate(data map[string]any) []ValidationError {
 		}
 
 		// Process field validation based on type
-		s.processFieldValidation(name, value, field, &errors)
+		s.processFieldValidation(name, value, field, errors)
 	}
-
-	return errors
 }
 
 // handleRequiredField checks if a required field exists
@@ -787,6 +1017,7 @@ func (s *Schema) handleRequiredField(name string, field SchemaField, exists bool
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: name + " is required",
+			Code:    CodeRequired,
 		})
 		return true
 	}
@@ -808,20 +1039,83 @@ func (s *Schema) processFieldValidation(name string, value any, field SchemaFiel
 		s.validateNumber(name, value, field, errors)
 	case "array":
 		s.validateArray(name, value, field, errors)
+	case "object":
+		if field.Properties != nil {
+			field.Properties.validate(name, value.(map[string]any), errors)
+		}
 	}
+
+	s.validateComposition(name, value, field, errors)
 }
 
 // validateArray handles array-specific validations
 func (s *Schema) validateArray(name string, value any, field SchemaField, errors *[]ValidationError) {
+	arr, ok := value.([]any)
+	if !ok {
+		return
+	}
+
 	// Basic array validation
-	if arr, ok := value.([]any); ok && field.MinLength > 0 && len(arr) < field.MinLength {
+	if field.MinLength > 0 && len(arr) < field.MinLength {
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: fmt.Sprintf("%s must have at least %d items", name, field.MinLength),
+			Code:    CodeMin,
+			Params:  map[string]any{"min": field.MinLength},
+		})
+	}
+
+	if field.Items == nil {
+		return
+	}
+	for i, item := range arr {
+		itemName := indexPath(name, i)
+		if item == nil {
+			if field.Items.Required {
+				*errors = append(*errors, ValidationError{Field: itemName, Message: itemName + " is required", Code: CodeRequired})
+			}
+			continue
+		}
+		s.processFieldValidation(itemName, item, *field.Items, errors)
+	}
+}
+
+// validateComposition checks the oneOf and anyOf alternatives of a field
+func (s *Schema) validateComposition(name string, value any, field SchemaField, errors *[]ValidationError) {
+	if len(field.OneOf) == 0 && len(field.AnyOf) == 0 {
+		return
+	}
+
+	if len(field.OneOf) > 0 && s.countMatches(name, value, field.OneOf) != 1 {
+		*errors = append(*errors, ValidationError{
+			Field:   name,
+			Message: fmt.Sprintf("%s must match exactly one of the allowed schemas", name),
+			Code:    CodeOneOf,
+		})
+	}
+
+	if len(field.AnyOf) > 0 && s.countMatches(name, value, field.AnyOf) == 0 {
+		*errors = append(*errors, ValidationError{
+			Field:   name,
+			Message: fmt.Sprintf("%s must match at least one of the allowed schemas", name),
+			Code:    CodeAnyOf,
 		})
 	}
 }
 
+// countMatches returns the number of alternatives the value is valid against
+func (s *Schema) countMatches(name string, value any, alternatives []SchemaField) int {
+	matches := 0
+	for _, alternative := range alternatives {
+		var errs []ValidationError
+		s.processFieldValidation(name, value, alternative, &errs)
+		if len(errs) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
 // validateType checks if a value matches the expected type
 func (s *Schema) validateType(name string, value any, expectedType string, errors *[]ValidationError) bool {
 	var valid bool
@@ -851,6 +1145,8 @@ func (s *Schema) validateType(name string, value any, expectedType string, error
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: fmt.Sprintf("%s must be a %s", name, expectedType),
+			Code:    CodeType,
+			Params:  map[string]any{"type": expectedType},
 		})
 	}
 
@@ -871,6 +1167,8 @@ func (s *Schema) validateStringLength(name, value string, field SchemaField, err
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: fmt.Sprintf("%s must be at least %d characters", name, field.MinLength),
+			Code:    CodeMin,
+			Params:  map[string]any{"min": field.MinLength},
 		})
 	}
 
@@ -879,6 +1177,8 @@ func (s *Schema) validateStringLength(name, value string, field SchemaField, err
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: fmt.Sprintf("%s must be at most %d characters", name, field.MaxLength),
+			Code:    CodeMax,
+			Params:  map[string]any{"max": field.MaxLength},
 		})
 	}
 }
@@ -894,6 +1194,7 @@ func (s *Schema) validateStringPattern(name, value string, field SchemaField, er
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: fmt.Sprintf("%s has an invalid format", name),
+			Code:    CodePattern,
 		})
 	}
 }
@@ -916,6 +1217,8 @@ func (s *Schema) validateStringEnum(name, value string, field SchemaField, error
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: fmt.Sprintf("%s must be one of: %v", name, field.Enum),
+			Code:    CodeEnum,
+			Params:  map[string]any{"values": field.Enum},
 		})
 	}
 }
@@ -940,6 +1243,8 @@ func (s *Schema) validateNumber(name string, value any, field SchemaField, error
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: fmt.Sprintf("%s must be at least %v", name, field.Min),
+			Code:    CodeMin,
+			Params:  map[string]any{"min": field.Min},
 		})
 	}
 
@@ -948,6 +1253,8 @@ func (s *Schema) validateNumber(name string, value any, field SchemaField, error
 		*errors = append(*errors, ValidationError{
 			Field:   name,
 			Message: fmt.Sprintf("%s must be at most %v", name, field.Max),
+			Code:    CodeMax,
+			Params:  map[string]any{"max": field.Max},
 		})
 	}
 }
@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"testing"
+)
+
+type benchAddress struct {
+	Street string `json:"street" validate:"required,max=100"`
+	City   string `json:"city" validate:"required"`
+	Zip    string `json:"zip" validate:"regexp=^[0-9]{5}$"`
+}
+
+type benchOrder struct {
+	Email     string         `json:"email" validate:"required,email"`
+	Name      string         `json:"name" validate:"required,min=2,max=50"`
+	Quantity  int            `json:"quantity" validate:"min=1,max=100"`
+	Address   benchAddress   `json:"address" validate:"required"`
+	Shipments []benchAddress `json:"shipments" validate:"required"`
+}
+
+func newBenchOrder() benchOrder {
+	address := benchAddress{Street: testAddress, City: testCity, Zip: testZipCode}
+	return benchOrder{
+		Email:     testUserEmail,
+		Name:      testName,
+		Quantity:  3,
+		Address:   address,
+		Shipments: []benchAddress{address, address, address},
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	valid := newBenchOrder()
+	invalid := benchOrder{Email: "invalid", Shipments: []benchAddress{{}, {}}}
+
+	b.Run("Valid", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New().Validate(valid)
+		}
+	})
+
+	b.Run("Invalid", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New().Validate(invalid)
+		}
+	})
+
+	b.Run("Parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				New().Validate(valid)
+			}
+		})
+	})
+}
+
+func BenchmarkValidateBatch(b *testing.B) {
+	objects := make([]any, 1000)
+	for i := range objects {
+		objects[i] = newBenchOrder()
+	}
+
+	b.Run("Sequential", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New().ValidateBatch(objects)
+		}
+	})
+
+	b.Run("Concurrent", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New(WithConcurrency(8)).ValidateBatch(objects)
+		}
+	})
+}
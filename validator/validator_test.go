@@ -1386,3 +1386,206 @@ func TestSimpleSliceValidation(t *testing.T) {
 		t.Errorf("Expected 2 errors for nil slices, got %d", len(errors))
 	}
 }
+
+type dateRange struct {
+	Start int `json:"start" validate:"required"`
+	End   int `json:"end" validate:"required"`
+}
+
+func (r *dateRange) Validate() []ValidationError {
+	if r.End < r.Start {
+		return []ValidationError{{Field: "end", Message: "end must be after start"}}
+	}
+	return nil
+}
+
+type booking struct {
+	Room   string    `json:"room" validate:"required"`
+	Period dateRange `json:"period" validate:"required"`
+}
+
+type embeddedBooking struct {
+	dateRange
+	Guest string `json:"guest" validate:"required"`
+}
+
+// TestValidatable ensures struct-level errors are merged under the struct's path
+func TestValidatable(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected []ValidationError
+	}{
+		{
+			name:     "Top level",
+			input:    &dateRange{Start: 5, End: 1},
+			expected: []ValidationError{{Field: "end", Message: "end must be after start"}},
+		},
+		{
+			name:     "Valid",
+			input:    dateRange{Start: 1, End: 5},
+			expected: nil,
+		},
+		{
+			name:  "Nested with field rules",
+			input: booking{Period: dateRange{Start: 5, End: 1}},
+			expected: []ValidationError{
+				{Field: "room", Message: "room is required"},
+				{Field: "period.end", Message: "end must be after start"},
+			},
+		},
+		{
+			name:     "Embedded runs once",
+			input:    embeddedBooking{dateRange: dateRange{Start: 5, End: 1}, Guest: testName},
+			expected: []ValidationError{{Field: "end", Message: "end must be after start"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := New().Validate(tt.input)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf(msgErrorCount, len(tt.expected), len(errs), errs)
+			}
+			for i, err := range errs {
+				if err.Field != tt.expected[i].Field || err.Message != tt.expected[i].Message {
+					t.Errorf("Expected %v, got %v", tt.expected[i], err)
+				}
+			}
+		})
+	}
+}
+
+type accountRequest struct {
+	ID       int    `json:"id" validate:"required,groups=update"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,groups=create reset"`
+}
+
+// TestValidateGroup ensures grouped rules only apply to their groups
+func TestValidateGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		groups   []string
+		expected []string
+	}{
+		{name: "No group", expected: []string{fieldEmail}},
+		{name: "Create", groups: []string{"create"}, expected: []string{fieldEmail, fieldPassword}},
+		{name: "Update", groups: []string{"update"}, expected: []string{"id", fieldEmail}},
+		{name: "Several groups", groups: []string{"update", "reset"}, expected: []string{"id", fieldEmail, fieldPassword}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			errs := v.ValidateGroup(accountRequest{}, tt.groups...)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf(msgErrorCount, len(tt.expected), len(errs), errs)
+			}
+			for i, field := range tt.expected {
+				if errs[i].Field != field {
+					t.Errorf(msgInvalidField, field, errs[i].Field)
+				}
+			}
+
+			// Validate does not keep the groups of a previous call
+			if errs := v.Validate(accountRequest{}); len(errs) != 1 {
+				t.Errorf(msgErrorCount, 1, len(errs), errs)
+			}
+		})
+	}
+}
+
+// TestMaxErrors ensures validation stops once the error limit is reached
+func TestMaxErrors(t *testing.T) {
+	invalid := TestUser{Email: "invalid", Password: "1"}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected int
+	}{
+		{name: "No limit", expected: len(New().Validate(invalid))},
+		{name: "Stop on first error", opts: []Option{WithStopOnFirstError()}, expected: 1},
+		{name: "Max errors", opts: []Option{WithMaxErrors(2)}, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := New(tt.opts...).Validate(invalid); len(errs) != tt.expected {
+				t.Errorf(msgErrorCount, tt.expected, len(errs), errs)
+			}
+		})
+	}
+
+	if tests[0].expected <= 2 {
+		t.Fatalf("Expected the invalid user to have more than 2 errors, got %d", tests[0].expected)
+	}
+}
+
+// TestBatchMaxErrors ensures batch validation stops once the batch reaches the limit
+func TestBatchMaxErrors(t *testing.T) {
+	valid := TestUser{Name: testName, Email: testUserEmail, Age: 30, Password: testPassword}
+	invalid := TestUser{Name: testName, Email: "invalid", Age: 30, Password: testPassword}
+	objects := []any{valid, invalid, valid, invalid, invalid}
+
+	v := New(WithStopOnFirstError())
+	results := v.ValidateBatch(objects)
+	if len(results) != 2 || len(results[1].Errors) != 1 {
+		t.Fatalf("Expected validation to stop at the first invalid item, got %v", results)
+	}
+
+	v = New(WithMaxErrors(2))
+	results = v.ValidateBatch(objects)
+	if len(results) != 4 {
+		t.Fatalf("Expected validation to stop at the second invalid item, got %v", results)
+	}
+
+	// The limit applies to every batch
+	if results := v.ValidateBatch(objects); len(results) != 4 {
+		t.Errorf("Expected the limit to be restored, got %d results", len(results))
+	}
+}
+
+// TestConcurrentBatch ensures concurrent batch validation matches sequential validation
+func TestConcurrentBatch(t *testing.T) {
+	valid := TestUser{Name: testName, Email: testUserEmail, Age: 30, Password: testPassword}
+	invalid := TestUser{Name: testName, Email: "invalid", Age: 30, Password: testPassword}
+	objects := make([]any, 200)
+	for i := range objects {
+		objects[i] = valid
+		if i%7 == 0 {
+			objects[i] = invalid
+		}
+	}
+	objects[50] = TestUser{}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "all errors"},
+		{name: "max errors", opts: []Option{WithMaxErrors(12)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := New(tt.opts...).ValidateBatch(objects)
+			results := New(append(tt.opts, WithConcurrency(8))...).ValidateBatch(objects)
+
+			if len(results) != len(expected) {
+				t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+			}
+			for i, result := range results {
+				if result.Index != i || len(result.Errors) != len(expected[i].Errors) {
+					t.Fatalf("Expected %v at %d, got %v", expected[i], i, result)
+				}
+				for j, err := range result.Errors {
+					if err.Field != expected[i].Errors[j].Field || err.Message != expected[i].Errors[j].Message {
+						t.Errorf("Expected %v, got %v", expected[i].Errors[j], err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package validator
+
+import "reflect"
+
+// ValueValidator is implemented by field types that validate themselves, such as
+// Money or PhoneNumber types. Fields of such types with a json tag are checked
+// during struct validation and a returned error is reported under the field path.
+type ValueValidator interface {
+	ValidateValue() error
+}
+
+// validateValueType reports the error of a field of a type implementing ValueValidator
+func (v *Validator) validateValueType(field reflect.Value, fieldName string) {
+	if field.Kind() == reflect.Ptr && field.IsNil() {
+		return
+	}
+
+	if err := valueAs(field).(ValueValidator).ValidateValue(); err != nil {
+		v.report(ValidationError{Field: fieldName, Message: v.translate(err.Error()), Code: CodeInvalid})
+	}
+}
@@ -0,0 +1,73 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+type testMoney struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m testMoney) ValidateValue() error {
+	if len(m.Currency) != 3 {
+		return errors.New("currency must be an ISO 4217 code")
+	}
+	return nil
+}
+
+type testPhone string
+
+func (p *testPhone) ValidateValue() error {
+	if len(*p) < 7 {
+		return errors.New("phone number is too short")
+	}
+	return nil
+}
+
+type paymentRequest struct {
+	Price   testMoney  `json:"price"`
+	Phone   *testPhone `json:"phone"`
+	Backup  testPhone  `json:"backup" validate:"required"`
+	Ignored testMoney  `json:"-"`
+}
+
+// TestValueValidator ensures self-validating field types are checked under the field path
+func TestValueValidator(t *testing.T) {
+	short := testPhone("123")
+
+	tests := []struct {
+		name     string
+		input    paymentRequest
+		expected []ValidationError
+	}{
+		{
+			name:  "Valid",
+			input: paymentRequest{Price: testMoney{Amount: 100, Currency: "EUR"}, Backup: "5550100"},
+		},
+		{
+			name:  "Invalid values",
+			input: paymentRequest{Price: testMoney{Amount: 100}, Phone: &short, Backup: short},
+			expected: []ValidationError{
+				{Field: "price", Message: "currency must be an ISO 4217 code"},
+				{Field: "phone", Message: "phone number is too short"},
+				{Field: "backup", Message: "phone number is too short"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := New().Validate(tt.input)
+			if len(errs) != len(tt.expected) {
+				t.Fatalf(msgErrorCount, len(tt.expected), len(errs), errs)
+			}
+			for i, err := range errs {
+				if err.Field != tt.expected[i].Field || err.Message != tt.expected[i].Message {
+					t.Errorf("Expected %v, got %v", tt.expected[i], err)
+				}
+			}
+		})
+	}
+}
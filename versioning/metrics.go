@@ -0,0 +1,86 @@
+package versioning
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
+)
+
+// UnsupportedVersion is the version metrics report rejected requests for versions
+// that aren't supported under, so clients can't add a label per value they send
+const UnsupportedVersion = "unsupported"
+
+// MetricsRecorder is notified of the version of every request, e.g. to increment a
+// counter labeled by version and find out when a version can be sunset. Rejected
+// requests are reported with IsSupported set to false, and with UnsupportedVersion
+// unless the version is supported, e.g. below the minimum version.
+type MetricsRecorder interface {
+	RecordVersion(c *context.Context, info VersionInfo)
+}
+
+// VersionCounter is a MetricsRecorder counting requests per version in memory.
+// The zero value is ready to use.
+type VersionCounter struct {
+	mu       sync.Mutex
+	served   map[string]int64
+	rejected map[string]int64
+}
+
+// RecordVersion counts a served or rejected request
+func (vc *VersionCounter) RecordVersion(_ *context.Context, info VersionInfo) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+
+	if info.IsSupported {
+		if vc.served == nil {
+			vc.served = make(map[string]int64)
+		}
+		vc.served[info.Version]++
+		return
+	}
+
+	if vc.rejected == nil {
+		vc.rejected = make(map[string]int64)
+	}
+	vc.rejected[info.Requested]++
+}
+
+// Counts returns the number of served requests per resolved version
+func (vc *VersionCounter) Counts() map[string]int64 {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return maps.Clone(vc.served)
+}
+
+// Rejected returns the number of rejected requests per requested version, with ""
+// for requests without a version and UnsupportedVersion for unknown versions
+func (vc *VersionCounter) Rejected() map[string]int64 {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return maps.Clone(vc.rejected)
+}
+
+// Handler serves the counts as JSON, e.g. r.GET("/metrics/versions", counter.Handler())
+func (vc *VersionCounter) Handler() router.HandlerFunc {
+	return func(c *context.Context) {
+		c.JSON(http.StatusOK, map[string]map[string]int64{
+			"served":   vc.Counts(),
+			"rejected": vc.Rejected(),
+		})
+	}
+}
+
+// recordVersion reports the version of a request to the metrics recorder, if any
+func (vo *Options) recordVersion(c *context.Context, info VersionInfo) {
+	if vo.Metrics == nil {
+		return
+	}
+	if !info.IsSupported && info.Requested != "" && !slices.Contains(vo.SupportedVersions, info.Requested) {
+		info.Version, info.Requested = UnsupportedVersion, UnsupportedVersion
+	}
+	vo.Metrics.RecordVersion(c, info)
+}
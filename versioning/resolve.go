@@ -0,0 +1,136 @@
+package versioning
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// parseVersion splits a version such as "2.1" into its numeric parts. A trailing
+// "x" or "*" part, as in "2.x", marks a range and is reported by wildcard.
+func parseVersion(version string) (parts []int, wildcard bool, ok bool) {
+	fields := strings.Split(version, ".")
+	for i, field := range fields {
+		if (field == "x" || field == "*") && i == len(fields)-1 && i > 0 {
+			return parts, true, true
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, false, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, false, true
+}
+
+// isVersion reports whether s looks like a version or version range, e.g. "2", "2.1" or "2.x"
+func isVersion(s string) bool {
+	_, _, ok := parseVersion(s)
+	return ok
+}
+
+// compareVersions compares two versions part by part, treating missing parts as
+// zero, so "2" equals "2.0" and "2.1" is below "2.10". Versions that are not numeric
+// are compared as strings.
+func compareVersions(a, b string) int {
+	pa, _, okA := parseVersion(a)
+	pb, _, okB := parseVersion(b)
+	if !okA || !okB {
+		return strings.Compare(a, b)
+	}
+
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// ResolveVersion returns the supported version serving a requested version. Exact
+// matches win; otherwise the request is a range matching the latest supported
+// version it prefixes, so "2" and "2.x" resolve to "2.1" when "2.0" and "2.1" are
+// supported.
+func (vo *Options) ResolveVersion(requested string) (string, bool) {
+	for _, v := range vo.SupportedVersions {
+		if v == requested {
+			return v, true
+		}
+	}
+
+	prefix, _, ok := parseVersion(requested)
+	if !ok {
+		return "", false
+	}
+
+	resolved := ""
+	for _, v := range vo.SupportedVersions {
+		parts, _, ok := parseVersion(v)
+		if !ok || len(parts) < len(prefix) || !equalParts(parts[:len(prefix)], prefix) {
+			continue
+		}
+		if resolved == "" || compareVersions(v, resolved) > 0 {
+			resolved = v
+		}
+	}
+	return resolved, resolved != ""
+}
+
+// equalParts reports whether two version prefixes are equal
+func equalParts(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// isBelowMinimum reports whether a version is older than the minimum supported version
+func (vo *Options) isBelowMinimum(version string) bool {
+	if vo.MinimumVersion == "" || !isVersion(version) {
+		return false
+	}
+	return compareVersions(version, vo.MinimumVersion) < 0
+}
+
+// missingVersionPolicy returns the policy for requests that don't specify a version
+func (vo *Options) missingVersionPolicy() FallbackPolicy {
+	switch {
+	case vo.MissingVersionPolicy != 0:
+		return vo.MissingVersionPolicy
+	case vo.StrictVersioning:
+		return FallbackReject
+	default:
+		return FallbackDefaultVersion
+	}
+}
+
+// fallbackVersion returns the version chosen by a policy, or false to reject the request
+func (vo *Options) fallbackVersion(policy FallbackPolicy) (string, bool) {
+	switch policy {
+	case FallbackDefaultVersion:
+		return vo.DefaultVersion, true
+	case FallbackLatest, FallbackOldest:
+		versions := vo.availableVersions()
+		if len(versions) == 0 {
+			return "", false
+		}
+		if policy == FallbackLatest {
+			return slices.MaxFunc(versions, compareVersions), true
+		}
+		return slices.MinFunc(versions, compareVersions), true
+	default:
+		return "", false
+	}
+}
@@ -2,8 +2,10 @@
 package versioning
 
 import (
+	stdcontext "context"
 	"fmt"
-	"strconv"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/lamboktulussimamora/gra/context"
@@ -23,11 +25,20 @@ type VersionStrategy interface {
 	Apply(c *context.Context, version string)
 }
 
-// PathVersionStrategy extracts version from URL path (/v1/resource)
+// PathVersionStrategy extracts version from URL path (/v1/resource). Use
+// StripVersion or Mount to route versioned paths to the same handlers.
 type PathVersionStrategy struct {
 	Prefix string // Optional prefix before version number (default: "v")
 }
 
+// pathVersionKey holds the version of a request found by StripVersion or Mount
+type pathVersionKey struct{}
+
+// Grouper creates route groups; implemented by *router.Router and *router.Group
+type Grouper interface {
+	Group(prefix string) *router.Group
+}
+
 // QueryVersionStrategy extracts version from query parameter
 type QueryVersionStrategy struct {
 	ParamName string // The query parameter name (default: "version" or "v")
@@ -38,24 +49,56 @@ type HeaderVersionStrategy struct {
 	HeaderName string // The header name (default: "Accept-Version")
 }
 
-// MediaTypeVersionStrategy extracts version from the Accept header media type
+// MediaTypeVersionStrategy extracts version from the Accept header media type,
+// e.g. "application/vnd.myapp.v2+json"
 type MediaTypeVersionStrategy struct {
 	MediaTypePrefix string // The media type prefix (default: "application/vnd.")
+	Vendor          string // Optional vendor name the media type must use, e.g. "myapp"
 }
 
 // VersionInfo represents API version information
 type VersionInfo struct {
 	Version     string
+	Requested   string // Version asked for by the client, e.g. "2" when resolved to "2.1"
 	IsSupported bool
 }
 
+// VersionError is the data of version error responses, telling clients which
+// versions they can upgrade to
+type VersionError struct {
+	Version           string   `json:"version,omitempty"`
+	MinimumVersion    string   `json:"minimum_version,omitempty"`
+	SupportedVersions []string `json:"supported_versions"`
+}
+
+// FallbackPolicy decides which version serves requests without a supported version.
+// The zero value keeps the built-in behavior: requests without a version use
+// DefaultVersion, or are rejected with StrictVersioning, and unsupported versions
+// are rejected.
+type FallbackPolicy int
+
+// Fallback policies
+const (
+	FallbackDefaultVersion FallbackPolicy = iota + 1 // Use DefaultVersion
+	FallbackLatest                                   // Use the latest supported version
+	FallbackOldest                                   // Use the oldest supported version not below MinimumVersion
+	FallbackReject                                   // Reject with 400 and the supported versions
+)
+
 // Options contains configuration for API versioning.
 type Options struct {
-	Strategy          VersionStrategy    // The versioning strategy to use
-	DefaultVersion    string             // The default version to use if none is specified
-	SupportedVersions []string           // List of supported versions
-	StrictVersioning  bool               // If true, rejects requests that don't specify a version
-	ErrorHandler      router.HandlerFunc // Custom handler for version errors
+	Strategy                 VersionStrategy    // The versioning strategy to use
+	DefaultVersion           string             // The default version to use if none is specified
+	SupportedVersions        []string           // List of supported versions
+	StrictVersioning         bool               // If true, rejects requests that don't specify a version
+	ErrorHandler             router.HandlerFunc // Custom handler for version errors
+	MinimumVersion           string             // Versions below it are rejected with MinimumVersionStatus
+	MinimumVersionStatus     int                // Status for versions below MinimumVersion (default: 426 Upgrade Required)
+	MissingVersionPolicy     FallbackPolicy     // Version for requests that don't specify one
+	UnsupportedVersionPolicy FallbackPolicy     // Version for requests asking for an unsupported one
+	Metrics                  MetricsRecorder    // Optional recorder of the version of every request
+
+	transforms map[string][]func(any) any // Response transforms by version, see Transform
 }
 
 // New creates a new versioning middleware with default options
@@ -75,6 +118,12 @@ func (vo *Options) WithStrategy(strategy VersionStrategy) *Options {
 	return vo
 }
 
+// WithMediaTypeVersioning negotiates the version from vendor media types such as
+// "Accept: application/vnd.myapp.v2+json" and answers with the matching Content-Type
+func (vo *Options) WithMediaTypeVersioning(vendor string) *Options {
+	return vo.WithStrategy(&MediaTypeVersionStrategy{Vendor: vendor})
+}
+
 // WithDefaultVersion sets the default API version
 func (vo *Options) WithDefaultVersion(version string) *Options {
 	vo.DefaultVersion = version
@@ -93,6 +142,48 @@ func (vo *Options) WithStrictVersioning(strict bool) *Options {
 	return vo
 }
 
+// WithMinimumVersion rejects versions below version with 426 Upgrade Required, or
+// with status if given, e.g. http.StatusBadRequest
+func (vo *Options) WithMinimumVersion(version string, status ...int) *Options {
+	vo.MinimumVersion = version
+	if len(status) > 0 {
+		vo.MinimumVersionStatus = status[0]
+	}
+	return vo
+}
+
+// WithFallback sets the policies for requests without a version and with an
+// unsupported version, e.g. WithFallback(FallbackLatest, FallbackReject)
+func (vo *Options) WithFallback(missing, unsupported FallbackPolicy) *Options {
+	vo.MissingVersionPolicy = missing
+	vo.UnsupportedVersionPolicy = unsupported
+	return vo
+}
+
+// WithMetrics reports the version of every request to recorder, e.g. a *VersionCounter
+func (vo *Options) WithMetrics(recorder MetricsRecorder) *Options {
+	vo.Metrics = recorder
+	return vo
+}
+
+// Transform registers a function converting the data of c.Success and c.SuccessPaged
+// responses for a version, so one handler can serve older versions by down-converting
+// its payload. Transforms of the same version run in registration order.
+//
+//	v.Transform("1", func(data any) any {
+//		if p, ok := data.(Product); ok {
+//			return ProductV1{ID: p.ID, Name: p.Name}
+//		}
+//		return data
+//	})
+func (vo *Options) Transform(version string, transform func(any) any) *Options {
+	if vo.transforms == nil {
+		vo.transforms = make(map[string][]func(any) any)
+	}
+	vo.transforms[version] = append(vo.transforms[version], transform)
+	return vo
+}
+
 // WithErrorHandler sets a custom error handler for version errors
 func (vo *Options) WithErrorHandler(handler router.HandlerFunc) *Options {
 	vo.ErrorHandler = handler
@@ -100,35 +191,67 @@ func (vo *Options) WithErrorHandler(handler router.HandlerFunc) *Options {
 }
 
 // handleVersionError handles versioning errors with custom or default error responses
-func (vo *Options) handleVersionError(c *context.Context, message string) {
+func (vo *Options) handleVersionError(c *context.Context, status int, message string, version string) {
+	vo.recordVersion(c, VersionInfo{Version: version, Requested: version})
+
+	c.Abort()
 	if vo.ErrorHandler != nil {
 		vo.ErrorHandler(c)
-	} else {
-		c.Error(400, message)
+		return
 	}
+
+	c.JSON(status, context.APIResponse{
+		Status: "error",
+		Error:  message,
+		Data: VersionError{
+			Version:           version,
+			MinimumVersion:    vo.MinimumVersion,
+			SupportedVersions: vo.availableVersions(),
+		},
+	})
 }
 
-// isVersionSupported checks if the given version is in the list of supported versions
-func (vo *Options) isVersionSupported(version string) bool {
+// availableVersions returns the supported versions not below the minimum version
+func (vo *Options) availableVersions() []string {
+	versions := make([]string, 0, len(vo.SupportedVersions))
 	for _, v := range vo.SupportedVersions {
-		if v == version {
-			return true
+		if !vo.isBelowMinimum(v) {
+			versions = append(versions, v)
 		}
 	}
-	return false
+	return versions
+}
+
+// minimumVersionStatus returns the status for versions below the minimum
+func (vo *Options) minimumVersionStatus() int {
+	if vo.MinimumVersionStatus == 0 {
+		return http.StatusUpgradeRequired
+	}
+	return vo.MinimumVersionStatus
 }
 
 // applyVersionToContext adds version information to the request context
-func (vo *Options) applyVersionToContext(c *context.Context, version string) {
+func (vo *Options) applyVersionToContext(c *context.Context, version, requested string) {
 	// Apply version to the request
 	vo.Strategy.Apply(c, version)
 
 	// Store version info in context
 	versionInfo := VersionInfo{
 		Version:     version,
+		Requested:   requested,
 		IsSupported: true,
 	}
 	c.WithValue("API-Version", versionInfo)
+	vo.recordVersion(c, versionInfo)
+
+	if transforms := vo.transforms[version]; len(transforms) > 0 {
+		context.Set(c, context.ResponseTransformKey, func(data any) any {
+			for _, transform := range transforms {
+				data = transform(data)
+			}
+			return data
+		})
+	}
 }
 
 // Middleware returns a middleware that applies API versioning
@@ -136,25 +259,44 @@ func (vo *Options) Middleware() router.Middleware {
 	return func(next router.HandlerFunc) router.HandlerFunc {
 		return func(c *context.Context) {
 			// Extract version
-			version, err := vo.Strategy.ExtractVersion(c)
+			requested, err := vo.Strategy.ExtractVersion(c)
+			version := requested
 
 			// Handle missing version
 			if err != nil {
-				if vo.StrictVersioning {
-					vo.handleVersionError(c, "API version required")
+				requested = ""
+				var ok bool
+				if version, ok = vo.fallbackVersion(vo.missingVersionPolicy()); !ok {
+					vo.handleVersionError(c, http.StatusBadRequest, "API version required", "")
 					return
 				}
-				version = vo.DefaultVersion
 			}
 
-			// Check if version is supported
-			if !vo.isVersionSupported(version) {
-				vo.handleVersionError(c, fmt.Sprintf("API version %s is not supported", version))
+			// Resolve ranges such as 2.x to the latest supported version
+			resolved, supported := vo.ResolveVersion(version)
+			if !supported {
+				resolved = version
+			}
+
+			// Reject versions below the minimum with upgrade information
+			if vo.isBelowMinimum(resolved) {
+				message := fmt.Sprintf("API version %s is no longer supported, upgrade to %s or later", version, vo.MinimumVersion)
+				vo.handleVersionError(c, vo.minimumVersionStatus(), message, version)
 				return
 			}
 
+			// Check if version is supported
+			if !supported {
+				fallback, ok := vo.fallbackVersion(vo.UnsupportedVersionPolicy)
+				if !ok {
+					vo.handleVersionError(c, http.StatusBadRequest, fmt.Sprintf("API version %s is not supported", version), version)
+					return
+				}
+				resolved = fallback
+			}
+
 			// Apply version and continue
-			vo.applyVersionToContext(c, version)
+			vo.applyVersionToContext(c, resolved, requested)
 			next(c)
 		}
 	}
@@ -173,8 +315,23 @@ func extractPathSegments(path string) []string {
 	return strings.Split(strings.TrimPrefix(path, "/"), "/")
 }
 
+// pathVersion returns the version of a path segment such as "v1". A digit must
+// follow the prefix, so segments such as "videos" are not taken as versions.
+func pathVersion(segment, prefix string) (string, bool) {
+	version, ok := strings.CutPrefix(segment, prefix)
+	if !ok || version == "" || version[0] < '0' || version[0] > '9' {
+		return "", false
+	}
+	return version, true
+}
+
 // ExtractVersion extracts version from URL path
 func (s *PathVersionStrategy) ExtractVersion(c *context.Context) (string, error) {
+	// Version removed from the path by StripVersion or set by Mount
+	if version, ok := c.Value(pathVersionKey{}).(string); ok {
+		return version, nil
+	}
+
 	path := c.Request.URL.Path
 	prefix := getDefaultPrefix(s.Prefix)
 
@@ -185,13 +342,61 @@ func (s *PathVersionStrategy) ExtractVersion(c *context.Context) (string, error)
 	}
 
 	// Check if first segment matches our version format
-	if strings.HasPrefix(segments[0], prefix) {
-		return strings.TrimPrefix(segments[0], prefix), nil
+	if version, ok := pathVersion(segments[0], prefix); ok {
+		return version, nil
 	}
 
 	return "", fmt.Errorf("no version in path")
 }
 
+// StripVersion removes the leading version segment from request paths before
+// they reach next, usually the router, so /v1/users and /v2/users both match
+// the /users route. ExtractVersion still reports the removed version:
+//
+//	http.ListenAndServe(":8080", strategy.StripVersion(r))
+func (s *PathVersionStrategy) StripVersion(next http.Handler) http.Handler {
+	prefix := getDefaultPrefix(s.Prefix)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		segment, rest, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+		version, ok := pathVersion(segment, prefix)
+		if !ok {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		stripped := req.WithContext(stdcontext.WithValue(req.Context(), pathVersionKey{}, version))
+		stripped.URL = new(url.URL)
+		*stripped.URL = *req.URL
+		stripped.URL.Path = "/" + rest
+		stripped.URL.RawPath = ""
+		next.ServeHTTP(w, stripped)
+	})
+}
+
+// Mount registers the routes added by register once per version under the
+// version prefix, e.g. /v1/users and /v2/users, so one handler tree serves
+// several versions. It works under other groups too, such as /api/v1/users.
+func (s *PathVersionStrategy) Mount(parent Grouper, register func(g *router.Group), versions ...string) {
+	prefix := getDefaultPrefix(s.Prefix)
+
+	for _, version := range versions {
+		g := parent.Group("/" + prefix + version)
+		g.UsePhase(router.PhasePreRouting, withPathVersion(version))
+		register(g)
+	}
+}
+
+// withPathVersion records the version of a mounted group for ExtractVersion
+func withPathVersion(version string) router.Middleware {
+	return func(next router.HandlerFunc) router.HandlerFunc {
+		return func(c *context.Context) {
+			c.WithValue(pathVersionKey{}, version)
+			next(c)
+		}
+	}
+}
+
 // Apply doesn't need to do anything for path versioning
 func (s *PathVersionStrategy) Apply(_ *context.Context, _ string) {
 	// Path versioning is handled by the router, so we don't need to do anything here
@@ -256,58 +461,57 @@ func (s *HeaderVersionStrategy) Apply(c *context.Context, version string) {
 	c.SetHeader(s.getHeaderName(), version)
 }
 
-// parseVersionFromMediaType attempts to extract a version from a media type string
-func parseVersionFromMediaType(mediaType string, prefix string) (string, bool) {
-	mediaType = strings.TrimSpace(mediaType)
-	if !strings.HasPrefix(mediaType, prefix) {
-		return "", false
+// parseVersionFromMediaType attempts to extract the vendor and version from a media
+// type such as "application/vnd.myapp.v2+json; q=0.9"
+func parseVersionFromMediaType(mediaType string, prefix string) (vendor, version string, ok bool) {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	name, found := strings.CutPrefix(strings.TrimSpace(mediaType), prefix)
+	if !found {
+		return "", "", false
 	}
 
 	// Format is typically: application/vnd.company.resource.v1+json
-	parts := strings.Split(mediaType, ".")
-	for _, part := range parts {
-		if !strings.HasPrefix(part, "v") {
-			continue
+	name, _, _ = strings.Cut(name, "+")
+	if idx := strings.LastIndex(name, ".v"); idx >= 0 {
+		vendor, version = name[:idx], name[idx+2:]
+	} else {
+		version, found = strings.CutPrefix(name, "v")
+		if !found {
+			return "", "", false
 		}
+	}
 
-		// Extract version number
-		version := strings.TrimPrefix(part, "v")
-		// Handle +json or similar suffix
-		if idx := strings.Index(version, "+"); idx > 0 {
-			version = version[:idx]
-		}
+	// Ensure it's a valid numeric version, e.g. "2" or "2.1"
+	if !isVersion(version) {
+		return "", "", false
+	}
+	return vendor, version, true
+}
+
+// acceptedVersion returns the version of the first vendor media type in the Accept
+// header, of the configured vendor if any
+func (s *MediaTypeVersionStrategy) acceptedVersion(c *context.Context) (string, bool) {
+	prefix := getMediaTypePrefix(s.MediaTypePrefix)
 
-		// Ensure it's a valid numeric version
-		_, err := strconv.Atoi(version)
-		if err == nil {
+	// Parse Accept header and look for vendor media type
+	for _, mediaType := range strings.Split(c.GetHeader("Accept"), ",") {
+		vendor, version, found := parseVersionFromMediaType(mediaType, prefix)
+		if found && (s.Vendor == "" || vendor == s.Vendor) {
 			return version, true
 		}
 	}
-
 	return "", false
 }
 
 // ExtractVersion extracts version from Accept header media type
 func (s *MediaTypeVersionStrategy) ExtractVersion(c *context.Context) (string, error) {
-	mediaTypePrefix := s.MediaTypePrefix
-	if mediaTypePrefix == "" {
-		mediaTypePrefix = "application/vnd."
-	}
-
-	accept := c.GetHeader("Accept")
-	if accept == "" {
+	if c.GetHeader("Accept") == "" {
 		return "", fmt.Errorf("no Accept header")
 	}
 
-	// Parse Accept header and look for vendor media type
-	mediaTypes := strings.Split(accept, ",")
-	for _, mediaType := range mediaTypes {
-		version, found := parseVersionFromMediaType(mediaType, mediaTypePrefix)
-		if found {
-			return version, nil
-		}
+	if version, found := s.acceptedVersion(c); found {
+		return version, nil
 	}
-
 	return "", fmt.Errorf("no version in Accept header")
 }
 
@@ -319,13 +523,53 @@ func getMediaTypePrefix(prefix string) string {
 	return prefix
 }
 
-// Apply sets the content type with the current version
+// Apply sets the versioned media type of the configured vendor as the content type
+// of JSON responses, also for JSON written by handlers, so "Accept:
+// application/vnd.myapp.v2+json" is answered with "Content-Type:
+// application/vnd.myapp.v2+json". Without a vendor, the fixed "api" vendor is used
+// rather than one sent by the client.
 func (s *MediaTypeVersionStrategy) Apply(c *context.Context, version string) {
-	prefix := getMediaTypePrefix(s.MediaTypePrefix)
+	vendor := s.Vendor
+	if vendor == "" {
+		vendor = "api"
+	}
 
 	// Set the content type with version
-	contentType := fmt.Sprintf("%sAPI.v%s+json", prefix, version)
-	c.SetHeader("Content-Type", contentType)
+	contentType := fmt.Sprintf("%s%s.v%s+json", getMediaTypePrefix(s.MediaTypePrefix), vendor, version)
+	c.Writer = &mediaTypeWriter{ResponseWriter: c.Writer, contentType: contentType}
+}
+
+// mediaTypeWriter replaces the plain JSON content type of responses with the
+// versioned media type just before the headers are sent
+type mediaTypeWriter struct {
+	http.ResponseWriter
+	contentType string
+	wroteHeader bool
+}
+
+// WriteHeader sets the versioned media type on JSON responses
+func (w *mediaTypeWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		header := w.ResponseWriter.Header()
+		if strings.HasPrefix(header.Get("Content-Type"), context.ContentTypeJSON) {
+			header.Set("Content-Type", w.contentType)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write sends an implicit 200 status before the first body bytes
+func (w *mediaTypeWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter
+func (w *mediaTypeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
 }
 
 // GetAPIVersion retrieves the API version from the context
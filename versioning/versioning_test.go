@@ -1,11 +1,13 @@
 package versioning
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/lamboktulussimamora/gra/context"
+	"github.com/lamboktulussimamora/gra/router"
 )
 
 // Path constants for testing
@@ -174,6 +176,7 @@ func TestPathVersionStrategy(t *testing.T) {
 		{"Valid v2 path", pathV2Users, version2, false},
 		{"No version in path", pathUsers, "", true},
 		{"Custom prefix", "/api-v3/users", "", true},
+		{"Prefix without digits", "/videos", "", true},
 	}
 
 	for _, tt := range tests {
@@ -244,6 +247,8 @@ func TestMediaTypeVersionStrategy(t *testing.T) {
 		{"Multiple media types with valid one", mediaTypeMultipleWithV2, version2, false},
 		{"No valid vendor media type", mediaTypeJSON, "", true},
 		{"Missing Accept header", "", "", true},
+		{"Quality parameter", "application/vnd.myapp.v2+json; q=0.9", version2, false},
+		{"Without vendor", "application/vnd.v3+json", version3, false},
 	}
 
 	for _, tt := range tests {
@@ -305,3 +310,309 @@ func TestGetAPIVersion(t *testing.T) {
 	info, exists = GetAPIVersion(c)
 	checkVersionInfo(t, info, exists, true, expectedInfo)
 }
+
+// versionHandler writes the resolved API version and request path
+func versionHandler(c *context.Context) {
+	info, _ := GetAPIVersion(c)
+	c.JSON(http.StatusOK, map[string]string{"version": info.Version, "path": c.Request.URL.Path})
+}
+
+func TestStripVersion(t *testing.T) {
+	strategy := &PathVersionStrategy{Prefix: "v"}
+	r := router.New()
+	r.Use(New().WithStrategy(strategy).WithSupportedVersions(version1, version2).Middleware())
+	r.GET(pathUsers, versionHandler)
+	handler := strategy.StripVersion(r)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"Version 1", pathV1Users, http.StatusOK, `{"path":"/users","version":"1"}`},
+		{"Version 2", pathV2Users, http.StatusOK, `{"path":"/users","version":"2"}`},
+		{"No version", pathUsers, http.StatusOK, `{"path":"/users","version":"1"}`},
+		{"Unsupported version", pathV3Users, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, w, _ := setupPathRequest(tt.path)
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf(errExpectedStatus, tt.expectedStatus, w.Code)
+			}
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody+"\n" {
+				t.Errorf("Expected body %s but got %s", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMount(t *testing.T) {
+	strategy := &PathVersionStrategy{Prefix: "v"}
+	r := router.New()
+	r.Use(New().WithStrategy(strategy).WithSupportedVersions(version1, version2).Middleware())
+	strategy.Mount(r.Group("/api"), func(g *router.Group) {
+		g.GET(pathUsers, versionHandler)
+	}, version1, version2)
+
+	for _, version := range []string{version1, version2} {
+		req, w, _ := setupPathRequest("/api/v" + version + pathUsers)
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf(errExpectedStatus, http.StatusOK, w.Code)
+		}
+		expected := `{"path":"/api/v` + version + `/users","version":"` + version + `"}` + "\n"
+		if w.Body.String() != expected {
+			t.Errorf("Expected body %s but got %s", expected, w.Body.String())
+		}
+	}
+}
+
+func TestMediaTypeContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		vendor      string
+		accept      string
+		expectedVer string
+		expectedCT  string
+	}{
+		{"Matched vendor", "myapp", "application/json, application/vnd.myapp.v2+json", version2, "application/vnd.myapp.v2+json"},
+		{"Other vendor ignored", "myapp", "application/vnd.other.v2+json", version1, "application/vnd.myapp.v1+json"},
+		{"Any vendor", "", mediaTypeVndAPIV2, version2, "application/vnd.api.v2+json"},
+		{"Client vendor not repeated", "", "application/vnd.<script>.v2+json", version2, "application/vnd.api.v2+json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New().WithMediaTypeVersioning(tt.vendor).WithSupportedVersions(version1, version2)
+			_, w, c := setupMediaTypeRequest(tt.accept)
+			v.Middleware()(versionHandler)(c)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf(errExpectedStatus, http.StatusOK, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.expectedCT {
+				t.Errorf("Expected Content-Type %s but got %s", tt.expectedCT, ct)
+			}
+			if info, _ := GetAPIVersion(c); info.Version != tt.expectedVer {
+				t.Errorf(errExpectedVersion, tt.expectedVer, info.Version)
+			}
+		})
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	v := New().WithSupportedVersions(version1, "2.0", "2.1", "2.10", "3.0.1")
+
+	tests := []struct {
+		requested   string
+		expectedVer string
+		expectedOK  bool
+	}{
+		{version1, version1, true},
+		{"2.1", "2.1", true},
+		{version2, "2.10", true},
+		{"2.x", "2.10", true},
+		{"3.*", "3.0.1", true},
+		{"3.0", "3.0.1", true},
+		{"2.2", "", false},
+		{"4", "", false},
+		{"beta", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			version, ok := v.ResolveVersion(tt.requested)
+			if ok != tt.expectedOK || version != tt.expectedVer {
+				t.Errorf("Expected %s (%v) but got %s (%v)", tt.expectedVer, tt.expectedOK, version, ok)
+			}
+		})
+	}
+}
+
+func TestMinimumVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          []int
+		path            string
+		expectedStatus  int
+		expectedVersion string
+	}{
+		{"Below minimum", nil, pathV1Users, http.StatusUpgradeRequired, ""},
+		{"Below minimum with custom status", []int{http.StatusBadRequest}, pathV1Users, http.StatusBadRequest, ""},
+		{"Range resolved above minimum", nil, "/v2/users", http.StatusOK, "2.1"},
+		{"Unsupported above minimum", nil, "/v2.5/users", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New().
+				WithSupportedVersions(version1, "2.0", "2.1").
+				WithMinimumVersion("2.0", tt.status...)
+			var capturedVersion string
+			handler := func(c *context.Context) {
+				if versionInfo, exists := GetAPIVersion(c); exists {
+					capturedVersion = versionInfo.Version
+				}
+				c.Status(http.StatusOK)
+			}
+			_, w, c := setupPathRequest(tt.path)
+			v.Middleware()(handler)(c)
+			checkVersioningResults(t, w.Code, capturedVersion, tt.expectedStatus, tt.expectedVersion)
+
+			if tt.expectedStatus != http.StatusOK {
+				var resp struct {
+					Data VersionError `json:"data"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if resp.Data.MinimumVersion != "2.0" || len(resp.Data.SupportedVersions) != 2 {
+					t.Errorf("Expected upgrade information but got %+v", resp.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestFallbackPolicies(t *testing.T) {
+	tests := []struct {
+		name            string
+		missing         FallbackPolicy
+		unsupported     FallbackPolicy
+		path            string
+		expectedStatus  int
+		expectedVersion string
+	}{
+		{"Missing uses default version", 0, 0, pathUsers, http.StatusOK, version2},
+		{"Missing uses latest", FallbackLatest, 0, pathUsers, http.StatusOK, version3},
+		{"Missing uses oldest", FallbackOldest, 0, pathUsers, http.StatusOK, version1},
+		{"Missing rejected", FallbackReject, 0, pathUsers, http.StatusBadRequest, ""},
+		{"Unsupported rejected", 0, 0, "/v4/users", http.StatusBadRequest, ""},
+		{"Unsupported uses latest", 0, FallbackLatest, "/v4/users", http.StatusOK, version3},
+		{"Unsupported uses default version", 0, FallbackDefaultVersion, "/v4/users", http.StatusOK, version2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New().
+				WithSupportedVersions(version2, version1, version3).
+				WithDefaultVersion(version2).
+				WithFallback(tt.missing, tt.unsupported)
+			var capturedVersion string
+			handler := func(c *context.Context) {
+				if versionInfo, exists := GetAPIVersion(c); exists {
+					capturedVersion = versionInfo.Version
+				}
+				c.Status(http.StatusOK)
+			}
+			_, w, c := setupPathRequest(tt.path)
+			v.Middleware()(handler)(c)
+			checkVersioningResults(t, w.Code, capturedVersion, tt.expectedStatus, tt.expectedVersion)
+
+			if tt.expectedStatus == http.StatusBadRequest {
+				var resp struct {
+					Data VersionError `json:"data"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if len(resp.Data.SupportedVersions) != 3 {
+					t.Errorf("Expected the supported versions but got %+v", resp.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestVersionMetrics(t *testing.T) {
+	counter := &VersionCounter{}
+	v := New().
+		WithSupportedVersions(version1, "2.0", "2.1").
+		WithDefaultVersion(version1).
+		WithMetrics(counter)
+	handler := v.Middleware()(func(c *context.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{pathV1Users, pathUsers, "/v2/users", "/v2.1/users", pathV3Users, "/v4/users", "/v99999/users"} {
+		_, _, c := setupPathRequest(path)
+		handler(c)
+	}
+
+	served := counter.Counts()
+	if served[version1] != 2 || served["2.1"] != 2 || len(served) != 2 {
+		t.Errorf("Expected 2 requests for versions 1 and 2.1 but got %v", served)
+	}
+	// Unknown versions share a bucket, whatever clients send
+	if rejected := counter.Rejected(); rejected[UnsupportedVersion] != 3 || len(rejected) != 1 {
+		t.Errorf("Expected 3 rejected requests for unsupported versions but got %v", rejected)
+	}
+
+	// Supported versions below the minimum keep their own count
+	belowMinimum := &VersionCounter{}
+	handler = New().
+		WithSupportedVersions(version1, version2).
+		WithMinimumVersion(version2).
+		WithMetrics(belowMinimum).
+		Middleware()(func(c *context.Context) { c.Status(http.StatusOK) })
+	_, _, c := setupPathRequest(pathV1Users)
+	handler(c)
+	if rejected := belowMinimum.Rejected(); rejected[version1] != 1 || len(rejected) != 1 {
+		t.Errorf("Expected 1 rejected request for version 1 but got %v", rejected)
+	}
+
+	_, w, c := setupPathRequest("/metrics/versions")
+	counter.Handler()(c)
+	if expected := `{"rejected":{"unsupported":3},"served":{"1":2,"2.1":2}}` + "\n"; w.Body.String() != expected {
+		t.Errorf("Expected body %s but got %s", expected, w.Body.String())
+	}
+}
+
+// productV2 is the canonical payload of the transform test
+type productV2 struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func TestTransform(t *testing.T) {
+	v := New().
+		WithSupportedVersions(version1, version2).
+		Transform(version1, func(data any) any {
+			if p, ok := data.(productV2); ok {
+				return map[string]any{"id": p.ID, "name": p.Title}
+			}
+			return data
+		})
+	handler := v.Middleware()(func(c *context.Context) {
+		c.Success(http.StatusOK, "Product found", productV2{ID: 7, Title: "Lamp"})
+	})
+
+	tests := []struct {
+		path         string
+		expectedData string
+	}{
+		{pathV1Users, `{"id":7,"name":"Lamp"}`},
+		{pathV2Users, `{"id":7,"title":"Lamp"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, w, c := setupPathRequest(tt.path)
+			handler(c)
+
+			var resp struct {
+				Data json.RawMessage `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if string(resp.Data) != tt.expectedData {
+				t.Errorf("Expected data %s but got %s", tt.expectedData, resp.Data)
+			}
+		})
+	}
+}